@@ -0,0 +1,36 @@
+package beads_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+)
+
+func TestAssignedIssuesPastScanCap(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(beads.Issue{ID: "gt-x-wip", Status: "in_progress", Assignee: "rig/polecats/x"})
+	// Enough unrelated open work to overflow the open scan
+	for i := 0; i < 600; i++ {
+		fake.Add(beads.Issue{ID: fmt.Sprintf("gt-%d", i), Status: "open", Assignee: "rig/polecats/busy"})
+	}
+	fake.Add(
+		beads.Issue{ID: "gt-x-open", Status: "open", Assignee: "rig/polecats/x"},
+		beads.Issue{ID: "gt-y-open", Status: "open", Assignee: "rig/polecats/y"},
+	)
+	b := beads.New(t.TempDir())
+
+	assigned, err := b.AssignedIssues([]string{"rig/polecats/busy", "rig/polecats/x", "rig/polecats/y", "rig/polecats/z"})
+	if err != nil {
+		t.Fatalf("AssignedIssues: %v", err)
+	}
+	if len(assigned) != 3 || assigned["rig/polecats/busy"].ID != "gt-0" ||
+		assigned["rig/polecats/x"].ID != "gt-x-open" || assigned["rig/polecats/y"].ID != "gt-y-open" {
+		t.Errorf("AssignedIssues = %v", assigned)
+	}
+	// Two capped scans, then one uncapped rescan of the open issues
+	if n := fake.Count("list"); n != 3 {
+		t.Errorf("list invoked %d times, want 3", n)
+	}
+}
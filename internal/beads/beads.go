@@ -2,7 +2,7 @@
 package beads
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -76,6 +76,7 @@ type ListOptions struct {
 	Parent     string // filter by parent ID
 	Assignee   string // filter by assignee (e.g., "gastown/Toast")
 	NoAssignee bool   // filter for issues with no assignee
+	Limit      int    // max results; 0 for bd's default, -1 for no limit
 }
 
 // CreateOptions specifies options for creating an issue.
@@ -177,7 +178,23 @@ func (b *Beads) Init(prefix string) error {
 }
 
 // run executes a bd command and returns stdout.
+// While a request cache is enabled (see EnableRequestCache), reads are
+// answered from it and writes invalidate it.
 func (b *Beads) run(args ...string) ([]byte, error) {
+	cached, ok, gen := b.cacheGet(args)
+	if ok {
+		return cached, nil
+	}
+	out, err := b.exec(args)
+	if err == nil || !cacheable(args) {
+		// A failed write may still have changed something
+		b.cachePut(args, out, gen)
+	}
+	return out, err
+}
+
+// exec forks bd for a command and returns stdout.
+func (b *Beads) exec(args []string) ([]byte, error) {
 	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
 	// The daemon is primarily useful for write coalescing, not reads.
 	// Use --allow-stale to prevent failures when db is out of sync with JSONL
@@ -201,9 +218,6 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 		fullArgs = append([]string{"--db", beadsDB}, fullArgs...)
 	}
 
-	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Dir = b.workDir
-
 	// Build environment: filter beads env vars when in isolated mode (tests)
 	// to prevent routing to production databases.
 	var env []string
@@ -212,25 +226,24 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	} else {
 		env = os.Environ()
 	}
-	cmd.Env = append(env, "BEADS_DIR="+beadsDir)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	stdout, stderr, err := Exec(context.Background(), Command{
+		Dir:  b.workDir,
+		Args: fullArgs,
+		Env:  append(env, "BEADS_DIR="+beadsDir),
+	})
 	if err != nil {
-		return nil, b.wrapError(err, stderr.String(), args)
+		return nil, b.wrapError(err, string(stderr), args)
 	}
 
 	// Handle bd --no-daemon exit code 0 bug: when issue not found,
 	// --no-daemon exits 0 but writes error to stderr with empty stdout.
 	// Detect this case and treat as error to avoid JSON parse failures.
-	if stdout.Len() == 0 && stderr.Len() > 0 {
-		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
+	if len(stdout) == 0 && len(stderr) > 0 {
+		return nil, b.wrapError(fmt.Errorf("command produced no output"), string(stderr), args)
 	}
 
-	return stdout.Bytes(), nil
+	return stdout, nil
 }
 
 // Run executes a bd command and returns stdout.
@@ -312,6 +325,11 @@ func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
 	if opts.NoAssignee {
 		args = append(args, "--no-assignee")
 	}
+	if opts.Limit < 0 {
+		args = append(args, "--limit=0")
+	} else if opts.Limit > 0 {
+		args = append(args, fmt.Sprintf("--limit=%d", opts.Limit))
+	}
 
 	out, err := b.run(args...)
 	if err != nil {
@@ -367,6 +385,52 @@ func (b *Beads) GetAssignedIssue(assignee string) (*Issue, error) {
 	return issues[0], nil
 }
 
+// assignedScanLimit caps each status scan in AssignedIssues, so a large
+// beads database doesn't cost a full listing on every call.
+const assignedScanLimit = 500
+
+// AssignedIssues is the batched form of GetAssignedIssue: it looks up the
+// first open (or else in_progress) issue for each assignee with two capped
+// bd calls in total, rather than up to two per assignee. A scan that hits
+// the cap is repeated once without a limit, so the cost stays at most four
+// calls however many assignees are asked for.
+// Assignees with no such issue are not included in the map.
+func (b *Beads) AssignedIssues(assignees []string) (map[string]*Issue, error) {
+	result := make(map[string]*Issue, len(assignees))
+	if len(assignees) == 0 {
+		return result, nil
+	}
+
+	wanted := make(map[string]bool, len(assignees))
+	for _, a := range assignees {
+		wanted[a] = true
+	}
+
+	// Open issues take precedence, matching GetAssignedIssue
+	for _, status := range []string{"open", "in_progress"} {
+		issues, err := b.List(ListOptions{
+			Status:   status,
+			Priority: -1,
+			Limit:    assignedScanLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) >= assignedScanLimit {
+			if issues, err = b.List(ListOptions{Status: status, Priority: -1, Limit: -1}); err != nil {
+				return nil, err
+			}
+		}
+		for _, issue := range issues {
+			if wanted[issue.Assignee] && result[issue.Assignee] == nil {
+				result[issue.Assignee] = issue
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // Ready returns issues that are ready to work (not blocked).
 func (b *Beads) Ready() ([]*Issue, error) {
 	out, err := b.run("ready", "--json")
@@ -401,6 +465,10 @@ func (b *Beads) ReadyWithType(issueType string) ([]*Issue, error) {
 
 // Show returns detailed information about an issue.
 func (b *Beads) Show(id string) (*Issue, error) {
+	if issue, ok := b.cachedIssue(id); ok {
+		return issue, nil
+	}
+
 	out, err := b.run("show", id, "--json")
 	if err != nil {
 		return nil, err
@@ -421,17 +489,29 @@ func (b *Beads) Show(id string) (*Issue, error) {
 
 // ShowMultiple fetches multiple issues by ID in a single bd call.
 // Returns a map of ID to Issue. Missing IDs are not included in the map.
+// IDs already in the request cache are not fetched again.
 func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
-	if len(ids) == 0 {
-		return make(map[string]*Issue), nil
+	result := make(map[string]*Issue, len(ids))
+
+	var missing []string
+	for _, id := range ids {
+		if issue, ok := b.cachedIssue(id); ok {
+			result[id] = issue
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
 	}
 
 	// bd show supports multiple IDs
-	args := append([]string{"show", "--json"}, ids...)
+	gen := cacheGeneration()
+	args := append([]string{"show", "--json"}, missing...)
 	out, err := b.run(args...)
 	if err != nil {
-		// If bd fails, return empty map (some IDs might not exist)
-		return make(map[string]*Issue), nil
+		// If bd fails, return what we have (some IDs might not exist)
+		return result, nil
 	}
 
 	var issues []*Issue
@@ -439,10 +519,10 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 		return nil, fmt.Errorf("parsing bd show output: %w", err)
 	}
 
-	result := make(map[string]*Issue, len(issues))
 	for _, issue := range issues {
 		result[issue.ID] = issue
 	}
+	b.seedIssues(issues, gen)
 
 	return result, nil
 }
@@ -2,10 +2,10 @@
 package beads
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os/exec"
 	"strings"
 )
 
@@ -13,34 +13,36 @@ import (
 // This is needed when the agent bead was created via routing to a different
 // database than the Beads wrapper's default directory.
 func runSlotSet(workDir, beadID, slotName, slotValue string) error {
-	cmd := exec.Command("bd", "slot", "set", beadID, slotName, slotValue)
-	cmd.Dir = workDir
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("%s: %w", strings.TrimSpace(string(output)), err)
-	}
-	return nil
+	return runInDir(workDir, "slot", "set", beadID, slotName, slotValue)
 }
 
 // runSlotClear runs `bd slot clear` from a specific directory.
 func runSlotClear(workDir, beadID, slotName string) error {
-	cmd := exec.Command("bd", "slot", "clear", beadID, slotName)
-	cmd.Dir = workDir
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("%s: %w", strings.TrimSpace(string(output)), err)
+	return runInDir(workDir, "slot", "clear", beadID, slotName)
+}
+
+// runInDir runs a bd command in workDir with the inherited environment,
+// reporting its combined output on failure.
+func runInDir(workDir string, args ...string) error {
+	stdout, stderr, err := Exec(context.Background(), Command{Dir: workDir, Args: args})
+	if err != nil {
+		output := strings.TrimSpace(string(stdout) + string(stderr))
+		return fmt.Errorf("%s: %w", output, err)
 	}
 	return nil
 }
 
 // AgentFields holds structured fields for agent beads.
-// These are stored as "key: value" lines in the description.
+// These are stored in the description's field block, with readable
+// "key: value" lines alongside (see FormatAgentDescription).
 type AgentFields struct {
-	RoleType          string // polecat, witness, refinery, deacon, mayor
-	Rig               string // Rig name (empty for global agents like mayor/deacon)
-	AgentState        string // spawning, working, done, stuck
-	HookBead          string // Currently pinned work bead ID
-	CleanupStatus     string // ZFC: polecat self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
-	ActiveMR          string // Currently active merge request bead ID (for traceability)
-	NotificationLevel string // DND mode: verbose, normal, muted (default: normal)
+	RoleType          string `json:"role_type,omitempty"`          // polecat, witness, refinery, deacon, mayor
+	Rig               string `json:"rig,omitempty"`                // Rig name (empty for global agents like mayor/deacon)
+	AgentState        string `json:"agent_state,omitempty"`        // spawning, working, done, stuck
+	HookBead          string `json:"hook_bead,omitempty"`          // Currently pinned work bead ID
+	CleanupStatus     string `json:"cleanup_status,omitempty"`     // ZFC: polecat self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
+	ActiveMR          string `json:"active_mr,omitempty"`          // Currently active merge request bead ID (for traceability)
+	NotificationLevel string `json:"notification_level,omitempty"` // DND mode: verbose, normal, muted (default: normal)
 	// Note: RoleBead field removed - role definitions are now config-based.
 	// See internal/config/roles/*.toml and config-based-roles.md.
 }
@@ -97,12 +99,17 @@ func FormatAgentDescription(title string, fields *AgentFields) string {
 		lines = append(lines, "notification_level: null")
 	}
 
-	return strings.Join(lines, "\n")
+	return PutFields(strings.Join(lines, "\n"), FieldsAgent, fields)
 }
 
 // ParseAgentFields extracts agent fields from an issue's description.
+// The field block is read first; descriptions without one fall back to
+// "key: value" lines.
 func ParseAgentFields(description string) *AgentFields {
 	fields := &AgentFields{}
+	if readFieldBlock(description, FieldsAgent, fields) {
+		return fields
+	}
 
 	for _, line := range strings.Split(description, "\n") {
 		line = strings.TrimSpace(line)
@@ -358,40 +365,14 @@ func (b *Beads) ClearHookBead(agentBeadID string) error {
 // This is called by the polecat to self-report its git state (ZFC compliance).
 // Valid statuses: clean, has_uncommitted, has_stash, has_unpushed
 func (b *Beads) UpdateAgentCleanupStatus(id string, cleanupStatus string) error {
-	// First get current issue to preserve other fields
-	issue, err := b.Show(id)
-	if err != nil {
-		return err
-	}
-
-	// Parse existing fields
-	fields := ParseAgentFields(issue.Description)
-	fields.CleanupStatus = cleanupStatus
-
-	// Format new description
-	description := FormatAgentDescription(issue.Title, fields)
-
-	return b.Update(id, UpdateOptions{Description: &description})
+	return b.UpdateAgentFields(id, func(f *AgentFields) { f.CleanupStatus = cleanupStatus })
 }
 
 // UpdateAgentActiveMR updates the active_mr field in an agent bead.
 // This links the agent to their current merge request for traceability.
 // Pass empty string to clear the field (e.g., after merge completes).
 func (b *Beads) UpdateAgentActiveMR(id string, activeMR string) error {
-	// First get current issue to preserve other fields
-	issue, err := b.Show(id)
-	if err != nil {
-		return err
-	}
-
-	// Parse existing fields
-	fields := ParseAgentFields(issue.Description)
-	fields.ActiveMR = activeMR
-
-	// Format new description
-	description := FormatAgentDescription(issue.Title, fields)
-
-	return b.Update(id, UpdateOptions{Description: &description})
+	return b.UpdateAgentFields(id, func(f *AgentFields) { f.ActiveMR = activeMR })
 }
 
 // UpdateAgentNotificationLevel updates the notification_level field in an agent bead.
@@ -403,20 +384,111 @@ func (b *Beads) UpdateAgentNotificationLevel(id string, level string) error {
 		return fmt.Errorf("invalid notification level %q: must be verbose, normal, or muted", level)
 	}
 
-	// First get current issue to preserve other fields
-	issue, err := b.Show(id)
-	if err != nil {
-		return err
+	return b.UpdateAgentFields(id, func(f *AgentFields) { f.NotificationLevel = level })
+}
+
+// UpdateAgentFields applies mutate to the fields stored in an agent bead's
+// description, preserving the others. It costs one read and one write.
+func (b *Beads) UpdateAgentFields(id string, mutate func(*AgentFields)) error {
+	u := b.NewAgentUpdates()
+	u.SetFields(id, mutate)
+	return u.Flush()
+}
+
+// AgentUpdates coalesces writes to agent beads. Field changes queued for the
+// same bead are applied with a single read and a single description update,
+// and only the last agent_state queued for a bead is written.
+//
+//	u := bd.NewAgentUpdates()
+//	u.SetState(id, "stuck")
+//	u.SetFields(id, func(f *AgentFields) { f.CleanupStatus = "clean" })
+//	err := u.Flush()
+type AgentUpdates struct {
+	b      *Beads
+	order  []string
+	states map[string]string
+	fields map[string][]func(*AgentFields)
+}
+
+// NewAgentUpdates starts an empty batch of agent bead updates.
+func (b *Beads) NewAgentUpdates() *AgentUpdates {
+	return &AgentUpdates{
+		b:      b,
+		states: make(map[string]string),
+		fields: make(map[string][]func(*AgentFields)),
 	}
+}
 
-	// Parse existing fields
-	fields := ParseAgentFields(issue.Description)
-	fields.NotificationLevel = level
+// SetState queues an agent_state change (via `bd agent state`).
+func (u *AgentUpdates) SetState(id, state string) {
+	u.track(id)
+	u.states[id] = state
+}
+
+// SetFields queues a change to the fields in an agent bead's description.
+func (u *AgentUpdates) SetFields(id string, mutate func(*AgentFields)) {
+	u.track(id)
+	u.fields[id] = append(u.fields[id], mutate)
+}
+
+// Len returns the number of beads with queued updates.
+func (u *AgentUpdates) Len() int {
+	return len(u.order)
+}
+
+// track records the first time a bead is touched, for Flush ordering.
+func (u *AgentUpdates) track(id string) {
+	if _, ok := u.states[id]; ok {
+		return
+	}
+	if _, ok := u.fields[id]; ok {
+		return
+	}
+	u.order = append(u.order, id)
+}
 
-	// Format new description
+// Flush writes the queued updates in the order beads were first touched and
+// empties the batch. Every bead is attempted; the first error is returned.
+func (u *AgentUpdates) Flush() error {
+	var firstErr error
+	for _, id := range u.order {
+		if err := u.flushOne(id); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	u.order = nil
+	u.states = make(map[string]string)
+	u.fields = make(map[string][]func(*AgentFields))
+	return firstErr
+}
+
+// flushOne writes the state and then the fields queued for one bead. A
+// failed state update doesn't stop the field update.
+func (u *AgentUpdates) flushOne(id string) error {
+	var stateErr error
+	if state, ok := u.states[id]; ok {
+		if _, err := u.b.run("agent", "state", id, state); err != nil {
+			stateErr = fmt.Errorf("updating agent state: %w", err)
+		}
+	}
+
+	mutations := u.fields[id]
+	if len(mutations) == 0 {
+		return stateErr
+	}
+
+	// Read current issue to preserve other fields
+	issue, err := u.b.Show(id)
+	if err != nil {
+		return errors.Join(stateErr, err)
+	}
+	fields := ParseAgentFields(issue.Description)
+	for _, mutate := range mutations {
+		mutate(fields)
+	}
 	description := FormatAgentDescription(issue.Title, fields)
 
-	return b.Update(id, UpdateOptions{Description: &description})
+	return errors.Join(stateErr, u.b.Update(id, UpdateOptions{Description: &description}))
 }
 
 // GetAgentNotificationLevel returns the notification level for an agent.
@@ -3,7 +3,6 @@ package beads
 
 import (
 	"fmt"
-	"strings"
 )
 
 // FindMRForBranch searches for an existing merge-request bead for the given branch.
@@ -20,10 +19,8 @@ func (b *Beads) FindMRForBranch(branch string) (*Issue, error) {
 	}
 
 	// Search for one matching this branch
-	// MR description format: "branch: <branch>\ntarget: ..."
-	branchPrefix := "branch: " + branch + "\n"
 	for _, issue := range issues {
-		if strings.HasPrefix(issue.Description, branchPrefix) {
+		if fields := ParseMRFields(issue); fields != nil && fields.Branch == branch {
 			return issue, nil
 		}
 	}
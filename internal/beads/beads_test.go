@@ -467,7 +467,9 @@ func TestSetMRFields(t *testing.T) {
 				Target: "main",
 			},
 			want: `branch: polecat/Nux/gt-xyz
-target: main`,
+target: main
+
+<!-- gt:fields {"mr":{"branch":"polecat/Nux/gt-xyz","target":"main"}} -->`,
 		},
 		{
 			name:  "empty description",
@@ -479,7 +481,9 @@ target: main`,
 			},
 			want: `branch: polecat/Nux/gt-xyz
 target: main
-source_issue: gt-xyz`,
+source_issue: gt-xyz
+
+<!-- gt:fields {"mr":{"branch":"polecat/Nux/gt-xyz","target":"main","source_issue":"gt-xyz"}} -->`,
 		},
 		{
 			name:  "preserve prose content",
@@ -493,7 +497,9 @@ worker: Toast
 
 This is a description of the work.
 
-It spans multiple lines.`,
+It spans multiple lines.
+
+<!-- gt:fields {"mr":{"branch":"polecat/Toast/gt-abc","worker":"Toast"}} -->`,
 		},
 		{
 			name: "replace existing fields",
@@ -518,7 +524,9 @@ source_issue: gt-new
 worker: Nux
 merge_commit: abc123
 
-Some existing prose content.`,
+Some existing prose content.
+
+<!-- gt:fields {"mr":{"branch":"polecat/Nux/gt-new","target":"main","source_issue":"gt-new","worker":"Nux","merge_commit":"abc123"}} -->`,
 		},
 		{
 			name: "preserve non-MR key-value lines",
@@ -538,7 +546,9 @@ target: integration/epic
 close_reason: merged
 
 custom_field: some value
-author: someone`,
+author: someone
+
+<!-- gt:fields {"mr":{"branch":"polecat/Capable/gt-ghi","target":"integration/epic","close_reason":"merged"}} -->`,
 		},
 		{
 			name:   "empty fields clears MR data",
@@ -568,6 +578,7 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		Rig:         "gastown",
 		MergeCommit: "abc123def789",
 		CloseReason: "merged",
+		TraceID:     "4bf92f3577b34da6a3ce929d0e0e4736",
 	}
 
 	// Format to string
@@ -815,7 +826,9 @@ func TestSetAttachmentFields(t *testing.T) {
 				AttachedAt:       "2025-12-21T15:30:00Z",
 			},
 			want: `attached_molecule: mol-xyz
-attached_at: 2025-12-21T15:30:00Z`,
+attached_at: 2025-12-21T15:30:00Z
+
+<!-- gt:fields {"attachment":{"attached_molecule":"mol-xyz","attached_at":"2025-12-21T15:30:00Z"}} -->`,
 		},
 		{
 			name:  "empty description",
@@ -825,7 +838,9 @@ attached_at: 2025-12-21T15:30:00Z`,
 				AttachedAt:       "2025-12-21T10:00:00Z",
 			},
 			want: `attached_molecule: mol-abc
-attached_at: 2025-12-21T10:00:00Z`,
+attached_at: 2025-12-21T10:00:00Z
+
+<!-- gt:fields {"attachment":{"attached_molecule":"mol-abc","attached_at":"2025-12-21T10:00:00Z"}} -->`,
 		},
 		{
 			name:  "preserve prose content",
@@ -837,7 +852,9 @@ attached_at: 2025-12-21T10:00:00Z`,
 
 This is a handoff bead description.
 
-Keep working on the task.`,
+Keep working on the task.
+
+<!-- gt:fields {"attachment":{"attached_molecule":"mol-def"}} -->`,
 		},
 		{
 			name: "replace existing fields",
@@ -854,7 +871,9 @@ Some existing prose content.`,
 			want: `attached_molecule: mol-new
 attached_at: 2025-12-21T15:30:00Z
 
-Some existing prose content.`,
+Some existing prose content.
+
+<!-- gt:fields {"attachment":{"attached_molecule":"mol-new","attached_at":"2025-12-21T15:30:00Z"}} -->`,
 		},
 		{
 			name:   "nil fields clears attachment",
@@ -885,6 +904,7 @@ func TestAttachmentFieldsRoundTrip(t *testing.T) {
 	original := &AttachmentFields{
 		AttachedMolecule: "mol-roundtrip",
 		AttachedAt:       "2025-12-21T15:30:00Z",
+		TraceID:          "4bf92f3577b34da6a3ce929d0e0e4736",
 	}
 
 	// Format to string
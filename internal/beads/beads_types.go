@@ -2,9 +2,9 @@
 package beads
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -105,13 +105,15 @@ func EnsureCustomTypes(beadsDir string) error {
 
 	// Configure custom types via bd CLI
 	typesList := strings.Join(constants.BeadsCustomTypesList(), ",")
-	cmd := exec.Command("bd", "config", "set", "types.custom", typesList)
-	cmd.Dir = beadsDir
-	// Set BEADS_DIR explicitly to ensure bd operates on the correct database
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	stdout, stderr, err := Exec(context.Background(), Command{
+		Dir:  beadsDir,
+		Args: []string{"config", "set", "types.custom", typesList},
+		// Set BEADS_DIR explicitly to ensure bd operates on the correct database
+		Env: append(os.Environ(), "BEADS_DIR="+beadsDir),
+	})
+	if err != nil {
 		return fmt.Errorf("configure custom types in %s: %s: %w",
-			beadsDir, strings.TrimSpace(string(output)), err)
+			beadsDir, strings.TrimSpace(string(stdout)+string(stderr)), err)
 	}
 
 	// Write sentinel file (best effort - don't fail if this fails)
@@ -0,0 +1,950 @@
+// Package beadstest provides an in-memory bd for hermetic tests.
+//
+// Fake implements beads.Executor, so installing it routes every bd
+// invocation made through the beads package (beads.Beads, the mail router,
+// the web fetcher) to an in-memory issue store instead of the bd binary:
+//
+//	fake := beadstest.Install(t)
+//	fake.Add(beads.Issue{ID: "gt-1", Title: "Fix it", Status: "open"})
+//	issue, err := beads.New(dir).Show("gt-1")
+//
+// It covers the subset of bd that Gas Town uses: init, create, show, list,
+// update, close, reopen, delete, dep add/remove/list, label add/remove,
+// ready, blocked, slot set/clear, agent state and config set/get, with the
+// same JSON output shapes. All databases share one store; Calls records the
+// BEADS_DIR of each invocation for tests that check routing. Unsupported
+// commands and flags fail loudly rather than being ignored.
+package beadstest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// DefaultPrefix is the ID prefix used when none is configured.
+const DefaultPrefix = "bd"
+
+const (
+	statusOpen   = "open"
+	statusClosed = "closed"
+)
+
+// defaultListLimit matches bd's default page size for list and ready.
+const defaultListLimit = 50
+
+// Fake is an in-memory bd. The zero value is not usable; use New.
+type Fake struct {
+	// Prefix is used for generated IDs (<prefix>-<n>). `bd init --prefix`
+	// sets it when empty.
+	Prefix string
+
+	// Now stamps created_at, updated_at and closed_at.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	issues map[string]*record
+	order  []string // creation order
+	seq    int
+	config map[string]string
+	calls  []Call
+}
+
+// Call is one bd invocation seen by a Fake.
+type Call struct {
+	Dir      string
+	BeadsDir string
+
+	// Args are the command arguments with global flags removed, so Args[0]
+	// is the subcommand.
+	Args []string
+}
+
+// ExitError is the error returned for a failed command, standing in for
+// *exec.ExitError from the real binary. The message is written to stderr.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// record is a stored issue plus the state bd keeps outside beads.Issue.
+type record struct {
+	issue  beads.Issue
+	wisp   bool
+	pinned bool
+	notes  string
+	deps   []dep // issues this one depends on
+}
+
+// dep is a dependency edge from a record.
+type dep struct {
+	id  string
+	typ string
+}
+
+// New returns an empty Fake.
+func New() *Fake {
+	return &Fake{
+		Now:    time.Now,
+		issues: make(map[string]*record),
+		config: make(map[string]string),
+	}
+}
+
+// Install creates a Fake and routes all bd invocations to it for the
+// duration of the test.
+func Install(t testing.TB) *Fake {
+	t.Helper()
+	f := New()
+	restore := beads.SetExecutor(f)
+	t.Cleanup(restore)
+	t.Cleanup(beads.ResetEnsuredDirs)
+	return f
+}
+
+// Add stores issues as they are given, filling in a generated ID, status
+// "open", type "task" and timestamps when empty. It panics on a duplicate
+// ID, since that is a bug in the test.
+func (f *Fake) Add(issues ...beads.Issue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, issue := range issues {
+		if issue.ID == "" {
+			issue.ID = f.nextID("")
+		}
+		if _, exists := f.issues[issue.ID]; exists {
+			panic("beadstest: duplicate issue " + issue.ID)
+		}
+		now := f.now()
+		if issue.Status == "" {
+			issue.Status = statusOpen
+		}
+		if issue.Type == "" {
+			issue.Type = "task"
+		}
+		if issue.CreatedAt == "" {
+			issue.CreatedAt = now
+		}
+		if issue.UpdatedAt == "" {
+			issue.UpdatedAt = issue.CreatedAt
+		}
+		f.store(&record{issue: issue})
+	}
+}
+
+// AddDependency records that issue depends on dependsOn. An empty depType
+// means "blocks".
+func (f *Fake) AddDependency(issue, dependsOn, depType string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if depType == "" {
+		depType = "blocks"
+	}
+	if r := f.issues[issue]; r != nil {
+		r.deps = append(r.deps, dep{id: dependsOn, typ: depType})
+	}
+}
+
+// Issue returns a stored issue as `bd show` would report it.
+func (f *Fake) Issue(id string) (*beads.Issue, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := f.issues[id]
+	if r == nil {
+		return nil, false
+	}
+	issue := f.view(r, true).Issue
+	return &issue, true
+}
+
+// Issues returns all stored issues in creation order.
+func (f *Fake) Issues() []*beads.Issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issues := make([]*beads.Issue, 0, len(f.order))
+	for _, id := range f.order {
+		issue := f.view(f.issues[id], true).Issue
+		issues = append(issues, &issue)
+	}
+	return issues
+}
+
+// Calls returns the invocations seen so far.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Count returns how many invocations used the given subcommand.
+func (f *Fake) Count(subcommand string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.calls {
+		if len(c.Args) > 0 && c.Args[0] == subcommand {
+			n++
+		}
+	}
+	return n
+}
+
+// Exec implements beads.Executor.
+func (f *Fake) Exec(_ context.Context, cmd beads.Command) (stdout, stderr []byte, err error) {
+	args := beads.Subcommand(cmd.Args)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{
+		Dir:      cmd.Dir,
+		BeadsDir: envValue(cmd.Env, "BEADS_DIR"),
+		Args:     append([]string(nil), args...),
+	})
+
+	if len(args) == 0 {
+		return nil, []byte("Error: no command given\n"), &ExitError{Code: 1}
+	}
+
+	handler, ok := commands[args[0]]
+	if !ok {
+		return nil, []byte(fmt.Sprintf("Error: beadstest: unsupported command %q\n", args[0])), &ExitError{Code: 1}
+	}
+
+	out, cmdErr := handler(f, args[1:])
+	if cmdErr != nil {
+		return nil, []byte("Error: " + cmdErr.Error() + "\n"), &ExitError{Code: 1}
+	}
+	return out, nil, nil
+}
+
+// commands maps bd subcommands to their handlers. Handlers run with f.mu held.
+var commands = map[string]func(f *Fake, args []string) ([]byte, error){
+	"init":    (*Fake).cmdInit,
+	"create":  (*Fake).cmdCreate,
+	"show":    (*Fake).cmdShow,
+	"list":    (*Fake).cmdList,
+	"update":  (*Fake).cmdUpdate,
+	"close":   (*Fake).cmdClose,
+	"reopen":  (*Fake).cmdReopen,
+	"delete":  (*Fake).cmdDelete,
+	"dep":     (*Fake).cmdDep,
+	"label":   (*Fake).cmdLabel,
+	"ready":   (*Fake).cmdReady,
+	"blocked": (*Fake).cmdBlocked,
+	"slot":    (*Fake).cmdSlot,
+	"agent":   (*Fake).cmdAgent,
+	"config":  (*Fake).cmdConfig,
+}
+
+func (f *Fake) cmdInit(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{"prefix": false, "quiet": true})
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := fs.value("prefix"); ok && f.Prefix == "" {
+		f.Prefix = p
+	}
+	return nil, nil
+}
+
+func (f *Fake) cmdCreate(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{
+		"title": false, "type": false, "priority": false, "description": false,
+		"parent": false, "actor": false, "id": false, "labels": false, "label": false,
+		"assignee": false, "notes": false,
+		"ephemeral": true, "pinned": true, "force": true, "json": true, "silent": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	title, _ := fs.value("title")
+	if title == "" && len(fs.args) > 0 {
+		title = fs.args[0]
+	}
+	if title == "" {
+		return nil, fmt.Errorf("title required")
+	}
+
+	parent, _ := fs.value("parent")
+	if parent != "" && f.issues[parent] == nil {
+		return nil, fmt.Errorf("parent issue not found: %s", parent)
+	}
+
+	id, _ := fs.value("id")
+	if id == "" {
+		id = f.nextID(parent)
+	} else if f.issues[id] != nil {
+		return nil, fmt.Errorf("issue %s already exists (UNIQUE constraint failed)", id)
+	}
+
+	now := f.now()
+	issue := beads.Issue{
+		ID:        id,
+		Title:     title,
+		Status:    statusOpen,
+		Priority:  2,
+		Type:      "task",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Parent:    parent,
+		Labels:    fs.list("labels", "label"),
+	}
+	if t, ok := fs.value("type"); ok {
+		issue.Type = t
+	}
+	if p, ok := fs.value("priority"); ok {
+		if issue.Priority, err = parsePriority(p); err != nil {
+			return nil, err
+		}
+	}
+	issue.Description, _ = fs.value("description")
+	issue.Assignee, _ = fs.value("assignee")
+	issue.CreatedBy, _ = fs.value("actor")
+
+	r := &record{issue: issue, wisp: fs.bool("ephemeral"), pinned: fs.bool("pinned")}
+	r.notes, _ = fs.value("notes")
+	if parent != "" {
+		r.deps = append(r.deps, dep{id: parent, typ: "parent-child"})
+	}
+	f.store(r)
+
+	if !fs.bool("json") {
+		return []byte(fmt.Sprintf("✓ Created issue: %s\n", id)), nil
+	}
+	return marshal(f.view(r, true))
+}
+
+func (f *Fake) cmdShow(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{"json": true})
+	if err != nil {
+		return nil, err
+	}
+	if len(fs.args) == 0 {
+		return nil, fmt.Errorf("issue ID required")
+	}
+
+	var views []issueView
+	for _, id := range fs.args {
+		if r := f.issues[id]; r != nil {
+			views = append(views, f.view(r, true))
+		}
+	}
+	if len(views) == 0 {
+		return nil, fmt.Errorf("issue not found: %s", strings.Join(fs.args, ", "))
+	}
+
+	if !fs.bool("json") {
+		return textLines(views), nil
+	}
+	return marshal(views)
+}
+
+func (f *Fake) cmdList(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{
+		"status": false, "label": false, "labels": false, "type": false, "priority": false,
+		"parent": false, "assignee": false, "limit": false, "sort": false, "desc-contains": false,
+		"created-after": false, "all": true, "no-assignee": true, "asc": true, "json": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := f.query(fs, func(r *record) bool {
+		status, ok := fs.value("status")
+		switch {
+		case fs.bool("all") || status == "all":
+			return true
+		case ok:
+			return containsString(strings.Split(status, ","), r.issue.Status)
+		default:
+			return r.issue.Status != statusClosed
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !fs.bool("json") {
+		return textLines(views), nil
+	}
+	return marshal(views)
+}
+
+func (f *Fake) cmdReady(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{
+		"label": false, "labels": false, "type": false, "assignee": false, "limit": false,
+		"json": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	views, err := f.query(fs, func(r *record) bool {
+		return r.issue.Status == statusOpen && len(f.openBlockers(r)) == 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshal(views)
+}
+
+func (f *Fake) cmdBlocked(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{"json": true})
+	if err != nil {
+		return nil, err
+	}
+	views, err := f.query(fs, func(r *record) bool {
+		return r.issue.Status != statusClosed && len(f.openBlockers(r)) > 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshal(views)
+}
+
+// query returns issues passing keep and the shared list filters in fs.
+func (f *Fake) query(fs *flagSet, keep func(*record) bool) ([]issueView, error) {
+	labels := fs.list("label", "labels")
+	issueType, _ := fs.value("type")
+	parent, _ := fs.value("parent")
+	assignee, hasAssignee := fs.value("assignee")
+	descContains, _ := fs.value("desc-contains")
+
+	priority := -1
+	if p, ok := fs.value("priority"); ok {
+		var err error
+		if priority, err = parsePriority(p); err != nil {
+			return nil, err
+		}
+	}
+
+	var createdAfter time.Time
+	if v, ok := fs.value("created-after"); ok {
+		var err error
+		if createdAfter, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("beadstest: unsupported created-after %q", v)
+		}
+	}
+
+	limit := defaultListLimit
+	if l, ok := fs.value("limit"); ok {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit %q", l)
+		}
+		limit = n
+	}
+
+	ids := append([]string(nil), f.order...)
+	if sortBy, ok := fs.value("sort"); ok {
+		if sortBy != "created" {
+			return nil, fmt.Errorf("beadstest: unsupported sort %q", sortBy)
+		}
+		if !fs.bool("asc") {
+			for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+				ids[i], ids[j] = ids[j], ids[i]
+			}
+		}
+	}
+
+	var views []issueView
+	for _, id := range ids {
+		r := f.issues[id]
+		switch {
+		case !keep(r):
+		case !hasAllLabels(r.issue.Labels, labels):
+		case issueType != "" && r.issue.Type != issueType && !containsString(r.issue.Labels, "gt:"+issueType):
+		case priority >= 0 && r.issue.Priority != priority:
+		case parent != "" && r.issue.Parent != parent:
+		case hasAssignee && r.issue.Assignee != assignee:
+		case fs.bool("no-assignee") && r.issue.Assignee != "":
+		case !strings.Contains(strings.ToLower(r.issue.Description), strings.ToLower(descContains)):
+		case !createdAfter.IsZero() && createdBefore(r.issue.CreatedAt, createdAfter):
+		default:
+			views = append(views, f.view(r, false))
+		}
+		if limit > 0 && len(views) == limit {
+			break
+		}
+	}
+	if views == nil {
+		views = []issueView{}
+	}
+	return views, nil
+}
+
+func (f *Fake) cmdUpdate(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{
+		"title": false, "status": false, "priority": false, "description": false,
+		"assignee": false, "set-labels": false, "add-label": false, "remove-label": false,
+		"notes": false, "parent": false, "json": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(fs.args) == 0 {
+		return nil, fmt.Errorf("issue ID required")
+	}
+
+	for _, id := range fs.args {
+		r, err := f.get(id)
+		if err != nil {
+			return nil, err
+		}
+		issue := &r.issue
+		if v, ok := fs.value("title"); ok {
+			issue.Title = v
+		}
+		if v, ok := fs.value("status"); ok {
+			f.setStatus(r, v)
+		}
+		if v, ok := fs.value("priority"); ok {
+			if issue.Priority, err = parsePriority(v); err != nil {
+				return nil, err
+			}
+		}
+		if v, ok := fs.value("description"); ok {
+			issue.Description = v
+		}
+		if v, ok := fs.value("assignee"); ok {
+			issue.Assignee = v
+		}
+		if v, ok := fs.value("notes"); ok {
+			r.notes = v
+		}
+		if v, ok := fs.value("parent"); ok {
+			issue.Parent = v
+		}
+		if set := fs.list("set-labels"); len(set) > 0 {
+			issue.Labels = set
+		}
+		for _, l := range fs.list("add-label") {
+			issue.Labels = addString(issue.Labels, l)
+		}
+		for _, l := range fs.list("remove-label") {
+			issue.Labels = removeString(issue.Labels, l)
+		}
+		issue.UpdatedAt = f.now()
+	}
+	return f.ok("Updated", fs.args), nil
+}
+
+func (f *Fake) cmdClose(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{"reason": false, "session": false, "force": true, "json": true})
+	if err != nil {
+		return nil, err
+	}
+	if len(fs.args) == 0 {
+		return nil, fmt.Errorf("issue ID required")
+	}
+	for _, id := range fs.args {
+		r, err := f.get(id)
+		if err != nil {
+			return nil, err
+		}
+		f.setStatus(r, statusClosed)
+		if reason, ok := fs.value("reason"); ok {
+			r.notes = reason
+		}
+	}
+	return f.ok("Closed", fs.args), nil
+}
+
+func (f *Fake) cmdReopen(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{"reason": false, "json": true})
+	if err != nil {
+		return nil, err
+	}
+	if len(fs.args) == 0 {
+		return nil, fmt.Errorf("issue ID required")
+	}
+	for _, id := range fs.args {
+		r, err := f.get(id)
+		if err != nil {
+			return nil, err
+		}
+		f.setStatus(r, statusOpen)
+	}
+	return f.ok("Reopened", fs.args), nil
+}
+
+func (f *Fake) cmdDelete(args []string) ([]byte, error) {
+	fs, err := parseFlags(args, flagSpec{"hard": true, "force": true, "json": true})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range fs.args {
+		if _, err := f.get(id); err != nil {
+			return nil, err
+		}
+		delete(f.issues, id)
+		f.order = removeString(f.order, id)
+	}
+	return f.ok("Deleted", fs.args), nil
+}
+
+func (f *Fake) cmdDep(args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("dep: subcommand required")
+	}
+	fs, err := parseFlags(args[1:], flagSpec{"type": false, "direction": false, "json": true})
+	if err != nil {
+		return nil, err
+	}
+	depType, _ := fs.value("type")
+
+	switch args[0] {
+	case "add", "remove":
+		if len(fs.args) != 2 {
+			return nil, fmt.Errorf("dep %s: expected <issue> <depends-on>", args[0])
+		}
+		r, err := f.get(fs.args[0])
+		if err != nil {
+			return nil, err
+		}
+		if args[0] == "add" {
+			if depType == "" {
+				depType = "blocks"
+			}
+			r.deps = append(r.deps, dep{id: fs.args[1], typ: depType})
+		} else {
+			kept := r.deps[:0]
+			for _, d := range r.deps {
+				if d.id != fs.args[1] {
+					kept = append(kept, d)
+				}
+			}
+			r.deps = kept
+		}
+		r.issue.UpdatedAt = f.now()
+		return f.ok("Dependency updated for", fs.args[:1]), nil
+
+	case "list":
+		if len(fs.args) != 1 {
+			return nil, fmt.Errorf("dep list: expected <issue>")
+		}
+		r, err := f.get(fs.args[0])
+		if err != nil {
+			return nil, err
+		}
+		var related []issueDepView
+		direction, _ := fs.value("direction")
+		switch direction {
+		case "", "down":
+			for _, d := range r.deps {
+				if other := f.issues[d.id]; other != nil && (depType == "" || d.typ == depType) {
+					related = append(related, issueDepView{f.view(other, false), d.typ})
+				}
+			}
+		case "up":
+			for _, id := range f.order {
+				other := f.issues[id]
+				for _, d := range other.deps {
+					if d.id == r.issue.ID && (depType == "" || d.typ == depType) {
+						related = append(related, issueDepView{f.view(other, false), d.typ})
+					}
+				}
+			}
+		default:
+			return nil, fmt.Errorf("invalid direction %q", direction)
+		}
+		if related == nil {
+			related = []issueDepView{}
+		}
+		return marshal(related)
+	}
+	return nil, fmt.Errorf("beadstest: unsupported command \"dep %s\"", args[0])
+}
+
+func (f *Fake) cmdLabel(args []string) ([]byte, error) {
+	if len(args) != 3 || (args[0] != "add" && args[0] != "remove") {
+		return nil, fmt.Errorf("beadstest: expected \"label add|remove <issue> <label>\"")
+	}
+	r, err := f.get(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if args[0] == "add" {
+		r.issue.Labels = addString(r.issue.Labels, args[2])
+	} else {
+		r.issue.Labels = removeString(r.issue.Labels, args[2])
+	}
+	r.issue.UpdatedAt = f.now()
+	return f.ok("Label "+args[0], args[1:2]), nil
+}
+
+func (f *Fake) cmdSlot(args []string) ([]byte, error) {
+	if len(args) < 3 || args[2] != "hook" {
+		return nil, fmt.Errorf("beadstest: expected \"slot set|clear <agent> hook [<bead>]\"")
+	}
+	r, err := f.get(args[1])
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case args[0] == "set" && len(args) == 4:
+		if r.issue.HookBead != "" {
+			return nil, fmt.Errorf("slot hook already occupied by %s", r.issue.HookBead)
+		}
+		r.issue.HookBead = args[3]
+	case args[0] == "clear" && len(args) == 3:
+		r.issue.HookBead = ""
+	default:
+		return nil, fmt.Errorf("beadstest: unsupported slot command %q", strings.Join(args, " "))
+	}
+	r.issue.UpdatedAt = f.now()
+	return f.ok("Slot updated", args[1:2]), nil
+}
+
+func (f *Fake) cmdAgent(args []string) ([]byte, error) {
+	if len(args) != 3 || args[0] != "state" {
+		return nil, fmt.Errorf("beadstest: expected \"agent state <agent> <state>\"")
+	}
+	r, err := f.get(args[1])
+	if err != nil {
+		return nil, err
+	}
+	r.issue.AgentState = args[2]
+	r.issue.UpdatedAt = f.now()
+	return f.ok("Agent state updated", args[1:2]), nil
+}
+
+func (f *Fake) cmdConfig(args []string) ([]byte, error) {
+	switch {
+	case len(args) == 3 && args[0] == "set":
+		f.config[args[1]] = args[2]
+		return nil, nil
+	case len(args) == 2 && args[0] == "get":
+		return []byte(f.config[args[1]] + "\n"), nil
+	}
+	return nil, fmt.Errorf("beadstest: expected \"config set <key> <value>\" or \"config get <key>\"")
+}
+
+// issueView is the JSON shape of an issue in bd output.
+type issueView struct {
+	beads.Issue
+	Wisp   bool   `json:"wisp,omitempty"`
+	Pinned bool   `json:"pinned,omitempty"`
+	Notes  string `json:"notes,omitempty"`
+}
+
+// issueDepView is the JSON shape of an entry in `bd dep list`.
+type issueDepView struct {
+	issueView
+	DependencyType string `json:"dependency_type"`
+}
+
+// view renders r with its derived fields. detail adds the dependency lists
+// `bd show` includes; list output only carries counts.
+func (f *Fake) view(r *record, detail bool) issueView {
+	issue := r.issue
+	issue.Labels = append([]string(nil), issue.Labels...)
+	issue.DependsOn, issue.Blocks, issue.BlockedBy, issue.Children = nil, nil, nil, nil
+	issue.Dependencies, issue.Dependents = nil, nil
+
+	for _, d := range r.deps {
+		issue.DependsOn = append(issue.DependsOn, d.id)
+	}
+	issue.BlockedBy = f.openBlockers(r)
+	issue.BlockedByCount = len(issue.BlockedBy)
+	issue.DependencyCount = len(r.deps)
+	issue.DependentCount = 0
+
+	for _, id := range f.order {
+		other := f.issues[id]
+		if other.issue.Parent == issue.ID {
+			issue.Children = append(issue.Children, id)
+		}
+		for _, d := range other.deps {
+			if d.id != issue.ID {
+				continue
+			}
+			issue.DependentCount++
+			if d.typ == "blocks" {
+				issue.Blocks = append(issue.Blocks, id)
+			}
+			if detail {
+				issue.Dependents = append(issue.Dependents, depOf(other, d.typ))
+			}
+		}
+	}
+	if detail {
+		for _, d := range r.deps {
+			if other := f.issues[d.id]; other != nil {
+				issue.Dependencies = append(issue.Dependencies, depOf(other, d.typ))
+			}
+		}
+	}
+
+	return issueView{Issue: issue, Wisp: r.wisp, Pinned: r.pinned, Notes: r.notes}
+}
+
+func depOf(r *record, typ string) beads.IssueDep {
+	return beads.IssueDep{
+		ID:             r.issue.ID,
+		Title:          r.issue.Title,
+		Status:         r.issue.Status,
+		Priority:       r.issue.Priority,
+		Type:           r.issue.Type,
+		DependencyType: typ,
+	}
+}
+
+// openBlockers returns the unclosed issues r depends on with a "blocks"
+// dependency.
+func (f *Fake) openBlockers(r *record) []string {
+	var ids []string
+	for _, d := range r.deps {
+		if d.typ != "blocks" {
+			continue
+		}
+		if other := f.issues[d.id]; other != nil && other.issue.Status != statusClosed {
+			ids = append(ids, d.id)
+		}
+	}
+	return ids
+}
+
+func (f *Fake) store(r *record) {
+	f.issues[r.issue.ID] = r
+	f.order = append(f.order, r.issue.ID)
+}
+
+func (f *Fake) get(id string) (*record, error) {
+	r := f.issues[id]
+	if r == nil {
+		return nil, fmt.Errorf("issue not found: %s", id)
+	}
+	return r, nil
+}
+
+func (f *Fake) setStatus(r *record, status string) {
+	r.issue.Status = status
+	if status == statusClosed {
+		r.issue.ClosedAt = f.now()
+	} else {
+		r.issue.ClosedAt = ""
+	}
+	r.issue.UpdatedAt = f.now()
+}
+
+// nextID generates an ID: <prefix>-<n>, or <parent>.<n> for children.
+func (f *Fake) nextID(parent string) string {
+	if parent != "" {
+		n := 1
+		for _, id := range f.order {
+			if f.issues[id].issue.Parent == parent {
+				n++
+			}
+		}
+		return fmt.Sprintf("%s.%d", parent, n)
+	}
+	prefix := f.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	for {
+		f.seq++
+		id := fmt.Sprintf("%s-%d", prefix, f.seq)
+		if f.issues[id] == nil {
+			return id
+		}
+	}
+}
+
+func (f *Fake) now() string {
+	return f.Now().UTC().Format(time.RFC3339)
+}
+
+// ok formats the human-readable confirmation of a write.
+func (f *Fake) ok(verb string, ids []string) []byte {
+	return []byte(fmt.Sprintf("✓ %s %s\n", verb, strings.Join(ids, ", ")))
+}
+
+func marshal(v any) ([]byte, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func textLines(views []issueView) []byte {
+	var sb strings.Builder
+	for _, v := range views {
+		fmt.Fprintf(&sb, "%s [P%d] [%s] %s - %s\n", v.ID, v.Priority, v.Type, v.Status, v.Title)
+	}
+	return []byte(sb.String())
+}
+
+// createdBefore reports whether an issue's created_at is before t.
+func createdBefore(createdAt string, t time.Time) bool {
+	c, err := time.Parse(time.RFC3339, createdAt)
+	return err == nil && c.Before(t)
+}
+
+func parsePriority(s string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(s), "P"))
+	if err != nil || p < 0 || p > 4 {
+		return 0, fmt.Errorf("invalid priority %q", s)
+	}
+	return p, nil
+}
+
+func envValue(env []string, key string) string {
+	for i := len(env) - 1; i >= 0; i-- {
+		if v, ok := strings.CutPrefix(env[i], key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func hasAllLabels(have, want []string) bool {
+	for _, w := range want {
+		if !containsString(have, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func addString(list []string, s string) []string {
+	if containsString(list, s) {
+		return list
+	}
+	list = append(list, s)
+	sort.Strings(list)
+	return list
+}
+
+func removeString(list []string, s string) []string {
+	kept := list[:0]
+	for _, v := range list {
+		if v != s {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
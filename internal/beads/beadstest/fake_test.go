@@ -0,0 +1,219 @@
+package beadstest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+)
+
+func TestFakeCreateShowUpdateClose(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Prefix = "gt"
+	b := beads.New(t.TempDir())
+
+	created, err := b.Create(beads.CreateOptions{
+		Title:       "Fix the widget",
+		Type:        "task",
+		Priority:    1,
+		Description: "It is broken",
+		Actor:       "gastown/crew/max",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID != "gt-1" || created.CreatedBy != "gastown/crew/max" {
+		t.Errorf("created = %+v", created)
+	}
+
+	status, assignee := "in_progress", "gastown/polecats/toast"
+	if err := b.Update(created.ID, beads.UpdateOptions{
+		Status:    &status,
+		Assignee:  &assignee,
+		AddLabels: []string{"urgent"},
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	issue, err := b.Show(created.ID)
+	if err != nil {
+		t.Fatalf("Show: %v", err)
+	}
+	if issue.Status != "in_progress" || issue.Assignee != assignee || issue.Priority != 1 {
+		t.Errorf("after update = %+v", issue)
+	}
+	if strings.Join(issue.Labels, ",") != "gt:task,urgent" {
+		t.Errorf("labels = %v", issue.Labels)
+	}
+
+	if err := b.CloseWithReason("done", created.ID); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if issue, _ := fake.Issue(created.ID); issue.Status != "closed" || issue.ClosedAt == "" {
+		t.Errorf("after close = %+v", issue)
+	}
+
+	if _, err := b.Show("gt-404"); !errors.Is(err, beads.ErrNotFound) {
+		t.Errorf("Show(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFakeListFilters(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-a", Title: "a", Assignee: "rig/polecats/x", Labels: []string{"gt:agent"}},
+		beads.Issue{ID: "gt-b", Title: "b", Status: "in_progress", Assignee: "rig/polecats/y"},
+		beads.Issue{ID: "gt-c", Title: "c", Status: "closed", Assignee: "rig/polecats/x"},
+		beads.Issue{ID: "gt-d", Title: "d", Priority: 3},
+	)
+	b := beads.New(t.TempDir())
+
+	ids := func(issues []*beads.Issue) string {
+		var out []string
+		for _, i := range issues {
+			out = append(out, i.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name string
+		opts beads.ListOptions
+		want string
+	}{
+		{"default hides closed", beads.ListOptions{Priority: -1}, "gt-a,gt-b,gt-d"},
+		{"all", beads.ListOptions{Status: "all", Priority: -1}, "gt-a,gt-b,gt-c,gt-d"},
+		{"status", beads.ListOptions{Status: "in_progress", Priority: -1}, "gt-b"},
+		{"assignee", beads.ListOptions{Status: "all", Assignee: "rig/polecats/x", Priority: -1}, "gt-a,gt-c"},
+		{"type label", beads.ListOptions{Type: "agent", Priority: -1}, "gt-a"},
+		{"priority", beads.ListOptions{Priority: 3}, "gt-d"},
+		{"no assignee", beads.ListOptions{NoAssignee: true, Priority: -1}, "gt-d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.List(tt.opts)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if ids(got) != tt.want {
+				t.Errorf("List = %s, want %s", ids(got), tt.want)
+			}
+		})
+	}
+
+	assigned, err := b.AssignedIssues([]string{"rig/polecats/x", "rig/polecats/y", "rig/polecats/z"})
+	if err != nil {
+		t.Fatalf("AssignedIssues: %v", err)
+	}
+	if len(assigned) != 2 || assigned["rig/polecats/x"].ID != "gt-a" || assigned["rig/polecats/y"].ID != "gt-b" {
+		t.Errorf("AssignedIssues = %v", assigned)
+	}
+	if n := fake.Count("list"); n != len(tests)+2 {
+		t.Errorf("list invoked %d times, want %d", n, len(tests)+2)
+	}
+}
+
+func TestFakeDependencies(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-1", Title: "foundation"},
+		beads.Issue{ID: "gt-2", Title: "walls"},
+		beads.Issue{ID: "hq-cv-1", Title: "convoy", Type: "convoy"},
+	)
+	b := beads.New(t.TempDir())
+
+	if err := b.AddDependency("gt-2", "gt-1"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	// Tracking doesn't block
+	if _, err := b.Run("dep", "add", "hq-cv-1", "gt-2", "--type=tracks"); err != nil {
+		t.Fatalf("dep add --type=tracks: %v", err)
+	}
+
+	ready, err := b.Ready()
+	if err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+	if len(ready) != 2 || ready[0].ID != "gt-1" || ready[1].ID != "hq-cv-1" {
+		t.Errorf("Ready = %v", ready)
+	}
+	blocked, err := b.Blocked()
+	if err != nil {
+		t.Fatalf("Blocked: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != "gt-2" || blocked[0].BlockedByCount != 1 {
+		t.Errorf("Blocked = %v", blocked)
+	}
+
+	walls, _ := b.Show("gt-2")
+	if len(walls.Dependencies) != 1 || len(walls.Dependents) != 1 || walls.Dependents[0].DependencyType != "tracks" {
+		t.Errorf("show dependencies = %+v / %+v", walls.Dependencies, walls.Dependents)
+	}
+
+	if err := b.Close("gt-1"); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if blocked, _ := b.Blocked(); len(blocked) != 0 {
+		t.Errorf("Blocked after closing blocker = %v", blocked)
+	}
+}
+
+func TestFakeAgentSlots(t *testing.T) {
+	beadstest.Install(t)
+	b := beads.New(t.TempDir())
+
+	if _, err := b.CreateWithID("gt-rig-polecat-toast", beads.CreateOptions{
+		Title:       "toast",
+		Type:        "agent",
+		Priority:    -1,
+		Description: beads.FormatAgentDescription("toast", &beads.AgentFields{RoleType: "polecat", Rig: "rig"}),
+	}); err != nil {
+		t.Fatalf("CreateWithID: %v", err)
+	}
+
+	hook := "gt-1"
+	if err := b.UpdateAgentState("gt-rig-polecat-toast", "working", &hook); err != nil {
+		t.Fatalf("UpdateAgentState: %v", err)
+	}
+	// Re-slinging clears the occupied slot and retries
+	if err := b.SetHookBead("gt-rig-polecat-toast", "gt-2"); err != nil {
+		t.Fatalf("SetHookBead: %v", err)
+	}
+
+	u := b.NewAgentUpdates()
+	u.SetFields("gt-rig-polecat-toast", func(f *beads.AgentFields) { f.CleanupStatus = "clean" })
+	u.SetFields("gt-rig-polecat-toast", func(f *beads.AgentFields) { f.ActiveMR = "gt-mr-1" })
+	if err := u.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	issue, fields, err := b.GetAgentBead("gt-rig-polecat-toast")
+	if err != nil {
+		t.Fatalf("GetAgentBead: %v", err)
+	}
+	if issue.HookBead != "gt-2" || issue.AgentState != "working" {
+		t.Errorf("slots = hook %q state %q", issue.HookBead, issue.AgentState)
+	}
+	if fields.CleanupStatus != "clean" || fields.ActiveMR != "gt-mr-1" || fields.RoleType != "polecat" {
+		t.Errorf("fields = %+v", fields)
+	}
+}
+
+func TestFakeRejectsUnsupported(t *testing.T) {
+	fake := beadstest.Install(t)
+	b := beads.New(t.TempDir())
+
+	if _, err := b.Run("list", "--json", "--frobnicate"); err == nil || !strings.Contains(err.Error(), "unsupported flag") {
+		t.Errorf("unknown flag error = %v", err)
+	}
+	if _, err := b.Run("mol", "squash"); err == nil || !strings.Contains(err.Error(), "unsupported command") {
+		t.Errorf("unknown command error = %v", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0].Args[0] != "list" || calls[0].BeadsDir == "" {
+		t.Errorf("calls = %+v", calls)
+	}
+}
@@ -0,0 +1,111 @@
+package beadstest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// flagSpec lists the flags a command accepts, by long name, mapped to
+// whether the flag is boolean.
+type flagSpec map[string]bool
+
+// shortFlags maps bd's single-letter flags to their long names.
+var shortFlags = map[string]string{
+	"-d": "description",
+	"-l": "labels",
+	"-n": "limit",
+	"-t": "type",
+	"-p": "priority",
+	"-a": "assignee",
+}
+
+// flagSet is a parsed command line.
+type flagSet struct {
+	args   []string
+	values map[string][]string
+	bools  map[string]bool
+}
+
+// parseFlags parses args against spec, accepting --name=value,
+// --name value, short aliases and positional arguments in any order.
+// Flags outside spec are an error, so tests notice when they depend on bd
+// behavior the fake doesn't model.
+func parseFlags(args []string, spec flagSpec) (*flagSet, error) {
+	fs := &flagSet{values: make(map[string][]string), bools: make(map[string]bool)}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			fs.args = append(fs.args, args[i+1:]...)
+			break
+		}
+
+		var name, value string
+		hasValue := false
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue = strings.Cut(arg[2:], "=")
+		case shortFlags[arg] != "":
+			name = shortFlags[arg]
+		case len(arg) > 1 && arg[0] == '-':
+			return nil, fmt.Errorf("beadstest: unsupported flag %s", arg)
+		default:
+			fs.args = append(fs.args, arg)
+			continue
+		}
+
+		isBool, ok := spec[name]
+		if !ok {
+			return nil, fmt.Errorf("beadstest: unsupported flag --%s", name)
+		}
+		if isBool {
+			b := true
+			if hasValue {
+				var err error
+				if b, err = strconv.ParseBool(value); err != nil {
+					return nil, fmt.Errorf("invalid value for --%s: %q", name, value)
+				}
+			}
+			fs.bools[name] = b
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag --%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		fs.values[name] = append(fs.values[name], value)
+	}
+	return fs, nil
+}
+
+// value returns the last value given for a flag.
+func (fs *flagSet) value(name string) (string, bool) {
+	v := fs.values[name]
+	if len(v) == 0 {
+		return "", false
+	}
+	return v[len(v)-1], true
+}
+
+// list returns every value given for the named flags, splitting
+// comma-separated values.
+func (fs *flagSet) list(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		for _, v := range fs.values[name] {
+			for _, part := range strings.Split(v, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					out = append(out, part)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (fs *flagSet) bool(name string) bool {
+	return fs.bools[name]
+}
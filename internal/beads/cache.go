@@ -0,0 +1,255 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Request-scoped caching and instrumentation for bd invocations.
+//
+// Every Beads read forks a bd process, and reporting commands like
+// `gt status` read the same beads many times over. While a request cache is
+// enabled, the output of read-only bd commands is kept in memory and reused
+// for identical calls. Any other bd command is treated as a write and
+// empties the cache, so a command never sees its own writes stale.
+//
+// The cache is process-wide and off by default; long-running processes
+// (daemon, feed, dashboard) never enable it.
+
+// cachedSubcommands are the bd subcommands whose output may be reused.
+var cachedSubcommands = map[string]bool{
+	"list":    true,
+	"show":    true,
+	"ready":   true,
+	"blocked": true,
+}
+
+var (
+	cacheMu      sync.Mutex
+	cacheRefs    int
+	cacheEntries map[string][]byte
+
+	// cacheGen is bumped on every invalidation, so a read that raced with a
+	// write doesn't store what it saw before the write.
+	cacheGen uint64
+
+	statsMu sync.Mutex
+	stats   = InvocationStats{BySubcommand: make(map[string]int)}
+)
+
+// InvocationStats counts bd invocations made by this process.
+type InvocationStats struct {
+	// Invocations is the number of bd processes forked.
+	Invocations int `json:"invocations"`
+
+	// BySubcommand breaks Invocations down by bd subcommand.
+	BySubcommand map[string]int `json:"by_subcommand"`
+
+	// CacheHits counts calls answered from the request cache.
+	CacheHits int `json:"cache_hits"`
+}
+
+// String formats the stats on one line, e.g.
+// "12 bd invocations (list 4, show 8), 30 cache hits".
+func (s InvocationStats) String() string {
+	subs := make([]string, 0, len(s.BySubcommand))
+	for sub := range s.BySubcommand {
+		subs = append(subs, sub)
+	}
+	sort.Strings(subs)
+
+	parts := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		parts = append(parts, fmt.Sprintf("%s %d", sub, s.BySubcommand[sub]))
+	}
+
+	out := fmt.Sprintf("%d bd invocations", s.Invocations)
+	if len(parts) > 0 {
+		out += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return out + fmt.Sprintf(", %d cache hits", s.CacheHits)
+}
+
+// EnableRequestCache turns on the request cache and returns a function that
+// turns it off again. Calls nest; the cache is dropped when the last caller
+// is done.
+func EnableRequestCache() (done func()) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if cacheRefs == 0 {
+		cacheEntries = make(map[string][]byte)
+	}
+	cacheRefs++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cacheMu.Lock()
+			defer cacheMu.Unlock()
+			cacheRefs--
+			if cacheRefs == 0 {
+				cacheEntries = nil
+			}
+		})
+	}
+}
+
+// ClearRequestCache drops everything cached so far, for callers that
+// re-read on an interval (e.g. `gt status --watch`).
+func ClearRequestCache() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if cacheEntries != nil {
+		cacheEntries = make(map[string][]byte)
+	}
+	cacheGen++
+}
+
+// Stats returns a snapshot of the bd invocation counters.
+func Stats() InvocationStats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	snapshot := InvocationStats{
+		Invocations:  stats.Invocations,
+		BySubcommand: make(map[string]int, len(stats.BySubcommand)),
+		CacheHits:    stats.CacheHits,
+	}
+	for sub, n := range stats.BySubcommand {
+		snapshot.BySubcommand[sub] = n
+	}
+	return snapshot
+}
+
+// ResetStats zeroes the bd invocation counters.
+func ResetStats() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats = InvocationStats{BySubcommand: make(map[string]int)}
+}
+
+// recordInvocation counts a bd process about to be forked.
+func recordInvocation(args []string) {
+	sub := "(none)"
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats.Invocations++
+	stats.BySubcommand[sub]++
+}
+
+// recordCacheHit counts a call answered without forking bd.
+func recordCacheHit() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats.CacheHits++
+}
+
+// cacheable reports whether the output of a bd command may be cached.
+func cacheable(args []string) bool {
+	return len(args) > 0 && cachedSubcommands[args[0]]
+}
+
+// cacheKey identifies a bd command run by b. The working directory is part
+// of the key because bd routes some IDs by the directory it runs in.
+func (b *Beads) cacheKey(args []string) string {
+	return b.workDir + "\x00" + b.getResolvedBeadsDir() + "\x00" + fmt.Sprint(b.isolated) +
+		"\x00" + strings.Join(args, "\x00")
+}
+
+// cacheGet returns cached output for a bd command, if any, along with the
+// cache generation to pass to cachePut after running it.
+func (b *Beads) cacheGet(args []string) ([]byte, bool, uint64) {
+	var key string
+	if cacheable(args) {
+		key = b.cacheKey(args)
+	}
+	cacheMu.Lock()
+	out, ok := cacheEntries[key]
+	gen := cacheGen
+	cacheMu.Unlock()
+	if ok && key != "" {
+		recordCacheHit()
+		return out, true, gen
+	}
+	return nil, false, gen
+}
+
+// cachePut stores the output of a read, or empties the cache after
+// anything else. Reads are dropped if the cache was invalidated since gen.
+func (b *Beads) cachePut(args []string, out []byte, gen uint64) {
+	var key string
+	if cacheable(args) {
+		key = b.cacheKey(args)
+	}
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if key == "" {
+		cacheGen++
+		if cacheEntries != nil {
+			cacheEntries = make(map[string][]byte)
+		}
+		return
+	}
+	if cacheEntries != nil && gen == cacheGen {
+		cacheEntries[key] = out
+	}
+}
+
+// cacheGeneration returns the current cache generation, for reads that
+// store their results with seedIssues.
+func cacheGeneration() uint64 {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return cacheGen
+}
+
+// cacheEnabled reports whether a request cache is active.
+func cacheEnabled() bool {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return cacheEntries != nil
+}
+
+// issueArgs is the key under which a single issue is cached. It is the
+// one-ID form of the ShowMultiple command, so Show and ShowMultiple share
+// entries without touching what callers of Run("show", ...) get back.
+func issueArgs(id string) []string {
+	return []string{"show", "--json", id}
+}
+
+// cachedIssue returns an issue from the request cache, whether it was
+// fetched by Show or as part of a ShowMultiple batch.
+func (b *Beads) cachedIssue(id string) (*Issue, bool) {
+	for _, args := range [][]string{issueArgs(id), {"show", id, "--json"}} {
+		out, ok, _ := b.cacheGet(args)
+		if !ok {
+			continue
+		}
+		var issues []*Issue
+		if err := json.Unmarshal(out, &issues); err == nil && len(issues) == 1 {
+			return issues[0], true
+		}
+	}
+	return nil, false
+}
+
+// seedIssues stores issues read at cache generation gen under their
+// single-issue keys, so later Show and ShowMultiple calls for them are
+// answered from the cache.
+func (b *Beads) seedIssues(issues []*Issue, gen uint64) {
+	if !cacheEnabled() {
+		return
+	}
+	for _, issue := range issues {
+		out, err := json.Marshal([]*Issue{issue})
+		if err != nil {
+			continue
+		}
+		b.cachePut(issueArgs(issue.ID), out, gen)
+	}
+}
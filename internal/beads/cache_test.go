@@ -0,0 +1,142 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequestCacheDisabledByDefault(t *testing.T) {
+	b := NewIsolated(t.TempDir())
+	gen := cacheGeneration()
+	b.cachePut([]string{"list", "--json"}, []byte("[]"), gen)
+	if _, ok, _ := b.cacheGet([]string{"list", "--json"}); ok {
+		t.Error("cache hit with no request cache enabled")
+	}
+}
+
+func TestRequestCacheReadsAndInvalidation(t *testing.T) {
+	done := EnableRequestCache()
+	defer done()
+	ResetStats()
+
+	b := NewIsolated(t.TempDir())
+	args := []string{"list", "--json", "--status=open"}
+	b.cachePut(args, []byte(`[{"id":"gt-1"}]`), cacheGeneration())
+
+	out, err := b.run(args...)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if string(out) != `[{"id":"gt-1"}]` {
+		t.Errorf("run = %s, want cached output", out)
+	}
+
+	// A different directory is a different cache entry
+	other := NewIsolated(t.TempDir())
+	if _, ok, _ := other.cacheGet(args); ok {
+		t.Error("cache entry leaked across directories")
+	}
+
+	// Writes invalidate, even when they fail (bd isn't installed here)
+	_, _ = b.run("update", "gt-1", "--status=closed")
+	if _, ok, _ := b.cacheGet(args); ok {
+		t.Error("cache entry survived a write")
+	}
+
+	s := Stats()
+	if s.CacheHits != 1 || s.Invocations != 1 || s.BySubcommand["update"] != 1 {
+		t.Errorf("stats = %+v, want 1 hit and 1 update invocation", s)
+	}
+}
+
+func TestRequestCacheDropsRacingReads(t *testing.T) {
+	done := EnableRequestCache()
+	defer done()
+
+	b := NewIsolated(t.TempDir())
+	gen := cacheGeneration()
+	ClearRequestCache() // a write lands while the read is in flight
+	b.cachePut([]string{"show", "gt-1", "--json"}, []byte(`[{"id":"gt-1"}]`), gen)
+	if _, ok, _ := b.cacheGet([]string{"show", "gt-1", "--json"}); ok {
+		t.Error("read that raced with a write was cached")
+	}
+}
+
+func TestRequestCacheNesting(t *testing.T) {
+	outer := EnableRequestCache()
+	inner := EnableRequestCache()
+	inner()
+	inner() // idempotent
+	if !cacheEnabled() {
+		t.Fatal("cache disabled while outer scope is active")
+	}
+	outer()
+	if cacheEnabled() {
+		t.Error("cache still enabled after all scopes ended")
+	}
+}
+
+func TestShowUsesBatchedIssues(t *testing.T) {
+	done := EnableRequestCache()
+	defer done()
+	ResetStats()
+
+	b := NewIsolated(t.TempDir())
+	b.seedIssues([]*Issue{{ID: "gt-1", Title: "one"}, {ID: "gt-2", Title: "two"}}, cacheGeneration())
+
+	issue, err := b.Show("gt-2")
+	if err != nil {
+		t.Fatalf("Show: %v", err)
+	}
+	if issue.Title != "two" {
+		t.Errorf("Show title = %q, want two", issue.Title)
+	}
+
+	got, err := b.ShowMultiple([]string{"gt-1", "gt-2"})
+	if err != nil {
+		t.Fatalf("ShowMultiple: %v", err)
+	}
+	if len(got) != 2 || got["gt-1"].Title != "one" {
+		t.Errorf("ShowMultiple = %v", got)
+	}
+	if s := Stats(); s.Invocations != 0 {
+		t.Errorf("bd invoked %d times, want 0", s.Invocations)
+	}
+}
+
+func TestInvocationStatsString(t *testing.T) {
+	s := InvocationStats{
+		Invocations:  5,
+		BySubcommand: map[string]int{"show": 3, "list": 2},
+		CacheHits:    7,
+	}
+	got := s.String()
+	want := "5 bd invocations (list 2, show 3), 7 cache hits"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(InvocationStats{}.String(), "0 bd invocations,") {
+		t.Errorf("empty String() = %q", InvocationStats{}.String())
+	}
+}
+
+func TestAgentUpdatesCoalesce(t *testing.T) {
+	u := NewIsolated(t.TempDir()).NewAgentUpdates()
+	u.SetState("gt-a", "working")
+	u.SetFields("gt-b", func(f *AgentFields) { f.ActiveMR = "gt-mr" })
+	u.SetState("gt-a", "stuck")
+	u.SetFields("gt-a", func(f *AgentFields) { f.CleanupStatus = "clean" })
+
+	if u.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", u.Len())
+	}
+	if u.order[0] != "gt-a" || u.order[1] != "gt-b" {
+		t.Errorf("order = %v, want [gt-a gt-b]", u.order)
+	}
+	if u.states["gt-a"] != "stuck" {
+		t.Errorf("state = %q, want last queued state", u.states["gt-a"])
+	}
+	if len(u.fields["gt-a"]) != 1 || len(u.fields["gt-b"]) != 1 {
+		t.Errorf("fields = %v", u.fields)
+	}
+}
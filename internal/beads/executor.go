@@ -0,0 +1,101 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"sync"
+)
+
+// Command is a single bd invocation.
+type Command struct {
+	// Dir is the working directory bd runs in.
+	Dir string
+
+	// Args are the arguments after "bd", including global flags.
+	Args []string
+
+	// Env is the complete environment for bd. Nil inherits the process
+	// environment.
+	Env []string
+}
+
+// Executor runs bd commands. The default forks the bd binary; tests can
+// substitute an in-memory implementation (see package beadstest) with
+// SetExecutor.
+type Executor interface {
+	// Exec runs cmd and returns its stdout and stderr. A non-nil error means
+	// bd failed; stderr then carries its message, as it would from the CLI.
+	Exec(ctx context.Context, cmd Command) (stdout, stderr []byte, err error)
+}
+
+// binaryExecutor runs the bd binary on PATH.
+type binaryExecutor struct{}
+
+func (binaryExecutor) Exec(ctx context.Context, c Command) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, "bd", c.Args...) //nolint:gosec // G204: bd is a trusted internal tool
+	cmd.Dir = c.Dir
+	cmd.Env = c.Env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+var (
+	executorMu sync.RWMutex
+	executor   Executor = binaryExecutor{}
+)
+
+// SetExecutor routes every bd invocation in the process through e, and
+// returns a function that restores the previous executor.
+func SetExecutor(e Executor) (restore func()) {
+	executorMu.Lock()
+	prev := executor
+	executor = e
+	executorMu.Unlock()
+
+	return func() {
+		executorMu.Lock()
+		executor = prev
+		executorMu.Unlock()
+	}
+}
+
+// Exec runs a bd command through the current executor and counts it in
+// Stats. Packages that build their own bd command lines (mail, web) use
+// this rather than forking bd themselves.
+func Exec(ctx context.Context, cmd Command) (stdout, stderr []byte, err error) {
+	recordInvocation(Subcommand(cmd.Args))
+
+	executorMu.RLock()
+	e := executor
+	executorMu.RUnlock()
+
+	return e.Exec(ctx, cmd)
+}
+
+// globalValueFlags are bd global flags that take a separate value.
+var globalValueFlags = map[string]bool{
+	"--db":    true,
+	"--actor": true,
+}
+
+// Subcommand returns the arguments of a bd command line with leading
+// global flags (--no-daemon, --db <path>, ...) removed, so args[0] is the
+// subcommand.
+func Subcommand(args []string) []string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if len(a) == 0 || a[0] != '-' {
+			return args[i:]
+		}
+		if globalValueFlags[a] {
+			i++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+// Package beads provides queries over structured bead fields.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldFilter is a comparison against one structured field, written as
+// key, operator, value: "retry_count>2", "target=main", "branch~polecat/".
+//
+// Operators are =, !=, >, >=, <, <= and ~ (substring). Values that parse as
+// numbers compare numerically against numeric fields; everything else
+// compares as strings, which orders ISO 8601 timestamps correctly. A field
+// that is unset compares as 0 or "".
+type FieldFilter struct {
+	Key   string
+	Op    string
+	Value string
+}
+
+// fieldFilterOps are the filter operators, two-character ones first so ">="
+// isn't read as ">".
+var fieldFilterOps = []string{"!=", ">=", "<=", "=", ">", "<", "~"}
+
+// ParseFieldFilter parses a filter expression such as "retry_count>2".
+func ParseFieldFilter(expr string) (FieldFilter, error) {
+	idx := strings.IndexAny(expr, "!=<>~")
+	if idx <= 0 {
+		return FieldFilter{}, fmt.Errorf("invalid field filter %q: want <key><op><value>, e.g. retry_count>2", expr)
+	}
+	for _, op := range fieldFilterOps {
+		if strings.HasPrefix(expr[idx:], op) {
+			return FieldFilter{
+				Key:   strings.TrimSpace(expr[:idx]),
+				Op:    op,
+				Value: strings.TrimSpace(expr[idx+len(op):]),
+			}, nil
+		}
+	}
+	return FieldFilter{}, fmt.Errorf("invalid operator in field filter %q", expr)
+}
+
+// String returns the filter in the form ParseFieldFilter accepts.
+func (f FieldFilter) String() string {
+	return f.Key + f.Op + f.Value
+}
+
+// Match reports whether the field values (keyed by JSON name, as returned by
+// IssueFields) satisfy the filter.
+func (f FieldFilter) Match(values map[string]any) bool {
+	v := values[f.Key]
+
+	if want, err := strconv.ParseFloat(f.Value, 64); err == nil && f.Op != "~" {
+		got, isNum := v.(float64)
+		if isNum || v == nil {
+			return compareOrdered(got, want, f.Op)
+		}
+	}
+
+	got := ""
+	if v != nil {
+		got = fmt.Sprint(v)
+	}
+	if f.Op == "~" {
+		return strings.Contains(got, f.Value)
+	}
+	return compareOrdered(got, f.Value, f.Op)
+}
+
+func compareOrdered[T float64 | string](got, want T, op string) bool {
+	switch op {
+	case "=":
+		return got == want
+	case "!=":
+		return got != want
+	case ">":
+		return got > want
+	case ">=":
+		return got >= want
+	case "<":
+		return got < want
+	case "<=":
+		return got <= want
+	}
+	return false
+}
+
+// kindLabels are the labels that identify the beads carrying each field kind.
+// Kinds without a label can appear on any bead.
+var kindLabels = map[FieldKind]string{
+	FieldsMR:         "gt:merge-request",
+	FieldsAgent:      "gt:agent",
+	FieldsRoleConfig: "gt:role",
+}
+
+// ParseFieldKind validates a field kind name.
+func ParseFieldKind(s string) (FieldKind, error) {
+	for _, kind := range FieldKinds {
+		if string(kind) == s {
+			return kind, nil
+		}
+	}
+	names := make([]string, len(FieldKinds))
+	for i, kind := range FieldKinds {
+		names[i] = string(kind)
+	}
+	return "", fmt.Errorf("unknown field kind %q (want one of: %s)", s, strings.Join(names, ", "))
+}
+
+// typedFields returns an issue's fields of the given kind via the typed
+// parser, so legacy "key: value" descriptions are read too. Returns nil if the
+// issue has none.
+func typedFields(issue *Issue, kind FieldKind) any {
+	switch kind {
+	case FieldsMR:
+		if f := ParseMRFields(issue); f != nil {
+			return f
+		}
+	case FieldsAttachment:
+		if f := ParseAttachmentFields(issue); f != nil {
+			return f
+		}
+	case FieldsSynthesis:
+		if f := ParseSynthesisFields(issue); f != nil {
+			return f
+		}
+	case FieldsAgent:
+		if issue != nil {
+			if f := ParseAgentFields(issue.Description); *f != (AgentFields{}) {
+				return f
+			}
+		}
+	case FieldsRoleConfig:
+		if issue != nil {
+			if f := ParseRoleConfig(issue.Description); f != nil {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// renderFieldLines returns the readable "key: value" lines the setters write
+// for fields of the given kind.
+func renderFieldLines(kind FieldKind, fields any) string {
+	switch f := fields.(type) {
+	case *MRFields:
+		return FormatMRFields(f)
+	case *AttachmentFields:
+		return FormatAttachmentFields(f)
+	case *SynthesisFields:
+		return FormatSynthesisFields(f)
+	case *AgentFields:
+		body, _ := splitFieldBlock(FormatAgentDescription("", f))
+		return body
+	case *RoleConfig:
+		return FormatRoleConfig(f)
+	}
+	return ""
+}
+
+// IssueFields returns an issue's fields of the given kind as a map keyed by
+// their JSON names, or nil if the issue has none.
+func IssueFields(issue *Issue, kind FieldKind) map[string]any {
+	fields := typedFields(issue, kind)
+	if fields == nil {
+		return nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil
+	}
+	return values
+}
+
+// QueryFields lists issues matching opts whose fields of the given kind
+// satisfy every filter. Issues without fields of that kind never match. If
+// opts has no label, the kind's label (e.g. gt:merge-request) is used.
+func (b *Beads) QueryFields(kind FieldKind, opts ListOptions, filters ...FieldFilter) ([]*Issue, error) {
+	if opts.Label == "" && opts.Type == "" {
+		opts.Label = kindLabels[kind]
+	}
+	issues, err := b.List(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*Issue
+	for _, issue := range issues {
+		values := IssueFields(issue, kind)
+		if values == nil {
+			continue
+		}
+		ok := true
+		for _, f := range filters {
+			if !f.Match(values) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, issue)
+		}
+	}
+	return matched, nil
+}
+
+// ListMRs lists merge-request beads matching opts whose fields satisfy match
+// (nil matches every MR), e.g. MRs that have been through more than two
+// conflict-resolution cycles:
+//
+//	b.ListMRs(ListOptions{Status: "open", Priority: -1}, func(f *MRFields) bool {
+//		return f.RetryCount > 2
+//	})
+func (b *Beads) ListMRs(opts ListOptions, match func(*MRFields) bool) ([]*Issue, error) {
+	if opts.Label == "" && opts.Type == "" {
+		opts.Label = kindLabels[FieldsMR]
+	}
+	issues, err := b.List(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*Issue
+	for _, issue := range issues {
+		fields := ParseMRFields(issue)
+		if fields == nil {
+			continue
+		}
+		if match == nil || match(fields) {
+			matched = append(matched, issue)
+		}
+	}
+	return matched, nil
+}
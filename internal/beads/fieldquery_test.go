@@ -0,0 +1,93 @@
+package beads_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+)
+
+func TestQueryMRFields(t *testing.T) {
+	fake := beadstest.Install(t)
+	mr := func(id string, fields *beads.MRFields) beads.Issue {
+		return beads.Issue{ID: id, Title: "Merge: " + id, Labels: []string{"gt:merge-request"},
+			Description: beads.SetMRFields(nil, fields)}
+	}
+	fake.Add(
+		mr("gt-mr-1", &beads.MRFields{Branch: "polecat/a", Target: "main", RetryCount: 3}),
+		mr("gt-mr-2", &beads.MRFields{Branch: "polecat/b", Target: "main", RetryCount: 1}),
+		mr("gt-mr-3", &beads.MRFields{Branch: "polecat/c", Target: "integration/epic", RetryCount: 5}),
+		// Written before the field block existed
+		beads.Issue{ID: "gt-mr-4", Title: "legacy", Labels: []string{"gt:merge-request"},
+			Description: "branch: polecat/d\ntarget: main\nretry_count: 4"},
+		beads.Issue{ID: "gt-task", Title: "not an MR", Description: "retry_count: 9"},
+	)
+	b := beads.New(t.TempDir())
+
+	ids := func(issues []*beads.Issue) string {
+		var out []string
+		for _, i := range issues {
+			out = append(out, i.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	stuck, err := b.ListMRs(beads.ListOptions{Priority: -1}, func(f *beads.MRFields) bool { return f.RetryCount > 2 })
+	if err != nil {
+		t.Fatalf("ListMRs: %v", err)
+	}
+	if got := ids(stuck); got != "gt-mr-1,gt-mr-3,gt-mr-4" {
+		t.Errorf("ListMRs(retry_count > 2) = %s", got)
+	}
+
+	retry, _ := beads.ParseFieldFilter("retry_count>2")
+	target, _ := beads.ParseFieldFilter("target=main")
+	got, err := b.QueryFields(beads.FieldsMR, beads.ListOptions{Priority: -1}, retry, target)
+	if err != nil {
+		t.Fatalf("QueryFields: %v", err)
+	}
+	if ids(got) != "gt-mr-1,gt-mr-4" {
+		t.Errorf("QueryFields = %s", ids(got))
+	}
+
+	if mr, err := b.FindMRForBranch("polecat/d"); err != nil || mr == nil || mr.ID != "gt-mr-4" {
+		t.Errorf("FindMRForBranch(legacy) = %v, %v", mr, err)
+	}
+}
+
+func TestMigrateFields(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-mr-1", Title: "legacy MR", Labels: []string{"gt:merge-request"},
+			Description: "branch: polecat/a\ntarget: main\nretry_count: 2"},
+		beads.Issue{ID: "gt-2", Title: "hooked", Status: "closed",
+			Description: "attached_molecule: mol-1\n\nNotes."},
+		beads.Issue{ID: "gt-3", Title: "plain", Description: "Nothing structured here."},
+	)
+	b := beads.New(t.TempDir())
+
+	planned, err := b.MigrateFields(true)
+	if err != nil {
+		t.Fatalf("MigrateFields(dry run): %v", err)
+	}
+	if len(planned) != 2 || fake.Count("update") != 0 {
+		t.Fatalf("dry run = %+v with %d updates", planned, fake.Count("update"))
+	}
+
+	migrated, err := b.MigrateFields(false)
+	if err != nil {
+		t.Fatalf("MigrateFields: %v", err)
+	}
+	if len(migrated) != 2 || migrated[0].ID != "gt-mr-1" || migrated[1].Kinds[0] != beads.FieldsAttachment {
+		t.Errorf("migrated = %+v", migrated)
+	}
+	issue, _ := fake.Issue("gt-mr-1")
+	if !beads.HasFields(issue.Description, beads.FieldsMR) || !strings.HasPrefix(issue.Description, "branch: polecat/a\n") {
+		t.Errorf("migrated description:\n%s", issue.Description)
+	}
+
+	if again, _ := b.MigrateFields(false); len(again) != 0 {
+		t.Errorf("second migration = %+v", again)
+	}
+}
@@ -17,21 +17,26 @@ func ParseAgentFieldsFromDescription(description string) *AgentFields {
 // AttachmentFields holds the attachment info for pinned beads.
 // These fields track which molecule is attached to a handoff/pinned bead.
 type AttachmentFields struct {
-	AttachedMolecule string // Root issue ID of the attached molecule
-	AttachedAt       string // ISO 8601 timestamp when attached
-	AttachedArgs     string // Natural language args passed via gt sling --args (no-tmux mode)
-	DispatchedBy     string // Agent ID that dispatched this work (for completion notification)
-	NoMerge          bool   // If true, gt done skips merge queue (for upstream PRs/human review)
+	AttachedMolecule string `json:"attached_molecule,omitempty"` // Root issue ID of the attached molecule
+	AttachedAt       string `json:"attached_at,omitempty"`       // ISO 8601 timestamp when attached
+	AttachedArgs     string `json:"attached_args,omitempty"`     // Natural language args passed via gt sling --args (no-tmux mode)
+	DispatchedBy     string `json:"dispatched_by,omitempty"`     // Agent ID that dispatched this work (for completion notification)
+	NoMerge          bool   `json:"no_merge,omitempty"`          // If true, gt done skips merge queue (for upstream PRs/human review)
+	TraceID          string `json:"trace_id,omitempty"`          // Trace ID assigned at sling time (see gt trace)
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
-// Fields are expected as "key: value" lines. Returns nil if no attachment fields found.
+// The field block is read first; descriptions without one fall back to
+// "key: value" lines. Returns nil if no attachment fields found.
 func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 	if issue == nil || issue.Description == "" {
 		return nil
 	}
 
 	fields := &AttachmentFields{}
+	if readFieldBlock(issue.Description, FieldsAttachment, fields) {
+		return fields
+	}
 	hasFields := false
 
 	for _, line := range strings.Split(issue.Description, "\n") {
@@ -69,6 +74,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "no_merge", "no-merge", "nomerge":
 			fields.NoMerge = strings.ToLower(value) == "true"
 			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
 		}
 	}
 
@@ -102,105 +110,110 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.NoMerge {
 		lines = append(lines, "no_merge: true")
 	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
 
 	return strings.Join(lines, "\n")
 }
 
+// attachmentFieldKeys are the legacy "key: value" keys (lowercase) of AttachmentFields.
+var attachmentFieldKeys = map[string]bool{
+	"attached_molecule": true,
+	"attached-molecule": true,
+	"attachedmolecule":  true,
+	"attached_at":       true,
+	"attached-at":       true,
+	"attachedat":        true,
+	"attached_args":     true,
+	"attached-args":     true,
+	"attachedargs":      true,
+	"dispatched_by":     true,
+	"dispatched-by":     true,
+	"dispatchedby":      true,
+	"no_merge":          true,
+	"no-merge":          true,
+	"nomerge":           true,
+	"trace_id":          true,
+	"trace-id":          true,
+	"traceid":           true,
+}
+
 // SetAttachmentFields updates an issue's description with the given attachment fields.
-// Existing attachment field lines are replaced; other content is preserved.
+// The fields are stored in the field block, with readable "key: value" lines
+// placed first; existing attachment lines are replaced and other content is
+// preserved. Nil or empty fields clear the attachment.
 // Returns the new description string.
 func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
-	// Known attachment field keys (lowercase)
-	attachmentKeys := map[string]bool{
-		"attached_molecule": true,
-		"attached-molecule": true,
-		"attachedmolecule":  true,
-		"attached_at":       true,
-		"attached-at":       true,
-		"attachedat":        true,
-		"attached_args":     true,
-		"attached-args":     true,
-		"attachedargs":      true,
-		"dispatched_by":     true,
-		"dispatched-by":     true,
-		"dispatchedby":      true,
-		"no_merge":          true,
-		"no-merge":          true,
-		"nomerge":           true,
-	}
-
-	// Collect non-attachment lines from existing description
-	var otherLines []string
-	if issue != nil && issue.Description != "" {
-		for _, line := range strings.Split(issue.Description, "\n") {
-			trimmed := strings.TrimSpace(line)
-			if trimmed == "" {
-				// Preserve blank lines in content
-				otherLines = append(otherLines, line)
-				continue
-			}
-
-			// Check if this is an attachment field line
-			colonIdx := strings.Index(trimmed, ":")
-			if colonIdx == -1 {
-				otherLines = append(otherLines, line)
-				continue
-			}
-
-			key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
-			if !attachmentKeys[key] {
-				otherLines = append(otherLines, line)
-			}
-			// Skip attachment field lines - they'll be replaced
-		}
+	description := ""
+	if issue != nil {
+		description = issue.Description
 	}
 
-	// Build new description: attachment fields first, then other content
 	formatted := FormatAttachmentFields(fields)
-
-	// Trim trailing blank lines from other content
-	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
-		otherLines = otherLines[:len(otherLines)-1]
-	}
-	// Trim leading blank lines from other content
-	for len(otherLines) > 0 && strings.TrimSpace(otherLines[0]) == "" {
-		otherLines = otherLines[1:]
-	}
-
+	description = replaceFieldLines(description, attachmentFieldKeys, formatted)
 	if formatted == "" {
-		return strings.Join(otherLines, "\n")
+		return DeleteFields(description, FieldsAttachment)
 	}
-	if len(otherLines) == 0 {
-		return formatted
-	}
-
-	return formatted + "\n\n" + strings.Join(otherLines, "\n")
+	return PutFields(description, FieldsAttachment, fields)
 }
 
 // MRFields holds the structured fields for a merge-request issue.
-// These fields are stored as key: value lines in the issue description.
+// These fields are stored in the description's field block (see SetMRFields).
 type MRFields struct {
-	Branch      string // Source branch name (e.g., "polecat/Nux/gt-xyz")
-	Target      string // Target branch (e.g., "main" or "integration/gt-epic")
-	SourceIssue string // The work item being merged (e.g., "gt-xyz")
-	Worker      string // Who did the work
-	Rig         string // Which rig
-	MergeCommit string // SHA of merge commit (set on close)
-	CloseReason string // Reason for closing: merged, rejected, conflict, superseded
-	AgentBead   string // Agent bead ID that created this MR (for traceability)
+	Branch      string `json:"branch,omitempty"`       // Source branch name (e.g., "polecat/Nux/gt-xyz")
+	Target      string `json:"target,omitempty"`       // Target branch (e.g., "main" or "integration/gt-epic")
+	SourceIssue string `json:"source_issue,omitempty"` // The work item being merged (e.g., "gt-xyz")
+	Worker      string `json:"worker,omitempty"`       // Who did the work
+	Rig         string `json:"rig,omitempty"`          // Which rig
+	MergeCommit string `json:"merge_commit,omitempty"` // SHA of merge commit (set on close)
+	CloseReason string `json:"close_reason,omitempty"` // Reason for closing: merged, rejected, conflict, superseded
+	AgentBead   string `json:"agent_bead,omitempty"`   // Agent bead ID that created this MR (for traceability)
 
 	// Conflict resolution fields (for priority scoring)
-	RetryCount      int    // Number of conflict-resolution cycles
-	LastConflictSHA string // SHA of main when conflict occurred
-	ConflictTaskID  string // Link to conflict-resolution task (if any)
+	RetryCount      int    `json:"retry_count,omitempty"`       // Number of conflict-resolution cycles
+	LastConflictSHA string `json:"last_conflict_sha,omitempty"` // SHA of main when conflict occurred
+	ConflictTaskID  string `json:"conflict_task_id,omitempty"`  // Link to conflict-resolution task (if any)
 
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
-	ConvoyID        string // Parent convoy ID if part of a convoy
-	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+	ConvoyID        string `json:"convoy_id,omitempty"`         // Parent convoy ID if part of a convoy
+	ConvoyCreatedAt string `json:"convoy_created_at,omitempty"` // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Lifecycle tracing (see gt trace)
+	TraceID string `json:"trace_id,omitempty"` // Trace ID assigned when the source issue was slung
+
+	// Merge ordering: comma-separated MR (or source issue) IDs that must
+	// merge before this one, on top of the source issue's bead dependencies
+	DependsOnMR string `json:"depends_on_mr,omitempty"`
+
+	// Pull request merge mode (merge_queue.merge_mode "pr")
+	PRNumber     int    `json:"pr_number,omitempty"`      // PR number (GitHub) or MR IID (GitLab)
+	PRURL        string `json:"pr_url,omitempty"`         // PR web URL
+	ReviewCursor string `json:"review_cursor,omitempty"`  // Time (RFC 3339) of the newest review comment sent back as rework
+	ReworkTaskID string `json:"rework_task_id,omitempty"` // Link to the latest review rework task (if any)
+
+	// External CI gate (merge_queue.ci); set while a candidate is under test
+	CIStatus    string `json:"ci_status,omitempty"`     // "pending" while waiting on CI
+	CIRef       string `json:"ci_ref,omitempty"`        // Candidate ref pushed for CI
+	CISHA       string `json:"ci_sha,omitempty"`        // Candidate commit (the squash merge onto the target)
+	CIStartedAt string `json:"ci_started_at,omitempty"` // When the candidate was pushed (RFC 3339)
+	CIURL       string `json:"ci_url,omitempty"`        // CI run link, if the provider reports one
+}
+
+// DependsOnMRs returns the IDs listed in DependsOnMR.
+func (f *MRFields) DependsOnMRs() []string {
+	var ids []string
+	for _, id := range strings.Split(f.DependsOnMR, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
-// Fields are expected as "key: value" lines, with optional prose text mixed in.
+// The field block is read first; descriptions without one fall back to
+// "key: value" lines, with optional prose text mixed in.
 // Returns nil if no MR fields are found.
 func ParseMRFields(issue *Issue) *MRFields {
 	if issue == nil || issue.Description == "" {
@@ -208,6 +221,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 	}
 
 	fields := &MRFields{}
+	if readFieldBlock(issue.Description, FieldsMR, fields) {
+		return fields
+	}
 	hasFields := false
 
 	for _, line := range strings.Split(issue.Description, "\n") {
@@ -224,7 +240,7 @@ func ParseMRFields(issue *Issue) *MRFields {
 
 		key := strings.TrimSpace(line[:colonIdx])
 		value := strings.TrimSpace(line[colonIdx+1:])
-		if value == "" {
+		if value == "" || value == "null" {
 			continue
 		}
 
@@ -271,6 +287,41 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
+		case "depends_on_mr", "depends-on-mr", "dependsonmr":
+			fields.DependsOnMR = value
+			hasFields = true
+		case "pr_number", "pr-number", "prnumber":
+			if n, err := parseIntField(value); err == nil {
+				fields.PRNumber = n
+				hasFields = true
+			}
+		case "pr_url", "pr-url", "prurl":
+			fields.PRURL = value
+			hasFields = true
+		case "review_cursor", "review-cursor", "reviewcursor":
+			fields.ReviewCursor = value
+			hasFields = true
+		case "rework_task_id", "rework-task-id", "reworktaskid":
+			fields.ReworkTaskID = value
+			hasFields = true
+		case "ci_status", "ci-status", "cistatus":
+			fields.CIStatus = value
+			hasFields = true
+		case "ci_ref", "ci-ref", "ciref":
+			fields.CIRef = value
+			hasFields = true
+		case "ci_sha", "ci-sha", "cisha":
+			fields.CISHA = value
+			hasFields = true
+		case "ci_started_at", "ci-started-at", "cistartedat":
+			fields.CIStartedAt = value
+			hasFields = true
+		case "ci_url", "ci-url", "ciurl":
+			fields.CIURL = value
+			hasFields = true
 		}
 	}
 
@@ -335,100 +386,129 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
+	if fields.DependsOnMR != "" {
+		lines = append(lines, "depends_on_mr: "+fields.DependsOnMR)
+	}
+	if fields.PRNumber > 0 {
+		lines = append(lines, fmt.Sprintf("pr_number: %d", fields.PRNumber))
+	}
+	if fields.PRURL != "" {
+		lines = append(lines, "pr_url: "+fields.PRURL)
+	}
+	if fields.ReviewCursor != "" {
+		lines = append(lines, "review_cursor: "+fields.ReviewCursor)
+	}
+	if fields.ReworkTaskID != "" {
+		lines = append(lines, "rework_task_id: "+fields.ReworkTaskID)
+	}
+	if fields.CIStatus != "" {
+		lines = append(lines, "ci_status: "+fields.CIStatus)
+	}
+	if fields.CIRef != "" {
+		lines = append(lines, "ci_ref: "+fields.CIRef)
+	}
+	if fields.CISHA != "" {
+		lines = append(lines, "ci_sha: "+fields.CISHA)
+	}
+	if fields.CIStartedAt != "" {
+		lines = append(lines, "ci_started_at: "+fields.CIStartedAt)
+	}
+	if fields.CIURL != "" {
+		lines = append(lines, "ci_url: "+fields.CIURL)
+	}
 
 	return strings.Join(lines, "\n")
 }
 
+// mrFieldKeys are the legacy "key: value" keys (lowercase) of MRFields.
+var mrFieldKeys = map[string]bool{
+	"branch":            true,
+	"target":            true,
+	"source_issue":      true,
+	"source-issue":      true,
+	"sourceissue":       true,
+	"worker":            true,
+	"rig":               true,
+	"merge_commit":      true,
+	"merge-commit":      true,
+	"mergecommit":       true,
+	"close_reason":      true,
+	"close-reason":      true,
+	"closereason":       true,
+	"agent_bead":        true,
+	"agent-bead":        true,
+	"agentbead":         true,
+	"retry_count":       true,
+	"retry-count":       true,
+	"retrycount":        true,
+	"last_conflict_sha": true,
+	"last-conflict-sha": true,
+	"lastconflictsha":   true,
+	"conflict_task_id":  true,
+	"conflict-task-id":  true,
+	"conflicttaskid":    true,
+	"convoy_id":         true,
+	"convoy-id":         true,
+	"convoyid":          true,
+	"convoy":            true,
+	"convoy_created_at": true,
+	"convoy-created-at": true,
+	"convoycreatedat":   true,
+	"trace_id":          true,
+	"trace-id":          true,
+	"traceid":           true,
+	"depends_on_mr":     true,
+	"depends-on-mr":     true,
+	"dependsonmr":       true,
+	"pr_number":         true,
+	"pr-number":         true,
+	"prnumber":          true,
+	"pr_url":            true,
+	"pr-url":            true,
+	"prurl":             true,
+	"review_cursor":     true,
+	"review-cursor":     true,
+	"reviewcursor":      true,
+	"rework_task_id":    true,
+	"rework-task-id":    true,
+	"reworktaskid":      true,
+	"ci_status":         true,
+	"ci-status":         true,
+	"cistatus":          true,
+	"ci_ref":            true,
+	"ci-ref":            true,
+	"ciref":             true,
+	"ci_sha":            true,
+	"ci-sha":            true,
+	"cisha":             true,
+	"ci_started_at":     true,
+	"ci-started-at":     true,
+	"cistartedat":       true,
+	"ci_url":            true,
+	"ci-url":            true,
+	"ciurl":             true,
+}
+
 // SetMRFields updates an issue's description with the given MR fields.
-// Existing MR field lines are replaced; other content is preserved.
+// The fields are stored in the field block, with readable "key: value" lines
+// placed first; existing MR lines are replaced and other content is
+// preserved. Nil or empty fields clear the MR data.
 // Returns the new description string.
 func SetMRFields(issue *Issue, fields *MRFields) string {
-	if issue == nil {
-		return FormatMRFields(fields)
-	}
-
-	// Known MR field keys (lowercase)
-	mrKeys := map[string]bool{
-		"branch":             true,
-		"target":             true,
-		"source_issue":       true,
-		"source-issue":       true,
-		"sourceissue":        true,
-		"worker":             true,
-		"rig":                true,
-		"merge_commit":       true,
-		"merge-commit":       true,
-		"mergecommit":        true,
-		"close_reason":       true,
-		"close-reason":       true,
-		"closereason":        true,
-		"agent_bead":         true,
-		"agent-bead":         true,
-		"agentbead":          true,
-		"retry_count":        true,
-		"retry-count":        true,
-		"retrycount":         true,
-		"last_conflict_sha":  true,
-		"last-conflict-sha":  true,
-		"lastconflictsha":    true,
-		"conflict_task_id":   true,
-		"conflict-task-id":   true,
-		"conflicttaskid":     true,
-		"convoy_id":          true,
-		"convoy-id":          true,
-		"convoyid":           true,
-		"convoy":             true,
-		"convoy_created_at":  true,
-		"convoy-created-at":  true,
-		"convoycreatedat":    true,
-	}
-
-	// Collect non-MR lines from existing description
-	var otherLines []string
-	if issue.Description != "" {
-		for _, line := range strings.Split(issue.Description, "\n") {
-			trimmed := strings.TrimSpace(line)
-			if trimmed == "" {
-				// Preserve blank lines in content
-				otherLines = append(otherLines, line)
-				continue
-			}
-
-			// Check if this is an MR field line
-			colonIdx := strings.Index(trimmed, ":")
-			if colonIdx == -1 {
-				otherLines = append(otherLines, line)
-				continue
-			}
-
-			key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
-			if !mrKeys[key] {
-				otherLines = append(otherLines, line)
-			}
-			// Skip MR field lines - they'll be replaced
-		}
+	description := ""
+	if issue != nil {
+		description = issue.Description
 	}
 
-	// Build new description: MR fields first, then other content
 	formatted := FormatMRFields(fields)
-
-	// Trim trailing blank lines from other content
-	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
-		otherLines = otherLines[:len(otherLines)-1]
-	}
-	// Trim leading blank lines from other content
-	for len(otherLines) > 0 && strings.TrimSpace(otherLines[0]) == "" {
-		otherLines = otherLines[1:]
-	}
-
+	description = replaceFieldLines(description, mrFieldKeys, formatted)
 	if formatted == "" {
-		return strings.Join(otherLines, "\n")
-	}
-	if len(otherLines) == 0 {
-		return formatted
+		return DeleteFields(description, FieldsMR)
 	}
-
-	return formatted + "\n\n" + strings.Join(otherLines, "\n")
+	return PutFields(description, FieldsMR, fields)
 }
 
 // SynthesisFields holds structured fields for synthesis beads.
@@ -441,13 +521,16 @@ type SynthesisFields struct {
 }
 
 // ParseSynthesisFields extracts synthesis fields from an issue's description.
-// Fields are expected as "key: value" lines. Returns nil if no fields found.
+// The field block is read first, then "key: value" lines. Returns nil if no fields found.
 func ParseSynthesisFields(issue *Issue) *SynthesisFields {
 	if issue == nil || issue.Description == "" {
 		return nil
 	}
 
 	fields := &SynthesisFields{}
+	if readFieldBlock(issue.Description, FieldsSynthesis, fields) {
+		return fields
+	}
 	hasFields := false
 
 	for _, line := range strings.Split(issue.Description, "\n") {
@@ -512,59 +595,94 @@ func FormatSynthesisFields(fields *SynthesisFields) string {
 	return strings.Join(lines, "\n")
 }
 
+// synthesisFieldKeys are the legacy "key: value" keys (lowercase) of SynthesisFields.
+var synthesisFieldKeys = map[string]bool{
+	"convoy":      true,
+	"convoy_id":   true,
+	"convoy-id":   true,
+	"review_id":   true,
+	"review-id":   true,
+	"reviewid":    true,
+	"output_path": true,
+	"output-path": true,
+	"outputpath":  true,
+	"formula":     true,
+}
+
+// SetSynthesisFields updates an issue's description with the given synthesis
+// fields, like SetMRFields. Returns the new description string.
+func SetSynthesisFields(issue *Issue, fields *SynthesisFields) string {
+	description := ""
+	if issue != nil {
+		description = issue.Description
+	}
+
+	formatted := FormatSynthesisFields(fields)
+	description = replaceFieldLines(description, synthesisFieldKeys, formatted)
+	if formatted == "" {
+		return DeleteFields(description, FieldsSynthesis)
+	}
+	return PutFields(description, FieldsSynthesis, fields)
+}
+
 // RoleConfig holds structured lifecycle configuration for role beads.
-// These fields are stored as "key: value" lines in the role bead description.
+// These fields are stored in the role bead description's field block.
 // This enables agents to self-register their lifecycle configuration,
 // replacing hardcoded identity string parsing in the daemon.
 type RoleConfig struct {
 	// SessionPattern defines how to derive tmux session name.
 	// Supports placeholders: {rig}, {name}, {role}
 	// Examples: "hq-mayor", "hq-deacon", "gt-{rig}-{role}", "gt-{rig}-{name}"
-	SessionPattern string
+	SessionPattern string `json:"session_pattern,omitempty"`
 
 	// WorkDirPattern defines the working directory relative to town root.
 	// Supports placeholders: {town}, {rig}, {name}, {role}
 	// Examples: "{town}", "{town}/{rig}", "{town}/{rig}/polecats/{name}"
-	WorkDirPattern string
+	WorkDirPattern string `json:"work_dir_pattern,omitempty"`
 
 	// NeedsPreSync indicates whether workspace needs git sync before starting.
 	// True for agents with persistent clones (refinery, crew, polecat).
-	NeedsPreSync bool
+	NeedsPreSync bool `json:"needs_pre_sync,omitempty"`
 
 	// StartCommand is the command to run after creating the session.
 	// Default: "exec claude --dangerously-skip-permissions"
-	StartCommand string
+	StartCommand string `json:"start_command,omitempty"`
 
 	// EnvVars are additional environment variables to set in the session.
 	// Stored as "key=value" pairs.
-	EnvVars map[string]string
+	EnvVars map[string]string `json:"env_vars,omitempty"`
 
 	// Health check thresholds - per ZFC, agents control their own stuck detection.
 	// These allow the Deacon's patrol config to be agent-defined rather than hardcoded.
 
 	// PingTimeout is how long to wait for a health check response.
 	// Format: duration string (e.g., "30s", "1m"). Default: 30s.
-	PingTimeout string
+	PingTimeout string `json:"ping_timeout,omitempty"`
 
 	// ConsecutiveFailures is how many failed health checks before force-kill.
 	// Default: 3.
-	ConsecutiveFailures int
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
 
 	// KillCooldown is the minimum time between force-kills of the same agent.
 	// Format: duration string (e.g., "5m", "10m"). Default: 5m.
-	KillCooldown string
+	KillCooldown string `json:"kill_cooldown,omitempty"`
 
 	// StuckThreshold is how long a wisp can be in_progress before considered stuck.
 	// Format: duration string (e.g., "1h", "30m"). Default: 1h.
-	StuckThreshold string
+	StuckThreshold string `json:"stuck_threshold,omitempty"`
 }
 
 // ParseRoleConfig extracts RoleConfig from a role bead's description.
-// Fields are expected as "key: value" lines. Returns nil if no config found.
+// The field block is read first, then "key: value" lines. Returns nil if no config found.
 func ParseRoleConfig(description string) *RoleConfig {
-	config := &RoleConfig{
-		EnvVars: make(map[string]string),
+	config := &RoleConfig{}
+	if readFieldBlock(description, FieldsRoleConfig, config) {
+		if config.EnvVars == nil {
+			config.EnvVars = make(map[string]string)
+		}
+		return config
 	}
+	config.EnvVars = make(map[string]string)
 	hasFields := false
 
 	for _, line := range strings.Split(description, "\n") {
@@ -664,6 +782,49 @@ func FormatRoleConfig(config *RoleConfig) string {
 	return strings.Join(lines, "\n")
 }
 
+// roleConfigFieldKeys are the legacy "key: value" keys (lowercase) of RoleConfig.
+var roleConfigFieldKeys = map[string]bool{
+	"session_pattern":      true,
+	"session-pattern":      true,
+	"sessionpattern":       true,
+	"work_dir_pattern":     true,
+	"work-dir-pattern":     true,
+	"workdirpattern":       true,
+	"workdir_pattern":      true,
+	"needs_pre_sync":       true,
+	"needs-pre-sync":       true,
+	"needspresync":         true,
+	"start_command":        true,
+	"start-command":        true,
+	"startcommand":         true,
+	"env_var":              true,
+	"env-var":              true,
+	"envvar":               true,
+	"ping_timeout":         true,
+	"ping-timeout":         true,
+	"pingtimeout":          true,
+	"consecutive_failures": true,
+	"consecutive-failures": true,
+	"consecutivefailures":  true,
+	"kill_cooldown":        true,
+	"kill-cooldown":        true,
+	"killcooldown":         true,
+	"stuck_threshold":      true,
+	"stuck-threshold":      true,
+	"stuckthreshold":       true,
+}
+
+// SetRoleConfig updates a role bead's description with the given config,
+// like SetMRFields. Returns the new description string.
+func SetRoleConfig(description string, config *RoleConfig) string {
+	formatted := FormatRoleConfig(config)
+	description = replaceFieldLines(description, roleConfigFieldKeys, formatted)
+	if config == nil {
+		return DeleteFields(description, FieldsRoleConfig)
+	}
+	return PutFields(description, FieldsRoleConfig, config)
+}
+
 // ExpandRolePattern expands placeholders in a pattern string.
 // Supported placeholders: {town}, {rig}, {name}, {role}
 func ExpandRolePattern(pattern, townRoot, rig, name, role string) string {
@@ -0,0 +1,342 @@
+// Package beads provides the structured field store for bead descriptions.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldKind names one group of structured fields in a bead's field block.
+type FieldKind string
+
+// Field kinds stored in the field block.
+const (
+	FieldsMR         FieldKind = "mr"
+	FieldsAgent      FieldKind = "agent"
+	FieldsAttachment FieldKind = "attachment"
+	FieldsSynthesis  FieldKind = "synthesis"
+	FieldsRoleConfig FieldKind = "role_config"
+)
+
+// FieldKinds lists every kind the field store knows about.
+var FieldKinds = []FieldKind{FieldsMR, FieldsAgent, FieldsAttachment, FieldsSynthesis, FieldsRoleConfig}
+
+// The field block is a single HTML comment line at the end of a description:
+//
+//	<!-- gt:fields {"mr":{"branch":"polecat/Nux/gt-xyz","target":"main"}} -->
+//
+// It holds a bead's structured fields with their types intact. The "key: value"
+// lines written alongside it are a readable rendering for humans and agents
+// running bd show. Those lines can be hand-edited (bd edit), so a line that no
+// longer matches what the block renders is taken as the newer value; the next
+// write then regenerates both from the merged fields. Markdown renderers hide
+// the comment, and encoding/json escapes '>' inside strings, so values can't
+// end the comment.
+const (
+	fieldBlockPrefix = "<!-- gt:fields "
+	fieldBlockSuffix = " -->"
+)
+
+// splitFieldBlock separates a description into its prose (with the block line
+// removed) and the decoded block. A malformed block is dropped so callers fall
+// back to the legacy "key: value" lines.
+func splitFieldBlock(description string) (string, map[FieldKind]json.RawMessage) {
+	if !strings.Contains(description, fieldBlockPrefix) {
+		return description, nil
+	}
+
+	var block map[FieldKind]json.RawMessage
+	var kept []string
+	for _, line := range strings.Split(description, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, fieldBlockPrefix) || !strings.HasSuffix(trimmed, fieldBlockSuffix) {
+			kept = append(kept, line)
+			continue
+		}
+		payload := strings.TrimSuffix(strings.TrimPrefix(trimmed, fieldBlockPrefix), fieldBlockSuffix)
+		var parsed map[FieldKind]json.RawMessage
+		if err := json.Unmarshal([]byte(payload), &parsed); err != nil {
+			continue
+		}
+		if block == nil {
+			block = make(map[FieldKind]json.RawMessage)
+		}
+		for kind, raw := range parsed {
+			block[kind] = raw
+		}
+	}
+
+	return strings.TrimRight(strings.Join(kept, "\n"), " \t\n"), block
+}
+
+// HasFields reports whether a description's field block holds the given kind.
+func HasFields(description string, kind FieldKind) bool {
+	_, block := splitFieldBlock(description)
+	_, ok := block[kind]
+	return ok
+}
+
+// GetFields decodes the given kind from a description's field block into v.
+// Returns false if the block has no (valid) entry for kind.
+func GetFields(description string, kind FieldKind, v any) bool {
+	_, block := splitFieldBlock(description)
+	raw, ok := block[kind]
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(raw, v) == nil
+}
+
+// readFieldBlock decodes the given kind from a description's field block into
+// v, then applies any hand edits made to the readable lines since the block was
+// written: every field whose line differs from the block's own rendering takes
+// the line's value, and a deleted line clears its field. Fields the lines
+// don't carry keep their block values. Returns false if the block has no
+// (valid) entry for kind, leaving v for the caller's line parser.
+func readFieldBlock(description string, kind FieldKind, v any) bool {
+	if !GetFields(description, kind, v) {
+		return false
+	}
+
+	body, _ := splitFieldBlock(description)
+	current := typedFields(&Issue{Description: body}, kind)
+	if current == nil {
+		// No readable lines to compare against
+		return true
+	}
+	written := typedFields(&Issue{Description: renderFieldLines(kind, v)}, kind)
+
+	edited, errE := fieldMap(current)
+	rendered, errR := fieldMap(written)
+	merged, errM := fieldMap(v)
+	if errE != nil || errR != nil || errM != nil {
+		return true
+	}
+	changed := false
+	for key := range unionKeys(edited, rendered) {
+		if reflect.DeepEqual(edited[key], rendered[key]) {
+			continue
+		}
+		changed = true
+		if val, ok := edited[key]; ok {
+			merged[key] = val
+		} else {
+			delete(merged, key)
+		}
+	}
+	if !changed {
+		return true
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return true
+	}
+	target := reflect.ValueOf(v).Elem()
+	saved := reflect.New(target.Type()).Elem()
+	saved.Set(target)
+	target.SetZero()
+	if err := json.Unmarshal(data, v); err != nil {
+		target.Set(saved)
+	}
+	return true
+}
+
+// fieldMap round-trips a field struct through JSON into a map keyed by the
+// fields' JSON names.
+func fieldMap(v any) (map[string]any, error) {
+	m := map[string]any{}
+	if v == nil {
+		return m, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func unionKeys(a, b map[string]any) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+// PutFields stores v as the given kind in a description's field block and
+// returns the new description. A nil v removes the kind; the block itself is
+// removed once it holds no kinds. Prose and other kinds are preserved.
+func PutFields(description string, kind FieldKind, v any) string {
+	body, block := splitFieldBlock(description)
+
+	if v == nil {
+		delete(block, kind)
+	} else {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			// Field structs always marshal; keep the description unchanged
+			// rather than silently dropping the other kinds.
+			return description
+		}
+		if block == nil {
+			block = make(map[FieldKind]json.RawMessage)
+		}
+		block[kind] = raw
+	}
+
+	if len(block) == 0 {
+		return body
+	}
+	encoded, err := json.Marshal(block)
+	if err != nil {
+		return description
+	}
+	line := fieldBlockPrefix + string(encoded) + fieldBlockSuffix
+	if body == "" {
+		return line
+	}
+	return body + "\n\n" + line
+}
+
+// DeleteFields removes the given kind from a description's field block.
+func DeleteFields(description string, kind FieldKind) string {
+	return PutFields(description, kind, nil)
+}
+
+// replaceFieldLines replaces the "key: value" lines whose (lowercased) key is
+// in keys with formatted, placed before the remaining content. Blank lines
+// around the remaining content are trimmed.
+func replaceFieldLines(description string, keys map[string]bool, formatted string) string {
+	var otherLines []string
+	for _, line := range strings.Split(description, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			// Preserve blank lines in content
+			otherLines = append(otherLines, line)
+			continue
+		}
+
+		colonIdx := strings.Index(trimmed, ":")
+		if colonIdx == -1 {
+			otherLines = append(otherLines, line)
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
+		if !keys[key] {
+			otherLines = append(otherLines, line)
+		}
+		// Skip field lines - they'll be replaced
+	}
+
+	// Trim trailing blank lines from other content
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+	// Trim leading blank lines from other content
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[0]) == "" {
+		otherLines = otherLines[1:]
+	}
+
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return formatted + "\n\n" + strings.Join(otherLines, "\n")
+}
+
+// MigrateIssueFields copies an issue's legacy "key: value" fields into the
+// field block, leaving the readable lines in place. Returns the new
+// description and the kinds added; no kinds means nothing to migrate.
+//
+// Which kinds to look for follows the bead's labels: agent, merge-request and
+// role beads carry their own kind, and any other bead may carry attachment or
+// synthesis fields.
+func MigrateIssueFields(issue *Issue) (string, []FieldKind) {
+	if issue == nil || issue.Description == "" {
+		return "", nil
+	}
+
+	var candidates []FieldKind
+	switch {
+	case HasLabel(issue, kindLabels[FieldsAgent]) || issue.Type == "agent":
+		candidates = []FieldKind{FieldsAgent}
+	case HasLabel(issue, kindLabels[FieldsMR]) || issue.Type == "merge-request":
+		candidates = []FieldKind{FieldsMR}
+	case HasLabel(issue, kindLabels[FieldsRoleConfig]):
+		candidates = []FieldKind{FieldsRoleConfig}
+	default:
+		candidates = []FieldKind{FieldsAttachment, FieldsSynthesis}
+	}
+
+	description := issue.Description
+	var added []FieldKind
+	for _, kind := range candidates {
+		if HasFields(description, kind) {
+			continue
+		}
+		fields := typedFields(&Issue{Description: description}, kind)
+		if fields == nil || !worthMigrating(fields) {
+			continue
+		}
+		description = PutFields(description, kind, fields)
+		added = append(added, kind)
+	}
+	return description, added
+}
+
+// worthMigrating filters out matches on keys too generic to identify a field
+// kind by themselves (a lone trace_id or formula line in prose).
+func worthMigrating(fields any) bool {
+	switch f := fields.(type) {
+	case *AttachmentFields:
+		return f.AttachedMolecule != "" || f.AttachedArgs != "" || f.DispatchedBy != "" || f.NoMerge
+	case *SynthesisFields:
+		return f.ReviewID != "" || f.OutputPath != ""
+	}
+	return true
+}
+
+// FieldMigration records the kinds moved into one issue's field block.
+type FieldMigration struct {
+	ID    string
+	Title string
+	Kinds []FieldKind
+}
+
+// MigrateFields adds a field block to every bead (open or closed) whose
+// structured fields are still stored only as "key: value" lines. With dryRun
+// nothing is written. Beads are rewritten one at a time; on error the
+// migrations completed so far are returned with it, and rerunning is safe.
+func (b *Beads) MigrateFields(dryRun bool) ([]FieldMigration, error) {
+	issues, err := b.List(ListOptions{Status: "all", Priority: -1, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	var migrated []FieldMigration
+	for _, issue := range issues {
+		description, kinds := MigrateIssueFields(issue)
+		if len(kinds) == 0 {
+			continue
+		}
+		if !dryRun {
+			if err := b.Update(issue.ID, UpdateOptions{Description: &description}); err != nil {
+				return migrated, fmt.Errorf("migrating %s: %w", issue.ID, err)
+			}
+		}
+		migrated = append(migrated, FieldMigration{ID: issue.ID, Title: issue.Title, Kinds: kinds})
+	}
+	return migrated, nil
+}
@@ -0,0 +1,214 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldBlockKinds(t *testing.T) {
+	desc := PutFields("Handoff notes.", FieldsAttachment, &AttachmentFields{AttachedMolecule: "mol-1"})
+	desc = PutFields(desc, FieldsSynthesis, &SynthesisFields{ReviewID: "r-1"})
+
+	if !HasFields(desc, FieldsAttachment) || !HasFields(desc, FieldsSynthesis) || HasFields(desc, FieldsMR) {
+		t.Fatalf("kinds in %q", desc)
+	}
+	if f := ParseAttachmentFields(&Issue{Description: desc}); f == nil || f.AttachedMolecule != "mol-1" {
+		t.Errorf("attachment = %+v", f)
+	}
+
+	desc = DeleteFields(desc, FieldsAttachment)
+	if HasFields(desc, FieldsAttachment) || !HasFields(desc, FieldsSynthesis) {
+		t.Errorf("after delete: %q", desc)
+	}
+	if desc = DeleteFields(desc, FieldsSynthesis); desc != "Handoff notes." {
+		t.Errorf("empty block not removed: %q", desc)
+	}
+}
+
+func TestMalformedFieldBlockFallsBack(t *testing.T) {
+	desc := "branch: legacy\ntarget: main\n\n<!-- gt:fields {\"mr\":{\"branch\": -->"
+	fields := ParseMRFields(&Issue{Description: desc})
+	if fields == nil || fields.Branch != "legacy" {
+		t.Errorf("ParseMRFields = %+v, want legacy lines", fields)
+	}
+}
+
+func TestFieldBlockEscapesCommentEnd(t *testing.T) {
+	desc := SetAttachmentFields(nil, &AttachmentFields{AttachedArgs: "stop at --> here"})
+	block := desc[strings.Index(desc, fieldBlockPrefix):]
+	if strings.Count(block, "-->") != 1 {
+		t.Fatalf("value terminated the comment early: %q", desc)
+	}
+	if f := ParseAttachmentFields(&Issue{Description: desc}); f == nil || f.AttachedArgs != "stop at --> here" {
+		t.Errorf("round trip = %+v", f)
+	}
+}
+
+func TestAgentAndRoleConfigFieldBlock(t *testing.T) {
+	desc := FormatAgentDescription("toast", &AgentFields{RoleType: "polecat", Rig: "gastown", AgentState: "working"})
+	// Readable lines stay for desc-contains queries and bd show
+	if !strings.Contains(desc, "role_type: polecat\nrig: gastown\n") {
+		t.Errorf("readable lines missing:\n%s", desc)
+	}
+	got := ParseAgentFields(desc)
+	if got.AgentState != "working" || got.Rig != "gastown" {
+		t.Errorf("ParseAgentFields = %+v", got)
+	}
+
+	role := SetRoleConfig("Role bead.", &RoleConfig{SessionPattern: "gt-{rig}-{role}", PingTimeout: "45s"})
+	cfg := ParseRoleConfig(role)
+	if cfg == nil || cfg.PingTimeout != "45s" || cfg.SessionPattern != "gt-{rig}-{role}" || cfg.EnvVars == nil {
+		t.Errorf("ParseRoleConfig = %+v", cfg)
+	}
+}
+
+func TestHandEditedFieldLines(t *testing.T) {
+	issue := &Issue{Description: SetMRFields(&Issue{Description: "Merge Nux's work."}, &MRFields{
+		Branch:     "polecat/nux",
+		Target:     "main",
+		Worker:     "nux",
+		RetryCount: 2,
+		ConvoyID:   "hq-cv-1",
+	})}
+
+	// A human retargets the MR, resets the retry count and drops the convoy
+	// line in bd edit; the block still holds the old values
+	edited := strings.Replace(issue.Description, "target: main", "target: integration/epic", 1)
+	edited = strings.Replace(edited, "retry_count: 2\n", "", 1)
+	edited = strings.Replace(edited, "convoy_id: hq-cv-1\n", "", 1)
+	issue.Description = edited
+
+	got := ParseMRFields(issue)
+	if got == nil {
+		t.Fatal("ParseMRFields = nil")
+	}
+	if got.Target != "integration/epic" || got.RetryCount != 0 || got.ConvoyID != "" {
+		t.Errorf("hand edits ignored: %+v", got)
+	}
+	if got.Branch != "polecat/nux" || got.Worker != "nux" {
+		t.Errorf("unedited fields lost: %+v", got)
+	}
+
+	// The next write keeps the edit in both the lines and the block
+	got.MergeCommit = "abc123"
+	issue.Description = SetMRFields(issue, got)
+	if !strings.Contains(issue.Description, "target: integration/epic") {
+		t.Errorf("write reverted the edited line:\n%s", issue.Description)
+	}
+	var block MRFields
+	if !GetFields(issue.Description, FieldsMR, &block) || block.Target != "integration/epic" || block.RetryCount != 0 {
+		t.Errorf("block = %+v, want the edited values", block)
+	}
+	if strings.Count(issue.Description, fieldBlockPrefix) != 1 || !strings.Contains(issue.Description, "Merge Nux's work.") {
+		t.Errorf("rewritten description:\n%s", issue.Description)
+	}
+
+	// Fields with no readable line keep their block values
+	role := SetRoleConfig("Role bead.", &RoleConfig{SessionPattern: "gt-{rig}-{role}", PingTimeout: "45s"})
+	role = strings.Replace(role, "session_pattern: gt-{rig}-{role}", "session_pattern: gt-{role}", 1)
+	if cfg := ParseRoleConfig(role); cfg == nil || cfg.SessionPattern != "gt-{role}" || cfg.PingTimeout != "45s" {
+		t.Errorf("ParseRoleConfig = %+v", cfg)
+	}
+}
+
+func TestMigrateIssueFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		issue     *Issue
+		wantKinds string
+	}{
+		{
+			name: "legacy MR with placeholders",
+			issue: &Issue{
+				Labels:      []string{"gt:merge-request"},
+				Description: "branch: polecat/Nux/gt-xyz\ntarget: main\nretry_count: 0\nlast_conflict_sha: null",
+			},
+			wantKinds: "mr",
+		},
+		{
+			name:      "agent",
+			issue:     &Issue{Type: "agent", Description: "toast\n\nrole_type: polecat\nrig: gastown"},
+			wantKinds: "agent",
+		},
+		{
+			name:      "hooked work bead",
+			issue:     &Issue{Description: "attached_molecule: mol-1\ndispatched_by: mayor/\n\nDo the thing."},
+			wantKinds: "attachment",
+		},
+		{
+			name:  "already migrated",
+			issue: &Issue{Labels: []string{"gt:merge-request"}, Description: SetMRFields(nil, &MRFields{Branch: "b"})},
+		},
+		{
+			name:  "generic keys alone",
+			issue: &Issue{Description: "trace_id: abc\nformula: mol-x\n\nProse."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, kinds := MigrateIssueFields(tt.issue)
+			var names []string
+			for _, k := range kinds {
+				names = append(names, string(k))
+			}
+			if got := strings.Join(names, ","); got != tt.wantKinds {
+				t.Fatalf("kinds = %q, want %q", got, tt.wantKinds)
+			}
+			if len(kinds) == 0 {
+				return
+			}
+			if !strings.HasPrefix(desc, tt.issue.Description) {
+				t.Errorf("legacy lines not preserved:\n%s", desc)
+			}
+			if again, more := MigrateIssueFields(&Issue{Labels: tt.issue.Labels, Type: tt.issue.Type, Description: desc}); len(more) != 0 || again != desc {
+				t.Errorf("second migration changed kinds %v", more)
+			}
+		})
+	}
+
+	mr := &Issue{Labels: []string{"gt:merge-request"}, Description: tests[0].issue.Description}
+	desc, _ := MigrateIssueFields(mr)
+	if f := ParseMRFields(&Issue{Description: desc}); f.LastConflictSHA != "" || f.Branch != "polecat/Nux/gt-xyz" {
+		t.Errorf("migrated MR fields = %+v", f)
+	}
+}
+
+func TestFieldFilter(t *testing.T) {
+	values := map[string]any{
+		"branch":      "polecat/Nux/gt-xyz",
+		"target":      "main",
+		"retry_count": float64(3),
+		"no_merge":    true,
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"retry_count>2", true},
+		{"retry_count>=3", true},
+		{"retry_count<3", false},
+		{"retry_count!=3", false},
+		{"target=main", true},
+		{"target != main", false},
+		{"branch~polecat/", true},
+		{"no_merge=true", true},
+		{"merge_commit=", true}, // unset compares as ""
+		{"conflict_count<1", true},
+		{"convoy_created_at<2026-01-01", true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFieldFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseFieldFilter(%q): %v", tt.expr, err)
+		}
+		if got := f.Match(values); got != tt.want {
+			t.Errorf("%s Match = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"retry_count", ">2", ""} {
+		if _, err := ParseFieldFilter(bad); err == nil {
+			t.Errorf("ParseFieldFilter(%q) succeeded", bad)
+		}
+	}
+}
@@ -15,8 +15,14 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 )
 
-// Filename is the checkpoint file name within the polecat directory.
-const Filename = ".polecat-checkpoint.json"
+// Filename is the checkpoint file within the polecat directory. It lives
+// under .runtime/, which worktrees gitignore, so a checkpoint never makes
+// the worktree look dirty or gets committed.
+const Filename = ".runtime/checkpoint.json"
+
+// legacyFilename is where checkpoints were written before they moved under
+// .runtime/. Read still falls back to it and Remove cleans it up.
+const legacyFilename = ".polecat-checkpoint.json"
 
 // Checkpoint represents a session recovery checkpoint.
 type Checkpoint struct {
@@ -49,19 +55,24 @@ type Checkpoint struct {
 
 	// Notes contains optional context from the session.
 	Notes string `json:"notes,omitempty"`
+
+	// Auto is set when the daemon captured the checkpoint rather than the
+	// session itself.
+	Auto bool `json:"auto,omitempty"`
 }
 
 // Path returns the checkpoint file path for a given polecat directory.
 func Path(polecatDir string) string {
-	return filepath.Join(polecatDir, Filename)
+	return filepath.Join(polecatDir, filepath.FromSlash(Filename))
 }
 
 // Read loads a checkpoint from the polecat directory.
 // Returns nil, nil if no checkpoint exists.
 func Read(polecatDir string) (*Checkpoint, error) {
-	path := Path(polecatDir)
-
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted polecatDir
+	data, err := os.ReadFile(Path(polecatDir)) //nolint:gosec // G304: path is constructed from trusted polecatDir
+	if os.IsNotExist(err) {
+		data, err = os.ReadFile(filepath.Join(polecatDir, legacyFilename)) //nolint:gosec // G304: as above
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -98,6 +109,9 @@ func Write(polecatDir string, cp *Checkpoint) error {
 	}
 
 	path := Path(polecatDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
 	if err := os.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing checkpoint: %w", err)
 	}
@@ -107,9 +121,10 @@ func Write(polecatDir string, cp *Checkpoint) error {
 
 // Remove deletes the checkpoint file.
 func Remove(polecatDir string) error {
-	path := Path(polecatDir)
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("removing checkpoint: %w", err)
+	for _, path := range []string{Path(polecatDir), filepath.Join(polecatDir, legacyFilename)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing checkpoint: %w", err)
+		}
 	}
 	return nil
 }
@@ -125,12 +140,13 @@ func Capture(polecatDir string) (*Checkpoint, error) {
 	cmd.Dir = polecatDir
 	output, err := cmd.Output()
 	if err == nil {
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
 		for _, line := range lines {
 			if len(line) > 3 {
 				// Format: XY filename
 				file := strings.TrimSpace(line[3:])
-				if file != "" {
+				// gt's own runtime state (the checkpoint included) isn't work
+				if file != "" && file != legacyFilename && !strings.HasPrefix(file, ".runtime/") {
 					cp.ModifiedFiles = append(cp.ModifiedFiles, file)
 				}
 			}
@@ -122,6 +122,9 @@ func TestWritePreservesTimestamp(t *testing.T) {
 func TestReadCorruptedJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	path := Path(tmpDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
 
 	// Write invalid JSON
 	if err := os.WriteFile(path, []byte("not valid json{"), 0600); err != nil {
@@ -396,3 +399,22 @@ func TestCheckpointJSONRoundtrip(t *testing.T) {
 		t.Errorf("ModifiedFiles length mismatch")
 	}
 }
+
+func TestReadLegacyLocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	legacy := filepath.Join(tmpDir, legacyFilename)
+	if err := os.WriteFile(legacy, []byte(`{"hooked_bead": "gt-old"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := Read(tmpDir)
+	if err != nil || cp == nil || cp.HookedBead != "gt-old" {
+		t.Fatalf("Read() = %+v, %v; want the legacy checkpoint", cp, err)
+	}
+	if err := Remove(tmpDir); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy checkpoint still present after Remove: %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package checkpoint
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxRecoveryAge is how old a checkpoint may be and still be used to resume
+// a session. Older checkpoints describe work that has likely moved on.
+const MaxRecoveryAge = 24 * time.Hour
+
+// maxPromptFiles caps the modified files listed in a recovery prompt.
+const maxPromptFiles = 20
+
+// RecoveryPrompt builds the instructions given to a session restarted after
+// a crash or account rotation: where the previous session was, and what to
+// check before resuming.
+func RecoveryPrompt(cp *Checkpoint) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Your previous session was interrupted. It left a checkpoint %s ago:\n",
+		cp.Age().Round(time.Minute))
+	if cp.HookedBead != "" {
+		fmt.Fprintf(&b, "- Hooked work: %s\n", cp.HookedBead)
+	}
+	if cp.MoleculeID != "" {
+		fmt.Fprintf(&b, "- Molecule: %s\n", cp.MoleculeID)
+	}
+	if cp.CurrentStep != "" {
+		step := cp.CurrentStep
+		if cp.StepTitle != "" {
+			step += " (" + cp.StepTitle + ")"
+		}
+		fmt.Fprintf(&b, "- Step in progress: %s\n", step)
+	}
+	if cp.Branch != "" {
+		fmt.Fprintf(&b, "- Branch: %s\n", cp.Branch)
+	}
+	if cp.LastCommit != "" {
+		fmt.Fprintf(&b, "- Last commit: %s\n", shortSHA(cp.LastCommit))
+	}
+	if len(cp.ModifiedFiles) > 0 {
+		fmt.Fprintf(&b, "- Uncommitted files: %s\n", listFiles(cp.ModifiedFiles))
+	}
+	if cp.Notes != "" {
+		fmt.Fprintf(&b, "- Notes: %s\n", cp.Notes)
+	}
+
+	b.WriteString("\nRun `gt checkpoint diff` to see what changed since, then `gt hook` " +
+		"and resume from the step in progress. Review uncommitted files before " +
+		"continuing - the crash may have left them half-edited.")
+	return b.String()
+}
+
+// Refresh captures the current git state of a worktree into its checkpoint,
+// keeping the molecule, step, hooked bead and notes recorded by the session.
+// hookedBead overrides the recorded hook when non-empty. Used for periodic
+// automatic checkpoints, which can't see the session's molecule progress.
+//
+// The molecule, step and notes describe progress on the recorded hook, so
+// they are dropped when hookedBead names different work.
+func Refresh(workDir, hookedBead string) (*Checkpoint, error) {
+	prev, err := Read(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	cp, err := Capture(workDir)
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		cp.HookedBead = prev.HookedBead
+		cp.SessionID = prev.SessionID
+		if hookedBead == "" || hookedBead == prev.HookedBead {
+			cp.MoleculeID = prev.MoleculeID
+			cp.CurrentStep = prev.CurrentStep
+			cp.StepTitle = prev.StepTitle
+			cp.Notes = prev.Notes
+		}
+	}
+	if hookedBead != "" {
+		cp.HookedBead = hookedBead
+	}
+	cp.Auto = true
+
+	if err := Write(workDir, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Delta describes what changed in a worktree since a checkpoint.
+type Delta struct {
+	// Branch is the current branch; BranchChanged is set when it differs
+	// from the checkpoint's.
+	Branch        string `json:"branch,omitempty"`
+	BranchChanged bool   `json:"branch_changed,omitempty"`
+
+	// Commits are the one-line summaries of commits made since the checkpoint.
+	Commits []string `json:"commits,omitempty"`
+
+	// CommittedFiles are files changed by those commits ("M path" style).
+	CommittedFiles []string `json:"committed_files,omitempty"`
+
+	// NewlyModified are uncommitted files that weren't dirty at the checkpoint.
+	NewlyModified []string `json:"newly_modified,omitempty"`
+
+	// StillModified were dirty at the checkpoint and still are.
+	StillModified []string `json:"still_modified,omitempty"`
+
+	// NoLongerModified were dirty at the checkpoint and are now clean
+	// (committed or reverted).
+	NoLongerModified []string `json:"no_longer_modified,omitempty"`
+}
+
+// Empty reports whether nothing changed since the checkpoint.
+func (d *Delta) Empty() bool {
+	return !d.BranchChanged && len(d.Commits) == 0 && len(d.NewlyModified) == 0 &&
+		len(d.NoLongerModified) == 0
+}
+
+// Diff compares a worktree's current state with a checkpoint.
+func Diff(workDir string, cp *Checkpoint) (*Delta, error) {
+	now, err := Capture(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Delta{Branch: now.Branch}
+	d.BranchChanged = cp.Branch != "" && now.Branch != cp.Branch
+
+	if cp.LastCommit != "" && now.LastCommit != cp.LastCommit {
+		rangeSpec := cp.LastCommit + "..HEAD"
+		if out, err := gitOutput(workDir, "log", "--oneline", "--no-decorate", rangeSpec); err == nil {
+			d.Commits = splitLines(out)
+		}
+		if out, err := gitOutput(workDir, "diff", "--name-status", cp.LastCommit, "HEAD"); err == nil {
+			for _, line := range splitLines(out) {
+				d.CommittedFiles = append(d.CommittedFiles, strings.Join(strings.Fields(line), " "))
+			}
+		}
+	}
+
+	before := make(map[string]bool, len(cp.ModifiedFiles))
+	for _, f := range cp.ModifiedFiles {
+		before[f] = true
+	}
+	after := make(map[string]bool, len(now.ModifiedFiles))
+	for _, f := range now.ModifiedFiles {
+		after[f] = true
+		if before[f] {
+			d.StillModified = append(d.StillModified, f)
+		} else {
+			d.NewlyModified = append(d.NewlyModified, f)
+		}
+	}
+	for _, f := range cp.ModifiedFiles {
+		if !after[f] {
+			d.NoLongerModified = append(d.NoLongerModified, f)
+		}
+	}
+	sort.Strings(d.StillModified)
+	sort.Strings(d.NewlyModified)
+	sort.Strings(d.NoLongerModified)
+
+	return d, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func shortSHA(sha string) string {
+	return sha[:min(12, len(sha))]
+}
+
+// listFiles formats a file list for a prompt, truncating long lists.
+func listFiles(files []string) string {
+	if len(files) <= maxPromptFiles {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(files[:maxPromptFiles], ", "), len(files)-maxPromptFiles)
+}
@@ -0,0 +1,199 @@
+package checkpoint
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// initRepo creates a git repo with one commit of a.txt.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run(t, dir, "init", "-q", "-b", "main")
+	writeFile(t, dir, "a.txt", "a\n")
+	run(t, dir, "add", "a.txt")
+	run(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecoveryPrompt(t *testing.T) {
+	cp := &Checkpoint{
+		MoleculeID:    "mol-123",
+		CurrentStep:   "step-2",
+		StepTitle:     "Write tests",
+		HookedBead:    "gt-abc",
+		Branch:        "polecat/Toast",
+		LastCommit:    "0123456789abcdef0123",
+		ModifiedFiles: []string{"main.go"},
+		Notes:         "halfway through",
+		Timestamp:     time.Now().Add(-30 * time.Minute),
+	}
+
+	prompt := RecoveryPrompt(cp)
+	for _, want := range []string{
+		"gt-abc", "mol-123", "step-2 (Write tests)", "polecat/Toast",
+		"0123456789ab", "main.go", "halfway through", "gt checkpoint diff",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("RecoveryPrompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "0123456789abc") {
+		t.Error("RecoveryPrompt should shorten the commit SHA")
+	}
+}
+
+func TestListFilesTruncates(t *testing.T) {
+	files := make([]string, maxPromptFiles+5)
+	for i := range files {
+		files[i] = "f"
+	}
+	if got := listFiles(files); !strings.HasSuffix(got, "and 5 more") {
+		t.Errorf("listFiles = %q, want truncation suffix", got)
+	}
+}
+
+func TestRefreshKeepsSessionContext(t *testing.T) {
+	dir := initRepo(t)
+
+	prev := &Checkpoint{
+		MoleculeID:  "mol-123",
+		CurrentStep: "step-2",
+		HookedBead:  "gt-abc",
+		Notes:       "note",
+	}
+	if err := Write(dir, prev); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "a.txt", "changed\n")
+
+	cp, err := Refresh(dir, "gt-abc")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if cp.MoleculeID != "mol-123" || cp.CurrentStep != "step-2" || cp.Notes != "note" {
+		t.Errorf("Refresh lost session context: %+v", cp)
+	}
+	if cp.HookedBead != "gt-abc" {
+		t.Errorf("HookedBead = %q, want gt-abc", cp.HookedBead)
+	}
+	if !cp.Auto {
+		t.Error("Auto = false, want true")
+	}
+	if len(cp.ModifiedFiles) != 1 || cp.ModifiedFiles[0] != "a.txt" {
+		t.Errorf("ModifiedFiles = %v, want [a.txt] (checkpoint file excluded)", cp.ModifiedFiles)
+	}
+
+	read, err := Read(dir)
+	if err != nil || read == nil || read.MoleculeID != "mol-123" {
+		t.Errorf("Read after Refresh = %+v, %v", read, err)
+	}
+
+	// No hook known: the recorded hook and its context are kept
+	cp, err = Refresh(dir, "")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if cp.HookedBead != "gt-abc" || cp.MoleculeID != "mol-123" {
+		t.Errorf("Refresh without hook = %+v", cp)
+	}
+}
+
+func TestRefreshDropsContextOfOldHook(t *testing.T) {
+	dir := initRepo(t)
+
+	prev := &Checkpoint{
+		MoleculeID:  "mol-123",
+		CurrentStep: "step-2",
+		StepTitle:   "Write tests",
+		HookedBead:  "gt-old",
+		Notes:       "note",
+		SessionID:   "sess-1",
+	}
+	if err := Write(dir, prev); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := Refresh(dir, "gt-new")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if cp.HookedBead != "gt-new" {
+		t.Errorf("HookedBead = %q, want gt-new", cp.HookedBead)
+	}
+	if cp.MoleculeID != "" || cp.CurrentStep != "" || cp.StepTitle != "" || cp.Notes != "" {
+		t.Errorf("Refresh kept gt-old's progress for gt-new: %+v", cp)
+	}
+	if cp.SessionID != "sess-1" {
+		t.Errorf("SessionID = %q, want sess-1", cp.SessionID)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, dir, "a.txt", "dirty\n")
+
+	cp, err := Capture(dir)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+
+	// Nothing changed yet
+	d, err := Diff(dir, cp)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if !d.Empty() || len(d.StillModified) != 1 {
+		t.Errorf("Diff right after capture = %+v, want empty with a.txt still modified", d)
+	}
+
+	// Commit a.txt, dirty a new file
+	run(t, dir, "commit", "-q", "-am", "fix a")
+	writeFile(t, dir, "b.txt", "b\n")
+
+	d, err = Diff(dir, cp)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if d.Empty() {
+		t.Fatal("Diff after commit is empty")
+	}
+	if len(d.Commits) != 1 || !strings.Contains(d.Commits[0], "fix a") {
+		t.Errorf("Commits = %v, want [.. fix a]", d.Commits)
+	}
+	if len(d.CommittedFiles) != 1 || d.CommittedFiles[0] != "M a.txt" {
+		t.Errorf("CommittedFiles = %v, want [M a.txt]", d.CommittedFiles)
+	}
+	if len(d.NewlyModified) != 1 || d.NewlyModified[0] != "b.txt" {
+		t.Errorf("NewlyModified = %v, want [b.txt]", d.NewlyModified)
+	}
+	if len(d.NoLongerModified) != 1 || d.NoLongerModified[0] != "a.txt" {
+		t.Errorf("NoLongerModified = %v, want [a.txt]", d.NoLongerModified)
+	}
+	if d.BranchChanged {
+		t.Error("BranchChanged = true, want false")
+	}
+}
@@ -0,0 +1,295 @@
+// Package ci asks an external CI system for its verdict on a merge
+// candidate when a rig gates its merge queue on CI instead of (or as well
+// as) a local test command.
+//
+// The refinery squash-merges an MR onto its target, pushes the result to a
+// candidate ref, and polls a Provider until CI passes or fails it. Providers
+// differ only in how they learn the verdict: an HTTP status endpoint, a
+// webhook followed by that endpoint, or a status file.
+package ci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Verdict states.
+const (
+	StatePending = "pending"
+	StatePassed  = "passed"
+	StateFailed  = "failed"
+)
+
+// Defaults for unset config.
+const (
+	DefaultRefPrefix = "refs/heads/gt-ci/"
+	DefaultTimeout   = time.Hour
+)
+
+// requestTimeout bounds each HTTP call.
+const requestTimeout = 30 * time.Second
+
+// Candidate is a merge result under test.
+type Candidate struct {
+	MR     string // MR bead ID
+	Branch string // MR source branch
+	Target string // Branch the candidate would land on
+	Ref    string // Ref the candidate was pushed to
+	SHA    string // Candidate commit
+}
+
+// Status is CI's verdict on a candidate.
+type Status struct {
+	State  string // StatePending, StatePassed or StateFailed
+	Detail string // Provider's description, if any
+	URL    string // Link to the CI run, if any
+}
+
+// Provider is an external CI system.
+type Provider interface {
+	// Name returns the provider name ("http", "webhook" or "status-file").
+	Name() string
+
+	// Submit announces a freshly pushed candidate. Providers that CI
+	// triggers on the push itself do nothing.
+	Submit(ctx context.Context, c Candidate) error
+
+	// Status reports CI's verdict on c. A missing verdict is pending.
+	Status(ctx context.Context, c Candidate) (*Status, error)
+}
+
+// New returns the Provider for cfg. Relative status files are resolved
+// against rigPath.
+func New(cfg *config.CIConfig, rigPath string) (Provider, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no CI gate configured")
+	}
+	switch cfg.Provider {
+	case config.CIProviderHTTP:
+		if cfg.StatusURL == "" {
+			return nil, fmt.Errorf("ci.status_url not set")
+		}
+		return &httpStatus{cfg: cfg, client: &http.Client{Timeout: requestTimeout}}, nil
+	case config.CIProviderWebhook:
+		if cfg.WebhookURL == "" || cfg.StatusURL == "" {
+			return nil, fmt.Errorf("ci.webhook_url and ci.status_url must both be set")
+		}
+		return &webhook{httpStatus{cfg: cfg, client: &http.Client{Timeout: requestTimeout}}}, nil
+	case config.CIProviderStatusFile:
+		if cfg.StatusFile == "" {
+			return nil, fmt.Errorf("ci.status_file not set")
+		}
+		return &statusFile{cfg: cfg, rigPath: rigPath}, nil
+	default:
+		return nil, fmt.Errorf("unknown CI provider %q", cfg.Provider)
+	}
+}
+
+// RefFor returns the candidate ref for an MR under cfg.
+func RefFor(cfg *config.CIConfig, mrID string) string {
+	prefix := DefaultRefPrefix
+	if cfg != nil && cfg.RefPrefix != "" {
+		prefix = cfg.RefPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix + mrID
+}
+
+// Timeout returns how long to wait for a verdict under cfg.
+func Timeout(cfg *config.CIConfig) time.Duration {
+	if cfg != nil && cfg.Timeout != "" {
+		if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultTimeout
+}
+
+// expand fills the candidate placeholders in s, passing each value
+// through escape.
+func expand(s string, c Candidate, escape func(string) string) string {
+	return strings.NewReplacer(
+		"{mr}", escape(c.MR),
+		"{branch}", escape(c.Branch),
+		"{target}", escape(c.Target),
+		"{ref}", escape(c.Ref),
+		"{sha}", escape(c.SHA),
+	).Replace(s)
+}
+
+// httpStatus polls a JSON status endpoint.
+type httpStatus struct {
+	cfg    *config.CIConfig
+	client *http.Client
+}
+
+func (h *httpStatus) Name() string { return config.CIProviderHTTP }
+
+func (h *httpStatus) Submit(context.Context, Candidate) error { return nil }
+
+func (h *httpStatus) Status(ctx context.Context, c Candidate) (*Status, error) {
+	u := expand(h.cfg.StatusURL, c, url.PathEscape)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	h.authorize(req)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", u, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// CI hasn't seen the candidate yet
+		return &Status{State: StatePending, Detail: "not reported yet"}, nil
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	return parseJSONStatus(data, h.cfg.StatusField)
+}
+
+// authorize adds the bearer token from TokenEnv, if set.
+func (h *httpStatus) authorize(req *http.Request) {
+	if h.cfg.TokenEnv == "" {
+		return
+	}
+	if token := os.Getenv(h.cfg.TokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// webhook announces candidates to WebhookURL, then polls like httpStatus.
+type webhook struct {
+	httpStatus
+}
+
+func (w *webhook) Name() string { return config.CIProviderWebhook }
+
+func (w *webhook) Submit(ctx context.Context, c Candidate) error {
+	body, err := json.Marshal(map[string]string{
+		"mr":     c.MR,
+		"branch": c.Branch,
+		"target": c.Target,
+		"ref":    c.Ref,
+		"sha":    c.SHA,
+	})
+	if err != nil {
+		return err
+	}
+	u := expand(w.cfg.WebhookURL, c, url.PathEscape)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	w.authorize(req)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("POST %s: %s", u, resp.Status)
+	}
+	return nil
+}
+
+// statusFile polls a file written by CI.
+type statusFile struct {
+	cfg     *config.CIConfig
+	rigPath string
+}
+
+func (s *statusFile) Name() string { return config.CIProviderStatusFile }
+
+func (s *statusFile) Submit(context.Context, Candidate) error { return nil }
+
+func (s *statusFile) Status(_ context.Context, c Candidate) (*Status, error) {
+	path := expand(s.cfg.StatusFile, c, func(v string) string { return v })
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.rigPath, path)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted rig config
+	if os.IsNotExist(err) {
+		return &Status{State: StatePending, Detail: "not reported yet"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return parseJSONStatus(trimmed, s.cfg.StatusField)
+	}
+	word, detail, _ := strings.Cut(string(trimmed), "\n")
+	return &Status{State: normalize(word), Detail: strings.TrimSpace(detail)}, nil
+}
+
+// parseJSONStatus reads a verdict from a JSON object. The status comes from
+// field ("state", then "status", if empty); the detail from "description"
+// or "message"; the link from "url", "target_url" or "web_url".
+func parseJSONStatus(data []byte, field string) (*Status, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("parsing CI status: %w", err)
+	}
+
+	fields := []string{"state", "status"}
+	if field != "" {
+		fields = []string{field}
+	}
+	raw := firstString(obj, fields...)
+	if raw == "" {
+		return nil, fmt.Errorf("CI status has no %s field", strings.Join(fields, " or "))
+	}
+	return &Status{
+		State:  normalize(raw),
+		Detail: firstString(obj, "description", "message"),
+		URL:    firstString(obj, "url", "target_url", "web_url"),
+	}, nil
+}
+
+func firstString(obj map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := obj[k].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalize maps the status words CI systems use onto the verdict states.
+// Anything unrecognized (queued, running, ...) is pending.
+func normalize(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "success", "succeeded", "successful", "passed", "pass", "ok", "green":
+		return StatePassed
+	case "failure", "failed", "fail", "error", "errored", "red",
+		"canceled", "cancelled", "timed_out", "timedout":
+		return StateFailed
+	default:
+		return StatePending
+	}
+}
@@ -0,0 +1,193 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var testCandidate = Candidate{
+	MR:     "gt-mr-1",
+	Branch: "polecat/nux",
+	Target: "main",
+	Ref:    "refs/heads/gt-ci/gt-mr-1",
+	SHA:    "abc123",
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"success":     StatePassed,
+		"PASSED":      StatePassed,
+		" ok ":        StatePassed,
+		"failure":     StateFailed,
+		"cancelled":   StateFailed,
+		"timed_out":   StateFailed,
+		"running":     StatePending,
+		"queued":      StatePending,
+		"":            StatePending,
+		"in_progress": StatePending,
+	}
+	for in, want := range tests {
+		if got := normalize(in); got != want {
+			t.Errorf("normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.EscapedPath(), r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/status/abc123":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"status":      "failed",
+				"description": "3 tests failed",
+				"target_url":  "https://ci.example/run/7",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("TEST_CI_TOKEN", "s3cret")
+
+	p, err := New(&config.CIConfig{
+		Provider:  config.CIProviderHTTP,
+		StatusURL: srv.URL + "/status/{sha}",
+		TokenEnv:  "TEST_CI_TOKEN",
+	}, "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	st, err := p.Status(context.Background(), testCandidate)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.State != StateFailed || st.Detail != "3 tests failed" || st.URL != "https://ci.example/run/7" {
+		t.Errorf("Status = %+v", st)
+	}
+	if gotAuth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+
+	// Unknown candidates haven't been reported yet
+	st, err = p.Status(context.Background(), Candidate{SHA: "def456", Branch: "polecat/nux"})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.State != StatePending {
+		t.Errorf("unreported Status = %+v, want pending", st)
+	}
+	if gotPath != "/status/def456" {
+		t.Errorf("requested %q", gotPath)
+	}
+}
+
+func TestHTTPStatus_CustomField(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state": "running", "result": "success"}`))
+	}))
+	defer srv.Close()
+
+	p, err := New(&config.CIConfig{Provider: config.CIProviderHTTP, StatusURL: srv.URL, StatusField: "result"}, "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	st, err := p.Status(context.Background(), testCandidate)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.State != StatePassed {
+		t.Errorf("State = %q, want passed (from the result field)", st.State)
+	}
+}
+
+func TestWebhookSubmit(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/hook" {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		_, _ = w.Write([]byte(`{"state": "pending"}`))
+	}))
+	defer srv.Close()
+
+	p, err := New(&config.CIConfig{
+		Provider:   config.CIProviderWebhook,
+		WebhookURL: srv.URL + "/hook",
+		StatusURL:  srv.URL + "/status/{mr}",
+	}, "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := p.Submit(context.Background(), testCandidate); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if got["mr"] != "gt-mr-1" || got["sha"] != "abc123" || got["ref"] != testCandidate.Ref || got["target"] != "main" {
+		t.Errorf("webhook payload = %v", got)
+	}
+	st, err := p.Status(context.Background(), testCandidate)
+	if err != nil || st.State != StatePending {
+		t.Errorf("Status = %+v, %v", st, err)
+	}
+}
+
+func TestStatusFile(t *testing.T) {
+	rigPath := t.TempDir()
+	p, err := New(&config.CIConfig{Provider: config.CIProviderStatusFile, StatusFile: "ci/{mr}.status"}, rigPath)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := context.Background()
+
+	st, err := p.Status(ctx, testCandidate)
+	if err != nil || st.State != StatePending {
+		t.Fatalf("missing file: Status = %+v, %v", st, err)
+	}
+
+	path := filepath.Join(rigPath, "ci", "gt-mr-1.status")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("failed\nlint: 2 errors\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	st, err = p.Status(ctx, testCandidate)
+	if err != nil || st.State != StateFailed || st.Detail != "lint: 2 errors" {
+		t.Errorf("word file: Status = %+v, %v", st, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"state": "success", "url": "https://ci.example/9"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	st, err = p.Status(ctx, testCandidate)
+	if err != nil || st.State != StatePassed || st.URL != "https://ci.example/9" {
+		t.Errorf("JSON file: Status = %+v, %v", st, err)
+	}
+}
+
+func TestRefForAndTimeout(t *testing.T) {
+	if got := RefFor(nil, "gt-mr-1"); got != "refs/heads/gt-ci/gt-mr-1" {
+		t.Errorf("RefFor(default) = %q", got)
+	}
+	if got := RefFor(&config.CIConfig{RefPrefix: "refs/ci"}, "gt-mr-1"); got != "refs/ci/gt-mr-1" {
+		t.Errorf("RefFor(custom) = %q", got)
+	}
+	if got := Timeout(nil); got != DefaultTimeout {
+		t.Errorf("Timeout(default) = %v", got)
+	}
+	if got := Timeout(&config.CIConfig{Timeout: "45m"}); got != 45*time.Minute {
+		t.Errorf("Timeout(45m) = %v", got)
+	}
+}
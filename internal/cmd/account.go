@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -30,11 +32,16 @@ var accountCmd = &cobra.Command{
 This enables switching between accounts (e.g., personal vs work) with
 easy account selection per spawn or globally.
 
+When an account hits its usage limit, the daemon marks it as cooling down
+until the limit resets and moves affected polecats to the next available
+account. New polecats skip cooling accounts unless one is requested
+explicitly with --account or GT_ACCOUNT.
+
 Commands:
   gt account list              List registered accounts
   gt account add <handle>      Add a new account
   gt account default <handle>  Set the default account
-  gt account status            Show current account info`,
+  gt account status            Show current account and quota state`,
 }
 
 var accountListCmd = &cobra.Command{
@@ -251,15 +258,19 @@ func runAccountDefault(cmd *cobra.Command, args []string) error {
 
 var accountStatusCmd = &cobra.Command{
 	Use:   "status",
-	Short: "Show current account info",
+	Short: "Show current account and quota state",
 	Long: `Show which Claude Code account would be used for new sessions.
 
 Displays the currently resolved account based on:
 1. GT_ACCOUNT environment variable (highest priority)
 2. Default account from config
 
+Also shows the quota state of every account: available, or cooling down
+after a usage limit until the time it resets.
+
 Examples:
   gt account status           # Show current account
+  gt account status --json    # JSON output
   GT_ACCOUNT=work gt account status  # Show with env override`,
 	RunE: runAccountStatus,
 }
@@ -283,6 +294,23 @@ Examples:
 	RunE: runAccountSwitch,
 }
 
+// AccountQuotaItem represents an account's quota state in status output.
+type AccountQuotaItem struct {
+	Handle    string     `json:"handle"`
+	Available bool       `json:"available"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	Session   string     `json:"session,omitempty"`
+}
+
+// AccountStatus is the JSON output of gt account status.
+type AccountStatus struct {
+	Current   string             `json:"current"`
+	ConfigDir string             `json:"config_dir"`
+	ViaEnv    bool               `json:"via_env,omitempty"`
+	Quota     []AccountQuotaItem `json:"quota"`
+}
+
 func runAccountStatus(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
@@ -318,6 +346,23 @@ func runAccountStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("account '%s' not found", handle)
 	}
 
+	state, err := quota.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading quota state: %w", err)
+	}
+	quotas := accountQuotaItems(cfg, state, time.Now())
+
+	if accountJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(AccountStatus{
+			Current:   handle,
+			ConfigDir: configDir,
+			ViaEnv:    envAccount != "",
+			Quota:     quotas,
+		})
+	}
+
 	fmt.Printf("%s\n\n", style.Bold.Render("Current Account"))
 	fmt.Printf("Handle:     %s\n", style.Bold.Render(handle))
 	if acct.Email != "" {
@@ -334,9 +379,80 @@ func runAccountStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n%s\n", style.Dim.Render("(default account)"))
 	}
 
+	fmt.Printf("\n%s\n\n", style.Bold.Render("Quota"))
+	for _, q := range quotas {
+		if q.Available {
+			fmt.Printf("  %s  %s\n", q.Handle, style.Success.Render("available"))
+			continue
+		}
+		fmt.Printf("  %s  %s\n", q.Handle,
+			style.Warning.Render("cooling down until "+q.ResetsAt.Local().Format("2006-01-02 15:04")))
+		if q.Reason != "" {
+			detail := q.Reason
+			if q.Session != "" {
+				detail += " (in " + q.Session + ")"
+			}
+			fmt.Printf("    %s\n", style.Dim.Render(detail))
+		}
+	}
+
 	return nil
 }
 
+// accountQuotaItems returns the quota state of every account, sorted by handle.
+func accountQuotaItems(cfg *config.AccountsConfig, state *quota.State, now time.Time) []AccountQuotaItem {
+	handles := make([]string, 0, len(cfg.Accounts))
+	for h := range cfg.Accounts {
+		handles = append(handles, h)
+	}
+	sort.Strings(handles)
+
+	items := make([]AccountQuotaItem, 0, len(handles))
+	for _, h := range handles {
+		item := AccountQuotaItem{Handle: h, Available: true}
+		if a := state.Cooling(h, now); a != nil {
+			resets := a.ResetsAt
+			item.Available = false
+			item.ResetsAt = &resets
+			item.Reason = a.Reason
+			item.Session = a.Session
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// avoidCoolingAccount replaces a resolved default account that is cooling
+// down after a usage limit with the next available one. Accounts chosen
+// explicitly (accountFlag or GT_ACCOUNT) are kept as-is.
+func avoidCoolingAccount(townRoot, accountFlag, configDir, handle string) (string, string) {
+	if handle == "" || accountFlag != "" || os.Getenv("GT_ACCOUNT") != "" {
+		return configDir, handle
+	}
+	cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil {
+		return configDir, handle
+	}
+	state, err := quota.Load(townRoot)
+	if err != nil {
+		return configDir, handle
+	}
+	now := time.Now()
+	cooling := state.Cooling(handle, now)
+	if cooling == nil {
+		return configDir, handle
+	}
+	next := quota.NextAvailable(cfg, state, handle, now)
+	if next == "" {
+		fmt.Printf("%s Account %s is cooling down until %s and no other account is available\n",
+			style.Warning.Render("⚠"), handle, cooling.ResetsAt.Local().Format("15:04"))
+		return configDir, handle
+	}
+	fmt.Printf("Account %s is cooling down until %s; using %s\n",
+		handle, cooling.ResetsAt.Local().Format("15:04"), next)
+	return quota.ExpandHome(cfg.Accounts[next].ConfigDir), next
+}
+
 func runAccountSwitch(cmd *cobra.Command, args []string) error {
 	targetHandle := args[0]
 
@@ -456,6 +572,7 @@ func runAccountSwitch(cmd *cobra.Command, args []string) error {
 func init() {
 	// Add flags
 	accountListCmd.Flags().BoolVar(&accountJSON, "json", false, "Output as JSON")
+	accountStatusCmd.Flags().BoolVar(&accountJSON, "json", false, "Output as JSON")
 
 	accountAddCmd.Flags().StringVar(&accountEmail, "email", "", "Account email address")
 	accountAddCmd.Flags().StringVar(&accountDescription, "desc", "", "Account description")
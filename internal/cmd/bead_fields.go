@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead fields command flags
+var (
+	beadFieldsRig    string
+	beadFieldsDryRun bool
+	beadFieldsStatus string
+	beadFieldsJSON   bool
+)
+
+var beadFieldsCmd = &cobra.Command{
+	Use:   "fields",
+	Short: "Query and migrate structured bead fields",
+	Long: `Query and migrate the structured fields Gas Town stores on beads.
+
+Merge-request, agent, attachment, synthesis and role-config fields live in a
+JSON field block at the end of the bead description:
+
+  <!-- gt:fields {"mr":{"branch":"polecat/Nux/gt-xyz","retry_count":3}} -->
+
+The readable "key: value" lines above it can be edited by hand (bd edit):
+a line that no longer matches the block is read as the newer value, and the
+next write regenerates both the lines and the block from the merged fields.
+Beads written before the field block existed are still read through their
+"key: value" lines until they are migrated.`,
+	RunE: requireSubcommand,
+}
+
+var beadFieldsMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Add field blocks to beads that only have key: value lines",
+	Long: `Rewrite beads whose structured fields are stored only as "key: value"
+description lines, adding the equivalent field block. The lines are left in
+place. Safe to rerun; beads that already have a block are skipped.
+
+Covers town beads and every rig, or one rig with --rig.
+
+Examples:
+  gt bead fields migrate --dry-run     # Show what would change
+  gt bead fields migrate               # Migrate all beads
+  gt bead fields migrate --rig gastown # Migrate one rig`,
+	Args: cobra.NoArgs,
+	RunE: runBeadFieldsMigrate,
+}
+
+var beadFieldsQueryCmd = &cobra.Command{
+	Use:   "query <kind> [filter...]",
+	Short: "List beads whose fields match filters",
+	Long: `List beads whose structured fields match every filter.
+
+Kinds: mr, agent, attachment, synthesis, role_config.
+
+Filters are <key><op><value>, where key is the field's JSON name and op is
+one of =, !=, >, >=, <, <= or ~ (substring). Numeric values compare
+numerically; unset fields compare as 0 or "".
+
+Examples:
+  gt bead fields query mr 'retry_count>2'              # MRs stuck in conflict loops
+  gt bead fields query mr target=main --rig gastown
+  gt bead fields query agent agent_state=stuck
+  gt bead fields query attachment 'attached_molecule~mol-' --status all --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadFieldsQuery,
+}
+
+func init() {
+	beadFieldsMigrateCmd.Flags().StringVar(&beadFieldsRig, "rig", "", "Only migrate this rig's beads")
+	beadFieldsMigrateCmd.Flags().BoolVarP(&beadFieldsDryRun, "dry-run", "n", false, "Show what would be migrated")
+
+	beadFieldsQueryCmd.Flags().StringVar(&beadFieldsRig, "rig", "", "Only query this rig's beads")
+	beadFieldsQueryCmd.Flags().StringVar(&beadFieldsStatus, "status", "open", "Bead status: open, closed, in_progress or all")
+	beadFieldsQueryCmd.Flags().BoolVar(&beadFieldsJSON, "json", false, "Output as JSON")
+
+	beadFieldsCmd.AddCommand(beadFieldsMigrateCmd)
+	beadFieldsCmd.AddCommand(beadFieldsQueryCmd)
+	beadCmd.AddCommand(beadFieldsCmd)
+}
+
+// fieldStore is one beads database searched by the fields commands.
+type fieldStore struct {
+	name string // "town" or the rig name
+	b    *beads.Beads
+}
+
+// fieldStores returns the town and rig beads databases, or just the named rig's.
+func fieldStores(rigName string) ([]fieldStore, error) {
+	if rigName != "" {
+		_, r, err := getRig(rigName)
+		if err != nil {
+			return nil, err
+		}
+		return []fieldStore{{name: r.Name, b: beads.New(r.BeadsPath())}}, nil
+	}
+
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+
+	stores := []fieldStore{{name: "town", b: beads.New(townRoot)}}
+	for _, r := range rigs {
+		stores = append(stores, fieldStore{name: r.Name, b: beads.New(r.BeadsPath())})
+	}
+	return stores, nil
+}
+
+func runBeadFieldsMigrate(cmd *cobra.Command, args []string) error {
+	stores, err := fieldStores(beadFieldsRig)
+	if err != nil {
+		return err
+	}
+
+	verb := "Migrated"
+	if beadFieldsDryRun {
+		verb = "Would migrate"
+	}
+
+	total := 0
+	var failed []string
+	for _, s := range stores {
+		migrated, err := s.b.MigrateFields(beadFieldsDryRun)
+		for _, m := range migrated {
+			kinds := make([]string, len(m.Kinds))
+			for i, k := range m.Kinds {
+				kinds[i] = string(k)
+			}
+			fmt.Printf("  %s %s %s %s\n", style.Success.Render("✓"), m.ID,
+				style.Dim.Render("("+strings.Join(kinds, ", ")+")"), m.Title)
+		}
+		total += len(migrated)
+		if err != nil {
+			style.PrintWarning("%s: %v", s.name, err)
+			failed = append(failed, s.name)
+		}
+	}
+
+	fmt.Printf("\n%s %d bead(s)\n", style.Bold.Render(verb), total)
+	if len(failed) > 0 {
+		return fmt.Errorf("migration incomplete for %s (rerun to continue)", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// fieldQueryResult is one bead in gt bead fields query --json output.
+type fieldQueryResult struct {
+	ID     string         `json:"id"`
+	Title  string         `json:"title"`
+	Status string         `json:"status"`
+	Store  string         `json:"store"`
+	Fields map[string]any `json:"fields"`
+}
+
+func runBeadFieldsQuery(cmd *cobra.Command, args []string) error {
+	kind, err := beads.ParseFieldKind(args[0])
+	if err != nil {
+		return err
+	}
+	var filters []beads.FieldFilter
+	for _, expr := range args[1:] {
+		f, err := beads.ParseFieldFilter(expr)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, f)
+	}
+
+	stores, err := fieldStores(beadFieldsRig)
+	if err != nil {
+		return err
+	}
+
+	opts := beads.ListOptions{Status: beadFieldsStatus, Priority: -1, Limit: -1}
+	var results []fieldQueryResult
+	for _, s := range stores {
+		issues, err := s.b.QueryFields(kind, opts, filters...)
+		if err != nil {
+			style.PrintWarning("%s: %v", s.name, err)
+			continue
+		}
+		for _, issue := range issues {
+			results = append(results, fieldQueryResult{
+				ID:     issue.ID,
+				Title:  issue.Title,
+				Status: issue.Status,
+				Store:  s.name,
+				Fields: beads.IssueFields(issue, kind),
+			})
+		}
+	}
+
+	if beadFieldsJSON {
+		if results == nil {
+			results = []fieldQueryResult{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No matching beads.")
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "ID", Width: 16},
+		style.Column{Name: "STORE", Width: 10},
+		style.Column{Name: "STATUS", Width: 11},
+		style.Column{Name: "FIELDS", Width: 40},
+		style.Column{Name: "TITLE", Width: 30},
+	)
+	for _, r := range results {
+		table.AddRow(r.ID, r.Store, r.Status, formatFilteredFields(r.Fields, filters), r.Title)
+	}
+	fmt.Print(table.Render())
+	fmt.Printf("\n%d bead(s)\n", len(results))
+	return nil
+}
+
+// formatFilteredFields renders the fields named in filters (or, without
+// filters, every field) as key=value pairs.
+func formatFilteredFields(fields map[string]any, filters []beads.FieldFilter) string {
+	var keys []string
+	if len(filters) > 0 {
+		seen := make(map[string]bool)
+		for _, f := range filters {
+			if !seen[f.Key] {
+				seen[f.Key] = true
+				keys = append(keys, f.Key)
+			}
+		}
+	} else {
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v, ok := fields[k]
+		if !ok {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(parts, " ")
+}
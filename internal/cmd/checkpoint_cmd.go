@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -28,7 +30,12 @@ Checkpoint data includes:
 - Git branch and last commit
 - Timestamp
 
-Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
+Checkpoints are stored in .runtime/checkpoint.json in the polecat directory.
+
+The daemon also captures checkpoints of working polecats periodically. When
+it restarts a crashed polecat, the new session is started with a recovery
+prompt built from the latest checkpoint (if less than 24h old), and can run
+'gt checkpoint diff' to see what changed since.`,
 }
 
 var checkpointWriteCmd = &cobra.Command{
@@ -52,6 +59,25 @@ var checkpointReadCmd = &cobra.Command{
 	RunE:  runCheckpointRead,
 }
 
+var checkpointDiffCmd = &cobra.Command{
+	Use:   "diff [<rig>/<polecat>]",
+	Short: "Show what changed since the checkpoint",
+	Long: `Compare the worktree with its checkpoint.
+
+Shows commits made since the checkpoint, the files they changed, and how
+the set of uncommitted files has changed. Useful after a crash to see how
+far the previous session got beyond its last checkpoint.
+
+Defaults to the current directory; pass <rig>/<polecat> to inspect a
+polecat from elsewhere.
+
+Examples:
+  gt checkpoint diff
+  gt checkpoint diff gastown/Toast --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCheckpointDiff,
+}
+
 var checkpointClearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear the checkpoint file",
@@ -63,11 +89,13 @@ var (
 	checkpointNotes    string
 	checkpointMolecule string
 	checkpointStep     string
+	checkpointDiffJSON bool
 )
 
 func init() {
 	checkpointCmd.AddCommand(checkpointWriteCmd)
 	checkpointCmd.AddCommand(checkpointReadCmd)
+	checkpointCmd.AddCommand(checkpointDiffCmd)
 	checkpointCmd.AddCommand(checkpointClearCmd)
 
 	checkpointWriteCmd.Flags().StringVar(&checkpointNotes, "notes", "",
@@ -76,6 +104,7 @@ func init() {
 		"Override molecule ID (auto-detected if not specified)")
 	checkpointWriteCmd.Flags().StringVar(&checkpointStep, "step", "",
 		"Override step ID (auto-detected if not specified)")
+	checkpointDiffCmd.Flags().BoolVar(&checkpointDiffJSON, "json", false, "Output as JSON")
 
 	rootCmd.AddCommand(checkpointCmd)
 }
@@ -200,10 +229,82 @@ func runCheckpointRead(cmd *cobra.Command, args []string) error {
 	if cp.SessionID != "" {
 		fmt.Printf("Session ID: %s\n", cp.SessionID)
 	}
+	if cp.Auto {
+		fmt.Printf("Captured by: daemon\n")
+	}
+
+	return nil
+}
+
+func runCheckpointDiff(cmd *cobra.Command, args []string) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	if len(args) > 0 {
+		rigName, polecatName, err := parseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		mgr, _, err := getPolecatManager(rigName)
+		if err != nil {
+			return err
+		}
+		p, err := mgr.Get(polecatName)
+		if err != nil {
+			return fmt.Errorf("polecat '%s' not found in rig '%s'", polecatName, rigName)
+		}
+		workDir = p.ClonePath
+	}
+
+	cp, err := checkpoint.Read(workDir)
+	if err != nil {
+		return fmt.Errorf("reading checkpoint: %w", err)
+	}
+	if cp == nil {
+		return fmt.Errorf("no checkpoint in %s", workDir)
+	}
+
+	delta, err := checkpoint.Diff(workDir, cp)
+	if err != nil {
+		return fmt.Errorf("comparing with checkpoint: %w", err)
+	}
+
+	if checkpointDiffJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(delta)
+	}
 
+	fmt.Printf("%s %s\n", style.Bold.Render("Since checkpoint"),
+		style.Dim.Render(fmt.Sprintf("(%s ago)", cp.Age().Round(time.Second))))
+
+	if delta.Empty() {
+		fmt.Printf("\n%s No changes since checkpoint\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	if delta.BranchChanged {
+		fmt.Printf("\nBranch: %s → %s\n", cp.Branch, delta.Branch)
+	}
+	printCheckpointDiffSection("Commits", delta.Commits)
+	printCheckpointDiffSection("Committed files", delta.CommittedFiles)
+	printCheckpointDiffSection("Newly modified", delta.NewlyModified)
+	printCheckpointDiffSection("Still modified", delta.StillModified)
+	printCheckpointDiffSection("No longer modified", delta.NoLongerModified)
 	return nil
 }
 
+func printCheckpointDiffSection(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("\n%s (%d):\n", title, len(items))
+	for _, item := range items {
+		fmt.Printf("  %s\n", item)
+	}
+}
+
 func runCheckpointClear(cmd *cobra.Command, args []string) error {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -86,6 +87,9 @@ var (
 	convoyCloseReason  string
 	convoyCloseNotify  string
 	convoyCheckDryRun  bool
+	convoyTemplate     string
+	convoyVars         []string
+	convoyCreateDryRun bool
 )
 
 var convoyCmd = &cobra.Command{
@@ -121,11 +125,12 @@ TRACKING SEMANTICS:
   - Landed: all tracked issues closed → notification sent to subscribers
 
 COMMANDS:
-  create    Create a convoy tracking specified issues
+  create    Create a convoy tracking specified issues (or from a template)
   add       Add issues to an existing convoy (reopens if closed)
   close     Close a convoy (manually, regardless of tracked issue status)
   status    Show convoy progress, tracked issues, and active workers
-  list      List convoys (the dashboard view)`,
+  list      List convoys (the dashboard view)
+  template  List and inspect convoy templates and their schedules`,
 }
 
 var convoyCreateCmd = &cobra.Command{
@@ -145,8 +150,16 @@ Examples:
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
   gt convoy create "Release prep" gt-abc --notify ops/      # notify ops/
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
-  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release`,
-	Args: cobra.MinimumNArgs(1),
+  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
+
+With --template, the convoy comes from a convoy template: its issues are
+filed in their rigs and tracked, and the convoy is labeled with the template
+it came from. Arguments are then extra existing issues to track. See
+gt convoy template --help.
+
+  gt convoy create --template deps-bump
+  gt convoy create --template release-prep --var version=2.1 --dry-run
+  gt convoy create --template release-prep --var version=2.1 gt-changelog`,
 	RunE: runConvoyCreate,
 }
 
@@ -256,6 +269,9 @@ func init() {
 	convoyCreateCmd.Flags().StringVar(&convoyOwner, "owner", "", "Owner who requested convoy (gets completion notification)")
 	convoyCreateCmd.Flags().StringVar(&convoyNotify, "notify", "", "Additional address to notify on completion (default: mayor/ if flag used without value)")
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
+	convoyCreateCmd.Flags().StringVar(&convoyTemplate, "template", "", "Create the convoy from a convoy template")
+	convoyCreateCmd.Flags().StringArrayVar(&convoyVars, "var", nil, "Template variable (key=value), can be repeated")
+	convoyCreateCmd.Flags().BoolVar(&convoyCreateDryRun, "dry-run", false, "With --template, show the issues that would be filed without creating anything")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -301,6 +317,16 @@ func getTownBeadsDir() (string, error) {
 }
 
 func runConvoyCreate(cmd *cobra.Command, args []string) error {
+	if convoyTemplate != "" {
+		return runConvoyCreateFromTemplate(args)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("requires a convoy name or issue IDs (or --template)")
+	}
+	if len(convoyVars) > 0 || convoyCreateDryRun {
+		return fmt.Errorf("--var and --dry-run only apply with --template")
+	}
+
 	name := args[0]
 	trackedIssues := args[1:]
 
@@ -316,44 +342,79 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	_, err := createConvoy(convoySpec{
+		name:     name,
+		issues:   trackedIssues,
+		owner:    convoyOwner,
+		notify:   convoyNotify,
+		molecule: convoyMolecule,
+	})
+	return err
+}
+
+// convoySpec describes a convoy to create.
+type convoySpec struct {
+	name     string
+	issues   []string // Existing issues to track
+	owner    string   // Defaults to the creator
+	notify   string
+	molecule string
+
+	// template and vars record the template the convoy instantiates, if any.
+	template string
+	vars     map[string]string
+}
+
+// createConvoy creates a convoy in town beads tracking spec.issues, prints
+// a summary, and returns the convoy's ID.
+func createConvoy(spec convoySpec) (string, error) {
+	name := spec.name
+	trackedIssues := spec.issues
+
 	townBeads, err := getTownBeadsDir()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
-		return fmt.Errorf("finding town root: %w", err)
+		return "", fmt.Errorf("finding town root: %w", err)
 	}
 
 	// Get town name for prefix
 	townName, err := workspace.GetTownName(townRoot)
 	if err != nil {
-		return fmt.Errorf("getting town name: %w", err)
+		return "", fmt.Errorf("getting town name: %w", err)
 	}
 
 	// Ensure custom types (including 'convoy') are registered in town beads.
 	// This handles cases where install didn't complete or beads was initialized manually.
 	if err := beads.EnsureCustomTypes(townBeads); err != nil {
-		return fmt.Errorf("ensuring custom types: %w", err)
+		return "", fmt.Errorf("ensuring custom types: %w", err)
 	}
 
 	// Create convoy issue in town beads
 	description := fmt.Sprintf("Convoy tracking %d issues", len(trackedIssues))
 
 	// Default owner to creator identity if not specified
-	owner := convoyOwner
+	owner := spec.owner
 	if owner == "" {
 		owner = detectSender()
 	}
 	if owner != "" {
 		description += fmt.Sprintf("\nOwner: %s", owner)
 	}
-	if convoyNotify != "" {
-		description += fmt.Sprintf("\nNotify: %s", convoyNotify)
+	if spec.notify != "" {
+		description += fmt.Sprintf("\nNotify: %s", spec.notify)
+	}
+	if spec.molecule != "" {
+		description += fmt.Sprintf("\nMolecule: %s", spec.molecule)
 	}
-	if convoyMolecule != "" {
-		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
+	if spec.template != "" {
+		description += fmt.Sprintf("\nTemplate: %s", spec.template)
+		if vars := formatConvoyVars(spec.vars); vars != "" {
+			description += fmt.Sprintf("\nVars: %s", vars)
+		}
 	}
 
 	// Get the configured issue prefix from beads config
@@ -374,6 +435,10 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		"--description=" + description,
 		"--json",
 	}
+	if spec.template != "" {
+		// Label instances so gt convoy template show can find them
+		createArgs = append(createArgs, "--labels="+templateInstanceLabel(spec.template))
+	}
 	if beads.NeedsForceForID(convoyID) {
 		createArgs = append(createArgs, "--force")
 	}
@@ -386,7 +451,7 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	createCmd.Stderr = &stderr
 
 	if err := createCmd.Run(); err != nil {
-		return fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
+		return "", fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
 	}
 
 	// Notify address is stored in description (line 166-168) and read from there
@@ -411,7 +476,7 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 			deleteCmd := exec.Command("bd", "delete", convoyID, "--force")
 			deleteCmd.Dir = townRoot
 			_ = deleteCmd.Run() // Best effort cleanup
-			return fmt.Errorf("no valid issues to track - convoy not created")
+			return "", fmt.Errorf("no valid issues to track - convoy not created")
 		}
 	}
 
@@ -446,16 +511,22 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if owner != "" {
 		fmt.Printf("  Owner:    %s\n", owner)
 	}
-	if convoyNotify != "" {
-		fmt.Printf("  Notify:   %s\n", convoyNotify)
+	if spec.notify != "" {
+		fmt.Printf("  Notify:   %s\n", spec.notify)
+	}
+	if spec.molecule != "" {
+		fmt.Printf("  Molecule: %s\n", spec.molecule)
 	}
-	if convoyMolecule != "" {
-		fmt.Printf("  Molecule: %s\n", convoyMolecule)
+	if spec.template != "" {
+		fmt.Printf("  Template: %s\n", spec.template)
 	}
 
 	fmt.Printf("\n  %s\n", style.Dim.Render("Convoy auto-closes when all tracked issues complete"))
 
-	return nil
+	_ = events.LogFeed(events.TypeConvoyCreated, detectActor(),
+		events.ConvoyCreatedPayload(convoyID, name, spec.template, validIssues))
+
+	return convoyID, nil
 }
 
 func runConvoyAdd(cmd *cobra.Command, args []string) error {
@@ -657,6 +728,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	}
 
 	fmt.Printf("%s Auto-closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, "All tracked issues completed", tracked)
 
 	// Send completion notification
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
@@ -727,6 +799,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, reason, getTrackedIssues(townBeads, convoyID))
 	if convoyCloseReason != "" {
 		fmt.Printf("  Reason: %s\n", convoyCloseReason)
 	}
@@ -742,6 +815,15 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// logConvoyClosed records a convoy close in the activity feed (non-fatal).
+func logConvoyClosed(convoyID, title, reason string, tracked []trackedIssueInfo) {
+	ids := make([]string, 0, len(tracked))
+	for _, t := range tracked {
+		ids = append(ids, t.ID)
+	}
+	_ = events.LogFeed(events.TypeConvoyClosed, detectActor(), events.ConvoyClosedPayload(convoyID, title, reason, ids))
+}
+
 // sendCloseNotification sends a notification about convoy closure.
 func sendCloseNotification(addr, convoyID, title, reason string) {
 	subject := fmt.Sprintf("🚚 Convoy closed: %s", title)
@@ -988,6 +1070,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			}
 
 			closed = append(closed, struct{ ID, Title string }{convoy.ID, convoy.Title})
+			logConvoyClosed(convoy.ID, convoy.Title, "All tracked issues completed", tracked)
 
 			// Check if convoy has notify address and send notification
 			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title)
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var convoyTemplateListJSON bool
+
+var convoyTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "List and inspect convoy templates",
+	Long: `Convoy templates define convoys you create again and again - weekly
+dependency bumps across rigs, release prep - as issue blueprints per rig.
+
+Templates live in <town>/settings/convoy-templates/<name>.toml:
+
+  description = "Weekly dependency bumps"
+  title = "Dependency bumps {{week}}"
+  owner = "mayor/"
+  schedule = "0 9 * * mon"          # Optional: the daemon creates one every Monday 09:00
+
+  [vars.go_version]
+  description = "Go toolchain to move to"
+  default = "1.24"                  # Or: required = true
+
+  [[issues]]
+  rigs = ["gastown", "beads"]       # One issue per rig (or rig = "gastown")
+  title = "Bump dependencies in {{rig}}"
+  description = "go get -u ./... and move go.mod to go {{go_version}}"
+  type = "task"
+  priority = 2
+  labels = ["deps"]
+
+Built-in variables: {{template}}, {{date}} (2006-01-02), {{week}} (2006-W01),
+{{month}} (2006-01), and {{rig}} inside issue blueprints. Set declared
+variables with gt convoy create --template <name> --var key=value.
+
+Every convoy created from a template, and every issue filed for it, is
+labeled template:<name>. Scheduled templates use cron syntax (minute hour
+day month weekday, or @daily/@weekly/@monthly) and are instantiated by the
+daemon; a run missed while the daemon was down happens once when it's back.
+Scheduled templates can't have required variables without defaults.`,
+	RunE: requireSubcommand,
+}
+
+var convoyTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List convoy templates and their schedules",
+	Args:  cobra.NoArgs,
+	RunE:  runConvoyTemplateList,
+}
+
+var convoyTemplateShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a convoy template and the convoys created from it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConvoyTemplateShow,
+}
+
+func init() {
+	convoyTemplateListCmd.Flags().BoolVar(&convoyTemplateListJSON, "json", false, "Output as JSON")
+
+	convoyTemplateCmd.AddCommand(convoyTemplateListCmd)
+	convoyTemplateCmd.AddCommand(convoyTemplateShowCmd)
+	convoyCmd.AddCommand(convoyTemplateCmd)
+}
+
+// templateInstanceLabel returns the label marking convoys and issues created
+// from a template.
+func templateInstanceLabel(name string) string {
+	return convoy.TemplateLabel(name)
+}
+
+// parseConvoyVars parses --var key=value flags.
+func parseConvoyVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+	for _, f := range flags {
+		key, value, ok := strings.Cut(f, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (want key=value)", f)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// formatConvoyVars renders variables as "k=v, k=v", sorted by name.
+func formatConvoyVars(vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+vars[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runConvoyCreateFromTemplate instantiates a convoy template: it files the
+// template's issues in their rigs, then creates a convoy tracking them plus
+// any issues given as arguments.
+func runConvoyCreateFromTemplate(extraIssues []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	t, err := convoy.LoadTemplate(townRoot, convoyTemplate)
+	if err != nil {
+		return err
+	}
+	vars, err := parseConvoyVars(convoyVars)
+	if err != nil {
+		return err
+	}
+	inst, err := t.Instantiate(vars, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Resolve every rig up front so a typo files nothing
+	rigs := make(map[string]*rig.Rig)
+	for _, issue := range inst.Issues {
+		if _, ok := rigs[issue.Rig]; ok {
+			continue
+		}
+		_, r, err := getRig(issue.Rig)
+		if err != nil {
+			return fmt.Errorf("template %s: %w", t.Name, err)
+		}
+		rigs[issue.Rig] = r
+	}
+
+	owner, notify, molecule := convoyOwner, convoyNotify, convoyMolecule
+	if owner == "" {
+		owner = t.Owner
+	}
+	if notify == "" {
+		notify = t.Notify
+	}
+	if molecule == "" {
+		molecule = t.Molecule
+	}
+
+	if convoyCreateDryRun {
+		fmt.Printf("%s Would create convoy 🚚 %s\n\n", style.Bold.Render("○"), inst.Title)
+		fmt.Printf("  Template: %s\n", t.Name)
+		if len(inst.Vars) > 0 {
+			fmt.Printf("  Vars:     %s\n", formatConvoyVars(inst.Vars))
+		}
+		fmt.Printf("\n  Issues to file:\n")
+		for _, issue := range inst.Issues {
+			fmt.Printf("    %s %s %s\n", style.Dim.Render(issue.Rig+":"), issue.Title, style.Dim.Render("["+issue.Type+"]"))
+		}
+		if len(extraIssues) > 0 {
+			fmt.Printf("\n  Also tracking: %s\n", strings.Join(extraIssues, ", "))
+		}
+		return nil
+	}
+
+	label := templateInstanceLabel(t.Name)
+	filed := make([]string, 0, len(inst.Issues))
+	for _, planned := range inst.Issues {
+		b := beads.New(rigs[planned.Rig].BeadsPath())
+		issue, err := b.Create(beads.CreateOptions{
+			Title:       planned.Title,
+			Type:        planned.Type,
+			Priority:    planned.Priority,
+			Description: planned.Description,
+		})
+		if err != nil {
+			style.PrintWarning("couldn't file %q in %s: %v", planned.Title, planned.Rig, err)
+			continue
+		}
+		if err := b.Update(issue.ID, beads.UpdateOptions{AddLabels: append([]string{label}, planned.Labels...)}); err != nil {
+			style.PrintWarning("couldn't label %s: %v", issue.ID, err)
+		}
+		filed = append(filed, issue.ID)
+	}
+	if len(filed) == 0 && len(extraIssues) == 0 {
+		return fmt.Errorf("no issues filed for template %s - convoy not created", t.Name)
+	}
+
+	_, err = createConvoy(convoySpec{
+		name:     inst.Title,
+		issues:   append(filed, extraIssues...),
+		owner:    owner,
+		notify:   notify,
+		molecule: molecule,
+		template: t.Name,
+		vars:     inst.Vars,
+	})
+	return err
+}
+
+// convoyTemplateInfo is a template's JSON summary for gt convoy template list.
+type convoyTemplateInfo struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Title       string    `json:"title"`
+	Issues      int       `json:"issues"` // Issues per instance
+	Schedule    string    `json:"schedule,omitempty"`
+	NextRun     time.Time `json:"next_run,omitempty"`
+	LastRun     time.Time `json:"last_run,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	Path        string    `json:"path"`
+}
+
+func runConvoyTemplateList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	templates, loadErr := convoy.LoadTemplates(townRoot)
+	if loadErr != nil {
+		// Non-fatal: list the templates that did load
+		style.PrintWarning("%v", loadErr)
+	}
+	state, err := convoy.LoadScheduleState(townRoot)
+	if err != nil {
+		style.PrintWarning("reading schedule state: %v", err)
+	}
+
+	infos := make([]convoyTemplateInfo, 0, len(templates))
+	for _, t := range templates {
+		info := convoyTemplateInfo{
+			Name:        t.Name,
+			Description: t.Description,
+			Title:       t.Title,
+			Schedule:    t.Schedule,
+			Path:        t.Path,
+		}
+		for _, b := range t.Issues {
+			info.Issues += len(b.TargetRigs())
+		}
+		if run := state[t.Name]; run != nil && t.Schedule != "" {
+			info.NextRun = t.NextRun(run)
+			info.LastRun = run.LastRun
+			info.LastError = run.LastError
+		}
+		infos = append(infos, info)
+	}
+
+	if convoyTemplateListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+
+	if len(infos) == 0 {
+		fmt.Printf("No convoy templates. Add them in %s\n", convoy.TemplatesDir(townRoot))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Convoy Templates"))
+	for _, info := range infos {
+		fmt.Printf("  📋 %s  %s\n", style.Bold.Render(info.Name), style.Dim.Render(fmt.Sprintf("(%d issues)", info.Issues)))
+		if info.Description != "" {
+			fmt.Printf("     %s\n", info.Description)
+		}
+		if info.Schedule != "" {
+			fmt.Printf("     %s\n", formatTemplateSchedule(info.Schedule, info.NextRun, info.LastRun, info.LastError))
+		}
+	}
+	return nil
+}
+
+// formatTemplateSchedule describes a template's schedule and its runs.
+func formatTemplateSchedule(schedule string, next, last time.Time, lastErr string) string {
+	parts := []string{"⏰ " + schedule}
+	if !next.IsZero() {
+		parts = append(parts, "next "+next.Local().Format("Mon 2006-01-02 15:04"))
+	} else if last.IsZero() {
+		parts = append(parts, "waiting for the daemon")
+	}
+	if !last.IsZero() {
+		parts = append(parts, "last "+last.Local().Format("2006-01-02 15:04"))
+	}
+	line := style.Dim.Render(strings.Join(parts, " • "))
+	if lastErr != "" {
+		line += " " + style.Error.Render("last run failed: "+lastErr)
+	}
+	return line
+}
+
+func runConvoyTemplateShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	t, err := convoy.LoadTemplate(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📋 %s\n", style.Bold.Render(t.Name))
+	if t.Description != "" {
+		fmt.Printf("   %s\n", t.Description)
+	}
+	fmt.Printf("\n  Title:    %s\n", t.Title)
+	if t.Owner != "" {
+		fmt.Printf("  Owner:    %s\n", t.Owner)
+	}
+	if t.Notify != "" {
+		fmt.Printf("  Notify:   %s\n", t.Notify)
+	}
+	if t.Molecule != "" {
+		fmt.Printf("  Molecule: %s\n", t.Molecule)
+	}
+	if t.Schedule != "" {
+		state, _ := convoy.LoadScheduleState(townRoot)
+		var next, last time.Time
+		var lastErr string
+		if run := state[t.Name]; run != nil {
+			next, last, lastErr = t.NextRun(run), run.LastRun, run.LastError
+		}
+		fmt.Printf("  Schedule: %s\n", formatTemplateSchedule(t.Schedule, next, last, lastErr))
+	}
+
+	if len(t.Vars) > 0 {
+		fmt.Printf("\n  Variables:\n")
+		names := make([]string, 0, len(t.Vars))
+		for name := range t.Vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v := t.Vars[name]
+			detail := ""
+			switch {
+			case v.Required:
+				detail = "required"
+			case v.Default != "":
+				detail = "default " + v.Default
+			}
+			if v.Description != "" {
+				if detail != "" {
+					detail += " - "
+				}
+				detail += v.Description
+			}
+			fmt.Printf("    %s  %s\n", name, style.Dim.Render(detail))
+		}
+	}
+
+	fmt.Printf("\n  Issues:\n")
+	for _, b := range t.Issues {
+		fmt.Printf("    %s %s\n", style.Dim.Render(strings.Join(b.TargetRigs(), ", ")+":"), b.Title)
+	}
+
+	// Instances are found by label, so convoys created before a rename or
+	// by hand with the label still show up
+	instances, err := beads.New(townRoot).List(beads.ListOptions{
+		Status:   "all",
+		Label:    templateInstanceLabel(t.Name),
+		Priority: -1,
+		Limit:    -1,
+	})
+	if err != nil {
+		style.PrintWarning("listing instances: %v", err)
+		return nil
+	}
+	var convoys []*beads.Issue
+	for _, issue := range instances {
+		if issue.Type == "convoy" {
+			convoys = append(convoys, issue)
+		}
+	}
+	sort.Slice(convoys, func(i, j int) bool { return convoys[i].CreatedAt > convoys[j].CreatedAt })
+
+	fmt.Printf("\n  Instances (%d):\n", len(convoys))
+	if len(convoys) == 0 {
+		fmt.Printf("    %s\n", style.Dim.Render("none yet - gt convoy create --template "+t.Name))
+	}
+	for _, c := range convoys {
+		fmt.Printf("    🚚 %s %s: %s\n", formatConvoyStatus(c.Status), c.ID, c.Title)
+	}
+	return nil
+}
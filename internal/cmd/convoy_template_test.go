@@ -0,0 +1,22 @@
+package cmd
+
+import "testing"
+
+func TestParseConvoyVars(t *testing.T) {
+	vars, err := parseConvoyVars([]string{"version=2.1", "note=a=b", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["version"] != "2.1" || vars["note"] != "a=b" || vars["empty"] != "" || len(vars) != 3 {
+		t.Errorf("vars = %v", vars)
+	}
+	if got := formatConvoyVars(vars); got != "empty=, note=a=b, version=2.1" {
+		t.Errorf("formatConvoyVars = %q", got)
+	}
+
+	for _, bad := range []string{"version", "=2.1"} {
+		if _, err := parseConvoyVars([]string{bad}); err == nil {
+			t.Errorf("parseConvoyVars(%q) succeeded", bad)
+		}
+	}
+}
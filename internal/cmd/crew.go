@@ -53,7 +53,8 @@ Commands:
   gt crew at <name>        Attach to session
   gt crew remove <name>    Remove workspace
   gt crew refresh <name>   Context cycle with handoff mail
-  gt crew restart <name>   Kill and restart session fresh`,
+  gt crew restart <name>   Kill and restart session fresh
+  gt crew sync [<name>]    Report drift and sync with the default branch`,
 }
 
 var crewAddCmd = &cobra.Command{
@@ -240,6 +241,9 @@ var crewPristineCmd = &cobra.Command{
 Runs git pull for the specified crew, or all crew workers.
 Reports any uncommitted changes that may need attention.
 
+To see how far crew branches have drifted from the rig default branch, and
+rebase or merge with a conflict preview, use 'gt crew sync'.
+
 Examples:
   gt crew pristine                # Pristine all crew workers
   gt crew pristine dave           # Pristine specific worker
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	crewSyncRebase bool
+	crewSyncMerge  bool
+)
+
+var crewSyncCmd = &cobra.Command{
+	Use:   "sync [<name>]",
+	Short: "Report crew drift and sync with the default branch",
+	Long: `Fetch and report how far crew workspaces have drifted from the rig
+default branch, previewing conflicts before anything changes.
+
+For each crew worker (or the one named), shows commits behind/ahead of
+origin/<default>, unpushed commits, stashes, uncommitted changes, and the
+files that would conflict when bringing in the default branch. The
+preview compares commits with git merge-tree and never touches the
+working tree, so it is safe to run while agents are working.
+
+With --rebase or --merge, workspaces that are behind and would not
+conflict are brought up to date. Uncommitted changes are stashed around
+the operation and restored afterwards. Workspaces with conflicts are
+skipped and reported, so they can be resolved by hand.
+
+Examples:
+  gt crew sync                    # Drift table for all crew in the rig
+  gt crew sync dave               # Drift for one worker
+  gt crew sync --rebase           # Rebase all crew that can be rebased cleanly
+  gt crew sync dave --merge       # Merge the default branch into dave
+  gt crew sync --json             # JSON output`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCrewSync,
+}
+
+func init() {
+	crewSyncCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
+	crewSyncCmd.Flags().BoolVar(&crewSyncRebase, "rebase", false, "Rebase crew branches onto the default branch")
+	crewSyncCmd.Flags().BoolVar(&crewSyncMerge, "merge", false, "Merge the default branch into crew branches")
+	crewSyncCmd.Flags().BoolVar(&crewJSON, "json", false, "Output as JSON")
+	crewSyncCmd.MarkFlagsMutuallyExclusive("rebase", "merge")
+
+	crewCmd.AddCommand(crewSyncCmd)
+}
+
+func runCrewSync(cmd *cobra.Command, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+		// Parse rig/name format (e.g., "beads/emma" -> rig=beads, name=emma)
+		if rig, crewName, ok := parseRigSlashName(name); ok {
+			if crewRig == "" {
+				crewRig = rig
+			}
+			name = crewName
+		}
+	}
+
+	crewMgr, r, err := getCrewManager(crewRig)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if name != "" {
+		names = []string{name}
+	} else {
+		workers, err := crewMgr.List()
+		if err != nil {
+			return fmt.Errorf("listing crew workers: %w", err)
+		}
+		for _, w := range workers {
+			names = append(names, w.Name)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Println("No crew workspaces found.")
+		return nil
+	}
+
+	mode := crew.SyncPreview
+	if crewSyncRebase {
+		mode = crew.SyncRebase
+	} else if crewSyncMerge {
+		mode = crew.SyncMerge
+	}
+
+	var results []*crew.SyncResult
+	for _, n := range names {
+		result, err := crewMgr.Sync(n, mode)
+		if err != nil {
+			if err == crew.ErrCrewNotFound {
+				return fmt.Errorf("crew workspace '%s' not found", n)
+			}
+			return fmt.Errorf("sync %s: %w", n, err)
+		}
+		results = append(results, result)
+	}
+
+	if crewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Crew drift in %s (vs %s)", r.Name, results[0].Base)))
+	table := style.NewTable(
+		style.Column{Name: "CREW", Width: 12},
+		style.Column{Name: "BRANCH", Width: 20},
+		style.Column{Name: "BEHIND", Width: 6, Align: style.AlignRight},
+		style.Column{Name: "AHEAD", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "UNPUSHED", Width: 8, Align: style.AlignRight},
+		style.Column{Name: "STASH", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "DIRTY", Width: 5},
+		style.Column{Name: "STATUS", Width: 24},
+	)
+	for _, res := range results {
+		dirty := ""
+		if res.Dirty {
+			dirty = "yes"
+		}
+		table.AddRow(res.Name, res.Branch, strconv.Itoa(res.Behind), strconv.Itoa(res.Ahead),
+			strconv.Itoa(res.Unpushed), strconv.Itoa(res.Stashes), dirty, crewSyncStatus(res))
+	}
+	fmt.Print(table.Render())
+
+	var canSync []string
+	for _, res := range results {
+		if len(res.Conflicts) > 0 {
+			fmt.Printf("\n%s %s conflicts with %s:\n", style.Warning.Render("⚠"), res.Name, res.Base)
+			for _, f := range res.Conflicts {
+				fmt.Printf("    %s\n", f)
+			}
+		}
+		for _, msg := range []string{res.FetchError, res.PreviewError, res.SyncError, res.RestoreError} {
+			if msg != "" {
+				fmt.Printf("\n%s %s: %s\n", style.Bold.Render("✗"), res.Name, msg)
+			}
+		}
+		if mode == crew.SyncPreview && res.Behind > 0 && len(res.Conflicts) == 0 && res.PreviewError == "" {
+			canSync = append(canSync, res.Name)
+		}
+	}
+
+	if len(canSync) > 0 {
+		target := ""
+		if name != "" {
+			target = " " + name
+		}
+		fmt.Printf("\n%s %s can be updated cleanly:\n", style.Dim.Render("→"), strings.Join(canSync, ", "))
+		fmt.Printf("  gt crew sync%s --rebase    # Replay crew commits on top\n", target)
+		fmt.Printf("  gt crew sync%s --merge     # Merge the default branch in\n", target)
+	}
+
+	return nil
+}
+
+// crewSyncStatus summarizes a sync result for the drift table.
+func crewSyncStatus(res *crew.SyncResult) string {
+	switch {
+	case res.Synced && res.Mode == crew.SyncRebase:
+		return style.Success.Render("rebased")
+	case res.Synced:
+		return style.Success.Render("merged")
+	case res.SyncError != "":
+		return style.Error.Render(string(res.Mode) + " failed")
+	case len(res.Conflicts) > 0:
+		return style.Warning.Render(fmt.Sprintf("%d conflicting files", len(res.Conflicts)))
+	case res.PreviewError != "":
+		return style.Warning.Render("preview failed")
+	case res.Behind > 0:
+		return "can update"
+	default:
+		return style.Dim.Render("up to date")
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -13,12 +14,16 @@ import (
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-var dashboardNoAuth bool
+var (
+	dashboardNoAuth  bool
+	dashboardMetrics bool
+)
 
 var (
-	dashboardPort int
-	dashboardOpen bool
-	dashboardBind string
+	dashboardPort    int
+	dashboardOpen    bool
+	dashboardBind    string
+	dashboardRefresh time.Duration
 )
 
 var dashboardCmd = &cobra.Command{
@@ -31,12 +36,26 @@ The dashboard shows real-time convoy status with:
 - Convoy list with status indicators
 - Progress tracking for each convoy
 - Last activity indicator (green/yellow/red)
-- Auto-refresh every 30 seconds via htmx
+- Auto-refresh every 10 seconds via htmx
+
+Page loads are served from a shared snapshot that is refreshed in the
+background every --refresh interval, and within a couple of seconds of new
+events in .events.jsonl, so viewers don't multiply the load on bd, tmux and
+gh. The header shows when the snapshot was taken; panels whose last fetch
+failed or timed out keep their previous data and are marked stale. Use
+--refresh 0 to fetch on every request instead.
+
+With --metrics, the server also exposes /metrics in Prometheus text format
+(active polecats, merge queue depth and age, merge results, session deaths,
+GUPP violations, escalations, costs, Deacon health). The metrics endpoint is
+not behind dashboard authentication so Prometheus can scrape it.
 
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
-  gt dashboard --open       # Start and open browser`,
+  gt dashboard --open       # Start and open browser
+  gt dashboard --refresh 30s # Refresh the snapshot every 30 seconds
+  gt dashboard --metrics    # Also serve /metrics for Prometheus`,
 	RunE: runDashboard,
 }
 
@@ -44,7 +63,9 @@ func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
 	dashboardCmd.Flags().StringVar(&dashboardBind, "bind", "0.0.0.0", "Address to bind to (0.0.0.0 for all interfaces)")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
+	dashboardCmd.Flags().DurationVar(&dashboardRefresh, "refresh", web.DefaultRefreshInterval, "Background refresh interval for dashboard data (0 fetches on every request)")
 	dashboardCmd.Flags().BoolVar(&dashboardNoAuth, "no-auth", false, "Disable authentication (use with caution)")
+	dashboardCmd.Flags().BoolVar(&dashboardMetrics, "metrics", false, "Expose Prometheus metrics at /metrics (unauthenticated)")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
@@ -61,8 +82,16 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating convoy fetcher: %w", err)
 	}
 
-	// Create the convoy handler
-	convoyHandler, err := web.NewConvoyHandler(fetcher)
+	// Create the convoy handler, serving from a background-refreshed
+	// snapshot unless disabled
+	var convoyHandler *web.ConvoyHandler
+	if dashboardRefresh > 0 {
+		cache := web.NewSnapshotCache(fetcher, townRoot, dashboardRefresh)
+		cache.Start(context.Background())
+		convoyHandler, err = web.NewCachedConvoyHandler(cache)
+	} else {
+		convoyHandler, err = web.NewConvoyHandler(fetcher)
+	}
 	if err != nil {
 		return fmt.Errorf("creating convoy handler: %w", err)
 	}
@@ -92,6 +121,14 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Expose /metrics ahead of auth so scrapers don't need a session
+	if dashboardMetrics {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", web.NewMetricsHandler(townRoot))
+		mux.Handle("/", handler)
+		handler = mux
+	}
+
 	// Build the bind address
 	addr := fmt.Sprintf("%s:%d", dashboardBind, dashboardPort)
 	localURL := fmt.Sprintf("http://localhost:%d", dashboardPort)
@@ -109,6 +146,9 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	if lanIP := getLANIP(); lanIP != "" {
 		fmt.Printf("   Network: http://%s:%d\n", lanIP, dashboardPort)
 	}
+	if dashboardMetrics {
+		fmt.Printf("   Metrics: %s/metrics\n", localURL)
+	}
 	fmt.Printf("   Press Ctrl+C to stop\n")
 
 	server := &http.Server{
@@ -9,12 +9,14 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -240,6 +242,9 @@ func runDone(cmd *cobra.Command, args []string) error {
 		defaultBranch = rigCfg.DefaultBranch
 	}
 
+	// Trace ID assigned by gt sling, carried into the MR and completion events
+	traceID := resolveTraceID(beads.ResolveBeadsDir(cwd), issueID)
+
 	// For COMPLETED, we need an issue ID and branch must not be the default branch
 	var mrID string
 	if exitType == ExitCompleted {
@@ -378,19 +383,16 @@ func runDone(cmd *cobra.Command, args []string) error {
 		} else {
 			// Build MR bead title and description
 			title := fmt.Sprintf("Merge: %s", issueID)
-			description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
-				branch, target, issueID, rigName)
-			if worker != "" {
-				description += fmt.Sprintf("\nworker: %s", worker)
-			}
-			if agentBeadID != "" {
-				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
-			}
-
-			// Add conflict resolution tracking fields (initialized, updated by Refinery)
-			description += "\nretry_count: 0"
-			description += "\nlast_conflict_sha: null"
-			description += "\nconflict_task_id: null"
+			// Conflict resolution fields start empty and are updated by the Refinery
+			description := beads.SetMRFields(nil, &beads.MRFields{
+				Branch:      branch,
+				Target:      target,
+				SourceIssue: issueID,
+				Rig:         rigName,
+				Worker:      worker,
+				AgentBead:   agentBeadID,
+				TraceID:     traceID,
+			})
 
 			// Create MR bead (ephemeral wisp - will be cleaned up after merge)
 			mrIssue, err := bd.Create(beads.CreateOptions{
@@ -412,6 +414,8 @@ func runDone(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			_ = events.LogFeed(events.TypeMRSubmitted, sender, trace.Annotate(events.MRSubmittedPayload(mrID, issueID, branch, target), traceID))
+
 			// Success output
 			fmt.Printf("%s Work submitted to merge queue\n", style.Bold.Render("✓"))
 			fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
@@ -471,6 +475,9 @@ notifyWitness:
 		bodyLines = append(bodyLines, fmt.Sprintf("Gate: %s", doneGate))
 	}
 	bodyLines = append(bodyLines, fmt.Sprintf("Branch: %s", branch))
+	if traceID != "" {
+		bodyLines = append(bodyLines, fmt.Sprintf("Trace-ID: %s", traceID))
+	}
 
 	doneNotification := &mail.Message{
 		To:      witnessAddr,
@@ -505,11 +512,17 @@ notifyWitness:
 
 	// Log done event (townlog and activity feed)
 	_ = LogDone(townRoot, sender, issueID)
-	_ = events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch))
+	_ = events.LogFeed(events.TypeDone, sender, trace.Annotate(events.DonePayload(issueID, branch), traceID))
 
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
 
+	// The hook is cleared, so any checkpoint is of finished work; a reused
+	// polecat must not be told to resume it after a crash
+	if cwdAvailable {
+		_ = checkpoint.Remove(cwd)
+	}
+
 	// Self-cleaning: Nuke our own sandbox and session (if we're a polecat)
 	// This is the self-cleaning model - polecats clean up after themselves
 	// "done means gone" - both worktree and session are terminated
@@ -663,20 +676,20 @@ func updateAgentStateOnDone(cwd, townRoot, exitType, _ string) { // issueID unus
 		fmt.Fprintf(os.Stderr, "Warning: couldn't clear agent %s hook: %v\n", agentBeadID, err)
 	}
 
+	// Agent state and cleanup status are written as one batch, so the agent
+	// bead is read and updated once.
+	updates := bd.NewAgentUpdates()
+
 	// Only set non-observable states - "stuck" and "awaiting-gate" are intentional
 	// agent decisions that can't be discovered from tmux. Skip "done" and "idle"
 	// since those are observable (no session = done, session + no hook = idle).
 	switch exitType {
 	case ExitEscalated:
 		// "stuck" = agent is requesting help - not observable from tmux
-		if _, err := bd.Run("agent", "state", agentBeadID, "stuck"); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: couldn't set agent %s to stuck: %v\n", agentBeadID, err)
-		}
+		updates.SetState(agentBeadID, "stuck")
 	case ExitPhaseComplete:
 		// "awaiting-gate" = agent is waiting for external trigger - not observable
-		if _, err := bd.Run("agent", "state", agentBeadID, "awaiting-gate"); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: couldn't set agent %s to awaiting-gate: %v\n", agentBeadID, err)
-		}
+		updates.SetState(agentBeadID, "awaiting-gate")
 	// ExitCompleted and ExitDeferred don't set state - observable from tmux
 	}
 
@@ -685,12 +698,13 @@ func updateAgentStateOnDone(cwd, townRoot, exitType, _ string) { // issueID unus
 	if doneCleanupStatus != "" {
 		cleanupStatus := parseCleanupStatus(doneCleanupStatus)
 		if cleanupStatus != polecat.CleanupUnknown {
-			if err := bd.UpdateAgentCleanupStatus(agentBeadID, string(cleanupStatus)); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: couldn't update agent %s cleanup status: %v\n", agentBeadID, err)
-				return
-			}
+			updates.SetFields(agentBeadID, func(f *beads.AgentFields) { f.CleanupStatus = string(cleanupStatus) })
 		}
 	}
+
+	if err := updates.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: couldn't update agent %s: %v\n", agentBeadID, err)
+	}
 }
 
 // getIssueFromAgentHook retrieves the issue ID from an agent's hook_bead field.
@@ -9,6 +9,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/tui/feed"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -25,6 +26,9 @@ var (
 	feedNoFollow bool
 	feedWindow   bool
 	feedPlain    bool
+	feedFilter   string
+	feedView     string
+	feedBuffer   int
 )
 
 func init() {
@@ -39,6 +43,9 @@ func init() {
 	feedCmd.Flags().StringVar(&feedRig, "rig", "", "Run from specific rig's beads directory")
 	feedCmd.Flags().BoolVarP(&feedWindow, "window", "w", false, "Open in dedicated tmux window (creates 'feed' window)")
 	feedCmd.Flags().BoolVar(&feedPlain, "plain", false, "Use plain text output (bd activity) instead of TUI")
+	feedCmd.Flags().StringVar(&feedFilter, "filter", "", "Initial TUI filter query (e.g. \"rig:gastown type:done\")")
+	feedCmd.Flags().StringVar(&feedView, "view", "", "Open the TUI with a saved view")
+	feedCmd.Flags().IntVar(&feedBuffer, "buffer", feed.DefaultEventBuffer, "Events kept in the TUI for scroll-back")
 }
 
 var feedCmd = &cobra.Command{
@@ -60,6 +67,16 @@ The feed combines multiple event sources:
 
 Use --plain for simple text output (wraps bd activity only).
 
+Filtering and search (TUI):
+  f      Filter bar. Terms are AND'ed; comma-separated values are OR'ed:
+           rig:gastown  actor:*/polecats/*  type:done,merged
+           bead:gt-abc  role:witness  -type:update  <free text>
+  /      Incremental search over the event stream
+  esc    Clear filter and search
+  p      Pause the stream to scroll back (new events are buffered)
+  S      Save the current filter as a named view (in town settings)
+  v      Cycle through saved views
+
 Tmux Integration:
   Use --window to open the feed in a dedicated tmux window named 'feed'.
   This creates a persistent window you can cycle to with C-b n/p.
@@ -86,6 +103,8 @@ Examples:
   gt feed --plain               # Plain text output (bd activity)
   gt feed --window              # Open in dedicated tmux window
   gt feed --since 1h            # Events from last hour
+  gt feed --filter "type:done"  # TUI showing only completions
+  gt feed --view polecats       # TUI with a saved view
   gt feed --rig greenplace         # Use gastown rig's beads`,
 	RunE: runFeed,
 }
@@ -230,9 +249,28 @@ func runFeedTUI(workDir string) error {
 
 	// Create model and connect event source
 	m := feed.NewModel()
+	m.SetBufferSize(feedBuffer)
 	m.SetEventChannel(multiSource.Events())
 	m.SetTownRoot(townRoot)
 
+	// Saved views live in town settings
+	settingsPath := config.TownSettingsPath(townRoot)
+	if settings, err := config.LoadOrCreateTownSettings(settingsPath); err == nil {
+		m.SetViews(settings.FeedViews, func(name, query string) error {
+			return saveFeedView(settingsPath, name, query)
+		})
+	}
+	if feedView != "" {
+		if err := m.SelectView(feedView); err != nil {
+			return err
+		}
+	}
+	if feedFilter != "" {
+		if err := m.SetFilter(feedFilter); err != nil {
+			return fmt.Errorf("invalid --filter: %w", err)
+		}
+	}
+
 	// Run the TUI
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -242,6 +280,20 @@ func runFeedTUI(workDir string) error {
 	return nil
 }
 
+// saveFeedView stores a named feed filter in town settings.
+// Settings are re-read so concurrent edits aren't clobbered.
+func saveFeedView(settingsPath, name, query string) error {
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.FeedViews == nil {
+		settings.FeedViews = make(map[string]string)
+	}
+	settings.FeedViews[name] = query
+	return config.SaveTownSettings(settingsPath, settings)
+}
+
 // runFeedInWindow opens the feed in a dedicated tmux window.
 func runFeedInWindow(workDir string, bdArgs []string) error {
 	// Check if we're in tmux
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -261,7 +262,8 @@ func runHook(_ *cobra.Command, args []string) error {
 	fmt.Printf("  Use 'gt hook' to see hook status\n")
 
 	// Log hook event to activity feed (non-fatal)
-	if err := events.LogFeed(events.TypeHook, agentID, events.HookPayload(beadID)); err != nil {
+	hookPayload := trace.Annotate(events.HookPayload(beadID), resolveTraceID(townRoot, beadID))
+	if err := events.LogFeed(events.TypeHook, agentID, hookPayload); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Warning: failed to log hook event: %v\n", style.Dim.Render("⚠"), err)
 	}
 
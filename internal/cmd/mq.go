@@ -22,6 +22,7 @@ var (
 	mqSubmitEpic      string
 	mqSubmitPriority  int
 	mqSubmitNoCleanup bool
+	mqSubmitDependsOn []string
 
 	// Retry flags
 	mqRetryNow bool
@@ -88,6 +89,11 @@ Target branch auto-detection:
 
 This ensures batch work on epics automatically flows to integration branches.
 
+Merge order:
+  The Refinery holds an MR until the queued MRs it builds on have merged.
+  Bead dependencies between source issues are followed automatically; use
+  --depends-on to name other MRs (or their source issues) explicitly.
+
 Polecat auto-cleanup:
   When run from a polecat work branch (polecat/<worker>/<issue>), this command
   automatically triggers polecat shutdown after submitting the MR. The polecat
@@ -101,7 +107,8 @@ Examples:
   gt mq submit --issue gp-abc            # Explicit issue
   gt mq submit --epic gt-xyz             # Target integration branch explicitly
   gt mq submit --priority 0              # Override priority (P0)
-  gt mq submit --no-cleanup              # Submit without auto-cleanup`,
+  gt mq submit --no-cleanup              # Submit without auto-cleanup
+  gt mq submit --depends-on gp-mr-abc    # Merge only after gp-mr-abc`,
 	RunE: runMqSubmit,
 }
 
@@ -164,7 +171,8 @@ var mqStatusCmd = &cobra.Command{
 	Long: `Display detailed information about a merge request.
 
 Shows all MR fields, current status with timestamps, dependencies,
-blockers, and processing history.
+blockers, and processing history. Open MRs also show their merge order:
+the queued MRs that must merge first, and those waiting on this one.
 
 Example:
   gt mq status gp-mr-abc123`,
@@ -276,6 +284,7 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().StringSliceVar(&mqSubmitDependsOn, "depends-on", nil, "MR or issue IDs that must merge first (comma-separated)")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+var mqCICmd = &cobra.Command{
+	Use:   "ci <rig> [mr-id]",
+	Short: "Advance merge requests through the external CI gate",
+	Long: `Advance merge requests in a rig whose merge queue is gated on external CI.
+
+Rigs with a merge_queue.ci section don't trust a local test run alone. For
+each MR (the given one, or every ready MR), the refinery:
+
+  1. Squash-merges the branch onto the target (running test_command
+     first, if configured) and pushes the result to a candidate ref
+     (refs/heads/gt-ci/<mr-id> by default) instead of the target
+  2. Leaves the MR queued, shown as "waiting CI" in gt mq list
+  3. On later runs, asks CI for its verdict on the candidate
+  4. Pushes the tested candidate to the target once CI passes it, then
+     closes the MR and source issue and notifies the witness (MERGED)
+  5. Fails the MR (MERGE_FAILED) if CI fails it or gives no verdict
+     within the timeout
+
+Configure the gate in the rig's config.json. Providers:
+
+  http         Poll status_url, which returns JSON like {"state": "success"}
+  webhook      POST the candidate to webhook_url, then poll status_url
+  status-file  Poll status_file, written by CI or a relay
+
+  "merge_queue": {
+    "ci": {
+      "provider": "http",
+      "status_url": "https://ci.example.com/api/status/{sha}",
+      "timeout": "45m"
+    }
+  }
+
+URLs and paths may use {mr}, {branch}, {target}, {ref} and {sha}.
+
+Examples:
+  gt mq ci greenplace              # Advance every ready MR
+  gt mq ci greenplace gp-mr-abc    # Advance one MR`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runMQCI,
+}
+
+func init() {
+	mqCmd.AddCommand(mqCICmd)
+}
+
+func runMQCI(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return err
+	}
+	if eng.Config().MergeMode == config.MergeModePR {
+		return fmt.Errorf("rig %s merges through pull requests; use gt mq pr", r.Name)
+	}
+	if eng.Config().CI == nil {
+		return fmt.Errorf("rig %s has no CI gate (merge_queue.ci is not set)", r.Name)
+	}
+
+	return advanceMRs(eng, r, args[1:], "CI queue")
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ci"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -61,6 +62,18 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// MRs held in merge order behind other queued MRs count as blocked
+	graph, err := refinery.LoadMRGraph(b)
+	if err != nil {
+		style.PrintWarning("could not load merge order: %v", err)
+	}
+	waitingOn := func(id string) []string {
+		if graph == nil {
+			return nil
+		}
+		return graph.WaitingOn(id)
+	}
+
 	// Apply additional filters and calculate scores
 	now := time.Now()
 	type scoredIssue struct {
@@ -74,7 +87,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		// Manual status filtering as workaround for bd list not respecting --status filter
 		if mqListReady {
 			// Ready view should only show open MRs
-			if issue.Status != "open" {
+			if issue.Status != "open" || len(waitingOn(issue.ID)) > 0 {
 				continue
 			}
 		} else if mqListStatus != "" && !strings.EqualFold(mqListStatus, "all") {
@@ -161,8 +174,10 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		// Determine display status
 		displayStatus := issue.Status
 		if issue.Status == "open" {
-			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
+			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 || len(waitingOn(issue.ID)) > 0 {
 				displayStatus = "blocked"
+			} else if fields != nil && fields.CIStatus == ci.StatePending {
+				displayStatus = "waiting CI"
 			} else {
 				displayStatus = "ready"
 			}
@@ -177,6 +192,8 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			styledStatus = style.Warning.Render("active")
 		case "blocked":
 			styledStatus = style.Dim.Render("blocked")
+		case "waiting CI":
+			styledStatus = style.Warning.Render("waiting CI")
 		case "closed":
 			styledStatus = style.Dim.Render("closed")
 		}
@@ -231,19 +248,50 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		if issue.Status == "open" && (len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0) {
 			displayStatus = "blocked"
 		}
-		if displayStatus == "blocked" && len(issue.BlockedBy) > 0 {
-			displayID := issue.ID
-			if len(displayID) > 12 {
-				displayID = displayID[:12]
+		displayID := issue.ID
+		if len(displayID) > 12 {
+			displayID = displayID[:12]
+		}
+		if issue.Status == "open" {
+			if held := waitingOn(issue.ID); len(held) > 0 {
+				fmt.Printf("  %s %s\n", style.Dim.Render(displayID+":"),
+					style.Dim.Render(fmt.Sprintf("waiting on %s to merge first", strings.Join(held, ", "))))
+				continue
 			}
+		}
+		if displayStatus == "blocked" && len(issue.BlockedBy) > 0 {
 			fmt.Printf("  %s %s\n", style.Dim.Render(displayID+":"),
 				style.Dim.Render(fmt.Sprintf("waiting on %s", issue.BlockedBy[0])))
 		}
 	}
 
+	// Show candidates under test below that
+	for _, item := range scored {
+		if item.fields == nil || item.fields.CIStatus != ci.StatePending || item.issue.Status != "open" {
+			continue
+		}
+		displayID := item.issue.ID
+		if len(displayID) > 12 {
+			displayID = displayID[:12]
+		}
+		detail := fmt.Sprintf("waiting on CI for %s (%s)", shortSHA(item.fields.CISHA), formatMRAge(item.fields.CIStartedAt))
+		if item.fields.CIURL != "" {
+			detail += " " + item.fields.CIURL
+		}
+		fmt.Printf("  %s %s\n", style.Dim.Render(displayID+":"), style.Dim.Render(detail))
+	}
+
 	return nil
 }
 
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
 // formatMRAge formats the age of an MR from its created_at timestamp.
 func formatMRAge(createdAt string) string {
 	t, err := time.Parse(time.RFC3339, createdAt)
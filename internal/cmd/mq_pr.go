@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var mqPRCmd = &cobra.Command{
+	Use:   "pr <rig> [mr-id]",
+	Short: "Advance merge requests through pull requests (pr merge mode)",
+	Long: `Advance merge requests in a rig that merges through pull requests.
+
+Rigs whose merge_queue.merge_mode is "pr" land work through the code host
+instead of pushing to the target branch. For each MR (the given one, or
+every ready MR), the refinery:
+
+  1. Opens a pull request for the MR's branch, if it has none yet
+  2. Sends new review comments back to the worker as a rework task and
+     REWORK_REQUEST, blocking the MR until the task closes
+  3. Sends failed checks back the same way
+  4. Leaves the MR queued while checks run or approvals are missing
+  5. Merges through the API once the gate passes, then closes the MR
+     and source issue and notifies the witness (MERGED)
+
+Configure the code host in the rig's config.json:
+
+  "merge_queue": {
+    "merge_mode": "pr",
+    "forge": {
+      "provider": "github",
+      "repo": "acme/widgets",
+      "required_approvals": 1,
+      "required_checks": ["ci"]
+    }
+  }
+
+The API token is read from GITHUB_TOKEN or GITLAB_TOKEN (or forge.token_env).
+
+Examples:
+  gt mq pr greenplace              # Advance every ready MR
+  gt mq pr greenplace gp-mr-abc    # Advance one MR`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runMQPR,
+}
+
+func init() {
+	mqCmd.AddCommand(mqPRCmd)
+}
+
+func runMQPR(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return err
+	}
+	if mode := eng.Config().MergeMode; mode != config.MergeModePR {
+		return fmt.Errorf("rig %s merges locally (merge_queue.merge_mode is %q, not %q)", r.Name, mode, config.MergeModePR)
+	}
+
+	return advanceMRs(eng, r, args[1:], "PR queue")
+}
+
+// advanceMRs runs one gated processing pass (pull request or external CI)
+// over the given MR, or every ready MR, and reports the outcome. Merged MRs
+// are closed and announced to the witness; failures and rework go through
+// the engineer's usual failure handling; MRs still waiting stay queued.
+func advanceMRs(eng *refinery.Engineer, r *rig.Rig, ids []string, label string) error {
+	var mrs []*refinery.MRInfo
+	if len(ids) > 0 {
+		mr, err := eng.GetMRInfo(ids[0])
+		if err != nil {
+			return err
+		}
+		mrs = append(mrs, mr)
+	} else {
+		var err error
+		mrs, err = eng.ListReadyMRs()
+		if err != nil {
+			return err
+		}
+	}
+	if len(mrs) == 0 {
+		fmt.Printf("%s No ready merge requests in %s\n", style.Dim.Render("○"), r.Name)
+		return nil
+	}
+
+	router := mail.NewRouter(r.Path)
+	var merged, waiting, failed int
+	for _, mr := range mrs {
+		result := eng.ProcessMRInfo(context.Background(), mr)
+		switch {
+		case result.Success:
+			eng.HandleMRInfoSuccess(mr, result)
+			msg := protocol.NewMergedMessage(r.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, result.MergeCommit)
+			protocol.SetTraceID(msg, mr.TraceID)
+			if err := router.Send(msg); err != nil {
+				style.PrintWarning("could not send MERGED to witness: %v", err)
+			}
+			merged++
+		case result.Waiting != "":
+			eng.HandleMRInfoFailure(mr, result)
+			waiting++
+		default:
+			eng.HandleMRInfoFailure(mr, result)
+			failed++
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("%s %d merged, %d waiting, %d need attention\n", style.Bold.Render(label+":"), merged, waiting, failed)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -37,6 +38,12 @@ type MRStatusOutput struct {
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
 	Blocks    []DependencyInfo `json:"blocks,omitempty"`
+
+	// Merge order among queued MRs (open MRs only)
+	MergeAfter  []string `json:"merge_after,omitempty"`  // MRs that must merge first
+	MergeChain  []string `json:"merge_chain,omitempty"`  // Landing order through this MR, first to last
+	MergeBefore []string `json:"merge_before,omitempty"` // MRs waiting on this one
+	MergeCycle  []string `json:"merge_cycle,omitempty"`  // Prerequisites that also wait on this MR
 }
 
 // DependencyInfo represents a dependency or blocker.
@@ -119,6 +126,26 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	// Merge order among the queued MRs
+	var graph *refinery.MRGraph
+	if issue.Status == "open" && mrFields != nil {
+		graph, err = refinery.LoadMRGraph(bd)
+		if err != nil {
+			style.PrintWarning("could not load merge order: %v", err)
+			graph = nil
+		}
+	}
+	if graph != nil {
+		output.MergeAfter = graph.WaitingOn(issue.ID)
+		output.MergeBefore = graph.Dependents(issue.ID)
+		output.MergeCycle = graph.CycleWith(issue.ID)
+		if chain := graph.Chain(issue.ID); len(chain) > 1 {
+			for i := len(chain) - 1; i >= 0; i-- {
+				output.MergeChain = append(output.MergeChain, chain[i])
+			}
+		}
+	}
+
 	// JSON output
 	if mqStatusJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -127,11 +154,12 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Human-readable output
-	return printMqStatus(issue, mrFields)
+	return printMqStatus(issue, mrFields, graph)
 }
 
 // printMqStatus prints detailed MR status in human-readable format.
-func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
+// graph is the merge-order graph of the queue, or nil if not loaded.
+func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields, graph *refinery.MRGraph) error {
 	// Header
 	fmt.Printf("%s %s\n", style.Bold.Render("📋 Merge Request:"), issue.ID)
 	fmt.Printf("   %s\n\n", issue.Title)
@@ -212,6 +240,11 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		}
 	}
 
+	// Merge order among the queued MRs
+	if graph != nil {
+		printMergeOrder(issue.ID, graph)
+	}
+
 	// Description (if present and not just MR fields)
 	desc := getDescriptionWithoutMRFields(issue.Description)
 	if desc != "" {
@@ -225,6 +258,37 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 	return nil
 }
 
+// printMergeOrder prints which queued MRs must merge before and after id.
+func printMergeOrder(id string, graph *refinery.MRGraph) {
+	after := graph.WaitingOn(id)
+	before := graph.Dependents(id)
+	cycle := graph.CycleWith(id)
+	if len(after) == 0 && len(before) == 0 && len(cycle) == 0 {
+		return
+	}
+
+	describe := func(mrID string) string {
+		if mr := graph.MR(mrID); mr != nil && mr.Branch != "" {
+			return fmt.Sprintf("%s %s", mrID, style.Dim.Render("("+mr.Branch+")"))
+		}
+		return mrID
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Merge Order"))
+	for _, p := range after {
+		fmt.Printf("   ○ after  %s\n", describe(p))
+	}
+	for _, d := range before {
+		fmt.Printf("   ○ before %s\n", describe(d))
+	}
+	if chain := graph.Chain(id); len(chain) > 2 {
+		fmt.Printf("   Chain:   %s\n", formatMergeChain(graph, chain))
+	}
+	if len(cycle) > 0 {
+		style.PrintWarning("dependency cycle with %s; merge order falls back to priority", strings.Join(cycle, ", "))
+	}
+}
+
 // formatStatus formats the status with appropriate styling.
 func formatStatus(status string) string {
 	switch status {
@@ -163,11 +163,14 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 
 	// Build MR bead title and description
 	title := fmt.Sprintf("Merge: %s", issueID)
-	description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
-		branch, target, issueID, rigName)
-	if worker != "" {
-		description += fmt.Sprintf("\nworker: %s", worker)
-	}
+	description := beads.SetMRFields(nil, &beads.MRFields{
+		Branch:      branch,
+		Target:      target,
+		SourceIssue: issueID,
+		Rig:         rigName,
+		Worker:      worker,
+		DependsOnMR: strings.Join(mqSubmitDependsOn, ","),
+	})
 
 	// Check if MR bead already exists for this branch (idempotency)
 	var mrIssue *beads.Issue
@@ -202,6 +205,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	if len(mqSubmitDependsOn) > 0 {
+		fmt.Printf("  Depends on: %s\n", strings.Join(mqSubmitDependsOn, ", "))
+	}
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
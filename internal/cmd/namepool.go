@@ -13,8 +13,9 @@ import (
 )
 
 var (
-	namepoolListFlag  bool
-	namepoolThemeFlag string
+	namepoolListFlag     bool
+	namepoolThemeFlag    string
+	namepoolStrategyFlag string
 )
 
 var namepoolCmd = &cobra.Command{
@@ -26,11 +27,24 @@ var namepoolCmd = &cobra.Command{
 By default, polecats get themed names from the Mad Max universe
 (furiosa, nux, slit, etc.). You can change the theme or add custom names.
 
+THEME FILES:
+  Towns and rigs can define their own themes in settings/namepool/, as
+  <theme>.txt (one name per line, optional weight after the name) or
+  <theme>.toml (names = [...], optional [weights], strategy, description).
+  Rig themes override town themes, which override built-ins.
+
+ALLOCATION:
+  Set "strategy" in the rig's namepool settings to pick free names
+  sequentially (default), at random, or weighted by the theme's weights.
+  Set "retire_cooldown" (e.g. "24h") to keep the names of polecats nuked
+  with unfinished work out of rotation for a while.
+
 Examples:
   gt namepool              # Show current pool status
   gt namepool --list       # List available themes
   gt namepool themes       # Show theme names
   gt namepool set minerals # Set theme to 'minerals'
+  gt namepool set norse --strategy weighted
   gt namepool add ember    # Add custom name to pool
   gt namepool reset        # Reset pool state`,
 	RunE: runNamepool,
@@ -39,7 +53,9 @@ Examples:
 var namepoolThemesCmd = &cobra.Command{
 	Use:   "themes [theme]",
 	Short: "List available themes and their names",
-	RunE:  runNamepoolThemes,
+	Long: `List built-in themes and the themes defined in the town's and the
+current rig's settings/namepool/ directory, or show one theme's names.`,
+	RunE: runNamepoolThemes,
 }
 
 var namepoolSetCmd = &cobra.Command{
@@ -69,6 +85,7 @@ func init() {
 	namepoolCmd.AddCommand(namepoolAddCmd)
 	namepoolCmd.AddCommand(namepoolResetCmd)
 	namepoolCmd.Flags().BoolVarP(&namepoolListFlag, "list", "l", false, "List available themes")
+	namepoolSetCmd.Flags().StringVar(&namepoolStrategyFlag, "strategy", "", "Allocation strategy: sequential, random or weighted")
 }
 
 func runNamepool(cmd *cobra.Command, args []string) error {
@@ -85,21 +102,14 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 
 	// Load settings for namepool config
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
-	var pool *polecat.NamePool
-
+	var namepoolCfg *config.NamepoolConfig
 	settings, err := config.LoadRigSettings(settingsPath)
-	if err == nil && settings.Namepool != nil {
-		// Use configured namepool settings
-		pool = polecat.NewNamePoolWithConfig(
-			rigPath,
-			rigName,
-			settings.Namepool.Style,
-			settings.Namepool.Names,
-			settings.Namepool.MaxBeforeNumbering,
-		)
-	} else {
-		// Use defaults
-		pool = polecat.NewNamePool(rigPath, rigName)
+	if err == nil {
+		namepoolCfg = settings.Namepool
+	}
+	pool, err := polecat.NewNamePoolFromSettings(rigPath, rigName, namepoolCfg)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
 	}
 
 	if err := pool.Load(); err != nil {
@@ -114,6 +124,7 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 	// Show pool status
 	fmt.Printf("Rig: %s\n", rigName)
 	fmt.Printf("Theme: %s\n", pool.GetTheme())
+	fmt.Printf("Strategy: %s\n", pool.GetStrategy())
 	fmt.Printf("Active polecats: %d\n", pool.ActiveCount())
 	
 	activeNames := pool.ActiveNames()
@@ -121,8 +132,12 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 		fmt.Printf("In use: %s\n", strings.Join(activeNames, ", "))
 	}
 
+	for _, r := range pool.RetiredNames() {
+		fmt.Printf("Retired: %s (until %s)\n", r.Name, r.Until.Local().Format("2006-01-02 15:04"))
+	}
+
 	// Check if configured (already loaded above)
-	if settings.Namepool != nil {
+	if namepoolCfg != nil {
 		fmt.Printf("(configured in settings/config.json)\n")
 	}
 
@@ -130,16 +145,29 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 }
 
 func runNamepoolThemes(cmd *cobra.Command, args []string) error {
-	themes := polecat.ListThemes()
+	themes, err := loadNamepoolThemes()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	sorted := polecat.SortedThemes(themes)
 
 	if len(args) == 0 {
 		// List all themes
 		fmt.Println("Available themes:")
-		for _, theme := range themes {
-			names, _ := polecat.GetThemeNames(theme)
-			fmt.Printf("\n  %s (%d names):\n", theme, len(names))
+		for _, theme := range sorted {
+			label := fmt.Sprintf("%d names", len(theme.Names))
+			if theme.Source != polecat.ThemeSourceBuiltin {
+				label += ", " + theme.Source
+			}
+			if theme.Strategy != "" {
+				label += ", " + theme.Strategy
+			}
+			fmt.Printf("\n  %s (%s):\n", theme.Name, label)
+			if theme.Description != "" {
+				fmt.Printf("    %s\n", theme.Description)
+			}
 			// Show first 10 names
-			preview := names
+			preview := theme.Names
 			if len(preview) > 10 {
 				preview = preview[:10]
 			}
@@ -149,17 +177,23 @@ func runNamepoolThemes(cmd *cobra.Command, args []string) error {
 	}
 
 	// Show specific theme names
-	theme := args[0]
-	names, err := polecat.GetThemeNames(theme)
-	if err != nil {
-		return fmt.Errorf("unknown theme: %s (available: %s)", theme, strings.Join(themes, ", "))
+	theme, ok := themes[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown theme: %s (available: %s)", args[0], strings.Join(themeNameList(sorted), ", "))
 	}
 
-	fmt.Printf("Theme: %s (%d names)\n\n", theme, len(names))
-	for i, name := range names {
+	fmt.Printf("Theme: %s (%d names)\n", theme.Name, len(theme.Names))
+	if theme.Path != "" {
+		fmt.Printf("Defined in: %s\n", theme.Path)
+	}
+	fmt.Println()
+	for i, name := range theme.Names {
 		if i > 0 && i%5 == 0 {
 			fmt.Println()
 		}
+		if w := theme.Weights[name]; w > 0 {
+			name = fmt.Sprintf("%s×%d", name, w)
+		}
 		fmt.Printf("  %-12s", name)
 	}
 	fmt.Println()
@@ -167,20 +201,38 @@ func runNamepoolThemes(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loadNamepoolThemes loads the built-in themes plus the town's theme files
+// and, when run inside a rig, the rig's.
+func loadNamepoolThemes() (map[string]*polecat.Theme, error) {
+	townRoot, _ := workspace.FindFromCwd()
+	_, rigPath := detectCurrentRigWithPath()
+	return polecat.LoadThemes(townRoot, rigPath)
+}
+
+// themeNameList returns the names of themes.
+func themeNameList(themes []*polecat.Theme) []string {
+	names := make([]string, 0, len(themes))
+	for _, t := range themes {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
 func runNamepoolSet(cmd *cobra.Command, args []string) error {
 	theme := args[0]
 
 	// Validate theme
-	themes := polecat.ListThemes()
-	valid := false
-	for _, t := range themes {
-		if t == theme {
-			valid = true
-			break
-		}
+	themes, err := loadNamepoolThemes()
+	if err != nil {
+		return fmt.Errorf("loading themes: %w", err)
+	}
+	if _, ok := themes[theme]; !ok {
+		return fmt.Errorf("unknown theme: %s (available: %s)", theme, strings.Join(themeNameList(polecat.SortedThemes(themes)), ", "))
 	}
-	if !valid {
-		return fmt.Errorf("unknown theme: %s (available: %s)", theme, strings.Join(themes, ", "))
+	switch namepoolStrategyFlag {
+	case "", polecat.StrategySequential, polecat.StrategyRandom, polecat.StrategyWeighted:
+	default:
+		return fmt.Errorf("unknown strategy: %s (available: sequential, random, weighted)", namepoolStrategyFlag)
 	}
 
 	// Get rig
@@ -190,7 +242,7 @@ func runNamepoolSet(cmd *cobra.Command, args []string) error {
 	}
 
 	// Update pool
-	pool := polecat.NewNamePool(rigPath, rigName)
+	pool, _ := polecat.NewNamePoolFromSettings(rigPath, rigName, nil)
 	if err := pool.Load(); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("loading pool: %w", err)
 	}
@@ -203,21 +255,15 @@ func runNamepoolSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("saving pool: %w", err)
 	}
 
-	// Load existing settings to preserve custom names when changing theme
-	settingsPath := filepath.Join(rigPath, "settings", "config.json")
-	var existingNames []string
-	if existingSettings, err := config.LoadRigSettings(settingsPath); err == nil {
-		if existingSettings.Namepool != nil {
-			existingNames = existingSettings.Namepool.Names
-		}
-	}
-
 	// Also save to rig config, preserving existing custom names
-	if err := saveRigNamepoolConfig(rigPath, theme, existingNames); err != nil {
+	if err := saveRigNamepoolConfig(rigPath, theme, namepoolStrategyFlag); err != nil {
 		return fmt.Errorf("saving config: %w", err)
 	}
 
 	fmt.Printf("Theme '%s' set for rig '%s'\n", theme, rigName)
+	if namepoolStrategyFlag != "" {
+		fmt.Printf("Allocation strategy: %s\n", namepoolStrategyFlag)
+	}
 	fmt.Printf("New polecats will use names from this theme.\n")
 
 	return nil
@@ -321,8 +367,9 @@ func detectCurrentRigWithPath() (string, string) {
 	return "", ""
 }
 
-// saveRigNamepoolConfig saves the namepool config to rig settings.
-func saveRigNamepoolConfig(rigPath, theme string, customNames []string) error {
+// saveRigNamepoolConfig saves the namepool theme (and strategy, if set) to
+// rig settings, preserving the rest of the namepool config.
+func saveRigNamepoolConfig(rigPath, theme, strategy string) error {
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
 
 	// Load existing settings or create new
@@ -338,9 +385,12 @@ func saveRigNamepoolConfig(rigPath, theme string, customNames []string) error {
 	}
 
 	// Set namepool
-	settings.Namepool = &config.NamepoolConfig{
-		Style: theme,
-		Names: customNames,
+	if settings.Namepool == nil {
+		settings.Namepool = &config.NamepoolConfig{}
+	}
+	settings.Namepool.Style = theme
+	if strategy != "" {
+		settings.Namepool.Strategy = strategy
 	}
 
 	// Save (creates directory if needed)
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -64,6 +65,10 @@ all currently active polecats with their states:
   - working: Actively working on an issue
   - done: Completed work, waiting for cleanup
   - stuck: Needs assistance
+  - warm: Idle in the rig's warm pool, ready to be handed out by sling
+
+Rigs with a warm pool (polecat_pool in <rig>/settings/config.json) also
+show how many warm polecats are ready and how many have a session running.
 
 Examples:
   gt polecat list greenplace
@@ -138,6 +143,8 @@ Displays comprehensive information including:
   - Session status (running/stopped, attached/detached)
   - Session creation time
   - Last activity time
+  - Reserved ports (when the rig sets polecat_ports)
+  - Resource limits and usage (when the rig sets polecat_resources)
 
 Examples:
   gt polecat status greenplace/Toast
@@ -189,6 +196,9 @@ This is the nuclear option for post-merge cleanup. It:
   3. Deletes the polecat branch
   4. Closes the agent bead (if exists)
 
+A polecat nuked while it still has assigned work is treated as failed: its
+name is retired for the rig's namepool retire_cooldown, if one is set.
+
 SAFETY CHECKS: The command refuses to nuke a polecat if:
   - Worktree has unpushed/uncommitted changes
   - Polecat has an open merge request (MR bead)
@@ -375,12 +385,21 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 	// Collect polecats from all rigs
 	t := tmux.NewTmux()
 	var allPolecats []PolecatListItem
+	pools := make(map[string]*polecat.WarmPoolStatus)
+	var poolRigs []string
 
 	for _, r := range rigs {
 		polecatGit := git.NewGit(r.Path)
 		mgr := polecat.NewManager(r, polecatGit, t)
 		polecatMgr := polecat.NewSessionManager(t, r)
 
+		if mgr.WarmPoolConfig() != nil {
+			if pool, err := mgr.WarmPoolStatus(); err == nil {
+				pools[r.Name] = pool
+				poolRigs = append(poolRigs, r.Name)
+			}
+		}
+
 		polecats, err := mgr.List()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to list polecats in %s: %v\n", r.Name, err)
@@ -408,6 +427,7 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 
 	if len(allPolecats) == 0 {
 		fmt.Println("No active polecats found.")
+		printWarmPools(poolRigs, pools)
 		return nil
 	}
 
@@ -431,6 +451,8 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 			stateStr = style.Warning.Render(stateStr)
 		case polecat.StateDone:
 			stateStr = style.Success.Render(stateStr)
+		case polecat.StateWarm:
+			stateStr = style.Info.Render(stateStr)
 		default:
 			stateStr = style.Dim.Render(stateStr)
 		}
@@ -440,10 +462,29 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 			fmt.Printf("    %s\n", style.Dim.Render(p.Issue))
 		}
 	}
+	printWarmPools(poolRigs, pools)
 
 	return nil
 }
 
+// printWarmPools prints a summary line per rig with a warm pool.
+func printWarmPools(rigNames []string, pools map[string]*polecat.WarmPoolStatus) {
+	if len(rigNames) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render("Warm Pools"))
+	for _, name := range rigNames {
+		pool := pools[name]
+		ready := fmt.Sprintf("%d/%d ready", len(pool.Ready), pool.Size)
+		if len(pool.Ready) < pool.Size {
+			ready = style.Warning.Render(ready)
+		} else {
+			ready = style.Success.Render(ready)
+		}
+		fmt.Printf("  %s  %s  %s\n", name, ready, style.Dim.Render(fmt.Sprintf("(%d with sessions)", pool.Sessions)))
+	}
+}
+
 func runPolecatAdd(cmd *cobra.Command, args []string) error {
 	// Emit deprecation warning
 	fmt.Fprintf(os.Stderr, "%s 'gt polecat add' is deprecated. Use 'gt polecat identity add' instead.\n",
@@ -542,18 +583,20 @@ func runPolecatSync(cmd *cobra.Command, args []string) error {
 
 // PolecatStatus represents detailed polecat status for JSON output.
 type PolecatStatus struct {
-	Rig            string        `json:"rig"`
-	Name           string        `json:"name"`
-	State          polecat.State `json:"state"`
-	Issue          string        `json:"issue,omitempty"`
-	ClonePath      string        `json:"clone_path"`
-	Branch         string        `json:"branch"`
-	SessionRunning bool          `json:"session_running"`
-	SessionID      string        `json:"session_id,omitempty"`
-	Attached       bool          `json:"attached,omitempty"`
-	Windows        int           `json:"windows,omitempty"`
-	CreatedAt      string        `json:"created_at,omitempty"`
-	LastActivity   string        `json:"last_activity,omitempty"`
+	Rig            string             `json:"rig"`
+	Name           string             `json:"name"`
+	State          polecat.State      `json:"state"`
+	Issue          string             `json:"issue,omitempty"`
+	ClonePath      string             `json:"clone_path"`
+	Branch         string             `json:"branch"`
+	SessionRunning bool               `json:"session_running"`
+	SessionID      string             `json:"session_id,omitempty"`
+	Attached       bool               `json:"attached,omitempty"`
+	Windows        int                `json:"windows,omitempty"`
+	CreatedAt      string             `json:"created_at,omitempty"`
+	LastActivity   string             `json:"last_activity,omitempty"`
+	Ports          *polecat.PortBlock `json:"ports,omitempty"`
+	Resources      *sandbox.Report    `json:"resources,omitempty"`
 }
 
 func runPolecatStatus(cmd *cobra.Command, args []string) error {
@@ -585,6 +628,17 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Reserved port block (nil if the rig doesn't allocate ports)
+	ports := polecat.PortsFor(r.Path, polecatName)
+
+	// Resource policy and usage (non-fatal: status works without it)
+	var resources *sandbox.Report
+	if res, err := sandbox.LoadPolicy(r.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if res != nil {
+		resources = sandbox.Inspect(rigName, polecatName, p.ClonePath, res, sessInfo.Running)
+	}
+
 	// JSON output
 	if polecatStatusJSON {
 		status := PolecatStatus{
@@ -598,6 +652,8 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 			SessionID:      sessInfo.SessionID,
 			Attached:       sessInfo.Attached,
 			Windows:        sessInfo.Windows,
+			Ports:          ports,
+			Resources:      resources,
 		}
 		if !sessInfo.Created.IsZero() {
 			status.CreatedAt = sessInfo.Created.Format("2006-01-02 15:04:05")
@@ -637,6 +693,9 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 	// Clone path and branch
 	fmt.Printf("  Clone:         %s\n", style.Dim.Render(p.ClonePath))
 	fmt.Printf("  Branch:        %s\n", style.Dim.Render(p.Branch))
+	if ports != nil {
+		fmt.Printf("  Ports:         %s\n", ports)
+	}
 
 	// Session info
 	fmt.Println()
@@ -671,9 +730,74 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Status:        %s\n", style.Dim.Render("not running"))
 	}
 
+	if resources != nil {
+		printPolecatResources(resources, sessInfo.Running)
+	}
+
 	return nil
 }
 
+// printPolecatResources prints the Resources section of gt polecat status.
+func printPolecatResources(rep *sandbox.Report, running bool) {
+	fmt.Println()
+	fmt.Printf("%s\n", style.Bold.Render("Resources"))
+
+	limit := func(v string) string {
+		if v == "" {
+			return style.Dim.Render("unlimited")
+		}
+		return v
+	}
+	processes := ""
+	if rep.Limits.MaxProcesses > 0 {
+		processes = fmt.Sprintf("%d", rep.Limits.MaxProcesses)
+	}
+	network := rep.Limits.Network
+	if network == "" {
+		network = sandbox.NetworkHost
+	}
+
+	fmt.Printf("  CPU:           %s\n", limit(rep.Limits.CPU))
+	fmt.Printf("  Memory:        %s\n", limit(rep.Limits.Memory))
+	fmt.Printf("  Processes:     %s\n", limit(processes))
+	fmt.Printf("  Network:       %s\n", network)
+	if rep.Unit != "" && running {
+		if rep.Enforced {
+			fmt.Printf("  Scope:         %s\n", style.Dim.Render(rep.Unit))
+		} else {
+			fmt.Printf("  Scope:         %s\n", style.Warning.Render("not enforced"))
+		}
+	}
+
+	if u := rep.Usage; u != nil {
+		mem := sandbox.FormatSize(u.MemoryBytes)
+		if u.MemoryMaxBytes > 0 {
+			mem += " / " + sandbox.FormatSize(u.MemoryMaxBytes)
+		}
+		fmt.Printf("  Memory Used:   %s\n", mem)
+		procs := fmt.Sprintf("%d", u.Processes)
+		if u.ProcessesMax > 0 {
+			procs += fmt.Sprintf(" / %d", u.ProcessesMax)
+		}
+		fmt.Printf("  Tasks:         %s\n", procs)
+		fmt.Printf("  CPU Time:      %.0fs", u.CPUSeconds)
+		if u.Throttled > 0 {
+			fmt.Printf(" %s", style.Dim.Render(fmt.Sprintf("(throttled %d periods)", u.Throttled)))
+		}
+		fmt.Println()
+	}
+
+	disk := sandbox.FormatSize(rep.DiskBytes)
+	if rep.DiskWarnBytes > 0 {
+		disk += " / " + sandbox.FormatSize(rep.DiskWarnBytes) + " warn"
+	}
+	fmt.Printf("  Disk:          %s\n", disk)
+
+	for _, w := range rep.Warnings {
+		fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), w)
+	}
+}
+
 // formatActivityTime returns a human-readable relative time string.
 func formatActivityTime(t time.Time) string {
 	d := time.Since(t)
@@ -1122,8 +1246,10 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		// Step 2: Get polecat info before deletion (for branch name)
 		polecatInfo, err := p.mgr.Get(p.polecatName)
 		var branchToDelete string
+		var unfinished bool
 		if err == nil && polecatInfo != nil {
 			branchToDelete = polecatInfo.Branch
+			unfinished = polecatInfo.Issue != "" || polecatInfo.State == polecat.StateStuck
 		}
 
 		// Step 3: Delete worktree (nuclear mode - bypass all safety checks)
@@ -1136,6 +1262,11 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 			}
 		} else {
 			fmt.Printf("  %s deleted worktree\n", style.Success.Render("✓"))
+			// A polecat nuked with its work unfinished failed; keep its name
+			// out of rotation for the namepool retire_cooldown
+			if unfinished && p.mgr.RetireName(p.polecatName) {
+				fmt.Printf("  %s retired name %s\n", style.Success.Render("✓"), p.polecatName)
+			}
 		}
 
 		// Step 4: Delete branch (if we know it)
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var polecatPoolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage a rig's warm polecat pool",
+	Long: `Manage a rig's warm polecat pool.
+
+A warm pool keeps idle polecats with worktrees, overlay files and setup hooks
+already in place, so gt sling can hand one out instead of creating a polecat
+from scratch. Configure it in <rig>/settings/config.json:
+
+  "polecat_pool": {
+    "size": 2,
+    "start_sessions": true,
+    "max_age": "24h"
+  }
+
+The daemon replenishes pools on each heartbeat. Use these commands to fill a
+pool immediately or to drain it after lowering its size.`,
+	RunE: requireSubcommand,
+}
+
+var polecatPoolFillCmd = &cobra.Command{
+	Use:   "fill <rig>",
+	Short: "Fill a rig's warm pool to its configured size",
+	Long: `Fill a rig's warm pool to its configured size.
+
+Recycles warm polecats older than max_age, creates new ones until the pool
+reaches its size, and starts their sessions if start_sessions is set.
+
+Example:
+  gt polecat pool fill greenplace`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPoolFill,
+}
+
+var polecatPoolDrainCmd = &cobra.Command{
+	Use:   "drain <rig>",
+	Short: "Remove every warm polecat in a rig",
+	Long: `Remove every warm polecat in a rig, stopping any pre-started sessions.
+
+The daemon refills the pool on its next heartbeat unless polecat_pool is
+removed or its size set to 0.
+
+Example:
+  gt polecat pool drain greenplace`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPoolDrain,
+}
+
+func init() {
+	polecatPoolCmd.AddCommand(polecatPoolFillCmd)
+	polecatPoolCmd.AddCommand(polecatPoolDrainCmd)
+
+	polecatCmd.AddCommand(polecatPoolCmd)
+}
+
+func runPolecatPoolFill(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	if mgr.WarmPoolConfig() == nil {
+		return fmt.Errorf("rig %s has no warm pool (set polecat_pool.size in settings/config.json)", rigName)
+	}
+
+	created, recycled, err := mgr.ReplenishWarmPool()
+	if err != nil {
+		return err
+	}
+
+	status, err := mgr.WarmPoolStatus()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Warm pool for %s: %d/%d ready (%d created, %d recycled)\n",
+		style.Success.Render("✓"), rigName, len(status.Ready), status.Size, created, recycled)
+	return nil
+}
+
+func runPolecatPoolDrain(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	removed, err := mgr.DrainWarmPool()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Drained %d warm polecat(s) from %s\n", style.Success.Render("✓"), removed, rigName)
+	return nil
+}
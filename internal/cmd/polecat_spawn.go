@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -38,6 +39,7 @@ type SlingSpawnOptions struct {
 	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent    string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
+	TraceID  string // Lifecycle trace ID, exported to the session as GT_TRACE_ID
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -69,44 +71,29 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	t := tmux.NewTmux()
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
 
-	// Allocate a new polecat name
-	polecatName, err := polecatMgr.AllocateName()
-	if err != nil {
-		return nil, fmt.Errorf("allocating polecat name: %w", err)
-	}
-	fmt.Printf("Allocated polecat: %s\n", polecatName)
-
-	// Check if polecat already exists (shouldn't happen - indicates stale state needing repair)
-	existingPolecat, err := polecatMgr.Get(polecatName)
-
 	// Build add options with hook_bead set atomically at spawn time
 	addOpts := polecat.AddOptions{
 		HookBead: opts.HookBead,
 	}
 
-	if err == nil {
-		// Stale state: polecat exists despite fresh name allocation - repair it
-		// Check for uncommitted work first
-		if !opts.Force {
-			pGit := git.NewGit(existingPolecat.ClonePath)
-			workStatus, checkErr := pGit.CheckUncommittedWork()
-			if checkErr == nil && !workStatus.Clean() {
-				return nil, fmt.Errorf("polecat '%s' has uncommitted work: %s\nUse --force to proceed anyway",
-					polecatName, workStatus.String())
-			}
-		}
-		fmt.Printf("Repairing stale polecat %s with fresh worktree...\n", polecatName)
-		if _, err = polecatMgr.RepairWorktreeWithOptions(polecatName, opts.Force, addOpts); err != nil {
-			return nil, fmt.Errorf("repairing stale polecat: %w", err)
+	// Prefer a warm polecat from the rig's pool: its worktree and setup hooks
+	// are already done.
+	var polecatName string
+	claimedWarm := false
+	if useWarmPool(opts) {
+		if warm, err := polecatMgr.ClaimWarm(addOpts); err == nil {
+			polecatName = warm.Name
+			claimedWarm = true
+			fmt.Printf("Claimed warm polecat: %s\n", polecatName)
+		} else if err != polecat.ErrNoWarmPolecat {
+			fmt.Printf("Warning: could not claim warm polecat: %v\n", err)
 		}
-	} else if err == polecat.ErrPolecatNotFound {
-		// Create new polecat
-		fmt.Printf("Creating polecat %s...\n", polecatName)
-		if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
-			return nil, fmt.Errorf("creating polecat: %w", err)
+	}
+
+	if polecatName == "" {
+		if polecatName, err = allocatePolecatForSling(polecatMgr, addOpts, opts.Force); err != nil {
+			return nil, err
 		}
-	} else {
-		return nil, fmt.Errorf("getting polecat: %w", err)
 	}
 
 	// Get polecat object for path info
@@ -121,6 +108,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	if err != nil {
 		return nil, fmt.Errorf("resolving account: %w", err)
 	}
+	claudeConfigDir, accountHandle = avoidCoolingAccount(townRoot, opts.Account, claudeConfigDir, accountHandle)
 	if accountHandle != "" {
 		fmt.Printf("Using account: %s\n", accountHandle)
 	}
@@ -130,10 +118,26 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	// Check if already running
 	running, _ := polecatSessMgr.IsRunning(polecatName)
+	if running && claimedWarm {
+		if claudeConfigDir != "" {
+			// The warm session was started without an account; restart it on
+			// the resolved one so cooling accounts are avoided.
+			fmt.Printf("Restarting warm session for %s/%s on account %s...\n", rigName, polecatName, accountHandle)
+			if err := polecatSessMgr.Stop(polecatName, true); err != nil {
+				return nil, fmt.Errorf("stopping warm session: %w", err)
+			}
+			running = false
+		} else if opts.TraceID != "" {
+			// The running agent reads the trace ID from its hooked bead; the
+			// session environment covers processes started from now on.
+			_ = t.SetEnvironment(polecatSessMgr.SessionName(polecatName), trace.EnvVar, opts.TraceID)
+		}
+	}
 	if !running {
 		fmt.Printf("Starting session for %s/%s...\n", rigName, polecatName)
 		startOpts := polecat.SessionStartOptions{
 			RuntimeConfigDir: claudeConfigDir,
+			TraceID:          opts.TraceID,
 		}
 		if opts.Agent != "" {
 			cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, r.Path, "", opts.Agent)
@@ -157,7 +161,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	fmt.Printf("%s Polecat %s spawned\n", style.Bold.Render("✓"), polecatName)
 
 	// Log spawn event to activity feed
-	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
+	spawnPayload := events.SpawnPayload(rigName, polecatName)
+	if opts.HookBead != "" {
+		spawnPayload["bead"] = opts.HookBead
+	}
+	_ = events.LogFeed(events.TypeSpawn, "gt", trace.Annotate(spawnPayload, opts.TraceID))
 
 	return &SpawnedPolecatInfo{
 		RigName:     rigName,
@@ -168,6 +176,54 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	}, nil
 }
 
+// useWarmPool reports whether a spawn may claim a polecat from the rig's warm
+// pool. Agent and account overrides need a session started for them, so they
+// skip the pool.
+func useWarmPool(opts SlingSpawnOptions) bool {
+	return opts.Agent == "" && opts.Account == ""
+}
+
+// allocatePolecatForSling allocates a polecat name and creates its worktree,
+// repairing the polecat if stale state left one behind under that name.
+func allocatePolecatForSling(polecatMgr *polecat.Manager, addOpts polecat.AddOptions, force bool) (string, error) {
+	// Allocate a new polecat name
+	polecatName, err := polecatMgr.AllocateName()
+	if err != nil {
+		return "", fmt.Errorf("allocating polecat name: %w", err)
+	}
+	fmt.Printf("Allocated polecat: %s\n", polecatName)
+
+	// Check if polecat already exists (shouldn't happen - indicates stale state needing repair)
+	existingPolecat, err := polecatMgr.Get(polecatName)
+
+	if err == nil {
+		// Stale state: polecat exists despite fresh name allocation - repair it
+		// Check for uncommitted work first
+		if !force {
+			pGit := git.NewGit(existingPolecat.ClonePath)
+			workStatus, checkErr := pGit.CheckUncommittedWork()
+			if checkErr == nil && !workStatus.Clean() {
+				return "", fmt.Errorf("polecat '%s' has uncommitted work: %s\nUse --force to proceed anyway",
+					polecatName, workStatus.String())
+			}
+		}
+		fmt.Printf("Repairing stale polecat %s with fresh worktree...\n", polecatName)
+		if _, err = polecatMgr.RepairWorktreeWithOptions(polecatName, force, addOpts); err != nil {
+			return "", fmt.Errorf("repairing stale polecat: %w", err)
+		}
+	} else if err == polecat.ErrPolecatNotFound {
+		// Create new polecat
+		fmt.Printf("Creating polecat %s...\n", polecatName)
+		if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
+			return "", fmt.Errorf("creating polecat: %w", err)
+		}
+	} else {
+		return "", fmt.Errorf("getting polecat: %w", err)
+	}
+
+	return polecatName, nil
+}
+
 // IsRigName checks if a target string is a rig name (not a role or path).
 // Returns the rig name and true if it's a valid rig.
 func IsRigName(target string) (string, bool) {
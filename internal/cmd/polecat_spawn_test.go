@@ -0,0 +1,20 @@
+package cmd
+
+import "testing"
+
+func TestUseWarmPool(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SlingSpawnOptions
+		want bool
+	}{
+		{"plain sling", SlingSpawnOptions{HookBead: "gt-abc", TraceID: "0123"}, true},
+		{"account override", SlingSpawnOptions{HookBead: "gt-abc", Account: "work"}, false},
+		{"agent override", SlingSpawnOptions{HookBead: "gt-abc", Agent: "codex"}, false},
+	}
+	for _, tt := range tests {
+		if got := useWarmPool(tt.opts); got != tt.want {
+			t.Errorf("%s: useWarmPool() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -171,7 +172,8 @@ func emitSessionEvent(ctx RoleContext) {
 	}
 
 	// Emit the event
-	payload := events.SessionPayload(sessionID, actor, topic, ctx.WorkDir)
+	// Polecats spawned by gt sling inherit the trace ID of their work
+	payload := trace.Annotate(events.SessionPayload(sessionID, actor, topic, ctx.WorkDir), trace.FromEnv())
 	_ = events.LogFeed(events.TypeSessionStart, actor, payload)
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -202,12 +203,16 @@ var refineryReadyJSON bool
 
 var refineryBlockedCmd = &cobra.Command{
 	Use:   "blocked [rig]",
-	Short: "List MRs blocked by open tasks",
-	Long: `List merge requests blocked by open tasks.
+	Short: "List MRs blocked by open tasks or waiting on other MRs",
+	Long: `List merge requests blocked by open tasks or waiting on other MRs.
 
 Shows MRs waiting for conflict resolution or other blocking tasks to complete.
 When the blocking task closes, the MR will appear in 'ready'.
 
+Also shows MRs held in merge order: an MR whose source issue depends on
+another queued MR's source issue (or that names it in depends_on_mr) waits
+until that MR merges. Chains of such MRs are shown in the order they land.
+
 Examples:
   gt refinery blocked
   gt refinery blocked --json`,
@@ -747,14 +752,47 @@ func runRefineryBlocked(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// The merge-order graph explains MRs held behind other MRs
+	var graph *refinery.MRGraph
+	for _, mr := range blocked {
+		if len(mr.DependsOn) > 0 {
+			if graph, err = eng.MRGraph(); err != nil {
+				style.PrintWarning("could not load merge order: %v", err)
+			}
+			break
+		}
+	}
+
 	for i, mr := range blocked {
 		priority := fmt.Sprintf("P%d", mr.Priority)
 		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
 		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
-		if mr.BlockedBy != "" {
+		switch {
+		case len(mr.DependsOn) > 0:
+			fmt.Printf("     Waiting on MR: %s\n", strings.Join(mr.DependsOn, ", "))
+			if graph != nil {
+				if chain := graph.Chain(mr.ID); len(chain) > 2 {
+					fmt.Printf("     Merge order: %s\n", formatMergeChain(graph, chain))
+				}
+			}
+		case mr.BlockedBy != "":
 			fmt.Printf("     Blocked by: %s\n", mr.BlockedBy)
 		}
 	}
 
 	return nil
 }
+
+// formatMergeChain renders a merge-order chain (see MRGraph.Chain) in the
+// order the MRs will land: "gt-mr-a (polecat/a) → gt-mr-b → gt-mr-c".
+func formatMergeChain(graph *refinery.MRGraph, chain []string) string {
+	parts := make([]string, 0, len(chain))
+	for i := len(chain) - 1; i >= 0; i-- {
+		part := chain[i]
+		if mr := graph.MR(part); mr != nil && mr.Branch != "" {
+			part += " (" + mr.Branch + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " → ")
+}
@@ -1491,46 +1491,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 }
 
 // getRigOperationalState returns the operational state and source for a rig.
-// It checks the wisp layer first (local/ephemeral), then rig bead labels (global).
-// Returns state ("OPERATIONAL", "PARKED", or "DOCKED") and source ("local", "global - synced", or "default").
+// See rig.OperationalState.
 func getRigOperationalState(townRoot, rigName string) (state string, source string) {
-	// Check wisp layer first (local/ephemeral overrides)
-	wispConfig := wisp.NewConfig(townRoot, rigName)
-	if status := wispConfig.GetString("status"); status != "" {
-		switch strings.ToLower(status) {
-		case "parked":
-			return "PARKED", "local"
-		case "docked":
-			return "DOCKED", "local"
-		}
-	}
-
-	// Check rig bead labels (global/synced)
-	// Rig identity bead ID: <prefix>-rig-<name>
-	// Look for status:docked or status:parked labels
-	rigPath := filepath.Join(townRoot, rigName)
-	rigBeadsDir := beads.ResolveBeadsDir(rigPath)
-	bd := beads.NewWithBeadsDir(rigPath, rigBeadsDir)
-
-	// Try to find the rig identity bead
-	// Convention: <prefix>-rig-<rigName>
-	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil && rigCfg.Beads != nil {
-		rigBeadID := fmt.Sprintf("%s-rig-%s", rigCfg.Beads.Prefix, rigName)
-		if issue, err := bd.Show(rigBeadID); err == nil {
-			for _, label := range issue.Labels {
-				if strings.HasPrefix(label, "status:") {
-					statusValue := strings.TrimPrefix(label, "status:")
-					switch strings.ToLower(statusValue) {
-					case "docked":
-						return "DOCKED", "global - synced"
-					case "parked":
-						return "PARKED", "global - synced"
-					}
-				}
-			}
-		}
-	}
-
-	// Default: operational
-	return "OPERATIONAL", "default"
+	return rig.OperationalState(townRoot, rigName)
 }
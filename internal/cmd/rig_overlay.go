@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var rigOverlayDiffJSON bool
+
+var rigOverlayCmd = &cobra.Command{
+	Use:   "overlay",
+	Short: "Inspect a rig's overlay files",
+	Long: `Inspect a rig's overlay files.
+
+Files in <rig>/.runtime/overlay/ are copied into worktrees when they are
+created: polecats on spawn, crew on add, the refinery on start. The overlay
+is copied recursively, so nested files like .vscode/settings.json land at
+the same relative path.
+
+Role overlays in overlay/polecat/, overlay/crew/ and overlay/refinery/ are
+applied only to that role, overriding shared files with the same path.
+
+Files ending in .tmpl are rendered as Go templates, with the extension
+stripped. Available variables:
+  {{.Rig}}         Rig name
+  {{.Role}}        polecat, crew or refinery
+  {{.Name}}        Polecat or crew member name
+  {{.Worktree}}    Absolute worktree path
+  {{.BeadID}}      Hook bead at spawn time (polecats)
+  {{.PortBase}}    First port of the polecat's block (polecat_ports)
+  {{.Ports.web}}   Port of a named service in the block (polecat_ports)
+Functions: add (e.g. {{add .PortBase 1}}).`,
+	RunE: requireSubcommand,
+}
+
+var rigOverlayDiffCmd = &cobra.Command{
+	Use:   "diff <rig>/<polecat>",
+	Short: "Show where a worktree has drifted from the overlay",
+	Long: `Show where a worktree has drifted from the rig's overlay.
+
+Renders the overlay as it would be applied now and compares it with the
+worktree. Files that are missing or differ are listed, with a diff of the
+expected content against the worktree's.
+
+Targets:
+  <rig>/<polecat>     A polecat
+  <rig>/crew/<name>   A crew workspace
+  <rig>/refinery      The refinery worktree
+
+Examples:
+  gt rig overlay diff gastown/Toast
+  gt rig overlay diff gastown/crew/max
+  gt rig overlay diff gastown/refinery --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigOverlayDiff,
+}
+
+func init() {
+	rigOverlayDiffCmd.Flags().BoolVar(&rigOverlayDiffJSON, "json", false, "Output as JSON")
+
+	rigOverlayCmd.AddCommand(rigOverlayDiffCmd)
+	rigCmd.AddCommand(rigOverlayCmd)
+}
+
+func runRigOverlayDiff(cmd *cobra.Command, args []string) error {
+	rigName, target, ok := parseRigSlashName(args[0])
+	if !ok || target == "" {
+		return fmt.Errorf("invalid target %q: expected <rig>/<polecat>, <rig>/crew/<name> or <rig>/refinery", args[0])
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	vars, err := overlayTargetVars(r, target)
+	if err != nil {
+		return err
+	}
+
+	drift, err := rig.DiffOverlay(r.Path, vars.Worktree, vars)
+	if err != nil {
+		return err
+	}
+
+	if rigOverlayDiffJSON {
+		if drift == nil {
+			drift = []rig.OverlayDrift{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(drift)
+	}
+
+	if len(drift) == 0 {
+		fmt.Printf("%s %s matches the overlay\n", style.Success.Render("✓"), args[0])
+		return nil
+	}
+
+	for _, d := range drift {
+		switch d.Status {
+		case rig.OverlayMissing:
+			fmt.Printf("%s %s %s\n", style.Warning.Render("−"), d.Path, style.Dim.Render("(missing)"))
+		case rig.OverlayModified:
+			fmt.Printf("%s %s %s\n", style.Warning.Render("~"), d.Path, style.Dim.Render("(modified)"))
+			printOverlayPatch(d, filepath.Join(vars.Worktree, d.Path))
+		default:
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), d.Path, d.Error)
+		}
+	}
+	return nil
+}
+
+// overlayTargetVars resolves a diff target within a rig to its overlay variables.
+func overlayTargetVars(r *rig.Rig, target string) (rig.OverlayVars, error) {
+	switch {
+	case target == "refinery":
+		return refinery.NewManager(r).OverlayVars(), nil
+
+	case strings.HasPrefix(target, "crew/"):
+		name := strings.TrimPrefix(target, "crew/")
+		crewMgr := crew.NewManager(r, git.NewGit(r.Path))
+		worker, err := crewMgr.Get(name)
+		if err != nil {
+			return rig.OverlayVars{}, fmt.Errorf("crew workspace '%s' not found in rig '%s'", name, r.Name)
+		}
+		vars := crewMgr.OverlayVars(name)
+		vars.Worktree = worker.ClonePath
+		return vars, nil
+
+	default:
+		name := strings.TrimPrefix(target, "polecats/")
+		mgr, _, err := getPolecatManager(r.Name)
+		if err != nil {
+			return rig.OverlayVars{}, err
+		}
+		p, err := mgr.Get(name)
+		if err != nil {
+			return rig.OverlayVars{}, fmt.Errorf("polecat '%s' not found in rig '%s'", name, r.Name)
+		}
+		vars := mgr.OverlayVars(name, p.Issue)
+		vars.Worktree = p.ClonePath
+		return vars, nil
+	}
+}
+
+// printOverlayPatch prints a unified diff of the expected overlay content
+// against the worktree file. Nothing is printed if diff is unavailable.
+func printOverlayPatch(d rig.OverlayDrift, actualPath string) {
+	tmp, err := os.CreateTemp("", "gt-overlay-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(d.Expected)
+	_ = tmp.Close()
+	if err != nil {
+		return
+	}
+
+	out, _ := exec.Command("diff", "-u", //nolint:gosec // G204: args are file paths
+		"--label", "overlay/"+d.Path, "--label", "worktree/"+d.Path,
+		tmp.Name(), actualPath).Output()
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/ui"
@@ -56,6 +57,8 @@ var beadsExemptCommands = map[string]bool{
 	"reload":     true,
 	"nuke":       true,
 	"krc":        true, // KRC doesn't require beads
+	// Run by tmux every few seconds per session; must stay cheap
+	"status-line": true,
 }
 
 // Commands exempt from the town root branch warning.
@@ -67,8 +70,33 @@ var branchCheckExemptCommands = map[string]bool{
 	"doctor":     true, // Used to fix the problem
 	"install":    true, // Initial setup
 	"git-init":   true, // Git setup
+	// Output goes to the tmux status bar, and the git call adds up
+	"status-line": true,
 }
 
+// Read-only reporting commands that run with the beads request cache
+// enabled, so repeated bd reads within one invocation fork bd once.
+// Keyed by full command path (see buildCommandPath).
+var bdCachedCommands = map[string]bool{
+	"gt status":         true,
+	"gt status-line":    true,
+	"gt polecat list":   true,
+	"gt polecat status": true,
+	"gt convoy list":    true,
+	"gt convoy status":  true,
+	"gt rig status":     true,
+	"gt crew list":      true,
+	"gt crew status":    true,
+	"gt agents list":    true,
+}
+
+// bdStatsEnv names the environment variable that, when set, prints a count
+// of bd invocations to stderr when a command finishes.
+const bdStatsEnv = "GT_BD_STATS"
+
+// commandPath is the full path of the command being run, for bd stats.
+var commandPath string
+
 // persistentPreRun runs before every command.
 func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Check if binary was built properly (via make build, not raw go build).
@@ -88,6 +116,12 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
+	commandPath = buildCommandPath(cmd)
+	if bdCachedCommands[commandPath] {
+		// Lives for the rest of the process; Execute returns right after
+		beads.EnableRequestCache()
+	}
+
 	// Check for stale binary (warning only, doesn't block)
 	if !beadsExemptCommands[cmdName] {
 		checkStaleBinaryWarning()
@@ -219,6 +253,9 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
+	if os.Getenv(bdStatsEnv) != "" {
+		defer printBdStats()
+	}
 	if err := rootCmd.Execute(); err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
@@ -230,6 +267,15 @@ func Execute() int {
 	return 0
 }
 
+// printBdStats reports the bd invocations made by this command to stderr.
+func printBdStats() {
+	name := commandPath
+	if name == "" {
+		name = "gt"
+	}
+	fmt.Fprintf(os.Stderr, "%s %s: %s\n", style.Dim.Render("[bd]"), name, beads.Stats())
+}
+
 // Command group IDs - used by subcommands to organize help output
 const (
 	GroupWork      = "work"
@@ -54,6 +54,10 @@ THE SEANCE (talk to predecessor):
   gt seance --talk <session-id>              # Interactive conversation
   gt seance --talk <id> -p "Where is X?"     # One-shot question
 
+SEARCH (find who did what):
+  gt seance search payment.go                # Full-text search of transcripts
+  gt seance search payment.go --talk 1       # Talk to the top hit
+
 The --talk flag spawns: claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
 
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	seanceSearchRole   string
+	seanceSearchRig    string
+	seanceSearchAgent  string
+	seanceSearchBead   string
+	seanceSearchSince  string
+	seanceSearchLimit  int
+	seanceSearchTalk   int
+	seanceSearchPrompt string
+	seanceSearchJSON   bool
+)
+
+var seanceSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search predecessor session transcripts",
+	Long: `Full-text search across agent session transcripts.
+
+Finds which sessions discussed, read or edited something - "which polecat
+touched payment.go last week and why". Every query term must appear in a
+session; results are ranked by relevance and show the best matching
+messages.
+
+Transcripts are indexed from each agent preset's transcript directory
+(Claude Code: ~/.claude/projects, plus each account in mayor/accounts.json;
+Codex: ~/.codex/sessions; Gemini: ~/.gemini/tmp). Only sessions run inside
+this town are searchable. Each session is attributed to its agent and bead
+from its startup beacon or working directory.
+
+The index lives in .runtime/cache/seance/ and is updated incrementally
+before each search.
+
+Examples:
+  gt seance search payment.go
+  gt seance search "flaky retry" --rig gastown --since 7d
+  gt seance search migration --role polecat --bead gt-abc12
+  gt seance search payment.go --talk 1            # Resume the top hit
+  gt seance search payment.go --talk 2 -p "Why did you change the retry?"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSeanceSearch,
+}
+
+func init() {
+	seanceSearchCmd.Flags().StringVar(&seanceSearchRole, "role", "", "Filter by role (crew, polecat, witness, etc.)")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchRig, "rig", "", "Filter by rig name")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchAgent, "agent", "", "Filter by agent address (substring, e.g. Toast)")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchBead, "bead", "", "Filter by the bead a session was started on")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchSince, "since", "", "Only sessions active within this duration (e.g. 7d, 12h)")
+	seanceSearchCmd.Flags().IntVarP(&seanceSearchLimit, "limit", "n", 10, "Maximum number of hits")
+	seanceSearchCmd.Flags().IntVarP(&seanceSearchTalk, "talk", "t", 0, "Talk to the session of hit N (1-based)")
+	seanceSearchCmd.Flags().StringVarP(&seanceSearchPrompt, "prompt", "p", "", "One-shot prompt (with --talk)")
+	seanceSearchCmd.Flags().BoolVar(&seanceSearchJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceSearchCmd)
+}
+
+func runSeanceSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+
+	query := transcript.Query{
+		Text:  strings.Join(args, " "),
+		Role:  seanceSearchRole,
+		Rig:   seanceSearchRig,
+		Agent: seanceSearchAgent,
+		Bead:  seanceSearchBead,
+		Limit: seanceSearchLimit,
+	}
+	if seanceSearchSince != "" {
+		d, err := parseDuration(seanceSearchSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		query.Since = time.Now().Add(-d)
+	}
+	if len(transcript.Tokenize(query.Text)) == 0 {
+		return fmt.Errorf("query %q has no searchable terms", query.Text)
+	}
+
+	if !seanceSearchJSON {
+		fmt.Fprintf(os.Stderr, "%s\n", style.Dim.Render("Updating transcript index..."))
+	}
+	ix, stats, err := transcript.Refresh(townRoot)
+	if err != nil {
+		return fmt.Errorf("indexing transcripts: %w", err)
+	}
+
+	hits := ix.Search(query)
+
+	if seanceSearchTalk > 0 {
+		if seanceSearchTalk > len(hits) {
+			return fmt.Errorf("no hit %d (%d hits)", seanceSearchTalk, len(hits))
+		}
+		s := hits[seanceSearchTalk-1].Session
+		if preset := config.GetAgentPresetByName(s.Preset); preset == nil || !preset.SupportsForkSession {
+			return fmt.Errorf("session %s was run by %s, which seance can't resume", s.ID, s.Preset)
+		}
+		return runSeanceTalk(s.ID, seanceSearchPrompt)
+	}
+
+	if seanceSearchJSON {
+		if hits == nil {
+			hits = []transcript.Hit{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(hits)
+	}
+
+	if len(hits) == 0 {
+		fmt.Printf("No sessions match %q %s\n", query.Text,
+			style.Dim.Render(fmt.Sprintf("(%d sessions indexed)", stats.Sessions)))
+		return nil
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render(fmt.Sprintf("Sessions matching %q", query.Text)))
+	for i, h := range hits {
+		s := h.Session
+		agent := s.Agent
+		if agent == "" {
+			agent = s.Cwd
+		}
+		fmt.Printf("\n%s %s  %s\n", style.Bold.Render(fmt.Sprintf("%d.", i+1)), agent,
+			style.Dim.Render(formatSearchWhen(s)))
+		meta := "session " + s.ID
+		if s.Bead != "" {
+			meta += " • bead " + s.Bead
+		}
+		if s.Preset != string(config.AgentClaude) {
+			meta += " • " + s.Preset
+		}
+		fmt.Printf("   %s\n", style.Dim.Render(meta))
+		for _, sn := range h.Snippets {
+			speaker := sn.Speaker
+			if speaker == "" {
+				speaker = "log"
+			}
+			fmt.Printf("   %s %s\n", style.Info.Render(speaker+":"), sn.Text)
+		}
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Talk to a predecessor:"))
+	fmt.Printf("  gt seance search %s --talk <n>\n", strings.Join(args, " "))
+	return nil
+}
+
+func formatSearchWhen(s *transcript.Session) string {
+	if s.End.IsZero() {
+		return ""
+	}
+	return s.End.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/search"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	searchRig   string
+	searchActor string
+	searchSince string
+	searchUntil string
+	searchKinds []string
+	searchLimit int
+	searchSort  string
+	searchJSON  bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <query>",
+	GroupID: GroupDiag,
+	Short:   "Search beads, mail, events and commits across the town",
+	Long: `Search the whole town at once and merge the matches into one timeline.
+
+Sources, queried in parallel:
+  bead     Beads in the town and every rig (issues, convoys, MRs, ...)
+  mail     Mail messages, read or unread, to and from any agent
+  event    The town's activity log (.events.jsonl)
+  commit   Commit messages in the town repo and each rig's repo
+
+Every query term must appear (case-insensitive) in a result's ID, title or
+body. Results are ranked by where the terms matched - ID, then title, then
+body - with recent results breaking ties. Use --sort time for a plain
+timeline, newest first.
+
+--since and --until take a duration ago (30m, 24h, 7d) or a date
+(2026-01-15 or RFC 3339). To search agent session transcripts, use
+gt seance search.
+
+Examples:
+  gt search login                           # Anything about login
+  gt search gt-abc12                        # Everything mentioning a bead
+  gt search "merge conflict" --rig gastown --since 7d
+  gt search timeout --kind commit --kind bead
+  gt search deploy --actor gastown/Toast --sort time
+  gt search flaky --json | jq '.[] | select(.kind == "mail")'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchRig, "rig", "", "Only results from or about this rig")
+	searchCmd.Flags().StringVar(&searchActor, "actor", "", "Only results by this actor (partial match)")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Only results since this duration ago or date")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Only results before this duration ago or date")
+	searchCmd.Flags().StringSliceVar(&searchKinds, "kind", nil, "Only these kinds: bead, mail, event, commit (repeatable)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 50, "Maximum number of results (0 for no limit)")
+	searchCmd.Flags().StringVar(&searchSort, "sort", "relevance", "Order results by relevance or time")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	q := search.Query{
+		Text:  strings.Join(args, " "),
+		Rig:   searchRig,
+		Actor: searchActor,
+		Limit: searchLimit,
+	}
+	if strings.TrimSpace(q.Text) == "" {
+		return fmt.Errorf("empty query")
+	}
+	for _, k := range searchKinds {
+		kind, err := search.ParseKind(k)
+		if err != nil {
+			return err
+		}
+		q.Kinds = append(q.Kinds, kind)
+	}
+	switch searchSort {
+	case "relevance":
+	case "time":
+		q.Chronological = true
+	default:
+		return fmt.Errorf("invalid --sort %q (want relevance or time)", searchSort)
+	}
+	if searchSince != "" {
+		if q.Since, err = parseSearchTime(searchSince); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if searchUntil != "" {
+		if q.Until, err = parseSearchTime(searchUntil); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	var rigs []string
+	if rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+	}
+	if q.Rig != "" && !slices.Contains(rigs, q.Rig) {
+		return fmt.Errorf("rig %q not found", q.Rig)
+	}
+
+	results, errs := search.New(townRoot, rigs).Search(context.Background(), q)
+	for _, err := range errs {
+		// Non-fatal: the other sources still answered
+		style.PrintWarning("could not search %v", err)
+	}
+
+	if searchJSON {
+		if results == nil {
+			results = []search.Result{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s No matches for %q\n", style.Dim.Render("○"), q.Text)
+		return nil
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("🔍"),
+		style.Bold.Render(fmt.Sprintf("%d result(s) for %q", len(results), q.Text)))
+	for _, r := range results {
+		id := ""
+		if r.ID != "" {
+			id = style.Dim.Render(r.ID) + " "
+		}
+		fmt.Printf("\n%s %s%s\n", formatSearchKind(r.Kind), id, r.Title)
+
+		var meta []string
+		if !r.Time.IsZero() {
+			meta = append(meta, r.Time.Local().Format("2006-01-02 15:04"))
+		}
+		if r.Actor != "" {
+			meta = append(meta, "by "+r.Actor)
+		}
+		if r.Source != "" {
+			meta = append(meta, r.Source)
+		}
+		fmt.Printf("         %s\n", style.Dim.Render(strings.Join(meta, " • ")))
+		if r.Snippet != "" {
+			fmt.Printf("         %s\n", r.Snippet)
+		}
+	}
+	if searchLimit > 0 && len(results) == searchLimit {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Showing the first %d; use --limit to see more", searchLimit)))
+	}
+	return nil
+}
+
+// parseSearchTime parses a --since/--until value: a duration ago (with d
+// for days) or a date.
+func parseSearchTime(s string) (time.Time, error) {
+	if d, err := parseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration (7d, 24h) nor a date (2006-01-02)", s)
+}
+
+// formatSearchKind renders a result kind as a fixed-width source tag.
+func formatSearchKind(k search.Kind) string {
+	tag := fmt.Sprintf("%-8s", "["+string(k)+"]")
+	switch k {
+	case search.KindBead:
+		return style.Success.Render(tag)
+	case search.KindMail:
+		return style.Info.Render(tag)
+	case search.KindEvent:
+		return style.Warning.Render(tag)
+	case search.KindCommit:
+		return style.Bold.Render(tag)
+	default:
+		return tag
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -185,6 +186,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Assign a trace ID now so the spawned polecat inherits it (see gt trace)
+	traceID := trace.NewID()
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
@@ -238,6 +242,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 					Create:   slingCreate,
 					HookBead: beadID, // Set atomically at spawn time
 					Agent:    slingAgent,
+					TraceID:  traceID,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -270,6 +275,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 							Create:   slingCreate,
 							HookBead: beadID,
 							Agent:    slingAgent,
+							TraceID:  traceID,
 						}
 						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 						if spawnErr != nil {
@@ -470,7 +476,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	_ = events.LogFeed(events.TypeSling, actor, trace.Annotate(events.SlingPayload(beadID, targetAgent), traceID))
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -486,6 +492,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Could not store dispatcher in bead: %v\n", style.Dim.Render("Warning:"), err)
 	}
 
+	// Store trace ID in bead description (gt done carries it into the MR)
+	if err := storeTraceIDInBead(beadID, traceID); err != nil {
+		fmt.Printf("%s Could not store trace ID in bead: %v\n", style.Dim.Render("Warning:"), err)
+	}
+
 	// Store args in bead description (no-tmux mode: beads as data plane)
 	if slingArgs != "" {
 		if err := storeArgsInBead(beadID, slingArgs); err != nil {
@@ -9,6 +9,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
 )
 
 // runBatchSling handles slinging multiple beads to a rig.
@@ -65,6 +66,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		// Each bead gets its own trace (see gt trace)
+		traceID := trace.NewID()
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:    slingForce,
@@ -72,6 +76,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Create:   slingCreate,
 			HookBead: beadID, // Set atomically at spawn time
 			Agent:    slingAgent,
+			TraceID:  traceID,
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -137,7 +142,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		_ = events.LogFeed(events.TypeSling, actor, trace.Annotate(events.SlingPayload(beadToHook, targetAgent), traceID))
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
@@ -149,6 +154,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			}
 		}
 
+		// Store trace ID for gt done / gt trace
+		if err := storeTraceIDInBead(beadID, traceID); err != nil {
+			fmt.Printf("  %s Could not store trace ID: %v\n", style.Dim.Render("Warning:"), err)
+		}
+
 		// Store args if provided
 		if slingArgs != "" {
 			if err := storeArgsInBead(beadID, slingArgs); err != nil {
@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -81,6 +82,9 @@ func runSlingFormula(args []string) error {
 	}
 	townBeadsDir := filepath.Join(townRoot, ".beads")
 
+	// Assign a trace ID now so a spawned polecat inherits it (see gt trace)
+	traceID := trace.NewID()
+
 	// Determine target (self or specified)
 	var target string
 	if len(args) > 1 {
@@ -135,6 +139,7 @@ func runSlingFormula(args []string) error {
 					Account: slingAccount,
 					Create:  slingCreate,
 					Agent:   slingAgent,
+					TraceID: traceID,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -225,7 +230,7 @@ func runSlingFormula(args []string) error {
 	actor := detectActor()
 	payload := events.SlingPayload(wispRootID, targetAgent)
 	payload["formula"] = formulaName
-	_ = events.LogFeed(events.TypeSling, actor, payload)
+	_ = events.LogFeed(events.TypeSling, actor, trace.Annotate(payload, traceID))
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Note: formula slinging uses town root as workDir (no polecat-specific path)
@@ -237,6 +242,11 @@ func runSlingFormula(args []string) error {
 		fmt.Printf("%s Could not store dispatcher in bead: %v\n", style.Dim.Render("Warning:"), err)
 	}
 
+	// Store trace ID for gt done / gt trace
+	if err := storeTraceIDInBead(wispRootID, traceID); err != nil {
+		fmt.Printf("%s Could not store trace ID in bead: %v\n", style.Dim.Render("Warning:"), err)
+	}
+
 	// Store args in wisp bead if provided (no-tmux mode: beads as data plane)
 	if slingArgs != "" {
 		if err := storeArgsInBead(wispRootID, slingArgs); err != nil {
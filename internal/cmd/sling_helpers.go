@@ -332,6 +332,63 @@ func storeNoMergeInBead(beadID string, noMerge bool) error {
 	return nil
 }
 
+// storeTraceIDInBead sets the trace_id field in a bead's description.
+// gt done reads it back to tag the MR and completion events, so the whole
+// lifecycle can be reconstructed with gt trace.
+func storeTraceIDInBead(beadID, traceID string) error {
+	if traceID == "" {
+		return nil
+	}
+
+	// Resolve working directory for bd commands based on bead prefix routing
+	workDir, err := resolveBeadWorkDir(beadID)
+	if err != nil {
+		return err
+	}
+
+	// Get the bead to preserve existing description content
+	// Uses --no-daemon with --allow-stale to avoid database sync race conditions
+	// when called immediately after bead creation (GH #30).
+	showCmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
+	showCmd.Dir = workDir
+	out, err := showCmd.Output()
+	if err != nil {
+		return fmt.Errorf("fetching bead: %w", err)
+	}
+
+	// Parse the bead
+	var issues []beads.Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return fmt.Errorf("parsing bead: %w", err)
+	}
+	if len(issues) == 0 {
+		return fmt.Errorf("bead not found")
+	}
+	issue := &issues[0]
+
+	// Get or create attachment fields
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil {
+		fields = &beads.AttachmentFields{}
+	}
+
+	// Set the trace ID (a re-sling starts a new trace)
+	fields.TraceID = traceID
+
+	// Update the description
+	newDesc := beads.SetAttachmentFields(issue, fields)
+
+	// Update the bead
+	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
+	updateCmd.Dir = workDir
+	updateCmd.Stderr = os.Stderr
+	if err := updateCmd.Run(); err != nil {
+		return fmt.Errorf("updating bead description: %w", err)
+	}
+
+	return nil
+}
+
 // injectStartPrompt sends a prompt to the target pane to start working.
 // Uses the reliable nudge pattern: literal mode + 500ms debounce + separate Enter.
 func injectStartPrompt(pane, beadID, subject, args string) error {
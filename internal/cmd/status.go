@@ -167,6 +167,8 @@ func runStatusWatch(cmd *cobra.Command, args []string) error {
 			fmt.Printf("%s\n\n", header)
 		}
 
+		// Each refresh must see current beads, not the last round's reads
+		beads.ClearRequestCache()
 		if err := runStatusOnce(cmd, args); err != nil {
 			fmt.Printf("Error: %v\n", err)
 		}
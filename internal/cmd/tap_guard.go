@@ -2,10 +2,30 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/guard"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Guard test flags
+var (
+	guardTestTool    string
+	guardTestCommand string
+	guardTestPaths   []string
+	guardTestRole    string
+	guardTestRig     string
+	guardTestPolicy  string
 )
 
 var tapGuardCmd = &cobra.Command{
@@ -19,6 +39,7 @@ forbidden operation entirely.
 
 Available guards:
   pr-workflow   - Block PR creation and feature branches
+  eval          - Evaluate the town and rig guard policies
 
 Example hook configuration:
   {
@@ -29,6 +50,71 @@ Example hook configuration:
   }`,
 }
 
+var tapGuardEvalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Evaluate guard policies for a tool call (PreToolUse hook)",
+	Long: `Evaluate declarative guard policies against a PreToolUse hook payload.
+
+Reads the hook JSON from stdin and checks it against the rig's policy
+(<rig>/settings/guards.json) and then the town's (<town>/settings/guards.json).
+The first matching rule decides:
+
+  deny   Block the tool call (exit 2) and log a guard_blocked event
+  warn   Allow, printing the rule's message to stderr
+  allow  Allow, skipping later rules (for rig exceptions to town rules)
+
+Rules match on tool name, Bash command (regex, argv prefix, arguments),
+touched file paths, role and rig. Patterns may use $WORKTREE,
+$DEFAULT_BRANCH, $RIG, $ROLE and $TOWN_ROOT. When a variable has no value
+(e.g. $WORKTREE outside a git worktree), a deny rule using it denies any
+call its other conditions match, rather than matching a widened pattern;
+warn and allow rules using it are skipped.
+
+Example policy:
+  {
+    "version": 1,
+    "rules": [
+      {"name": "no-force-push-main", "action": "deny",
+       "message": "Force pushes to the default branch are forbidden",
+       "match": {"tools": ["Bash"], "argv": ["git", "push"],
+                 "args": ["-f", "--force", "--force-with-lease*", "+*"],
+                 "command": "\\b$DEFAULT_BRANCH\\b"}},
+      {"name": "stay-in-worktree", "action": "deny", "match": {
+        "tools": ["Edit", "Write", "MultiEdit", "NotebookEdit"],
+        "roles": ["polecat"], "paths_outside": ["$WORKTREE/**", "/tmp/**"]}},
+      {"name": "no-migrations", "action": "deny",
+       "match": {"tools": ["Edit", "Write"], "paths": ["migrations/**"]}},
+      {"name": "no-network-tools", "action": "deny",
+       "match": {"roles": ["polecat"], "argv": ["curl|wget|nc|ssh"]}}
+    ]
+  }
+
+Hook configuration:
+  {
+    "PreToolUse": [{
+      "matcher": "",
+      "hooks": [{"type": "command", "command": "gt tap guard eval"}]
+    }]
+  }
+
+A policy file that fails to load is reported on stderr and the tool call
+is allowed, so a typo can't wedge every agent in the town.`,
+	RunE: runTapGuardEval,
+}
+
+var tapGuardTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Dry-run a tool call against the guard policies",
+	Long: `Show which guard rule a tool call would match, without blocking anything
+or logging events.
+
+Examples:
+  gt tap guard test --command "git push --force origin main"
+  gt tap guard test --tool Edit --path db/migrations/001.sql --role polecat
+  gt tap guard test --command "curl example.com" --policy ./guards.json`,
+	RunE: runTapGuardTest,
+}
+
 var tapGuardPRWorkflowCmd = &cobra.Command{
 	Use:   "pr-workflow",
 	Short: "Block PR creation and feature branches",
@@ -47,13 +133,27 @@ Exit codes:
   2 - Operation BLOCKED (in agent context)
 
 The guard only blocks when running as a Gas Town agent (crew, polecat,
-witness, etc.). Humans running outside Gas Town can still use PRs.`,
+witness, etc.). Humans running outside Gas Town can still use PRs.
+
+In rigs whose merge queue lands work through pull requests
+(merge_queue.merge_mode "pr"), the Refinery is allowed through: it opens
+and merges the PRs. Other agents are still blocked and told to submit
+with gt done instead.`,
 	RunE: runTapGuardPRWorkflow,
 }
 
 func init() {
 	tapCmd.AddCommand(tapGuardCmd)
 	tapGuardCmd.AddCommand(tapGuardPRWorkflowCmd)
+	tapGuardCmd.AddCommand(tapGuardEvalCmd)
+	tapGuardCmd.AddCommand(tapGuardTestCmd)
+
+	tapGuardTestCmd.Flags().StringVar(&guardTestTool, "tool", "", "Tool name (default: Bash with --command, Edit with --path)")
+	tapGuardTestCmd.Flags().StringVar(&guardTestCommand, "command", "", "Bash command line")
+	tapGuardTestCmd.Flags().StringSliceVar(&guardTestPaths, "path", nil, "File path the tool touches (repeatable)")
+	tapGuardTestCmd.Flags().StringVar(&guardTestRole, "role", "", "Role to evaluate as (default: current role)")
+	tapGuardTestCmd.Flags().StringVar(&guardTestRig, "rig", "", "Rig to evaluate as (default: current rig)")
+	tapGuardTestCmd.Flags().StringVar(&guardTestPolicy, "policy", "", "Policy file to test instead of the town and rig policies")
 }
 
 func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
@@ -63,6 +163,27 @@ func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// In rigs that merge through pull requests, the Refinery owns them
+	prMode, isRefinery := prWorkflowContext()
+	if prMode && isRefinery {
+		return nil
+	}
+	if prMode {
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "╔══════════════════════════════════════════════════════════════════╗")
+		fmt.Fprintln(os.Stderr, "║  ❌ PR WORKFLOW BLOCKED                                          ║")
+		fmt.Fprintln(os.Stderr, "╠══════════════════════════════════════════════════════════════════╣")
+		fmt.Fprintln(os.Stderr, "║  This rig merges through pull requests, but the Refinery opens  ║")
+		fmt.Fprintln(os.Stderr, "║  them. Push your branch and submit it instead:                  ║")
+		fmt.Fprintln(os.Stderr, "║                                                                  ║")
+		fmt.Fprintln(os.Stderr, "║  Do this:     git push && gt done                               ║")
+		fmt.Fprintln(os.Stderr, "║                                                                  ║")
+		fmt.Fprintln(os.Stderr, "║  Review comments come back to you as rework requests.           ║")
+		fmt.Fprintln(os.Stderr, "╚══════════════════════════════════════════════════════════════════╝")
+		fmt.Fprintln(os.Stderr, "")
+		os.Exit(2) // Exit 2 = BLOCK in Claude Code hooks
+	}
+
 	// We're in a Gas Town context - block PR operations
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "╔══════════════════════════════════════════════════════════════════╗")
@@ -82,6 +203,33 @@ func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// prWorkflowContext reports whether the current rig merges through pull
+// requests (merge_queue.merge_mode "pr"), and whether we're its Refinery.
+func prWorkflowContext() (prMode, isRefinery bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false, false
+	}
+	townRoot, err := workspace.Find(cwd)
+	if err != nil || townRoot == "" {
+		return false, false
+	}
+	rigName, err := inferRigFromCwd(townRoot)
+	if err != nil || rigName == "" {
+		return false, false
+	}
+
+	eng := refinery.NewEngineer(&rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)})
+	if err := eng.LoadConfig(); err != nil || eng.Config().MergeMode != config.MergeModePR {
+		return false, false
+	}
+
+	if info, err := GetRoleWithContext(cwd, townRoot); err == nil {
+		isRefinery = info.Role == RoleRefinery
+	}
+	return true, isRefinery
+}
+
 // isGasTownAgentContext returns true if we're running as a Gas Town managed agent.
 func isGasTownAgentContext() bool {
 	// Check environment variables set by Gas Town session management
@@ -114,3 +262,186 @@ func isGasTownAgentContext() bool {
 
 	return false
 }
+
+func runTapGuardEval(cmd *cobra.Command, args []string) error {
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt tap guard eval: reading stdin: %v\n", err)
+		return nil
+	}
+
+	req, err := guard.ParseHookInput(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt tap guard eval: parsing hook input: %v\n", err)
+		return nil
+	}
+
+	townRoot, rigPath := guardContext(&req)
+	policy, err := guard.Load(townRoot, rigPath)
+	if err != nil {
+		// Fail open: a broken policy file must not block every tool call
+		fmt.Fprintf(os.Stderr, "gt tap guard eval: %v\n", err)
+		return nil
+	}
+
+	decision := policy.Evaluate(req)
+	switch decision.Action {
+	case guard.ActionWarn:
+		fmt.Fprintf(os.Stderr, "⚠ guard %s: %s\n", decision.Rule.Name, decision.Rule.Message)
+	case guard.ActionDeny:
+		_ = events.Log(events.TypeGuardBlocked, detectSender(),
+			events.GuardBlockedPayload(decision.Rule.Name, req.Tool, guardSubject(req), decision.Rule.Source),
+			events.VisibilityBoth)
+		printGuardBlocked(decision)
+		os.Exit(2) // Exit 2 = BLOCK in Claude Code hooks
+	}
+	return nil
+}
+
+func runTapGuardTest(cmd *cobra.Command, args []string) error {
+	if guardTestCommand == "" && len(guardTestPaths) == 0 && guardTestTool == "" {
+		return fmt.Errorf("specify --command, --path or --tool")
+	}
+
+	req := guard.Request{
+		Tool:    guardTestTool,
+		Command: guardTestCommand,
+		Paths:   guardTestPaths,
+	}
+	if req.Tool == "" {
+		req.Tool = "Bash"
+		if guardTestCommand == "" {
+			req.Tool = "Edit"
+		}
+	}
+
+	townRoot, rigPath := guardContext(&req)
+	if guardTestRole != "" {
+		req.Role = guardTestRole
+		req.Vars.Role = guardTestRole
+	}
+	if guardTestRig != "" {
+		req.Rig = guardTestRig
+		req.Vars.Rig = guardTestRig
+		if townRoot != "" {
+			rigPath = filepath.Join(townRoot, guardTestRig)
+		}
+	}
+
+	var policy *guard.Policy
+	var err error
+	if guardTestPolicy != "" {
+		if _, statErr := os.Stat(guardTestPolicy); statErr != nil {
+			return fmt.Errorf("policy file: %w", statErr)
+		}
+		policy, err = guard.LoadFile(guardTestPolicy)
+	} else {
+		policy, err = guard.Load(townRoot, rigPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s as %s", style.Bold.Render("Tool:"), req.Tool, req.Role)
+	if req.Rig != "" {
+		fmt.Printf(" in %s", req.Rig)
+	}
+	fmt.Println()
+	if subject := guardSubject(req); subject != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(subject))
+	}
+
+	decision := policy.Evaluate(req)
+	if decision.Rule == nil {
+		fmt.Printf("%s allow %s\n", style.Bold.Render("→"), style.Dim.Render("(no rule matched)"))
+		return nil
+	}
+
+	fmt.Printf("%s %s by rule %s %s\n", style.Bold.Render("→"), decision.Action,
+		decision.Rule.Name, style.Dim.Render("("+decision.Rule.Source+")"))
+	if len(decision.Unresolved) > 0 {
+		fmt.Printf("  %s\n", unresolvedMessage(decision.Unresolved))
+	} else if decision.Rule.Message != "" {
+		fmt.Printf("  %s\n", decision.Rule.Message)
+	}
+	return nil
+}
+
+// guardContext fills in the request's role, rig, cwd and pattern variables
+// from the environment, and returns the town root and rig path for loading
+// policies (either may be empty).
+func guardContext(req *guard.Request) (townRoot, rigPath string) {
+	cwd := req.Cwd
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+		req.Cwd = cwd
+	}
+
+	townRoot, _ = workspace.Find(cwd)
+
+	req.Role = "human"
+	if isGasTownAgentContext() && townRoot != "" {
+		if info, err := GetRoleWithContext(cwd, townRoot); err == nil && info.Role != RoleUnknown {
+			req.Role = string(info.Role)
+			req.Rig = info.Rig
+		}
+	}
+	if req.Rig == "" && townRoot != "" {
+		req.Rig, _ = inferRigFromCwd(townRoot)
+	}
+
+	req.Vars = guard.Vars{
+		Worktree: gitTopLevel(cwd),
+		Rig:      req.Rig,
+		Role:     req.Role,
+		TownRoot: townRoot,
+	}
+	if req.Rig != "" && townRoot != "" {
+		rigPath = filepath.Join(townRoot, req.Rig)
+		if cfg, err := rig.LoadRigConfig(rigPath); err == nil {
+			req.Vars.DefaultBranch = cfg.DefaultBranch
+		}
+	}
+	if req.Vars.DefaultBranch == "" {
+		req.Vars.DefaultBranch = "main"
+	}
+	return townRoot, rigPath
+}
+
+// gitTopLevel returns the root of the git worktree containing dir, or "".
+func gitTopLevel(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// guardSubject describes what a tool call acts on, for events and output.
+func guardSubject(req guard.Request) string {
+	if req.Command != "" {
+		return req.Command
+	}
+	return strings.Join(req.Paths, ", ")
+}
+
+func printGuardBlocked(decision guard.Decision) {
+	rule := decision.Rule
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintf(os.Stderr, "❌ BLOCKED by guard policy: %s\n", rule.Name)
+	if len(decision.Unresolved) > 0 {
+		fmt.Fprintf(os.Stderr, "   %s\n", unresolvedMessage(decision.Unresolved))
+	} else if rule.Message != "" {
+		fmt.Fprintf(os.Stderr, "   %s\n", rule.Message)
+	}
+	fmt.Fprintf(os.Stderr, "   Policy: %s\n", rule.Source)
+	fmt.Fprintln(os.Stderr, "")
+}
+
+// unresolvedMessage explains a denial caused by rule variables with no value.
+func unresolvedMessage(vars []string) string {
+	return fmt.Sprintf("Rule uses %s, which has no value for this call; denied on its other conditions.",
+		strings.Join(vars, ", "))
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Trace command flags
+var (
+	traceJSON bool
+	traceOTLP string
+)
+
+var traceCmd = &cobra.Command{
+	Use:     "trace <bead-id>",
+	GroupID: GroupDiag,
+	Short:   "Show the lifecycle timeline of a bead",
+	Long: `Reconstruct a bead's journey through Gas Town from the activity feed.
+
+gt sling assigns each bead a trace ID, which follows the work through the
+polecat's environment (GT_TRACE_ID), the MR bead and protocol messages.
+gt trace collects the matching events and shows each stage with the time
+spent before the next one:
+
+  sling → spawn → session_start → hook → mr_submitted → done
+        → merge_started → merged / merge_failed → convoy_closed
+
+Beads slung before tracing existed are matched by bead and MR ID instead.
+
+Use --otlp to write an OpenTelemetry (OTLP-JSON) trace file that can be
+loaded into Jaeger or other trace viewers for offline inspection.
+
+Examples:
+  gt trace gt-abc                   # Timeline with per-stage durations
+  gt trace gt-abc --json            # Machine-readable timeline
+  gt trace gt-abc --otlp trace.json # Export for a trace viewer`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrace,
+}
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "Output as JSON")
+	traceCmd.Flags().StringVar(&traceOTLP, "otlp", "", "Write the trace as OTLP-JSON to this file")
+
+	rootCmd.AddCommand(traceCmd)
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	beadID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	evs, err := trace.ReadEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	// The bead's own trace_id is authoritative (latest sling); without it
+	// trace.Build falls back to the most recent sling event.
+	traceID := ""
+	if workDir, err := resolveBeadWorkDir(beadID); err == nil {
+		traceID = getTraceIDFromBead(workDir, beadID)
+	}
+
+	timeline := trace.Build(evs, beadID, traceID)
+	if timeline == nil {
+		return fmt.Errorf("no lifecycle events found for %s", beadID)
+	}
+
+	if traceOTLP != "" {
+		f, err := os.Create(traceOTLP)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", traceOTLP, err)
+		}
+		if err := trace.WriteOTLP(f, timeline); err != nil {
+			f.Close()
+			return fmt.Errorf("writing OTLP trace: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing OTLP trace: %w", err)
+		}
+		if !traceJSON {
+			fmt.Printf("%s Wrote OTLP trace to %s\n\n", style.Bold.Render("✓"), traceOTLP)
+		}
+	}
+
+	if traceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(timeline)
+	}
+
+	printTraceTimeline(timeline)
+	return nil
+}
+
+func printTraceTimeline(t *trace.Timeline) {
+	fmt.Printf("%s %s\n", style.Bold.Render("Trace for"), t.Bead)
+	fmt.Printf("  Trace ID: %s\n", style.Dim.Render(t.TraceID))
+	fmt.Printf("  Started:  %s\n", t.Start.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Total:    %s\n\n", formatDuration(t.Duration()))
+
+	for _, s := range t.Stages {
+		duration := ""
+		if s.Duration > 0 {
+			duration = "+" + formatDuration(s.Duration)
+		}
+		fmt.Printf("  %s  %-14s %-10s %-28s %s\n",
+			s.Time.Local().Format("15:04:05"),
+			s.Name,
+			style.Dim.Render(fmt.Sprintf("%-10s", duration)),
+			s.Actor,
+			traceStageDetail(s))
+	}
+}
+
+// traceStageDetail picks the most useful payload fields for a stage.
+func traceStageDetail(s trace.Stage) string {
+	a := s.Attributes
+	switch s.Name {
+	case events.TypeSling:
+		return "→ " + a["target"]
+	case events.TypeSpawn:
+		return a["rig"] + "/polecats/" + a["polecat"]
+	case events.TypeMRSubmitted:
+		return a["mr"] + " → " + a["target"]
+	case events.TypeDone:
+		return a["branch"]
+	case events.TypeMergeStarted, events.TypeMerged:
+		return a["mr"]
+	case events.TypeMergeFailed, events.TypeMergeSkipped:
+		return a["mr"] + ": " + a["reason"]
+	case events.TypeConvoyClosed:
+		return a["convoy"]
+	}
+	return ""
+}
+
+// getTraceIDFromBead reads the trace_id attachment field that gt sling stored.
+func getTraceIDFromBead(workDir, beadID string) string {
+	if beadID == "" {
+		return ""
+	}
+
+	issue, err := beads.New(workDir).Show(beadID)
+	if err != nil {
+		return ""
+	}
+
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil {
+		return ""
+	}
+
+	return fields.TraceID
+}
+
+// resolveTraceID returns the trace ID for work on beadID: the bead's own
+// trace_id if set, else GT_TRACE_ID inherited from gt sling's spawn.
+func resolveTraceID(workDir, beadID string) string {
+	if id := getTraceIDFromBead(workDir, beadID); id != "" {
+		return id
+	}
+	return trace.FromEnv()
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Witness resources command flags
+var (
+	witnessResourcesNudge bool
+	witnessResourcesJSON  bool
+)
+
+var witnessResourcesCmd = &cobra.Command{
+	Use:   "resources [rig]",
+	Short: "Check polecats against the rig's resource policy",
+	Long: `Check each polecat's CPU, memory, process and disk usage against the
+rig's resource policy (polecat_resources in <rig>/settings/config.json).
+
+Limits are applied when a polecat is spawned: CPU, memory and process caps
+run the agent in a cgroup v2 scope via systemd-run, and network "none"
+runs it in a network namespace with no egress. Disk usage is not capped;
+the worktree is measured against disk_warn instead.
+
+This command reports polecats at or over a limit and polecats whose
+session is running without its limits. With --nudge, polecats over a limit
+are nudged to clean up. The Witness runs this during its patrol.
+
+Example policy:
+  "polecat_resources": {
+    "cpu": "200%",
+    "memory": "4G",
+    "max_processes": 512,
+    "disk_warn": "10G",
+    "network": "none"
+  }
+
+Examples:
+  gt witness resources
+  gt witness resources greenplace --nudge
+  gt witness resources greenplace --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWitnessResources,
+}
+
+func init() {
+	witnessResourcesCmd.Flags().BoolVar(&witnessResourcesNudge, "nudge", false, "Nudge polecats that are over a limit")
+	witnessResourcesCmd.Flags().BoolVar(&witnessResourcesJSON, "json", false, "Output as JSON")
+
+	witnessCmd.AddCommand(witnessResourcesCmd)
+}
+
+func runWitnessResources(cmd *cobra.Command, args []string) error {
+	var rigName string
+	if len(args) > 0 {
+		rigName = args[0]
+	} else {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return fmt.Errorf("could not determine rig: %w\nUsage: gt witness resources <rig>", err)
+		}
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	checks, err := witness.CheckPolecatResources(r.Path, rigName)
+	if err != nil {
+		return err
+	}
+
+	if witnessResourcesNudge {
+		t := tmux.NewTmux()
+		for i := range checks {
+			c := &checks[i]
+			if !c.Running || !c.NeedsAttention() {
+				continue
+			}
+			sessionName := session.PolecatSessionName(rigName, c.Polecat)
+			if err := t.NudgeSession(sessionName, witness.ResourceNudge(c)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not nudge %s: %v\n", c.Polecat, err)
+			}
+		}
+	}
+
+	if witnessResourcesJSON {
+		if checks == nil {
+			checks = []witness.ResourceCheck{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(checks)
+	}
+
+	if checks == nil {
+		fmt.Printf("%s No resource policy for rig %s\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+
+	flagged := 0
+	for i := range checks {
+		c := &checks[i]
+		rep := c.Report
+		unenforced := c.Running && rep.Unit != "" && !rep.Enforced
+		if !c.NeedsAttention() && !unenforced {
+			continue
+		}
+		flagged++
+		fmt.Printf("%s %s/%s\n", style.Warning.Render("⚠"), rigName, c.Polecat)
+		if unenforced {
+			fmt.Printf("    running without resource limits (no %s)\n", rep.Unit)
+		}
+		for _, w := range rep.Warnings {
+			fmt.Printf("    %s\n", w)
+		}
+	}
+
+	if flagged == 0 {
+		fmt.Printf("%s %d polecat(s) within limits\n", style.Success.Render("✓"), len(checks))
+	} else if witnessResourcesNudge {
+		fmt.Printf("\nNudged polecats over their limits.\n")
+	}
+	return nil
+}
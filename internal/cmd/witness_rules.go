@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Witness rules command flags
+var (
+	witnessRulesRig     string
+	witnessRulesFile    string
+	witnessRulesPolecat string
+	witnessRulesLabels  []string
+	witnessRulesRetries int
+	witnessRulesJSON    bool
+)
+
+var witnessRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect the Witness's help triage rules",
+	RunE:  requireSubcommand,
+	Long: `Inspect the rules the Witness uses to triage HELP requests from polecats.
+
+Each rig can define its own rules in <rig>/settings/witness-rules.json.
+Rules are evaluated in order and the first match wins. A rule matches on
+topic/problem regexes, the polecat name, issue bead labels and how many
+times the polecat has already asked, and produces one action:
+
+  hint      Relay a self-help hint to the polecat
+  nudge     Nudge the polecat's session with canned instructions
+  dog       Ask the Deacon to dispatch a dog (optionally with a formula)
+  escalate  Escalate to the Mayor at the rule's severity
+
+Without a rules file the built-in defaults apply.
+
+Example rules file:
+  {
+    "version": 1,
+    "rules": [
+      {"name": "flaky-ci", "match": {"problem": "flak|timed? out", "labels": ["ci"]},
+       "action": "nudge", "message": "Re-run the failing job once before asking again."},
+      {"name": "stuck-again", "match": {"min_retries": 2},
+       "action": "escalate", "severity": "critical", "message": "Polecat stuck after repeated help"}
+    ]
+  }`,
+}
+
+var witnessRulesTestCmd = &cobra.Command{
+	Use:   "test <message>",
+	Short: "Dry-run a help request against the triage rules",
+	Long: `Show which triage rule a HELP message would match and what the Witness
+would do, without sending mail or nudging anyone.
+
+The message is a HELP subject optionally followed by body lines
+(Agent:, Issue:, Problem:). A message without the "HELP:" prefix
+is treated as the topic. Use "-" to read the message from stdin.
+
+The live Witness counts earlier HELP messages from the same agent about
+the same issue in the last 24 hours for min_retries; a dry run assumes
+none unless --retries is given.
+
+Examples:
+  gt witness rules test "HELP: git push failing"
+  gt witness rules test $'HELP: stuck\nProblem: tests fail on CI' --rig gastown
+  gt witness rules test "build broken" --rules ./witness-rules.json --label ci
+  pbpaste | gt witness rules test -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessRulesTest,
+}
+
+func init() {
+	witnessRulesTestCmd.Flags().StringVar(&witnessRulesRig, "rig", "", "Rig whose rules to use (default: infer from cwd)")
+	witnessRulesTestCmd.Flags().StringVar(&witnessRulesFile, "rules", "", "Rules file to test instead of the rig's")
+	witnessRulesTestCmd.Flags().StringVar(&witnessRulesPolecat, "polecat", "", "Polecat name to match against (default: from Agent:)")
+	witnessRulesTestCmd.Flags().StringSliceVar(&witnessRulesLabels, "label", nil, "Issue bead label to match against (repeatable)")
+	witnessRulesTestCmd.Flags().IntVar(&witnessRulesRetries, "retries", 0, "Earlier help requests about the same issue, for min_retries")
+	witnessRulesTestCmd.Flags().BoolVar(&witnessRulesJSON, "json", false, "Output as JSON")
+
+	witnessRulesCmd.AddCommand(witnessRulesTestCmd)
+	witnessCmd.AddCommand(witnessRulesCmd)
+}
+
+func runWitnessRulesTest(cmd *cobra.Command, args []string) error {
+	message := args[0]
+	if message == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading message: %w", err)
+		}
+		message = string(data)
+	}
+
+	rules, source, err := loadWitnessRulesForTest()
+	if err != nil {
+		return err
+	}
+
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	if !witness.PatternHelp.MatchString(subject) {
+		subject = "HELP: " + subject
+	}
+	payload, err := witness.ParseHelp(subject, body)
+	if err != nil {
+		return err
+	}
+	payload.Retries = witnessRulesRetries
+	polecat := witnessRulesPolecat
+	if polecat == "" {
+		polecat = witness.PolecatFromAgent(payload.Agent)
+	}
+
+	assessment := witness.AssessHelpRequestWithRules(rules, payload, polecat, witnessRulesLabels)
+
+	action, rule, message := witness.HelpActionEscalate, "", assessment.EscalationReason
+	if assessment.Rule != nil {
+		action, rule = assessment.Rule.Action, assessment.Rule.Name
+	}
+	if assessment.CanHelp {
+		message = assessment.HelpAction
+	}
+
+	if witnessRulesJSON {
+		out := struct {
+			Rules    string `json:"rules"`
+			Topic    string `json:"topic"`
+			Rule     string `json:"rule,omitempty"`
+			Action   string `json:"action"`
+			Severity string `json:"severity,omitempty"`
+			Message  string `json:"message"`
+		}{source, payload.Topic, rule, action, assessment.Severity, message}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Rules:"), source)
+	fmt.Printf("%s %s\n", style.Bold.Render("Topic:"), payload.Topic)
+	if rule == "" {
+		fmt.Printf("%s %s\n", style.Bold.Render("Rule: "), style.Dim.Render("(no match)"))
+	} else {
+		fmt.Printf("%s %s\n", style.Bold.Render("Rule: "), rule)
+	}
+	if assessment.NeedsEscalation {
+		fmt.Printf("%s %s (%s)\n", style.Bold.Render("→"), action, assessment.Severity)
+	} else {
+		fmt.Printf("%s %s\n", style.Bold.Render("→"), action)
+	}
+	fmt.Printf("  %s\n", message)
+	return nil
+}
+
+// loadWitnessRulesForTest loads the --rules file, or the rig's rules.
+// Returns the rules and a description of where they came from.
+func loadWitnessRulesForTest() (*witness.HelpRules, string, error) {
+	if witnessRulesFile != "" {
+		if _, err := os.Stat(witnessRulesFile); err != nil {
+			return nil, "", fmt.Errorf("rules file: %w", err)
+		}
+		rules, err := witness.LoadHelpRulesFile(witnessRulesFile)
+		return rules, witnessRulesFile, err
+	}
+
+	rigName := witnessRulesRig
+	if rigName == "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return nil, "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not determine rig: %w\nUse --rig or --rules", err)
+		}
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	path := witness.HelpRulesPath(r.Path)
+	rules, err := witness.LoadHelpRules(r.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, statErr := os.Stat(path); statErr != nil {
+		return rules, "built-in defaults", nil
+	}
+	return rules, path, nil
+}
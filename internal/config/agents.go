@@ -76,6 +76,10 @@ type AgentPresetInfo struct {
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// TranscriptDir is where the agent writes session transcripts (~ is
+	// expanded). Searched recursively by gt seance search.
+	TranscriptDir string `json:"transcript_dir,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -116,6 +120,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		SupportsHooks:       true,
 		SupportsForkSession: true,
 		NonInteractive:      nil, // Claude is native non-interactive
+		TranscriptDir:       "~/.claude/projects",
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -131,6 +136,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
 		},
+		TranscriptDir: "~/.gemini/tmp",
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
@@ -146,6 +152,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			Subcommand: "exec",
 			OutputFlag: "--json",
 		},
+		TranscriptDir: "~/.codex/sessions",
 	},
 	AgentCursor: {
 		Name:                AgentCursor,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -35,6 +36,14 @@ type AgentEnvConfig struct {
 	// BeadsNoDaemon sets BEADS_NO_DAEMON=1 if true
 	// Used for polecats that should bypass the beads daemon
 	BeadsNoDaemon bool
+
+	// TraceID is the lifecycle trace ID of the work being started (see gt trace).
+	// Sets GT_TRACE_ID so gt hook/done can attach it to their events.
+	TraceID string
+
+	// Ports is the polecat's reserved port block, keyed by env var name
+	// (see PortEnv). Empty when the rig doesn't allocate ports.
+	Ports map[string]string
 }
 
 // AgentEnv returns all environment variables for an agent based on the config.
@@ -113,6 +122,33 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		env["GT_SESSION_ID_ENV"] = cfg.SessionIDEnv
 	}
 
+	if cfg.TraceID != "" {
+		env["GT_TRACE_ID"] = cfg.TraceID
+	}
+
+	for k, v := range cfg.Ports {
+		env[k] = v
+	}
+
+	return env
+}
+
+// PortEnv returns the environment variables for a reserved port block:
+// GT_PORT_BASE, GT_PORT_COUNT, and GT_PORT_<SERVICE> for each named service.
+func PortEnv(base, count int, services map[string]int) map[string]string {
+	env := map[string]string{
+		"GT_PORT_BASE":  strconv.Itoa(base),
+		"GT_PORT_COUNT": strconv.Itoa(count),
+	}
+	for name, port := range services {
+		key := strings.ToUpper(strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		}, name))
+		env["GT_PORT_"+key] = strconv.Itoa(port)
+	}
 	return env
 }
 
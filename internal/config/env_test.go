@@ -131,6 +131,38 @@ func TestAgentEnv_WithRuntimeConfigDir(t *testing.T) {
 	assertEnv(t, env, "CLAUDE_CONFIG_DIR", "/home/user/.config/claude")
 }
 
+func TestAgentEnv_WithTraceID(t *testing.T) {
+	t.Parallel()
+	env := AgentEnv(AgentEnvConfig{
+		Role:      "polecat",
+		Rig:       "myrig",
+		AgentName: "Toast",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+	})
+
+	assertEnv(t, env, "GT_TRACE_ID", "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	env = AgentEnv(AgentEnvConfig{Role: "polecat", Rig: "myrig", AgentName: "Toast"})
+	if _, ok := env["GT_TRACE_ID"]; ok {
+		t.Error("GT_TRACE_ID should not be set without a trace ID")
+	}
+}
+
+func TestAgentEnv_WithPorts(t *testing.T) {
+	t.Parallel()
+	env := AgentEnv(AgentEnvConfig{
+		Role:      "polecat",
+		Rig:       "myrig",
+		AgentName: "Toast",
+		Ports:     PortEnv(20010, 10, map[string]int{"web": 20010, "test-db": 20011}),
+	})
+
+	assertEnv(t, env, "GT_PORT_BASE", "20010")
+	assertEnv(t, env, "GT_PORT_COUNT", "10")
+	assertEnv(t, env, "GT_PORT_WEB", "20010")
+	assertEnv(t, env, "GT_PORT_TEST_DB", "20011")
+}
+
 func TestAgentEnv_WithoutRuntimeConfigDir(t *testing.T) {
 	t.Parallel()
 	env := AgentEnv(AgentEnvConfig{
@@ -241,6 +241,80 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
 
+	switch c.MergeMode {
+	case "", MergeModeLocal:
+	case MergeModePR:
+		if err := validateForgeConfig(c.Forge); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid merge_mode %q: want %q or %q", c.MergeMode, MergeModeLocal, MergeModePR)
+	}
+
+	if c.CI != nil {
+		if c.MergeMode == MergeModePR {
+			return fmt.Errorf("merge_queue.ci gates local merges; in merge_mode \"pr\" use forge.required_checks")
+		}
+		if err := validateCIConfig(c.CI); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateCIConfig validates the external CI gate settings.
+func validateCIConfig(c *CIConfig) error {
+	switch c.Provider {
+	case CIProviderHTTP:
+		if c.StatusURL == "" {
+			return fmt.Errorf("%w: ci.status_url", ErrMissingField)
+		}
+	case CIProviderWebhook:
+		if c.WebhookURL == "" {
+			return fmt.Errorf("%w: ci.webhook_url", ErrMissingField)
+		}
+		if c.StatusURL == "" {
+			return fmt.Errorf("%w: ci.status_url", ErrMissingField)
+		}
+	case CIProviderStatusFile:
+		if c.StatusFile == "" {
+			return fmt.Errorf("%w: ci.status_file", ErrMissingField)
+		}
+	default:
+		return fmt.Errorf("invalid ci provider %q: want %q, %q or %q",
+			c.Provider, CIProviderHTTP, CIProviderWebhook, CIProviderStatusFile)
+	}
+	if c.RefPrefix != "" && !strings.HasPrefix(c.RefPrefix, "refs/") {
+		return fmt.Errorf("invalid ci.ref_prefix %q: must start with refs/", c.RefPrefix)
+	}
+	if c.Timeout != "" {
+		if _, err := time.ParseDuration(c.Timeout); err != nil {
+			return fmt.Errorf("invalid ci.timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateForgeConfig validates the forge settings required by the "pr" merge mode.
+func validateForgeConfig(f *ForgeConfig) error {
+	if f == nil {
+		return fmt.Errorf("%w: merge_mode \"pr\" requires a forge section", ErrMissingField)
+	}
+	if f.Provider != ForgeGitHub && f.Provider != ForgeGitLab {
+		return fmt.Errorf("invalid forge provider %q: want %q or %q", f.Provider, ForgeGitHub, ForgeGitLab)
+	}
+	if f.Repo == "" {
+		return fmt.Errorf("%w: forge.repo", ErrMissingField)
+	}
+	switch f.MergeMethod {
+	case "", "squash", "merge", "rebase":
+	default:
+		return fmt.Errorf("invalid forge merge_method %q: want squash, merge or rebase", f.MergeMethod)
+	}
+	if f.RequiredApprovals < 0 {
+		return fmt.Errorf("%w: forge.required_approvals must be non-negative", ErrMissingField)
+	}
 	return nil
 }
 
@@ -360,6 +434,11 @@ func validateMayorConfig(c *MayorConfig) error {
 	if c.Version > CurrentMayorConfigVersion {
 		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentMayorConfigVersion)
 	}
+	if c.Daemon != nil && c.Daemon.HeartbeatInterval != "" {
+		if _, err := time.ParseDuration(c.Daemon.HeartbeatInterval); err != nil {
+			return fmt.Errorf("invalid daemon heartbeat_interval: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -333,6 +333,98 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid merge_mode",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeMode: "octopus",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pr merge_mode without forge",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeMode: MergeModePR,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pr merge_mode with unknown provider",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeMode: MergeModePR,
+					Forge:     &ForgeConfig{Provider: "gitea", Repo: "acme/widgets"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid pr merge_mode",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeMode: MergeModePR,
+					Forge:     &ForgeConfig{Provider: ForgeGitHub, Repo: "acme/widgets", RequiredApprovals: 1},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid ci gate",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					CI: &CIConfig{Provider: CIProviderHTTP, StatusURL: "https://ci.example/{sha}", Timeout: "45m"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ci webhook without status_url",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					CI: &CIConfig{Provider: CIProviderWebhook, WebhookURL: "https://ci.example/hook"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ci with invalid timeout",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					CI: &CIConfig{Provider: CIProviderStatusFile, StatusFile: "ci/{mr}", Timeout: "soon"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ci gate in pr merge_mode",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeMode: MergeModePR,
+					Forge:     &ForgeConfig{Provider: ForgeGitHub, Repo: "acme/widgets"},
+					CI:        &CIConfig{Provider: CIProviderStatusFile, StatusFile: "ci/{mr}"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
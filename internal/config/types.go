@@ -68,6 +68,11 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// FeedViews are named gt feed filters, saved from the feed TUI.
+	// Keys are view names; values are filter queries.
+	// Example: {"polecats": "actor:*/polecats/* -type:update"}
+	FeedViews map[string]string `json:"feed_views,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -83,8 +88,35 @@ func NewTownSettings() *TownSettings {
 
 // DaemonConfig represents daemon process settings.
 type DaemonConfig struct {
-	HeartbeatInterval string `json:"heartbeat_interval,omitempty"` // e.g., "30s"
-	PollInterval      string `json:"poll_interval,omitempty"`      // e.g., "10s"
+	// HeartbeatInterval is the recovery heartbeat interval (e.g., "5m").
+	// Default: "3m".
+	HeartbeatInterval string `json:"heartbeat_interval,omitempty"`
+	PollInterval      string `json:"poll_interval,omitempty"` // e.g., "10s"
+
+	// LivenessInterval is how often the heartbeat polls every agent
+	// session for liveness while event watching is running (e.g., "15m").
+	// Session deaths then arrive within seconds via inotify and tmux hooks;
+	// if the watcher or hook fails to start, every heartbeat polls.
+	// Default: "10m".
+	LivenessInterval string `json:"liveness_interval,omitempty"`
+
+	// EventWatch enables event-driven session death detection.
+	// nil means enabled; set to false to rely on the heartbeat alone.
+	EventWatch *bool `json:"event_watch,omitempty"`
+
+	// StatuslineInterval is how often the daemon re-renders the cached
+	// tmux status lines (e.g., "15s"); mail, hook and convoy events also
+	// trigger a refresh. "off" disables the cache, so gt status-line
+	// always renders live. Default: "15s".
+	StatuslineInterval string `json:"statusline_interval,omitempty"`
+}
+
+// IsEventWatchEnabled reports whether event-driven session watching is enabled.
+func (c *DaemonConfig) IsEventWatchEnabled() bool {
+	if c == nil || c.EventWatch == nil {
+		return true
+	}
+	return *c.EventWatch
 }
 
 // DaemonPatrolConfig represents the daemon patrol configuration (mayor/daemon.json).
@@ -236,6 +268,81 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// PolecatResources limits the resources each polecat session may use.
+	// Nil means polecats run unconstrained.
+	PolecatResources *PolecatResourcesConfig `json:"polecat_resources,omitempty"`
+
+	// PolecatPool keeps pre-created idle polecats ready for gt sling.
+	// Nil means every sling creates its polecat from scratch.
+	PolecatPool *PolecatPoolConfig `json:"polecat_pool,omitempty"`
+
+	// PolecatPorts reserves a block of ports for each polecat so dev servers
+	// and test databases in different polecats don't collide.
+	// Nil means no ports are reserved.
+	PolecatPorts *PolecatPortsConfig `json:"polecat_ports,omitempty"`
+}
+
+// PolecatPortsConfig configures per-polecat port allocation. Each polecat
+// gets BlockSize consecutive ports from Range when it is created, exported
+// to its session as GT_PORT_BASE, GT_PORT_COUNT and GT_PORT_<SERVICE>, and
+// to overlay templates as .PortBase and .Ports.
+type PolecatPortsConfig struct {
+	// Range is the inclusive port range to allocate from, e.g. "20000-29999".
+	Range string `json:"range"`
+
+	// BlockSize is the number of consecutive ports per polecat.
+	// Default: 10, or the number of services if larger.
+	BlockSize int `json:"block_size,omitempty"`
+
+	// Services names ports within a block, in order: with
+	// ["web", "db"], GT_PORT_WEB is the block's first port and GT_PORT_DB
+	// its second.
+	Services []string `json:"services,omitempty"`
+}
+
+// PolecatPoolConfig configures a rig's warm pool. Warm polecats have their
+// worktree created, overlay copied and setup hooks run ahead of time; gt sling
+// claims one instead of creating a polecat from scratch, and the daemon
+// replenishes the pool in the background.
+type PolecatPoolConfig struct {
+	// Size is how many warm polecats to keep ready. Zero disables the pool.
+	Size int `json:"size"`
+
+	// StartSessions also starts the agent session for warm polecats, so a
+	// claimed polecat only needs to be nudged with its work.
+	StartSessions bool `json:"start_sessions,omitempty"`
+
+	// MaxAge recycles warm polecats older than this duration (e.g. "12h") so
+	// their worktrees and setup hook output don't drift far from the default
+	// branch. Default: 24h.
+	MaxAge string `json:"max_age,omitempty"`
+}
+
+// PolecatResourcesConfig is a per-rig resource policy applied when a polecat
+// session is spawned. CPU, memory and process limits are enforced with a
+// cgroup v2 scope (systemd-run --user --scope).
+type PolecatResourcesConfig struct {
+	// CPU is the CPU quota as a percentage of one core, e.g. "200%" for two cores.
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory is the hard memory limit, e.g. "4G" or "512M".
+	Memory string `json:"memory,omitempty"`
+
+	// MaxProcesses caps the number of tasks (processes and threads).
+	MaxProcesses int `json:"max_processes,omitempty"`
+
+	// DiskWarn is the worktree size above which the witness warns the polecat,
+	// e.g. "10G". Disk usage is monitored, not hard-limited.
+	DiskWarn string `json:"disk_warn,omitempty"`
+
+	// Network is "host" (the default and only supported policy). "none" is
+	// rejected: the agent can't run without the model API and Dolt server.
+	Network string `json:"network,omitempty"`
+
+	// Required refuses to spawn a polecat when the limits cannot be applied
+	// on this host. By default the polecat starts unconstrained with a warning.
+	Required bool `json:"required,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -716,6 +823,19 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// MergeMode is how the refinery lands MRs: "local" (squash-merge and
+	// push to the target, the default) or "pr" (open a pull request through
+	// Forge and merge it through the API once checks and approvals pass).
+	MergeMode string `json:"merge_mode,omitempty"`
+
+	// Forge configures the code host used when MergeMode is "pr".
+	Forge *ForgeConfig `json:"forge,omitempty"`
+
+	// CI gates local merges on an external CI system: the refinery pushes
+	// the squash-merged candidate to a ref, waits for CI's verdict, and
+	// only then pushes it to the target branch. Nil means no CI gate.
+	CI *CIConfig `json:"ci,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -724,6 +844,96 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge mode constants.
+const (
+	MergeModeLocal = "local"
+	MergeModePR    = "pr"
+)
+
+// ForgeConfig describes the code host the refinery opens pull requests on.
+type ForgeConfig struct {
+	// Provider is "github" or "gitlab".
+	Provider string `json:"provider"`
+
+	// Repo identifies the repository: "owner/name" on GitHub, the project
+	// path (e.g., "group/subgroup/project") on GitLab.
+	Repo string `json:"repo"`
+
+	// APIURL overrides the API base URL, for GitHub Enterprise, self-hosted
+	// GitLab, or a local stand-in. Defaults to the provider's public API.
+	APIURL string `json:"api_url,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token.
+	// Defaults to GITHUB_TOKEN or GITLAB_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// RequiredApprovals is how many approving reviews a PR needs before the
+	// refinery merges it. Branch protection may require more.
+	RequiredApprovals int `json:"required_approvals,omitempty"`
+
+	// RequiredChecks lists the check names that must pass. Empty means
+	// every check reported on the PR's head commit must pass.
+	RequiredChecks []string `json:"required_checks,omitempty"`
+
+	// MergeMethod is "squash" (default), "merge" or "rebase".
+	MergeMethod string `json:"merge_method,omitempty"`
+}
+
+// Forge provider constants.
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+)
+
+// CIConfig describes the external CI system that gates the merge queue.
+//
+// URL and path settings may use the placeholders {mr}, {branch}, {target},
+// {ref} and {sha}, which expand to the MR bead ID, the MR's source and
+// target branches, the candidate ref and the candidate commit.
+type CIConfig struct {
+	// Provider is how the refinery learns CI's verdict:
+	//   - "http": poll StatusURL, which returns JSON with a status field
+	//   - "webhook": POST the candidate to WebhookURL, then poll StatusURL
+	//   - "status-file": poll StatusFile, which CI (or a relay) writes
+	Provider string `json:"provider"`
+
+	// StatusURL is the status endpoint polled by the http and webhook
+	// providers.
+	StatusURL string `json:"status_url,omitempty"`
+
+	// WebhookURL receives a JSON description of each candidate (webhook
+	// provider only).
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// StatusFile is the status file polled by the status-file provider.
+	// Relative paths are relative to the rig. It holds either JSON like
+	// the status endpoint's, or the status word on its first line.
+	StatusFile string `json:"status_file,omitempty"`
+
+	// StatusField names the JSON field holding the status. Defaults to
+	// "state", falling back to "status".
+	StatusField string `json:"status_field,omitempty"`
+
+	// TokenEnv names an environment variable holding a bearer token sent
+	// with HTTP requests.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// RefPrefix is where candidates are pushed; the MR ID is appended.
+	// Default: "refs/heads/gt-ci/" (a branch, so push-triggered CI runs).
+	RefPrefix string `json:"ref_prefix,omitempty"`
+
+	// Timeout is how long to wait for a verdict before failing the MR
+	// (e.g., "45m"). Default: "1h".
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// CI provider constants.
+const (
+	CIProviderHTTP       = "http"
+	CIProviderWebhook    = "webhook"
+	CIProviderStatusFile = "status-file"
+)
+
 // DefaultMergeQueueConfig returns a MergeQueueConfig with sensible defaults.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
@@ -742,14 +952,24 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 
 // NamepoolConfig represents namepool settings for themed polecat names.
 type NamepoolConfig struct {
-	// Style picks from a built-in theme (e.g., "mad-max", "minerals", "wasteland").
-	// If empty, defaults to "mad-max".
+	// Style picks from a built-in theme (e.g., "mad-max", "minerals", "wasteland"),
+	// or a theme file in the town's or rig's settings/namepool/ directory
+	// (<theme>.toml or <theme>.txt). If empty, defaults to "mad-max".
 	Style string `json:"style,omitempty"`
 
 	// Names is a custom list of names to use instead of a built-in theme.
 	// If provided, overrides the Style setting.
 	Names []string `json:"names,omitempty"`
 
+	// Strategy is how a free name is picked: "sequential" (first in theme
+	// order, the default), "random", or "weighted" (random, in proportion to
+	// the theme file's weights). Empty uses the theme file's strategy.
+	Strategy string `json:"strategy,omitempty"`
+
+	// RetireCooldown keeps the names of polecats nuked with unfinished work
+	// out of rotation for this long (e.g., "24h"). Empty disables retirement.
+	RetireCooldown string `json:"retire_cooldown,omitempty"`
+
 	// MaxBeforeNumbering is when to start appending numbers.
 	// Default is 50. After this many polecats, names become name-01, name-02, etc.
 	MaxBeforeNumbering int `json:"max_before_numbering,omitempty"`
@@ -1,4 +1,5 @@
-// Package convoy provides shared convoy operations for redundant observers.
+// Package convoy provides shared convoy operations: completion checks for
+// redundant observers, and convoy templates with their cron schedules.
 package convoy
 
 import (
@@ -0,0 +1,260 @@
+package convoy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Schedule is a parsed cron expression: minute, hour, day of month, month
+// and day of week, in the standard five-field form ("0 9 * * 1" is 09:00
+// every Monday). Each field accepts *, lists (1,15), ranges (1-5) and steps
+// (*/15, 9-17/2); months and weekdays also accept names (jan, mon). The
+// shorthands @hourly, @daily, @weekly and @monthly are accepted too.
+type Schedule struct {
+	expr    string
+	minutes uint64 // Bit i set means minute i matches
+	hours   uint64
+	doms    uint64 // 1-31
+	months  uint64 // 1-12
+	dows    uint64 // 0-6, Sunday is 0
+
+	// Cron matches a day if either day field matches when both are
+	// restricted, and the restricted one when only one is.
+	domStar, dowStar bool
+}
+
+// scheduleShorthands maps @-shorthands to their five-field expressions.
+var scheduleShorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+var (
+	monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// ParseSchedule parses a cron expression.
+func ParseSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	fields := strings.Fields(expr)
+	if len(fields) == 1 {
+		full, ok := scheduleShorthands[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule %q: unknown shorthand", expr)
+		}
+		fields = strings.Fields(full)
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: want 5 fields (minute hour day month weekday), got %d", expr, len(fields))
+	}
+
+	s := &Schedule{expr: expr}
+	var err error
+	if s.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: minute: %w", expr, err)
+	}
+	if s.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: hour: %w", expr, err)
+	}
+	if s.doms, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: day of month: %w", expr, err)
+	}
+	if s.months, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: month: %w", expr, err)
+	}
+	// 7 is Sunday too
+	if s.dows, err = parseCronField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: day of week: %w", expr, err)
+	}
+	if s.dows&(1<<7) != 0 {
+		s.dows = s.dows&^(1<<7) | 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bit set.
+// names, if set, are accepted for the values starting at low.
+func parseCronField(field string, low, high int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := low, high
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(a, low, high, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(b, low, high, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			v, err := parseCronValue(rangePart, low, high, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue parses a single number or name within [low, high].
+func parseCronValue(s string, low, high int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return low + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < low || v > high {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, low, high)
+	}
+	return v, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first time strictly after t that the schedule matches,
+// to the minute, in t's location. It returns the zero time if nothing
+// matches within five years (e.g. "0 0 31 2 *").
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchesDay reports whether t's date matches the day-of-month and
+// day-of-week fields.
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dom := s.doms&(1<<uint(t.Day())) != 0
+	dow := s.dows&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// ScheduleRun is the daemon's record of a scheduled template.
+type ScheduleRun struct {
+	// Since is when the daemon first saw the template scheduled. The
+	// first run is the first slot after it, so adding a schedule never
+	// fires for slots that passed before it existed.
+	Since time.Time `json:"since"`
+
+	LastRun   time.Time `json:"last_run,omitempty"`
+	LastError string    `json:"last_error,omitempty"` // Empty if the last run succeeded
+}
+
+// ScheduleStatePath returns the file recording scheduled template runs.
+func ScheduleStatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "convoy-schedules.json")
+}
+
+// LoadScheduleState reads the scheduled template runs, keyed by template
+// name. A missing file is an empty state.
+func LoadScheduleState(townRoot string) (map[string]*ScheduleRun, error) {
+	state := make(map[string]*ScheduleRun)
+	data, err := os.ReadFile(ScheduleStatePath(townRoot))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ScheduleStatePath(townRoot), err)
+	}
+	return state, nil
+}
+
+// SaveScheduleState writes the scheduled template runs.
+func SaveScheduleState(townRoot string, state map[string]*ScheduleRun) error {
+	if err := os.MkdirAll(filepath.Dir(ScheduleStatePath(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(ScheduleStatePath(townRoot), state)
+}
+
+// NextRun returns when the template is next due: the first slot after its
+// last run, or after run.Since if it hasn't run yet. Runs missed while the
+// daemon was down collapse into one. It returns the zero time for
+// unscheduled templates and schedules that never match.
+func (t *Template) NextRun(run *ScheduleRun) time.Time {
+	if t.Schedule == "" || run == nil {
+		return time.Time{}
+	}
+	sched, err := ParseSchedule(t.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+	base := run.LastRun
+	if base.IsZero() {
+		base = run.Since
+	}
+	return sched.Next(base)
+}
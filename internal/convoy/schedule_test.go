@@ -0,0 +1,106 @@
+package convoy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// Wednesday
+	base := time.Date(2026, 1, 14, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 14, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon", time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"30 10 * * 3", time.Date(2026, 1, 21, 10, 30, 0, 0, time.UTC)}, // Strictly after
+		{"0 0 1 * *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 jun *", time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)}, // 7 is Sunday
+		{"0 8-18/4 * * *", time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (the 15th is a Thursday)
+		{"0 0 15 * fri", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}}, // Never
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(base); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"@yearly-ish",
+	} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestTemplate_NextRun(t *testing.T) {
+	tmpl := &Template{Name: "weekly", Schedule: "0 9 * * mon"}
+	since := time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC)
+
+	run := &ScheduleRun{Since: since}
+	if got, want := tmpl.NextRun(run), time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("first NextRun = %v, want %v", got, want)
+	}
+
+	// Three weeks missed: the next run is the first slot after the last run,
+	// which is already past, so the daemon runs once and moves on
+	run.LastRun = time.Date(2026, 1, 19, 9, 0, 30, 0, time.UTC)
+	if got, want := tmpl.NextRun(run), time.Date(2026, 1, 26, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextRun after a run = %v, want %v", got, want)
+	}
+
+	if got := (&Template{Name: "manual"}).NextRun(run); !got.IsZero() {
+		t.Errorf("unscheduled NextRun = %v", got)
+	}
+}
+
+func TestScheduleState_RoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	state, err := LoadScheduleState(townRoot)
+	if err != nil || len(state) != 0 {
+		t.Fatalf("LoadScheduleState on empty town = %v, %v", state, err)
+	}
+
+	ran := time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC)
+	state["weekly"] = &ScheduleRun{Since: ran.Add(-time.Hour), LastRun: ran, LastError: "boom"}
+	if err := SaveScheduleState(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadScheduleState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run := got["weekly"]; run == nil || !run.LastRun.Equal(ran) || run.LastError != "boom" {
+		t.Errorf("loaded state = %+v", got["weekly"])
+	}
+}
@@ -0,0 +1,345 @@
+package convoy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// Template is a reusable convoy definition: a titled bundle of issue
+// blueprints, each filed in one or more rigs, with {{variables}} filled in
+// when the template is instantiated. A template with a schedule is also
+// instantiated by the daemon on that schedule.
+//
+// Templates live in <town>/settings/convoy-templates/<name>.toml:
+//
+//	title = "Dependency bumps {{week}}"
+//	schedule = "0 9 * * mon"
+//
+//	[vars.go_version]
+//	description = "Go toolchain to move to"
+//	default = "1.24"
+//
+//	[[issues]]
+//	rigs = ["gastown", "beads"]
+//	title = "Bump dependencies in {{rig}}"
+//	description = "go get -u ./... and move go.mod to {{go_version}}"
+type Template struct {
+	Name        string                 `toml:"name"`
+	Description string                 `toml:"description"` // What the template is for
+	Title       string                 `toml:"title"`       // Convoy title
+	Owner       string                 `toml:"owner"`       // Default --owner
+	Notify      string                 `toml:"notify"`      // Default --notify
+	Molecule    string                 `toml:"molecule"`    // Default --molecule
+	Schedule    string                 `toml:"schedule"`    // Cron expression; empty for manual only
+	Vars        map[string]formula.Var `toml:"vars"`
+	Issues      []Blueprint            `toml:"issues"`
+
+	// Path is the file the template was loaded from.
+	Path string `toml:"-"`
+}
+
+// Blueprint describes an issue a template files in each of its rigs.
+type Blueprint struct {
+	Rig         string   `toml:"rig"`
+	Rigs        []string `toml:"rigs"`
+	Title       string   `toml:"title"`
+	Description string   `toml:"description"`
+	Type        string   `toml:"type"`     // Issue type; defaults to task
+	Priority    *int     `toml:"priority"` // 0-4; defaults to the rig's default
+	Labels      []string `toml:"labels"`
+}
+
+// TargetRigs returns the rigs the blueprint is filed in, rig first.
+func (b *Blueprint) TargetRigs() []string {
+	var rigs []string
+	for _, r := range append([]string{b.Rig}, b.Rigs...) {
+		if r != "" && !slices.Contains(rigs, r) {
+			rigs = append(rigs, r)
+		}
+	}
+	return rigs
+}
+
+// Built-in template variables. rig is only set inside issue blueprints.
+var builtinVars = []string{"template", "date", "week", "month", "rig"}
+
+// TemplateLabelPrefix prefixes the label tying convoys and their issues to
+// the template that created them.
+const TemplateLabelPrefix = "template:"
+
+// TemplateLabel returns the label marking instances of a template.
+func TemplateLabel(name string) string {
+	return TemplateLabelPrefix + name
+}
+
+var (
+	templateNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	placeholderRe  = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+)
+
+// TemplatesDir returns the directory holding a town's convoy templates.
+func TemplatesDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "convoy-templates")
+}
+
+// ParseTemplate parses and validates a template. name is used when the
+// template doesn't set one.
+func ParseTemplate(data []byte, name string) (*Template, error) {
+	var t Template
+	if _, err := toml.Decode(string(data), &t); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	if t.Name == "" {
+		t.Name = name
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// LoadTemplate loads the named template from the town's templates directory.
+func LoadTemplate(townRoot, name string) (*Template, error) {
+	if !templateNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	path := filepath.Join(TemplatesDir(townRoot), name+".toml")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town's templates directory
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("convoy template %q not found (looked for %s)", name, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err := ParseTemplate(data, name)
+	if err != nil {
+		return nil, fmt.Errorf("convoy template %s: %w", name, err)
+	}
+	if t.Name != name {
+		return nil, fmt.Errorf("convoy template %s: name %q doesn't match file name", name, t.Name)
+	}
+	t.Path = path
+	return t, nil
+}
+
+// LoadTemplates loads every template in the town, sorted by name. Templates
+// that fail to load are skipped and reported in the returned error.
+func LoadTemplates(townRoot string) ([]*Template, error) {
+	entries, err := os.ReadDir(TemplatesDir(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*Template
+	var errs []error
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".toml")
+		if !ok || e.IsDir() {
+			continue
+		}
+		t, err := LoadTemplate(townRoot, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, errors.Join(errs...)
+}
+
+// Validate checks the template's structure, schedule and variable references.
+func (t *Template) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !templateNameRe.MatchString(t.Name) {
+		return fmt.Errorf("invalid template name %q (use lowercase letters, digits, - and _)", t.Name)
+	}
+	if strings.TrimSpace(t.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(t.Issues) == 0 {
+		return fmt.Errorf("at least one [[issues]] blueprint is required")
+	}
+
+	for name, v := range t.Vars {
+		if isBuiltinVar(name) {
+			return fmt.Errorf("variable %q shadows a built-in variable", name)
+		}
+		if t.Schedule != "" && v.Required && v.Default == "" {
+			return fmt.Errorf("variable %q is required but the template is scheduled; give it a default", name)
+		}
+	}
+	if t.Schedule != "" {
+		if _, err := ParseSchedule(t.Schedule); err != nil {
+			return err
+		}
+	}
+
+	if err := t.checkRefs("title", t.Title, false); err != nil {
+		return err
+	}
+	for i, b := range t.Issues {
+		where := fmt.Sprintf("issues[%d]", i)
+		if len(b.TargetRigs()) == 0 {
+			return fmt.Errorf("%s: rig or rigs is required", where)
+		}
+		if strings.TrimSpace(b.Title) == "" {
+			return fmt.Errorf("%s: title is required", where)
+		}
+		if b.Priority != nil && (*b.Priority < 0 || *b.Priority > 4) {
+			return fmt.Errorf("%s: priority must be 0-4", where)
+		}
+		if err := t.checkRefs(where+".title", b.Title, true); err != nil {
+			return err
+		}
+		if err := t.checkRefs(where+".description", b.Description, true); err != nil {
+			return err
+		}
+		for _, l := range b.Labels {
+			if err := t.checkRefs(where+".labels", l, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRefs reports placeholders in s that name no variable.
+func (t *Template) checkRefs(where, s string, inIssue bool) error {
+	for _, m := range placeholderRe.FindAllStringSubmatch(s, -1) {
+		name := m[1]
+		if _, ok := t.Vars[name]; ok {
+			continue
+		}
+		if name == "rig" && !inIssue {
+			return fmt.Errorf("%s: {{rig}} is only available in issue blueprints", where)
+		}
+		if !isBuiltinVar(name) {
+			return fmt.Errorf("%s: unknown variable {{%s}}", where, name)
+		}
+	}
+	return nil
+}
+
+func isBuiltinVar(name string) bool {
+	return slices.Contains(builtinVars, name)
+}
+
+// Instance is a template instantiated with concrete variables: the convoy
+// and the issues to file for it.
+type Instance struct {
+	Template string
+	Title    string
+	Vars     map[string]string // Declared variables as resolved
+	Issues   []PlannedIssue
+}
+
+// PlannedIssue is an issue to file in a rig for an instance.
+type PlannedIssue struct {
+	Rig         string
+	Title       string
+	Description string
+	Type        string
+	Priority    int // -1 for the rig's default
+	Labels      []string
+}
+
+// Instantiate resolves the template's variables and expands its blueprints,
+// one planned issue per blueprint per rig. vars override declared defaults;
+// every required variable must be set, and unknown ones are rejected so a
+// typo doesn't silently fall back to a default. now sets the date, week
+// and month built-ins.
+func (t *Template) Instantiate(vars map[string]string, now time.Time) (*Instance, error) {
+	for name := range vars {
+		if _, ok := t.Vars[name]; !ok {
+			if isBuiltinVar(name) {
+				return nil, fmt.Errorf("%q is a built-in variable and can't be set", name)
+			}
+			return nil, fmt.Errorf("template %s has no variable %q", t.Name, name)
+		}
+	}
+
+	resolved := make(map[string]string, len(t.Vars))
+	var missing []string
+	for name, v := range t.Vars {
+		value, ok := vars[name]
+		if !ok {
+			value = v.Default
+		}
+		if v.Required && value == "" {
+			missing = append(missing, name)
+			continue
+		}
+		resolved[name] = value
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("template %s needs --var for: %s", t.Name, strings.Join(missing, ", "))
+	}
+
+	year, week := now.ISOWeek()
+	scope := map[string]string{
+		"template": t.Name,
+		"date":     now.Format("2006-01-02"),
+		"week":     fmt.Sprintf("%d-W%02d", year, week),
+		"month":    now.Format("2006-01"),
+	}
+	for k, v := range resolved {
+		scope[k] = v
+	}
+
+	inst := &Instance{
+		Template: t.Name,
+		Title:    expand(t.Title, scope),
+		Vars:     resolved,
+	}
+	for _, b := range t.Issues {
+		for _, rig := range b.TargetRigs() {
+			scope["rig"] = rig
+			issue := PlannedIssue{
+				Rig:         rig,
+				Title:       expand(b.Title, scope),
+				Description: expand(b.Description, scope),
+				Type:        b.Type,
+				Priority:    -1,
+			}
+			if issue.Type == "" {
+				issue.Type = "task"
+			}
+			if b.Priority != nil {
+				issue.Priority = *b.Priority
+			}
+			for _, l := range b.Labels {
+				issue.Labels = append(issue.Labels, expand(l, scope))
+			}
+			inst.Issues = append(inst.Issues, issue)
+		}
+	}
+	return inst, nil
+}
+
+// expand replaces {{name}} placeholders with their values from scope.
+func expand(s string, scope map[string]string) string {
+	return placeholderRe.ReplaceAllStringFunc(s, func(m string) string {
+		name := placeholderRe.FindStringSubmatch(m)[1]
+		if v, ok := scope[name]; ok {
+			return v
+		}
+		return m
+	})
+}
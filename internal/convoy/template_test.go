@@ -0,0 +1,177 @@
+package convoy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const depsBumpTemplate = `
+description = "Weekly dependency bumps"
+title = "Dependency bumps {{week}}"
+owner = "mayor/"
+schedule = "0 9 * * mon"
+
+[vars.go_version]
+description = "Go toolchain to move to"
+default = "1.24"
+
+[[issues]]
+rig = "gastown"
+rigs = ["beads", "gastown"]
+title = "Bump dependencies in {{rig}}"
+description = "Move {{rig}} to go {{go_version}} ({{template}}, {{date}})"
+priority = 1
+labels = ["deps", "deps-{{month}}"]
+
+[[issues]]
+rig = "gastown"
+title = "Review {{rig}} changelog"
+type = "chore"
+`
+
+func writeTemplate(t *testing.T, townRoot, name, content string) {
+	t.Helper()
+	dir := TemplatesDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplate_Instantiate(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte(depsBumpTemplate), "deps-bump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC)
+
+	inst, err := tmpl.Instantiate(map[string]string{"go_version": "1.25"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.Title != "Dependency bumps 2026-W03" {
+		t.Errorf("Title = %q", inst.Title)
+	}
+	if inst.Vars["go_version"] != "1.25" {
+		t.Errorf("Vars = %v", inst.Vars)
+	}
+
+	// One issue per blueprint per rig, rig first and without duplicates
+	var got []string
+	for _, issue := range inst.Issues {
+		got = append(got, issue.Rig+": "+issue.Title)
+	}
+	want := []string{
+		"gastown: Bump dependencies in gastown",
+		"beads: Bump dependencies in beads",
+		"gastown: Review gastown changelog",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	bump := inst.Issues[1]
+	if bump.Description != "Move beads to go 1.25 (deps-bump, 2026-01-14)" {
+		t.Errorf("Description = %q", bump.Description)
+	}
+	if bump.Type != "task" || bump.Priority != 1 {
+		t.Errorf("type/priority = %s/%d, want task/1", bump.Type, bump.Priority)
+	}
+	if strings.Join(bump.Labels, ",") != "deps,deps-2026-01" {
+		t.Errorf("Labels = %v", bump.Labels)
+	}
+	if last := inst.Issues[2]; last.Type != "chore" || last.Priority != -1 {
+		t.Errorf("defaults: type/priority = %s/%d, want chore/-1", last.Type, last.Priority)
+	}
+
+	// Defaults fill unset variables
+	inst, err = tmpl.Instantiate(nil, now)
+	if err != nil || inst.Vars["go_version"] != "1.24" {
+		t.Errorf("default var: %v, %v", inst, err)
+	}
+}
+
+func TestTemplate_InstantiateVarErrors(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte(`
+title = "Release {{version}}"
+[vars.version]
+required = true
+[[issues]]
+rig = "gastown"
+title = "Tag {{version}}"
+`), "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	if _, err := tmpl.Instantiate(nil, now); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("missing required var: err = %v", err)
+	}
+	if _, err := tmpl.Instantiate(map[string]string{"version": "2.1", "verison": "x"}, now); err == nil {
+		t.Error("unknown var accepted")
+	}
+	if _, err := tmpl.Instantiate(map[string]string{"version": "2.1", "date": "x"}, now); err == nil {
+		t.Error("built-in var override accepted")
+	}
+	if inst, err := tmpl.Instantiate(map[string]string{"version": "2.1"}, now); err != nil || inst.Title != "Release 2.1" {
+		t.Errorf("Instantiate = %v, %v", inst, err)
+	}
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	issue := "\n[[issues]]\nrig = \"gastown\"\ntitle = \"x\"\n"
+	tests := map[string]string{
+		"no title":           issue,
+		"no issues":          `title = "x"`,
+		"no rig":             "title = \"x\"\n[[issues]]\ntitle = \"y\"\n",
+		"bad priority":       "title = \"x\"\n[[issues]]\nrig = \"g\"\ntitle = \"y\"\npriority = 7\n",
+		"bad schedule":       "title = \"x\"\nschedule = \"every monday\"\n" + issue,
+		"unknown var":        "title = \"{{nope}}\"\n" + issue,
+		"rig outside issue":  "title = \"{{rig}}\"\n" + issue,
+		"shadows builtin":    "title = \"x\"\n[vars.date]\ndefault = \"y\"\n" + issue,
+		"scheduled required": "title = \"x\"\nschedule = \"@daily\"\n[vars.v]\nrequired = true\n" + issue,
+		"bad toml":           "title = ",
+	}
+	for name, content := range tests {
+		if _, err := ParseTemplate([]byte(content), "t"); err == nil {
+			t.Errorf("%s: ParseTemplate succeeded", name)
+		}
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	townRoot := t.TempDir()
+
+	templates, err := LoadTemplates(townRoot)
+	if err != nil || len(templates) != 0 {
+		t.Fatalf("LoadTemplates without a directory = %v, %v", templates, err)
+	}
+
+	writeTemplate(t, townRoot, "deps-bump", depsBumpTemplate)
+	writeTemplate(t, townRoot, "broken", `title = "no issues"`)
+	writeTemplate(t, townRoot, "renamed", "name = \"other\"\n"+depsBumpTemplate)
+
+	templates, err = LoadTemplates(townRoot)
+	if len(templates) != 1 || templates[0].Name != "deps-bump" {
+		t.Fatalf("templates = %v", templates)
+	}
+	if templates[0].Path != filepath.Join(TemplatesDir(townRoot), "deps-bump.toml") {
+		t.Errorf("Path = %q", templates[0].Path)
+	}
+	if err == nil || !strings.Contains(err.Error(), "broken") || !strings.Contains(err.Error(), "renamed") {
+		t.Errorf("err = %v, want both bad templates reported", err)
+	}
+
+	if _, err := LoadTemplate(townRoot, "missing"); err == nil {
+		t.Error("LoadTemplate(missing) succeeded")
+	}
+	if _, err := LoadTemplate(townRoot, "../deps-bump"); err == nil {
+		t.Error("LoadTemplate accepted a path")
+	}
+}
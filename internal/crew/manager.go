@@ -106,6 +106,17 @@ func (m *Manager) mailDir(name string) string {
 	return filepath.Join(m.crewDir(name), "mail")
 }
 
+// OverlayVars returns the overlay template variables for a crew worker.
+// Crew workers have no reserved ports.
+func (m *Manager) OverlayVars(name string) rig.OverlayVars {
+	return rig.OverlayVars{
+		Rig:      m.rig.Name,
+		Role:     rig.OverlayRoleCrew,
+		Name:     name,
+		Worktree: m.crewDir(name),
+	}
+}
+
 // exists checks if a crew worker exists.
 func (m *Manager) exists(name string) bool {
 	_, err := os.Stat(m.crewDir(name))
@@ -180,9 +191,9 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 		fmt.Printf("Warning: could not provision PRIME.md: %v\n", err)
 	}
 
-	// Copy overlay files from .runtime/overlay/ to crew root.
-	// This allows services to have .env and other config files at their root.
-	if err := rig.CopyOverlay(m.rig.Path, crewPath); err != nil {
+	// Copy overlay files from .runtime/overlay/ into the crew workspace.
+	// This allows services to have .env and other config files in place.
+	if err := rig.ApplyOverlay(m.rig.Path, crewPath, m.OverlayVars(name)); err != nil {
 		// Non-fatal - log warning but continue
 		fmt.Printf("Warning: could not copy overlay files: %v\n", err)
 	}
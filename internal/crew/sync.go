@@ -0,0 +1,188 @@
+package crew
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// SyncMode selects how a crew workspace is brought up to date.
+type SyncMode string
+
+const (
+	// SyncPreview fetches and reports drift without changing the workspace.
+	SyncPreview SyncMode = ""
+
+	// SyncRebase rebases the crew branch onto the rig default branch.
+	SyncRebase SyncMode = "rebase"
+
+	// SyncMerge merges the rig default branch into the crew branch.
+	SyncMerge SyncMode = "merge"
+)
+
+// syncStashMessage labels stashes created by Sync, so they are recognizable
+// if a restore fails and the stash is left behind.
+const syncStashMessage = "gt crew sync: autostash"
+
+// Drift describes how far a crew workspace has diverged from the rig
+// default branch.
+type Drift struct {
+	Name   string `json:"name"`
+	Branch string `json:"branch"`
+	Base   string `json:"base"`
+
+	// Behind and Ahead count commits relative to Base.
+	Behind int `json:"behind"`
+	Ahead  int `json:"ahead"`
+
+	// Unpushed counts commits not on the branch's upstream.
+	Unpushed int  `json:"unpushed"`
+	Stashes  int  `json:"stashes"`
+	Dirty    bool `json:"dirty"`
+
+	// Conflicts lists files that would conflict when bringing in Base.
+	Conflicts []string `json:"conflicts,omitempty"`
+
+	FetchError   string `json:"fetch_error,omitempty"`
+	PreviewError string `json:"preview_error,omitempty"`
+}
+
+// SyncResult captures the results of a sync operation.
+type SyncResult struct {
+	Drift
+
+	Mode SyncMode `json:"mode,omitempty"`
+
+	// Stashed is set when uncommitted changes were stashed around the sync.
+	Stashed bool `json:"stashed,omitempty"`
+
+	// Synced is set when the workspace was rebased or merged.
+	Synced bool `json:"synced"`
+
+	// Skipped explains why a sync was not attempted.
+	Skipped string `json:"skipped,omitempty"`
+
+	SyncError    string `json:"sync_error,omitempty"`
+	RestoreError string `json:"restore_error,omitempty"`
+}
+
+// Sync fetches the rig default branch into a crew workspace, reports its
+// drift and previews conflicts with git merge-tree, which leaves the index
+// and working tree alone. With SyncRebase or SyncMerge it then brings the
+// workspace up to date, stashing uncommitted changes around the operation.
+// A workspace with previewed conflicts is left untouched.
+func (m *Manager) Sync(name string, mode SyncMode) (*SyncResult, error) {
+	if err := validateCrewName(name); err != nil {
+		return nil, err
+	}
+	if !m.exists(name) {
+		return nil, ErrCrewNotFound
+	}
+
+	crewGit := git.NewGit(m.crewDir(name))
+	base := "origin/" + m.rig.DefaultBranch()
+
+	result := &SyncResult{Drift: Drift{Name: name, Base: base}, Mode: mode}
+
+	branch, err := crewGit.CurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("getting current branch: %w", err)
+	}
+	result.Branch = branch
+
+	if err := crewGit.Fetch("origin"); err != nil {
+		result.FetchError = err.Error()
+	}
+
+	if err := m.measureDrift(crewGit, &result.Drift); err != nil {
+		return nil, err
+	}
+	if result.Behind == 0 {
+		return result, nil
+	}
+
+	// The preview works on commits only, so live worktrees are never touched
+	conflicts, err := crewGit.MergeTreeConflicts(base, "HEAD")
+	if err != nil {
+		result.PreviewError = err.Error()
+	}
+	result.Conflicts = conflicts
+
+	switch {
+	case mode == SyncPreview:
+		return result, nil
+	case result.PreviewError != "":
+		result.Skipped = "conflict preview failed"
+		return result, nil
+	case len(conflicts) > 0:
+		result.Skipped = "would conflict"
+		return result, nil
+	}
+
+	// Uncommitted changes are stashed around the sync and restored after
+	if result.Dirty {
+		if err := crewGit.StashPush(syncStashMessage); err != nil {
+			result.SyncError = fmt.Sprintf("stashing changes: %v", err)
+			return result, nil
+		}
+		result.Stashed = true
+	}
+
+	m.applySync(crewGit, result)
+
+	if result.Stashed {
+		if err := crewGit.StashPop(); err != nil {
+			result.RestoreError = fmt.Sprintf("restoring stashed changes: %v (left in stash as %q)", err, syncStashMessage)
+		}
+	}
+
+	if result.Synced {
+		// Refresh counts for the report; a failure here doesn't undo the sync
+		_ = m.measureDrift(crewGit, &result.Drift)
+	}
+	return result, nil
+}
+
+// applySync rebases or merges base into the current branch, aborting on
+// failure so the workspace is left as it was.
+func (m *Manager) applySync(g *git.Git, result *SyncResult) {
+	var err error
+	switch result.Mode {
+	case SyncRebase:
+		if err = g.Rebase(result.Base); err != nil {
+			_ = g.AbortRebase()
+		}
+	case SyncMerge:
+		if err = g.Merge(result.Base); err != nil {
+			_ = g.AbortMerge()
+		}
+	default:
+		err = fmt.Errorf("unknown sync mode %q", result.Mode)
+	}
+	if err != nil {
+		result.SyncError = err.Error()
+		return
+	}
+	result.Synced = true
+}
+
+// measureDrift fills in the commit and working tree counts of d.
+func (m *Manager) measureDrift(g *git.Git, d *Drift) error {
+	var err error
+	if d.Behind, err = g.CountCommitsBehind(d.Base); err != nil {
+		return fmt.Errorf("counting commits behind %s: %w", d.Base, err)
+	}
+	if d.Ahead, err = g.CommitsAhead(d.Base, "HEAD"); err != nil {
+		return fmt.Errorf("counting commits ahead of %s: %w", d.Base, err)
+	}
+	if d.Unpushed, err = g.UnpushedCommits(); err != nil {
+		return fmt.Errorf("counting unpushed commits: %w", err)
+	}
+	if d.Stashes, err = g.StashCount(); err != nil {
+		return fmt.Errorf("counting stashes: %w", err)
+	}
+	if d.Dirty, err = g.HasUncommittedChanges(); err != nil {
+		return fmt.Errorf("checking changes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package crew
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupSyncRig creates a rig whose remote has a main branch, a crew worker
+// "dave" on a feature branch, and a separate clone for pushing upstream work.
+func setupSyncRig(t *testing.T) (mgr *Manager, crewPath, upstream string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	remote := filepath.Join(tmpDir, "remote.git")
+	mustRun(t, "", "git", "init", "--bare", "-b", "main", remote)
+
+	upstream = filepath.Join(tmpDir, "upstream")
+	mustRun(t, "", "git", "clone", remote, upstream)
+	configureRepo(t, upstream)
+	writeAndCommit(t, upstream, "shared.txt", "one\n", "initial")
+	mustRun(t, upstream, "git", "push", "origin", "HEAD:main")
+
+	rigPath := filepath.Join(tmpDir, "test-rig")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := &rig.Rig{Name: "test-rig", Path: rigPath, GitURL: remote}
+	mgr = NewManager(r, git.NewGit(rigPath))
+
+	worker, err := mgr.Add("dave", true)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	crewPath = worker.ClonePath
+	configureRepo(t, crewPath)
+	// Commit the files Add provisions so the workspace starts clean
+	mustRun(t, crewPath, "git", "add", "-A")
+	mustRun(t, crewPath, "git", "commit", "-q", "-m", "provision")
+
+	return mgr, crewPath, upstream
+}
+
+func TestSyncPreviewReportsDrift(t *testing.T) {
+	mgr, crewPath, upstream := setupSyncRig(t)
+
+	writeAndCommit(t, upstream, "upstream.txt", "new\n", "upstream work")
+	mustRun(t, upstream, "git", "push", "origin", "HEAD:main")
+	if err := os.WriteFile(filepath.Join(crewPath, "scratch.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := mgr.Sync("dave", SyncPreview)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Branch != "crew/dave" || result.Base != "origin/main" {
+		t.Errorf("branch/base = %s/%s", result.Branch, result.Base)
+	}
+	if result.Behind != 1 || result.Ahead != 1 {
+		t.Errorf("behind/ahead = %d/%d, want 1/1", result.Behind, result.Ahead)
+	}
+	if !result.Dirty || result.Synced || len(result.Conflicts) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	// The preview must leave uncommitted work in place
+	if _, err := os.Stat(filepath.Join(crewPath, "scratch.txt")); err != nil {
+		t.Errorf("uncommitted file lost: %v", err)
+	}
+	if result.RestoreError != "" {
+		t.Errorf("restore error: %s", result.RestoreError)
+	}
+}
+
+func TestSyncRebaseKeepsUncommittedChanges(t *testing.T) {
+	mgr, crewPath, upstream := setupSyncRig(t)
+
+	writeAndCommit(t, upstream, "upstream.txt", "new\n", "upstream work")
+	mustRun(t, upstream, "git", "push", "origin", "HEAD:main")
+	if err := os.WriteFile(filepath.Join(crewPath, "shared.txt"), []byte("one\nlocal edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := mgr.Sync("dave", SyncRebase)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !result.Synced || result.SyncError != "" || result.RestoreError != "" {
+		t.Fatalf("expected clean rebase, got %+v", result)
+	}
+	if result.Behind != 0 || !result.Dirty {
+		t.Errorf("after rebase behind=%d dirty=%v, want 0/true", result.Behind, result.Dirty)
+	}
+	if _, err := os.Stat(filepath.Join(crewPath, "upstream.txt")); err != nil {
+		t.Errorf("upstream commit not applied: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(crewPath, "shared.txt"))
+	if string(data) != "one\nlocal edit\n" {
+		t.Errorf("uncommitted edit lost: %q", data)
+	}
+}
+
+func TestSyncSkipsConflicts(t *testing.T) {
+	mgr, crewPath, upstream := setupSyncRig(t)
+
+	writeAndCommit(t, upstream, "shared.txt", "upstream\n", "upstream edit")
+	mustRun(t, upstream, "git", "push", "origin", "HEAD:main")
+	writeAndCommit(t, crewPath, "shared.txt", "crew\n", "crew edit")
+	head := revParse(t, crewPath)
+
+	result, err := mgr.Sync("dave", SyncMerge)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Synced || result.Skipped == "" {
+		t.Fatalf("expected conflicting sync to be skipped, got %+v", result)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "shared.txt" {
+		t.Errorf("conflicts = %v, want [shared.txt]", result.Conflicts)
+	}
+	if got := revParse(t, crewPath); got != head {
+		t.Errorf("HEAD moved from %s to %s", head, got)
+	}
+}
+
+func TestSyncPreviewIsReadOnly(t *testing.T) {
+	mgr, crewPath, upstream := setupSyncRig(t)
+
+	writeAndCommit(t, upstream, "shared.txt", "upstream\n", "upstream edit")
+	mustRun(t, upstream, "git", "push", "origin", "HEAD:main")
+	writeAndCommit(t, crewPath, "shared.txt", "crew\n", "crew edit")
+	if err := os.WriteFile(filepath.Join(crewPath, "shared.txt"), []byte("crew\nwip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mustRun(t, crewPath, "git", "add", "shared.txt")
+	head := revParse(t, crewPath)
+
+	result, err := mgr.Sync("dave", SyncPreview)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "shared.txt" {
+		t.Errorf("conflicts = %v, want [shared.txt]", result.Conflicts)
+	}
+	if result.Stashed {
+		t.Error("preview stashed changes")
+	}
+	if got := revParse(t, crewPath); got != head {
+		t.Errorf("HEAD moved from %s to %s", head, got)
+	}
+	if n, _ := git.NewGit(crewPath).StashCount(); n != 0 {
+		t.Errorf("stash count = %d, want 0", n)
+	}
+	// The staged edit must still be staged, not reset or round-tripped
+	cmd := exec.Command("git", "diff", "--cached", "--name-only")
+	cmd.Dir = crewPath
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "shared.txt\n" {
+		t.Errorf("staged files = %q, want shared.txt", out)
+	}
+}
+
+func TestSyncNotFound(t *testing.T) {
+	mgr, _, _ := setupSyncRig(t)
+	if _, err := mgr.Sync("nobody", SyncPreview); err != ErrCrewNotFound {
+		t.Errorf("Sync(nobody) error = %v, want ErrCrewNotFound", err)
+	}
+}
+
+func configureRepo(t *testing.T, dir string) {
+	t.Helper()
+	mustRun(t, dir, "git", "config", "user.email", "test@test.com")
+	mustRun(t, dir, "git", "config", "user.name", "Test")
+}
+
+func writeAndCommit(t *testing.T, dir, file, content, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mustRun(t, dir, "git", "add", file)
+	mustRun(t, dir, "git", "commit", "-q", "-m", msg)
+}
+
+func revParse(t *testing.T, dir string) string {
+	t.Helper()
+	out, err := git.NewGit(dir).Rev("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func mustRun(t *testing.T, dir, name string, args ...string) {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s %v: %v\n%s", name, args, err, out)
+	}
+}
@@ -0,0 +1,200 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/quota"
+)
+
+// quotaPaneLines is how much of a pane is scanned for limit messages. Limit
+// notices are shown at the bottom of the pane while the session is stalled.
+const quotaPaneLines = 15
+
+// checkAccountQuotas detects polecats stalled on an account usage limit
+// (confirmed across two checks with an unchanged pane), marks the account as
+// cooling down until its reset time, and restarts the polecat on the next
+// available account. The hook lives in beads, so the restarted session picks
+// up the same work; a fresh checkpoint is captured first so it can resume
+// mid-step.
+func (d *Daemon) checkAccountQuotas() {
+	cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(d.config.TownRoot))
+	if err != nil || len(cfg.Accounts) == 0 {
+		clear(d.limitSightings)
+		return // Accounts not configured
+	}
+
+	// Forget sightings for sessions that are no longer checked (polecat
+	// removed, rig parked or docked)
+	checked := make(map[string]bool, len(d.limitSightings))
+	defer func() {
+		for sessionName := range d.limitSightings {
+			if !checked[sessionName] {
+				delete(d.limitSightings, sessionName)
+			}
+		}
+	}()
+
+	for _, rigName := range d.getKnownRigs() {
+		if operational, _ := d.isRigOperational(rigName); !operational {
+			continue
+		}
+		polecats, err := listPolecatWorktrees(filepath.Join(d.config.TownRoot, rigName, "polecats"))
+		if err != nil {
+			continue
+		}
+		for _, polecatName := range polecats {
+			checked[fmt.Sprintf("gt-%s-%s", rigName, polecatName)] = true
+			d.checkPolecatQuota(cfg, rigName, polecatName)
+		}
+	}
+}
+
+// checkPolecatQuota checks one polecat's session for a usage limit.
+func (d *Daemon) checkPolecatQuota(cfg *config.AccountsConfig, rigName, polecatName string) {
+	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+	if alive, err := d.tmux.HasSession(sessionName); err != nil || !alive {
+		delete(d.limitSightings, sessionName)
+		return
+	}
+
+	// Sessions started without an account use the default one
+	configDir, _ := d.tmux.GetEnvironment(sessionName, "CLAUDE_CONFIG_DIR")
+	handle := quota.HandleForConfigDir(cfg, configDir)
+	if handle == "" {
+		handle = cfg.Default
+	}
+	if handle == "" {
+		return
+	}
+
+	now := time.Now()
+	workDir := polecatWorkDir(filepath.Join(d.config.TownRoot, rigName), rigName, polecatName)
+	limit, pane := d.detectLimit(sessionName, cfg, handle, workDir, now)
+	if limit == nil {
+		delete(d.limitSightings, sessionName)
+		return
+	}
+
+	// A stalled session shows the same pane on consecutive checks; one that
+	// is still producing output is not stalled, whatever it printed
+	prevPane, seen := d.limitSightings[sessionName]
+	d.limitSightings[sessionName] = pane
+	if !seen || prevPane != pane {
+		return
+	}
+
+	var next string
+	var reset, fresh bool
+	err := quota.Update(d.config.TownRoot, func(s *quota.State) {
+		// A session still showing the limit that stalled it, after the
+		// cooldown ended, is idle rather than limited again
+		if prev := s.Accounts[handle]; prev != nil && prev.Session == sessionName && !prev.ResetsAt.After(now) {
+			s.Clear(handle)
+			reset = true
+			return
+		}
+		fresh = s.Cooling(handle, now) == nil
+		s.MarkLimited(handle, limit, sessionName, now)
+		next = quota.NextAvailable(cfg, s, handle, now)
+	})
+	if err != nil {
+		d.logger.Printf("Warning: failed to record limit for account %s: %v", handle, err)
+		return
+	}
+	if reset {
+		d.logger.Printf("Account %s limit has reset; nudging %s to continue", handle, sessionName)
+		_ = d.tmux.NudgeSession(sessionName, "Your account's usage limit has reset. Continue your work.")
+		return
+	}
+
+	// A session left on a cooling account is re-detected every heartbeat;
+	// only report the limit once, but retry rotation in case another
+	// account has become available since
+	if fresh {
+		d.logger.Printf("ACCOUNT LIMITED: %s hit %q in %s (resets %s)",
+			handle, limit.Reason, sessionName, limit.ResetsAt.Local().Format("2006-01-02 15:04"))
+		_ = events.LogFeed(events.TypeAccountLimited, "daemon",
+			events.AccountLimitedPayload(handle, sessionName, limit.Reason, limit.ResetsAt, next))
+		if next == "" {
+			d.logger.Printf("No account available for %s/%s; it waits for %s to reset", rigName, polecatName, handle)
+		}
+	}
+	if next == "" {
+		return
+	}
+
+	// Only rotate polecats that have work to resume
+	prefix := beads.GetPrefixForRig(d.config.TownRoot, rigName)
+	info, err := d.getAgentBeadInfo(beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName))
+	if err != nil || info.HookBead == "" {
+		return
+	}
+
+	if _, err := checkpoint.Refresh(workDir, info.HookBead); err != nil {
+		d.logger.Printf("Warning: failed to checkpoint %s/%s before rotation: %v", rigName, polecatName, err)
+	}
+	if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+		d.logger.Printf("Error stopping limited session %s: %v", sessionName, err)
+		return
+	}
+	delete(d.limitSightings, sessionName)
+
+	nextDir := quota.ExpandHome(cfg.Accounts[next].ConfigDir)
+	if err := d.restartPolecatSessionOn(rigName, polecatName, sessionName, nextDir); err != nil {
+		d.logger.Printf("Error restarting %s/%s on account %s: %v", rigName, polecatName, next, err)
+		d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, err)
+		return
+	}
+	d.logger.Printf("Moved %s/%s from account %s to %s", rigName, polecatName, handle, next)
+}
+
+// detectLimit looks for a limit banner in a session's pane, then in the
+// latest transcript the session wrote under its account's config dir. The
+// captured pane is returned so the caller can tell a stalled session from
+// one that is still working.
+func (d *Daemon) detectLimit(sessionName string, cfg *config.AccountsConfig, handle, workDir string, now time.Time) (*quota.Limit, string) {
+	pane, err := d.tmux.CapturePane(sessionName, quotaPaneLines)
+	if err != nil {
+		return nil, ""
+	}
+	if limit := quota.Detect(pane, now); limit != nil {
+		return limit, pane
+	}
+
+	acct := cfg.GetAccount(handle)
+	if acct == nil {
+		return nil, pane
+	}
+	transcript := quota.LatestTranscript(quota.ExpandHome(acct.ConfigDir), workDir)
+	if transcript == "" {
+		return nil, pane
+	}
+	return quota.DetectTranscript(transcript, now), pane
+}
+
+// availableAccountConfigDir returns the config dir of the account a new
+// session should use: the default account, or the next available one if
+// the default is cooling down. Returns "" when accounts aren't configured
+// or none is available, leaving the runtime's own default in place.
+func (d *Daemon) availableAccountConfigDir() string {
+	cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(d.config.TownRoot))
+	if err != nil || len(cfg.Accounts) == 0 {
+		return ""
+	}
+	state, err := quota.Load(d.config.TownRoot)
+	if err != nil {
+		return ""
+	}
+	handle := quota.NextAvailable(cfg, state, "", time.Now())
+	if handle == "" {
+		return ""
+	}
+	return quota.ExpandHome(cfg.Accounts[handle].ConfigDir)
+}
@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"os"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func TestCheckAccountQuotasPrunesSightings(t *testing.T) {
+	d, cleanup := testDaemonWithTown(t, "test-town")
+	defer cleanup()
+	d.limitSightings = map[string]string{"gt-gastown-nux": "usage limit reached"}
+
+	// The polecat's rig is gone, so its sighting is forgotten
+	accountsPath := constants.MayorAccountsPath(d.config.TownRoot)
+	if err := config.SaveAccountsConfig(accountsPath, &config.AccountsConfig{
+		Version:  config.CurrentAccountsVersion,
+		Accounts: map[string]config.Account{"work": {Email: "work@example.com", ConfigDir: "/tmp/work"}},
+		Default:  "work",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	d.checkAccountQuotas()
+	if len(d.limitSightings) != 0 {
+		t.Errorf("limitSightings = %v, want pruned", d.limitSightings)
+	}
+
+	// Without accounts there is nothing to track
+	d.limitSightings["gt-gastown-nux"] = "usage limit reached"
+	if err := os.Remove(accountsPath); err != nil {
+		t.Fatal(err)
+	}
+	d.checkAccountQuotas()
+	if len(d.limitSightings) != 0 {
+		t.Errorf("limitSightings = %v, want cleared", d.limitSightings)
+	}
+}
@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// checkpointCaptureInterval is the minimum time between automatic checkpoint
+// captures. Capturing runs git status in every working polecat, so it is
+// throttled independently of the heartbeat interval.
+const checkpointCaptureInterval = 10 * time.Minute
+
+// captureCheckpoints refreshes the checkpoint of every polecat that has a
+// live session and hooked work. Sessions only write checkpoints at molecule
+// step boundaries; these periodic captures keep the git state current so a
+// crash mid-step still resumes close to where it stopped.
+func (d *Daemon) captureCheckpoints() {
+	if time.Since(d.lastCheckpointCapture) < checkpointCaptureInterval {
+		return
+	}
+	d.lastCheckpointCapture = time.Now()
+
+	for _, rigName := range d.getKnownRigs() {
+		if operational, _ := d.isRigOperational(rigName); !operational {
+			continue
+		}
+		polecats, err := listPolecatWorktrees(filepath.Join(d.config.TownRoot, rigName, "polecats"))
+		if err != nil {
+			continue
+		}
+		for _, polecatName := range polecats {
+			d.capturePolecatCheckpoint(rigName, polecatName)
+		}
+	}
+}
+
+// capturePolecatCheckpoint refreshes one polecat's checkpoint if it is working.
+func (d *Daemon) capturePolecatCheckpoint(rigName, polecatName string) {
+	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+	if alive, err := d.tmux.HasSession(sessionName); err != nil || !alive {
+		return
+	}
+
+	prefix := beads.GetPrefixForRig(d.config.TownRoot, rigName)
+	info, err := d.getAgentBeadInfo(beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName))
+	if err != nil || info.HookBead == "" {
+		return
+	}
+
+	workDir := polecatWorkDir(filepath.Join(d.config.TownRoot, rigName), rigName, polecatName)
+	if _, err := checkpoint.Refresh(workDir, info.HookBead); err != nil {
+		d.logger.Printf("Warning: failed to checkpoint %s/%s: %v", rigName, polecatName, err)
+	}
+}
+
+// polecatRecoveryPrompt returns the startup prompt for a polecat restarted
+// after a crash, built from its latest checkpoint. Returns "" if there is no
+// usable checkpoint, in which case the polecat starts normally and finds its
+// work through gt prime. A checkpoint of a different hook than the polecat's
+// current one is from a previous assignment and is removed.
+func (d *Daemon) polecatRecoveryPrompt(rigName, polecatName, workDir string) string {
+	cp, err := checkpoint.Read(workDir)
+	if err != nil || cp == nil {
+		return ""
+	}
+
+	prefix := beads.GetPrefixForRig(d.config.TownRoot, rigName)
+	info, err := d.getAgentBeadInfo(beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName))
+	if err != nil {
+		return ""
+	}
+	if info.HookBead == "" || info.HookBead != cp.HookedBead {
+		d.logger.Printf("Ignoring checkpoint for %s/%s: it is for %s, hook is %q",
+			rigName, polecatName, cp.HookedBead, info.HookBead)
+		_ = checkpoint.Remove(workDir)
+		return ""
+	}
+	if cp.IsStale(checkpoint.MaxRecoveryAge) {
+		d.logger.Printf("Ignoring stale checkpoint for %s/%s (age %s)",
+			rigName, polecatName, cp.Age().Round(time.Minute))
+		return ""
+	}
+
+	d.logger.Printf("Resuming %s/%s from checkpoint (hook=%s, step=%s, age %s)",
+		rigName, polecatName, cp.HookedBead, cp.CurrentStep, cp.Age().Round(time.Minute))
+	return session.BuildStartupPrompt(session.BeaconConfig{
+		Recipient: rigName + "/polecats/" + polecatName,
+		Sender:    "daemon",
+		Topic:     "recovery",
+		MolID:     cp.HookedBead,
+	}, checkpoint.RecoveryPrompt(cp))
+}
+
+// polecatWorkDir returns a polecat's worktree, handling both the new
+// polecats/<name>/<rig>/ layout and the old polecats/<name>/ one.
+func polecatWorkDir(rigPath, rigName, polecatName string) string {
+	workDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
+	if _, err := os.Stat(workDir); os.IsNotExist(err) {
+		return filepath.Join(rigPath, "polecats", polecatName)
+	}
+	return workDir
+}
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+)
+
+func TestPolecatRecoveryPromptChecksHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script bd stub")
+	}
+	d, _ := testDaemonWithTown(t, "test")
+
+	// bd show reports the polecat's current hook from $STUB_HOOK
+	binDir := t.TempDir()
+	stub := "#!/bin/sh\necho '[{\"id\":\"agent\",\"issue_type\":\"agent\",\"hook_bead\":\"'\"$STUB_HOOK\"'\"}]'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workDir := t.TempDir()
+	if err := checkpoint.Write(workDir, &checkpoint.Checkpoint{HookedBead: "gt-old", CurrentStep: "step-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("STUB_HOOK", "gt-old")
+	if prompt := d.polecatRecoveryPrompt("gastown", "nux", workDir); prompt == "" {
+		t.Fatal("recovery prompt empty for a checkpoint of the current hook")
+	}
+
+	// The polecat was reused for other work since the checkpoint
+	t.Setenv("STUB_HOOK", "gt-new")
+	if prompt := d.polecatRecoveryPrompt("gastown", "nux", workDir); prompt != "" {
+		t.Errorf("recovery prompt = %q, want none for a previous assignment", prompt)
+	}
+	if cp, _ := checkpoint.Read(workDir); cp != nil {
+		t.Error("checkpoint of a previous assignment was not removed")
+	}
+}
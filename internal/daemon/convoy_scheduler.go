@@ -0,0 +1,182 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/convoy"
+)
+
+// Convoy scheduler timing
+const (
+	// convoySchedulerInterval is how often the scheduler checks templates.
+	// Cron schedules have minute resolution.
+	convoySchedulerInterval = time.Minute
+
+	// convoyCreateTimeout bounds a single template instantiation, which
+	// files one issue per blueprint rig.
+	convoyCreateTimeout = 5 * time.Minute
+)
+
+// ConvoyScheduler creates convoys from scheduled convoy templates. Each
+// minute it loads the town's templates and, for every template whose cron
+// schedule has come due since its last run, runs gt convoy create
+// --template. Runs are recorded in daemon/convoy-schedules.json, so a
+// restart neither repeats a run nor forgets one that came due while the
+// daemon was down (it runs once on the next check).
+type ConvoyScheduler struct {
+	townRoot string
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	logger   func(format string, args ...interface{})
+
+	// create instantiates a template. Swappable for tests.
+	create func(ctx context.Context, template string) error
+
+	// lastLoadErr suppresses repeating the same template load error every
+	// minute. Only accessed from the run goroutine.
+	lastLoadErr string
+}
+
+// NewConvoyScheduler creates a new convoy scheduler.
+func NewConvoyScheduler(townRoot string, logger func(format string, args ...interface{})) *ConvoyScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &ConvoyScheduler{
+		townRoot: townRoot,
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger,
+	}
+	s.create = s.createConvoy
+	return s
+}
+
+// Start begins the scheduler goroutine.
+func (s *ConvoyScheduler) Start() error {
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop gracefully stops the scheduler.
+func (s *ConvoyScheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run is the scheduler loop.
+func (s *ConvoyScheduler) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(convoySchedulerInterval)
+	defer ticker.Stop()
+
+	s.check(time.Now())
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.check(now)
+		}
+	}
+}
+
+// check creates a convoy for every scheduled template that is due at now.
+func (s *ConvoyScheduler) check(now time.Time) {
+	templates, loadErr := convoy.LoadTemplates(s.townRoot)
+	if loadErr != nil {
+		// Broken templates are skipped; the rest still run
+		if msg := loadErr.Error(); msg != s.lastLoadErr {
+			s.logger("convoy scheduler: %v", loadErr)
+			s.lastLoadErr = msg
+		}
+	} else {
+		s.lastLoadErr = ""
+	}
+
+	state, err := convoy.LoadScheduleState(s.townRoot)
+	if err != nil {
+		s.logger("convoy scheduler: %v", err)
+		return
+	}
+
+	changed := false
+	scheduled := make(map[string]bool)
+	for _, t := range templates {
+		if t.Schedule == "" {
+			continue
+		}
+		scheduled[t.Name] = true
+
+		run := state[t.Name]
+		if run == nil {
+			// Newly scheduled: the first run is the next slot from now
+			state[t.Name] = &convoy.ScheduleRun{Since: now}
+			changed = true
+			continue
+		}
+		next := t.NextRun(run)
+		if next.IsZero() || now.Before(next) {
+			continue
+		}
+
+		s.logger("convoy scheduler: creating convoy from template %s (due %s)", t.Name, next.Format(time.RFC3339))
+		ctx, cancel := context.WithTimeout(s.ctx, convoyCreateTimeout)
+		err := s.create(ctx, t.Name)
+		cancel()
+		if s.ctx.Err() != nil {
+			// Shutting down mid-run; try again next start
+			return
+		}
+
+		// Failed runs aren't retried until the next slot, so a broken
+		// template can't file a partial convoy every minute
+		run.LastRun = now
+		run.LastError = ""
+		if err != nil {
+			run.LastError = err.Error()
+			s.logger("convoy scheduler: template %s failed: %v", t.Name, err)
+		}
+		changed = true
+	}
+
+	// Forget templates that were deleted or unscheduled, so rescheduling
+	// one later starts fresh instead of firing for the gap. A template that
+	// failed to load (mid-edit, say) keeps its record.
+	for name := range state {
+		if !scheduled[name] && loadErr == nil {
+			delete(state, name)
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := convoy.SaveScheduleState(s.townRoot, state); err != nil {
+			s.logger("convoy scheduler: saving state: %v", err)
+		}
+	}
+}
+
+// createConvoy runs gt convoy create --template for a template.
+func (s *ConvoyScheduler) createConvoy(ctx context.Context, template string) error {
+	cmd := exec.CommandContext(ctx, "gt", "convoy", "create", "--template="+template)
+	cmd.Dir = s.townRoot
+	cmd.Env = append(os.Environ(), "GT_TOWN_ROOT="+s.townRoot) // Inherit PATH to find gt executable
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
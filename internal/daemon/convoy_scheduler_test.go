@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/convoy"
+)
+
+func writeConvoyTemplate(t *testing.T, townRoot, name, schedule string) {
+	t.Helper()
+	dir := convoy.TemplatesDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "title = \"" + name + " {{date}}\"\n"
+	if schedule != "" {
+		content += "schedule = \"" + schedule + "\"\n"
+	}
+	content += "[[issues]]\nrig = \"gastown\"\ntitle = \"work\"\n"
+	if err := os.WriteFile(filepath.Join(dir, name+".toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConvoyScheduler_Check(t *testing.T) {
+	townRoot := t.TempDir()
+	writeConvoyTemplate(t, townRoot, "weekly", "0 9 * * mon")
+	writeConvoyTemplate(t, townRoot, "manual", "")
+
+	s := NewConvoyScheduler(townRoot, t.Logf)
+	var created []string
+	s.create = func(_ context.Context, template string) error {
+		created = append(created, template)
+		return nil
+	}
+
+	// Wednesday: first sight records a baseline without running
+	wed := time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC)
+	s.check(wed)
+	if len(created) != 0 {
+		t.Fatalf("created on first sight: %v", created)
+	}
+	state, err := convoy.LoadScheduleState(townRoot)
+	if err != nil || state["weekly"] == nil || state["manual"] != nil {
+		t.Fatalf("state after first check = %v, %v", state, err)
+	}
+
+	// Not due until Monday 09:00
+	s.check(time.Date(2026, 1, 19, 8, 59, 0, 0, time.UTC))
+	if len(created) != 0 {
+		t.Fatalf("created early: %v", created)
+	}
+
+	mon := time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC)
+	s.check(mon)
+	s.check(mon.Add(time.Minute))
+	if len(created) != 1 || created[0] != "weekly" {
+		t.Fatalf("created = %v, want one weekly run", created)
+	}
+
+	// A restart after weeks down runs once, not once per missed week
+	s = NewConvoyScheduler(townRoot, t.Logf)
+	s.create = func(_ context.Context, template string) error {
+		created = append(created, template)
+		return errors.New("bd unavailable")
+	}
+	later := mon.Add(3 * 7 * 24 * time.Hour).Add(time.Hour)
+	s.check(later)
+	s.check(later.Add(time.Minute))
+	if len(created) != 2 {
+		t.Fatalf("created = %v, want one catch-up run", created)
+	}
+	state, _ = convoy.LoadScheduleState(townRoot)
+	if run := state["weekly"]; !run.LastRun.Equal(later) || run.LastError == "" {
+		t.Errorf("failed run recorded as %+v", run)
+	}
+
+	// Unscheduling forgets the template
+	writeConvoyTemplate(t, townRoot, "weekly", "")
+	s.check(later.Add(2 * time.Minute))
+	state, _ = convoy.LoadScheduleState(townRoot)
+	if state["weekly"] != nil {
+		t.Errorf("unscheduled template kept state: %+v", state["weekly"])
+	}
+}
+
+func TestConvoyScheduler_BrokenTemplateKeepsState(t *testing.T) {
+	townRoot := t.TempDir()
+	writeConvoyTemplate(t, townRoot, "daily", "@daily")
+
+	s := NewConvoyScheduler(townRoot, t.Logf)
+	s.create = func(context.Context, string) error { return nil }
+	now := time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC)
+	s.check(now)
+
+	// A half-edited template fails to load; its schedule survives the edit
+	path := filepath.Join(convoy.TemplatesDir(townRoot), "daily.toml")
+	if err := os.WriteFile(path, []byte("title = "), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s.check(now.Add(time.Minute))
+	state, _ := convoy.LoadScheduleState(townRoot)
+	if run := state["daily"]; run == nil || !run.Since.Equal(now) {
+		t.Errorf("state = %+v, want the original record kept", state["daily"])
+	}
+}
@@ -2,7 +2,6 @@ package daemon
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -38,16 +37,32 @@ import (
 // This is recovery-focused: normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is the safety net for dead sessions, GUPP violations, and orphaned work.
 type Daemon struct {
-	config       *Config
-	patrolConfig *DaemonPatrolConfig
-	tmux         *tmux.Tmux
-	logger       *log.Logger
-	ctx          context.Context
-	cancel       context.CancelFunc
-	curator       *feed.Curator
-	convoyWatcher *ConvoyWatcher
-	doltServer    *DoltServerManager
-	krcPruner     *KRCPruner
+	config         *Config
+	patrolConfig   *DaemonPatrolConfig
+	tmux           *tmux.Tmux
+	logger         *log.Logger
+	ctx            context.Context
+	cancel         context.CancelFunc
+	curator        *feed.Curator
+	convoyWatcher  *ConvoyWatcher
+	sessionWatcher *SessionWatcher
+	doltServer     *DoltServerManager
+	krcPruner      *KRCPruner
+	statusline     *StatuslineRefresher
+	convoySched    *ConvoyScheduler
+
+	// daemonConfig is the daemon section of mayor/config.json (nil if unset).
+	daemonConfig *config.DaemonConfig
+
+	// sessionEventsLive is set when both the tmux session-closed hook and
+	// the session watcher are running, so session deaths arrive as events.
+	// Set once at startup, before the heartbeat loop starts.
+	sessionEventsLive bool
+
+	// Event-driven recovery debounce: last time each target was recovered
+	// in response to a watcher event.
+	// Note: Only accessed from main loop goroutine - no sync needed.
+	lastEventRecovery map[string]time.Time
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -58,6 +73,24 @@ type Daemon struct {
 	// See: https://github.com/steveyegge/gastown/issues/567
 	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
 	deaconLastStarted time.Time
+
+	// Warm pool replenishment runs in background goroutines; this tracks
+	// which rigs have one in flight.
+	warmPoolMu   sync.Mutex
+	warmPoolBusy map[string]bool
+
+	// Last automatic checkpoint capture of working polecats.
+	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
+	lastCheckpointCapture time.Time
+
+	// Last heartbeat that polled every agent session for liveness.
+	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
+	lastLivenessPoll time.Time
+
+	// Pane content of polecat sessions that showed a usage limit at the
+	// last quota check, keyed by session name.
+	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
+	limitSightings map[string]string
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -104,14 +137,21 @@ func New(config *Config) (*Daemon, error) {
 		}
 	}
 
+	// Load daemon settings from mayor/config.json (optional - nil if missing)
+	daemonConfig := loadDaemonConfig(config.TownRoot)
+
 	return &Daemon{
-		config:       config,
-		patrolConfig: patrolConfig,
-		tmux:         tmux.NewTmux(),
-		logger:       logger,
-		ctx:          ctx,
-		cancel:       cancel,
-		doltServer:   doltServer,
+		config:            config,
+		patrolConfig:      patrolConfig,
+		tmux:              tmux.NewTmux(),
+		logger:            logger,
+		ctx:               ctx,
+		cancel:            cancel,
+		doltServer:        doltServer,
+		daemonConfig:      daemonConfig,
+		lastEventRecovery: make(map[string]time.Time),
+		warmPoolBusy:      make(map[string]bool),
+		limitSightings:    make(map[string]string),
 	}, nil
 }
 
@@ -156,12 +196,14 @@ func (d *Daemon) Run() error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, daemonSignals()...)
 
-	// Fixed recovery-focused heartbeat (no activity-based backoff)
-	// Normal wake is handled by feed subscription (bd activity --follow)
-	timer := time.NewTimer(recoveryHeartbeatInterval)
+	// Recovery-focused heartbeat (no activity-based backoff)
+	// Normal wake is handled by feed subscription (bd activity --follow);
+	// session death is handled by the session watcher when enabled.
+	heartbeatInterval := recoveryHeartbeatInterval(d.daemonConfig)
+	timer := time.NewTimer(heartbeatInterval)
 	defer timer.Stop()
 
-	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
+	d.logger.Printf("Daemon running, recovery heartbeat interval %v", heartbeatInterval)
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
@@ -179,6 +221,36 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Convoy watcher started")
 	}
 
+	// Start convoy scheduler for recurring convoy templates
+	d.convoySched = NewConvoyScheduler(d.config.TownRoot, d.logger.Printf)
+	if err := d.convoySched.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start convoy scheduler: %v", err)
+	} else {
+		d.logger.Println("Convoy scheduler started")
+	}
+
+	// Start session watcher for event-driven crash recovery
+	var sessionEvents <-chan SessionEvent
+	if d.daemonConfig.IsEventWatchEnabled() {
+		hookErr := d.tmux.SetSessionClosedHook(TmuxEventsFile(d.config.TownRoot))
+		if hookErr != nil {
+			d.logger.Printf("Warning: failed to set tmux session-closed hook: %v", hookErr)
+		}
+		d.sessionWatcher = NewSessionWatcher(d.config.TownRoot, d.logger.Printf)
+		if err := d.sessionWatcher.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start session watcher: %v", err)
+			d.sessionWatcher = nil
+		} else {
+			sessionEvents = d.sessionWatcher.Events()
+			d.logger.Println("Session watcher started")
+		}
+		// Without either, deaths of some sessions arrive only by polling
+		d.sessionEventsLive = hookErr == nil && d.sessionWatcher != nil
+		if !d.sessionEventsLive {
+			d.logger.Println("Session events unavailable; polling session liveness every heartbeat")
+		}
+	}
+
 	// Start KRC pruner for automatic ephemeral data cleanup
 	krcPruner, err := NewKRCPruner(d.config.TownRoot, d.logger.Printf)
 	if err != nil {
@@ -192,6 +264,17 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start statusline refresher so gt status-line can serve cached lines
+	if interval, ok := statuslineRefreshInterval(d.daemonConfig); ok {
+		d.statusline = NewStatuslineRefresher(d.config.TownRoot, interval, d.logger.Printf)
+		if err := d.statusline.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start statusline refresher: %v", err)
+			d.statusline = nil
+		} else {
+			d.logger.Printf("Statusline refresher started (interval %v)", interval)
+		}
+	}
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -211,20 +294,145 @@ func (d *Daemon) Run() error {
 				return d.shutdown(state)
 			}
 
+		case ev := <-sessionEvents:
+			d.handleSessionEvent(ev)
+
 		case <-timer.C:
 			d.heartbeat(state)
 
 			// Fixed recovery interval (no activity-based backoff)
-			timer.Reset(recoveryHeartbeatInterval)
+			timer.Reset(heartbeatInterval)
 		}
 	}
 }
 
-// recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
-// Normal wake is handled by feed subscription (bd activity --follow).
-// The daemon is a safety net for dead sessions, GUPP violations, and orphaned work.
+// defaultRecoveryHeartbeatInterval is the default heartbeat interval. The
+// heartbeat is the only driver of lifecycle requests, pending spawns, GUPP
+// and orphan checks, warm pools, checkpoints and quota rotation, and (when
+// the session watcher is disabled) the only detector of dead sessions.
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
-const recoveryHeartbeatInterval = 3 * time.Minute
+const defaultRecoveryHeartbeatInterval = 3 * time.Minute
+
+// defaultLivenessInterval is how often the heartbeat polls every agent
+// session for liveness while session events are live. Session death is
+// handled by events within seconds, so polling only needs to catch what
+// events miss; the rest of the heartbeat keeps its own cadence.
+const defaultLivenessInterval = 10 * time.Minute
+
+// eventRecoveryDebounce is the minimum time between event-triggered recovery
+// attempts for the same target. A dying session typically produces both a
+// session-closed hook and a lock removal.
+const eventRecoveryDebounce = 10 * time.Second
+
+// loadDaemonConfig loads the daemon section of mayor/config.json.
+// Returns nil if the file doesn't exist, can't be parsed, or has no daemon section.
+func loadDaemonConfig(townRoot string) *config.DaemonConfig {
+	mayorConfig, err := config.LoadMayorConfig(constants.MayorConfigPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return mayorConfig.Daemon
+}
+
+// recoveryHeartbeatInterval returns the heartbeat interval from mayor/config.json,
+// falling back to defaultRecoveryHeartbeatInterval.
+func recoveryHeartbeatInterval(cfg *config.DaemonConfig) time.Duration {
+	if cfg != nil && cfg.HeartbeatInterval != "" {
+		if interval, err := time.ParseDuration(cfg.HeartbeatInterval); err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return defaultRecoveryHeartbeatInterval
+}
+
+// livenessPollInterval returns the minimum time between heartbeats that poll
+// every agent session for liveness: the configured liveness interval while
+// session events are live, and 0 (every heartbeat) otherwise.
+func livenessPollInterval(cfg *config.DaemonConfig, eventsLive bool) time.Duration {
+	if !eventsLive {
+		return 0
+	}
+	if cfg != nil && cfg.LivenessInterval != "" {
+		if interval, err := time.ParseDuration(cfg.LivenessInterval); err == nil && interval >= 0 {
+			return interval
+		}
+	}
+	return defaultLivenessInterval
+}
+
+// livenessPollDue reports whether this heartbeat should poll agent sessions
+// for liveness, recording the poll if so.
+func (d *Daemon) livenessPollDue() bool {
+	now := time.Now()
+	if !d.lastLivenessPoll.IsZero() && now.Sub(d.lastLivenessPoll) < livenessPollInterval(d.daemonConfig, d.sessionEventsLive) {
+		return false
+	}
+	d.lastLivenessPoll = now
+	return true
+}
+
+// handleSessionEvent runs targeted recovery for a single watcher event.
+// Unlike heartbeat, this touches only the affected agent rather than
+// re-scanning every rig.
+func (d *Daemon) handleSessionEvent(ev SessionEvent) {
+	if d.isShutdownInProgress() {
+		return
+	}
+
+	switch ev.Kind {
+	case SessionEventClosed:
+		identity, err := session.ParseSessionName(ev.Session)
+		if err != nil {
+			return // Not a Gas Town session
+		}
+		switch identity.Role {
+		case session.RoleDeacon:
+			if IsPatrolEnabled(d.patrolConfig, "deacon") && d.debounceEventRecovery(ev.Session) {
+				d.logger.Printf("Session %s closed, ensuring Deacon is running", ev.Session)
+				d.ensureDeaconRunning()
+			}
+		case session.RoleWitness:
+			if IsPatrolEnabled(d.patrolConfig, "witness") && d.debounceEventRecovery(ev.Session) {
+				d.logger.Printf("Session %s closed, ensuring witness is running", ev.Session)
+				d.ensureWitnessRunning(identity.Rig)
+			}
+		case session.RoleRefinery:
+			if IsPatrolEnabled(d.patrolConfig, "refinery") && d.debounceEventRecovery(ev.Session) {
+				d.logger.Printf("Session %s closed, ensuring refinery is running", ev.Session)
+				d.ensureRefineryRunning(identity.Rig)
+			}
+		case session.RolePolecat:
+			d.handlePolecatEvent(identity.Rig, identity.Name)
+		}
+
+	case SessionEventLockReleased:
+		d.handlePolecatEvent(ev.Rig, ev.Polecat)
+
+	case SessionEventHeartbeat:
+		// Deacon is alive and writing heartbeats - nothing to recover.
+	}
+}
+
+// handlePolecatEvent checks a single polecat after its session or lock went away.
+func (d *Daemon) handlePolecatEvent(rigName, polecatName string) {
+	sessionName := session.PolecatSessionName(rigName, polecatName)
+	if !d.debounceEventRecovery(sessionName) {
+		return
+	}
+	d.logger.Printf("Session event for polecat %s/%s, checking health", rigName, polecatName)
+	d.checkPolecatHealth(rigName, polecatName)
+}
+
+// debounceEventRecovery reports whether recovery for key may run now,
+// recording the attempt if so.
+func (d *Daemon) debounceEventRecovery(key string) bool {
+	now := time.Now()
+	if last, ok := d.lastEventRecovery[key]; ok && now.Sub(last) < eventRecoveryDebounce {
+		return false
+	}
+	d.lastEventRecovery[key] = now
+	return true
+}
 
 // heartbeat performs one heartbeat cycle.
 // The daemon is recovery-focused: it ensures agents are running and detects failures.
@@ -242,7 +450,15 @@ func (d *Daemon) heartbeat(state *State) {
 		return
 	}
 
-	d.logger.Println("Heartbeat starting (recovery-focused)")
+	// Per-session liveness polling (steps 1, 4, 5 and 11). While session
+	// events are live, deaths arrive as events, so these only run every
+	// liveness interval; everything else runs every heartbeat.
+	pollLiveness := d.livenessPollDue()
+	if pollLiveness {
+		d.logger.Println("Heartbeat starting (recovery-focused, polling session liveness)")
+	} else {
+		d.logger.Println("Heartbeat starting (recovery-focused)")
+	}
 
 	// 0. Ensure Dolt server is running (if configured)
 	// This must happen before beads operations that depend on Dolt.
@@ -250,10 +466,10 @@ func (d *Daemon) heartbeat(state *State) {
 
 	// 1. Ensure Deacon is running (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
-		d.ensureDeaconRunning()
-	} else {
+	if !IsPatrolEnabled(d.patrolConfig, "deacon") {
 		d.logger.Printf("Deacon patrol disabled in config, skipping")
+	} else if pollLiveness {
+		d.ensureDeaconRunning()
 	}
 
 	// 2. Poke Boot for intelligent triage (stuck/nudge/interrupt)
@@ -272,18 +488,18 @@ func (d *Daemon) heartbeat(state *State) {
 
 	// 4. Ensure Witnesses are running for all rigs (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "witness") {
-		d.ensureWitnessesRunning()
-	} else {
+	if !IsPatrolEnabled(d.patrolConfig, "witness") {
 		d.logger.Printf("Witness patrol disabled in config, skipping")
+	} else if pollLiveness {
+		d.ensureWitnessesRunning()
 	}
 
 	// 5. Ensure Refineries are running for all rigs (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "refinery") {
-		d.ensureRefineriesRunning()
-	} else {
+	if !IsPatrolEnabled(d.patrolConfig, "refinery") {
 		d.logger.Printf("Refinery patrol disabled in config, skipping")
+	} else if pollLiveness {
+		d.ensureRefineriesRunning()
 	}
 
 	// 6. Trigger pending polecat spawns (bootstrap mode - ZFC violation acceptable)
@@ -304,13 +520,24 @@ func (d *Daemon) heartbeat(state *State) {
 
 	// 11. Check polecat session health (proactive crash detection)
 	// This validates tmux sessions are still alive for polecats with work-on-hook
-	d.checkPolecatSessionHealth()
+	if pollLiveness {
+		d.checkPolecatSessionHealth()
+	}
 
 	// 12. Clean up orphaned claude subagent processes (memory leak prevention)
 	// These are Task tool subagents that didn't clean up after completion.
 	// This is a safety net - Deacon patrol also does this more frequently.
 	d.cleanupOrphanedProcesses()
 
+	// 13. Replenish warm polecat pools (background - worktree setup is slow)
+	d.replenishWarmPools()
+
+	// 14. Checkpoint working polecats so a crash can resume where it left off
+	d.captureCheckpoints()
+
+	// 15. Move polecats stalled on an account usage limit to another account
+	d.checkAccountQuotas()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -579,24 +806,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 
 // getKnownRigs returns list of registered rig names.
 func (d *Daemon) getKnownRigs() []string {
-	rigsPath := filepath.Join(d.config.TownRoot, "mayor", "rigs.json")
-	data, err := os.ReadFile(rigsPath)
-	if err != nil {
-		return nil
-	}
-
-	var parsed struct {
-		Rigs map[string]interface{} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		return nil
-	}
-
-	var rigs []string
-	for name := range parsed.Rigs {
-		rigs = append(rigs, name)
-	}
-	return rigs
+	return knownRigs(d.config.TownRoot)
 }
 
 // isRigOperational checks if a rig is in an operational state.
@@ -726,12 +936,36 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Convoy watcher stopped")
 	}
 
+	// Stop convoy scheduler
+	if d.convoySched != nil {
+		d.convoySched.Stop()
+		d.logger.Println("Convoy scheduler stopped")
+	}
+
+	// Stop session watcher
+	if d.sessionWatcher != nil {
+		d.sessionWatcher.Stop()
+		d.logger.Println("Session watcher stopped")
+	}
+	if d.daemonConfig.IsEventWatchEnabled() {
+		if err := d.tmux.RemoveSessionClosedHook(TmuxEventsFile(d.config.TownRoot)); err != nil {
+			d.logger.Printf("Warning: failed to remove tmux session-closed hook: %v", err)
+		}
+	}
+
 	// Stop KRC pruner
 	if d.krcPruner != nil {
 		d.krcPruner.Stop()
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop statusline refresher; cached lines go stale and gt status-line
+	// falls back to rendering live
+	if d.statusline != nil {
+		d.statusline.Stop()
+		d.logger.Println("Statusline refresher stopped")
+	}
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
@@ -1029,6 +1263,8 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
+	_ = events.LogFeed(events.TypeSessionDeath, sessionName,
+		events.SessionDeathPayload(sessionName, rigName+"/polecats/"+polecatName, "crash detected", "daemon"))
 
 	// Auto-restart the polecat
 	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
@@ -1090,8 +1326,15 @@ func (d *Daemon) emitMassDeathEvent() {
 	d.recentDeaths = nil
 }
 
-// restartPolecatSession restarts a crashed polecat session.
+// restartPolecatSession restarts a crashed polecat session on an account
+// that isn't cooling down from a usage limit.
 func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string) error {
+	return d.restartPolecatSessionOn(rigName, polecatName, sessionName, d.availableAccountConfigDir())
+}
+
+// restartPolecatSessionOn restarts a polecat session using the given account
+// config dir (CLAUDE_CONFIG_DIR). An empty configDir uses the runtime default.
+func (d *Daemon) restartPolecatSessionOn(rigName, polecatName, sessionName, configDir string) error {
 	// Check rig operational state before auto-restarting
 	if operational, reason := d.isRigOperational(rigName); !operational {
 		return fmt.Errorf("cannot restart polecat: %s", reason)
@@ -1100,14 +1343,7 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Calculate rig path for agent config resolution
 	rigPath := filepath.Join(d.config.TownRoot, rigName)
 
-	// Determine working directory (handle both new and old structures)
-	// New structure: polecats/<name>/<rigname>/
-	// Old structure: polecats/<name>/
-	workDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
-	if _, err := os.Stat(workDir); os.IsNotExist(err) {
-		// Fall back to old structure
-		workDir = filepath.Join(rigPath, "polecats", polecatName)
-	}
+	workDir := polecatWorkDir(rigPath, rigName, polecatName)
 
 	// Verify the worktree exists
 	if _, err := os.Stat(workDir); os.IsNotExist(err) {
@@ -1117,21 +1353,33 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Pre-sync workspace (ensure beads are current)
 	d.syncWorkspace(workDir)
 
+	// Set environment variables using centralized AgentEnv
+	envVars := config.AgentEnv(config.AgentEnvConfig{
+		Role:             "polecat",
+		Rig:              rigName,
+		AgentName:        polecatName,
+		TownRoot:         d.config.TownRoot,
+		BeadsNoDaemon:    true,
+		RuntimeConfigDir: configDir,
+		Ports:            polecat.PortsFor(rigPath, polecatName).Env(),
+	})
+
+	// Launch Claude with environment exported inline
+	// Pass rigPath so rig agent settings are honored (not town-level defaults)
+	// If the crashed session left a recent checkpoint, resume from it
+	startCmd := config.BuildStartupCommand(envVars, rigPath, d.polecatRecoveryPrompt(rigName, polecatName, workDir))
+
+	startCmd, err := d.applyPolecatResourcePolicy(startCmd, rigName, polecatName)
+	if err != nil {
+		return err
+	}
+
 	// Create new tmux session
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
 	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
-	// Set environment variables using centralized AgentEnv
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:          "polecat",
-		Rig:           rigName,
-		AgentName:     polecatName,
-		TownRoot:      d.config.TownRoot,
-		BeadsNoDaemon: true,
-	})
-
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
 		_ = d.tmux.SetEnvironment(sessionName, k, v)
@@ -1145,9 +1393,6 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
 	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
 
-	// Launch Claude with environment exported inline
-	// Pass rigPath so rig agent settings are honored (not town-level defaults)
-	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
@@ -1162,6 +1407,17 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	return nil
 }
 
+// applyPolecatResourcePolicy wraps a polecat startup command with the rig's
+// resource limits, as the polecat session manager does for fresh spawns.
+// Restarts must not shed limits: a required policy that can't be applied
+// fails the restart rather than running the polecat unconstrained.
+func (d *Daemon) applyPolecatResourcePolicy(command, rigName, polecatName string) (string, error) {
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	return polecat.ApplyResourcePolicy(command, rigPath, rigName, polecatName, func(format string, args ...interface{}) {
+		d.logger.Printf("Warning: "+format, args...)
+	})
+}
+
 // notifyWitnessOfCrashedPolecat notifies the witness when a polecat restart fails.
 func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, restartErr error) {
 	witnessAddr := rigName + "/witness"
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -369,6 +370,14 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 		d.syncWorkspace(workDir)
 	}
 
+	// Get startup command; polecats keep their rig's resource limits
+	startCmd := d.getStartCommand(config, parsed)
+	if parsed.RoleType == "polecat" {
+		if startCmd, err = d.applyPolecatResourcePolicy(startCmd, parsed.RigName, parsed.AgentName); err != nil {
+			return err
+		}
+	}
+
 	// Create session
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
 	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
@@ -381,8 +390,7 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
 	d.applySessionTheme(sessionName, parsed)
 
-	// Get and send startup command
-	startCmd := d.getStartCommand(config, parsed)
+	// Send startup command
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
@@ -846,6 +854,9 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 				d.logger.Printf("GUPP violation: agent %s has hook_bead=%s but hasn't updated in %v (timeout: %v)",
 					agent.ID, agent.HookBead, age.Round(time.Minute), GUPPViolationTimeout)
 
+				_ = events.LogFeed(events.TypeGUPPViolation, "daemon",
+					events.GUPPViolationPayload(rigName, agent.ID, agent.HookBead, age))
+
 				// Notify the witness for this rig
 				d.notifyWitnessOfGUPP(rigName, agent.ID, agent.HookBead, age)
 			}
@@ -0,0 +1,324 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionEventKind identifies what the session watcher observed.
+type SessionEventKind string
+
+const (
+	// SessionEventClosed is emitted when the tmux session-closed hook fires.
+	SessionEventClosed SessionEventKind = "session-closed"
+
+	// SessionEventLockReleased is emitted when a polecat's agent.lock disappears.
+	SessionEventLockReleased SessionEventKind = "lock-released"
+
+	// SessionEventHeartbeat is emitted when the Deacon heartbeat file is rewritten.
+	SessionEventHeartbeat SessionEventKind = "heartbeat"
+)
+
+// SessionEvent is a single observation from the session watcher.
+type SessionEvent struct {
+	Kind SessionEventKind
+
+	// Session is the tmux session name (SessionEventClosed only).
+	Session string
+
+	// Rig and Polecat identify the worker (SessionEventLockReleased only).
+	Rig     string
+	Polecat string
+}
+
+// sessionWatcherRescanInterval is how often the watcher refreshes its set of
+// watched directories so newly spawned polecats are picked up.
+const sessionWatcherRescanInterval = 30 * time.Second
+
+// tmuxEventsRotateSize is the size at which the session watcher rotates
+// TmuxEventsFile to TmuxEventsFile + ".1" after consuming it.
+const tmuxEventsRotateSize = 64 * 1024
+
+// TmuxEventsFile returns the path of the file the tmux session-closed hook appends to.
+func TmuxEventsFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "tmux-events.log")
+}
+
+// SessionWatcher watches session, lock and heartbeat files and turns changes
+// into SessionEvents so the daemon can react to session death in seconds.
+// On Linux it uses inotify; elsewhere it falls back to polling file mtimes.
+// The periodic heartbeat remains the safety net for anything missed here.
+type SessionWatcher struct {
+	townRoot string
+	events   chan SessionEvent
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	logger   func(format string, args ...interface{})
+
+	// tmuxEventsOffset is how far into TmuxEventsFile we have read.
+	// Only accessed from the watch goroutine.
+	tmuxEventsOffset int64
+}
+
+// NewSessionWatcher creates a new session watcher.
+func NewSessionWatcher(townRoot string, logger func(format string, args ...interface{})) *SessionWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SessionWatcher{
+		townRoot: townRoot,
+		events:   make(chan SessionEvent, 64),
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger,
+	}
+}
+
+// Start begins the watcher goroutine.
+func (w *SessionWatcher) Start() error {
+	eventsFile := TmuxEventsFile(w.townRoot)
+	if err := os.MkdirAll(filepath.Dir(eventsFile), 0755); err != nil {
+		return err
+	}
+
+	// Skip anything written before we started - the initial heartbeat covers it.
+	if info, err := os.Stat(eventsFile); err == nil {
+		w.tmuxEventsOffset = info.Size()
+	}
+
+	backend, err := newWatchBackend()
+	if err != nil {
+		return err
+	}
+
+	w.wg.Add(1)
+	go w.run(backend)
+	return nil
+}
+
+// Stop gracefully stops the watcher.
+func (w *SessionWatcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// Events returns the channel on which session events are delivered.
+func (w *SessionWatcher) Events() <-chan SessionEvent {
+	return w.events
+}
+
+// run is the main watcher loop.
+func (w *SessionWatcher) run(backend watchBackend) {
+	defer w.wg.Done()
+	defer backend.Close()
+
+	backend.Sync(w.watchDirs())
+	lastRescan := time.Now()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		default:
+		}
+
+		paths, err := backend.Wait(time.Second)
+		if err != nil {
+			w.logger("session watcher: %v", err)
+		}
+		for _, path := range paths {
+			w.handlePath(path)
+		}
+
+		if time.Since(lastRescan) >= sessionWatcherRescanInterval {
+			backend.Sync(w.watchDirs())
+			lastRescan = time.Now()
+		}
+	}
+}
+
+// watchDirs returns the directories whose contents signal session state:
+// the daemon dir (tmux hook events), the deacon dir (heartbeat.json),
+// each rig's polecats dir, and each polecat's .runtime dir (agent.lock).
+func (w *SessionWatcher) watchDirs() []string {
+	dirs := []string{
+		filepath.Dir(TmuxEventsFile(w.townRoot)),
+		filepath.Join(w.townRoot, "deacon"),
+	}
+
+	for _, rigName := range knownRigs(w.townRoot) {
+		polecatsDir := filepath.Join(w.townRoot, rigName, "polecats")
+		polecats, err := listPolecatWorktrees(polecatsDir)
+		if err != nil {
+			continue
+		}
+		dirs = append(dirs, polecatsDir)
+		for _, name := range polecats {
+			dirs = append(dirs, filepath.Join(polecatsDir, name, ".runtime"))
+		}
+	}
+
+	return dirs
+}
+
+// handlePath translates a changed path into zero or more session events.
+func (w *SessionWatcher) handlePath(path string) {
+	if path == TmuxEventsFile(w.townRoot) {
+		for _, ev := range w.readTmuxEvents() {
+			w.emit(ev)
+		}
+		return
+	}
+
+	if ev, ok := classifyWatchedPath(w.townRoot, path); ok {
+		w.emit(ev)
+	}
+}
+
+// classifyWatchedPath maps a changed file to a session event.
+// Lock events are only emitted when the lock is gone - a new lock means a
+// session started, which needs no recovery.
+func classifyWatchedPath(townRoot, path string) (SessionEvent, bool) {
+	if path == filepath.Join(townRoot, "deacon", "heartbeat.json") {
+		return SessionEvent{Kind: SessionEventHeartbeat}, true
+	}
+
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil {
+		return SessionEvent{}, false
+	}
+
+	// <rig>/polecats/<name>/.runtime/agent.lock
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 5 || parts[1] != "polecats" || parts[3] != ".runtime" || parts[4] != "agent.lock" {
+		return SessionEvent{}, false
+	}
+	if _, err := os.Stat(path); err == nil {
+		return SessionEvent{}, false
+	}
+
+	return SessionEvent{
+		Kind:    SessionEventLockReleased,
+		Rig:     parts[0],
+		Polecat: parts[2],
+	}, true
+}
+
+// readTmuxEvents reads lines appended to TmuxEventsFile since the last read.
+// Lines have the form "session-closed <session>".
+func (w *SessionWatcher) readTmuxEvents() []SessionEvent {
+	path := TmuxEventsFile(w.townRoot)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	// File was truncated or rotated - start over.
+	info, err := f.Stat()
+	if err == nil && info.Size() < w.tmuxEventsOffset {
+		w.tmuxEventsOffset = 0
+	}
+
+	events, offset := readTmuxEventLines(f, w.tmuxEventsOffset)
+	w.tmuxEventsOffset = offset
+
+	// Rotate once the log is large and fully consumed, so it doesn't grow
+	// forever. Lines the hook appends while we rotate land in the rotated
+	// file and are read from there.
+	if info, err := f.Stat(); err == nil && offset >= tmuxEventsRotateSize && offset == info.Size() {
+		rotated := path + ".1"
+		if err := os.Rename(path, rotated); err != nil {
+			return events
+		}
+		w.tmuxEventsOffset = 0
+		if rf, err := os.Open(rotated); err == nil {
+			late, _ := readTmuxEventLines(rf, offset)
+			events = append(events, late...)
+			_ = rf.Close()
+		}
+	}
+	return events
+}
+
+// readTmuxEventLines parses the complete lines of f after offset and returns
+// them with the offset just past the last complete line.
+func readTmuxEventLines(f *os.File, offset int64) ([]SessionEvent, int64) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset
+	}
+
+	var events []SessionEvent
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// Leave partial lines for the next read
+			break
+		}
+		offset += int64(len(line))
+
+		if ev, ok := parseTmuxEventLine(line); ok {
+			events = append(events, ev)
+		}
+	}
+	return events, offset
+}
+
+// parseTmuxEventLine parses a single line written by the tmux hook.
+func parseTmuxEventLine(line string) (SessionEvent, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != string(SessionEventClosed) {
+		return SessionEvent{}, false
+	}
+	return SessionEvent{Kind: SessionEventClosed, Session: fields[1]}, true
+}
+
+// emit delivers an event without blocking the watcher.
+// If the daemon is busy the event is dropped; the heartbeat catches it later.
+func (w *SessionWatcher) emit(ev SessionEvent) {
+	select {
+	case w.events <- ev:
+	default:
+		w.logger("session watcher: event queue full, dropping %s event", ev.Kind)
+	}
+}
+
+// knownRigs returns registered rig names from mayor/rigs.json.
+func knownRigs(townRoot string) []string {
+	data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil
+	}
+
+	var parsed struct {
+		Rigs map[string]interface{} `json:"rigs"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil
+	}
+
+	rigs := make([]string, 0, len(parsed.Rigs))
+	for name := range parsed.Rigs {
+		rigs = append(rigs, name)
+	}
+	return rigs
+}
+
+// watchBackend is the platform-specific file change source.
+type watchBackend interface {
+	// Sync replaces the set of watched directories.
+	Sync(dirs []string)
+
+	// Wait blocks up to timeout and returns the paths that changed.
+	Wait(timeout time.Duration) ([]string, error)
+
+	// Close releases backend resources.
+	Close()
+}
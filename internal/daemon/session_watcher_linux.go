@@ -0,0 +1,132 @@
+//go:build linux
+
+package daemon
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask covers file creation, removal, completed writes and renames.
+const inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_CLOSE_WRITE |
+	unix.IN_MODIFY | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// inotifyBackend watches directories with Linux inotify.
+type inotifyBackend struct {
+	fd      int
+	watches map[int]string // watch descriptor -> directory
+	dirs    map[string]int // directory -> watch descriptor
+	buf     [64 * 1024]byte
+}
+
+func newWatchBackend() (watchBackend, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	return &inotifyBackend{
+		fd:      fd,
+		watches: make(map[int]string),
+		dirs:    make(map[string]int),
+	}, nil
+}
+
+// Sync adds watches for new directories and drops watches for removed ones.
+// Directories that don't exist yet are skipped; the next Sync retries them.
+func (b *inotifyBackend) Sync(dirs []string) {
+	want := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		want[dir] = true
+		if _, ok := b.dirs[dir]; ok {
+			continue
+		}
+		wd, err := unix.InotifyAddWatch(b.fd, dir, inotifyMask)
+		if err != nil {
+			continue
+		}
+		b.watches[wd] = dir
+		b.dirs[dir] = wd
+	}
+
+	for dir, wd := range b.dirs {
+		if want[dir] {
+			continue
+		}
+		_, _ = unix.InotifyRmWatch(b.fd, uint32(wd)) //nolint:gosec // G115: wd comes from InotifyAddWatch
+		delete(b.dirs, dir)
+		delete(b.watches, wd)
+	}
+}
+
+// Wait polls the inotify descriptor and decodes pending events into paths.
+func (b *inotifyBackend) Wait(timeout time.Duration) ([]string, error) {
+	fds := []unix.PollFd{{Fd: int32(b.fd), Events: unix.POLLIN}} //nolint:gosec // G115: fd fits in int32
+	n, err := unix.Poll(fds, int(timeout.Milliseconds()))
+	if err != nil {
+		if errors.Is(err, unix.EINTR) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	size, err := unix.Read(b.fd, b.buf[:])
+	if err != nil {
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var paths []string
+	for offset := 0; offset+unix.SizeofInotifyEvent <= size; {
+		event := (*unix.InotifyEvent)(unsafe.Pointer(&b.buf[offset])) //nolint:gosec // G103: decoding kernel struct
+		nameStart := offset + unix.SizeofInotifyEvent
+		nameEnd := nameStart + int(event.Len)
+		offset = nameEnd
+
+		dir, ok := b.watches[int(event.Wd)]
+		if !ok {
+			continue
+		}
+
+		if event.Mask&unix.IN_IGNORED != 0 {
+			// Directory was removed; forget it so Sync can re-add it later.
+			delete(b.watches, int(event.Wd))
+			delete(b.dirs, dir)
+			continue
+		}
+
+		name := ""
+		if event.Len > 0 && nameEnd <= size {
+			name = cString(b.buf[nameStart:nameEnd])
+		}
+		if name == "" {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, name))
+	}
+
+	return paths, nil
+}
+
+// Close releases the inotify descriptor.
+func (b *inotifyBackend) Close() {
+	_ = unix.Close(b.fd)
+}
+
+// cString trims the NUL padding inotify appends to names.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
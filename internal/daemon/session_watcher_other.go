@@ -0,0 +1,67 @@
+//go:build !linux
+
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pollBackend detects file changes by comparing directory snapshots.
+// Used on platforms without inotify.
+type pollBackend struct {
+	dirs     []string
+	snapshot map[string]time.Time
+}
+
+func newWatchBackend() (watchBackend, error) {
+	return &pollBackend{snapshot: make(map[string]time.Time)}, nil
+}
+
+// Sync replaces the watched directories and takes a fresh baseline.
+func (b *pollBackend) Sync(dirs []string) {
+	b.dirs = dirs
+	b.snapshot = b.scan()
+}
+
+// Wait sleeps for timeout and returns paths created, modified or removed since the last call.
+func (b *pollBackend) Wait(timeout time.Duration) ([]string, error) {
+	time.Sleep(timeout)
+
+	current := b.scan()
+	var paths []string
+	for path, mtime := range current {
+		if prev, ok := b.snapshot[path]; !ok || !prev.Equal(mtime) {
+			paths = append(paths, path)
+		}
+	}
+	for path := range b.snapshot {
+		if _, ok := current[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	b.snapshot = current
+	return paths, nil
+}
+
+// Close is a no-op for the polling backend.
+func (b *pollBackend) Close() {}
+
+func (b *pollBackend) scan() map[string]time.Time {
+	snapshot := make(map[string]time.Time)
+	for _, dir := range b.dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			snapshot[filepath.Join(dir, entry.Name())] = info.ModTime()
+		}
+	}
+	return snapshot
+}
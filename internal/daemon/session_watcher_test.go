@@ -0,0 +1,208 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseTmuxEventLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   string
+		wantOK bool
+	}{
+		{"session-closed gt-gastown-Toast\n", "gt-gastown-Toast", true},
+		{"session-closed hq-deacon", "hq-deacon", true},
+		{"session-closed", "", false},
+		{"window-closed gt-gastown-Toast", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		ev, ok := parseTmuxEventLine(tt.line)
+		if ok != tt.wantOK {
+			t.Errorf("parseTmuxEventLine(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			continue
+		}
+		if ok && ev.Session != tt.want {
+			t.Errorf("parseTmuxEventLine(%q) session = %q, want %q", tt.line, ev.Session, tt.want)
+		}
+	}
+}
+
+func TestClassifyWatchedPath(t *testing.T) {
+	townRoot := t.TempDir()
+
+	runtimeDir := filepath.Join(townRoot, "gastown", "polecats", "Toast", ".runtime")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(runtimeDir, "agent.lock")
+
+	// Lock present: session started, nothing to recover
+	if err := os.WriteFile(lockPath, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := classifyWatchedPath(townRoot, lockPath); ok {
+		t.Error("expected no event while agent.lock exists")
+	}
+
+	// Lock removed: emit lock-released
+	if err := os.Remove(lockPath); err != nil {
+		t.Fatal(err)
+	}
+	ev, ok := classifyWatchedPath(townRoot, lockPath)
+	if !ok {
+		t.Fatal("expected lock-released event after agent.lock removal")
+	}
+	if ev.Kind != SessionEventLockReleased || ev.Rig != "gastown" || ev.Polecat != "Toast" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	// Deacon heartbeat
+	ev, ok = classifyWatchedPath(townRoot, filepath.Join(townRoot, "deacon", "heartbeat.json"))
+	if !ok || ev.Kind != SessionEventHeartbeat {
+		t.Errorf("expected heartbeat event, got %+v (ok=%v)", ev, ok)
+	}
+
+	// Unrelated files are ignored
+	if _, ok := classifyWatchedPath(townRoot, filepath.Join(runtimeDir, "keepalive.json")); ok {
+		t.Error("expected no event for unrelated file")
+	}
+}
+
+func TestReadTmuxEvents_Incremental(t *testing.T) {
+	townRoot := t.TempDir()
+	eventsFile := TmuxEventsFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(eventsFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewSessionWatcher(townRoot, t.Logf)
+
+	appendLine := func(line string) {
+		f, err := os.OpenFile(eventsFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	appendLine("session-closed gt-gastown-Toast\n")
+	events := w.readTmuxEvents()
+	if len(events) != 1 || events[0].Session != "gt-gastown-Toast" {
+		t.Fatalf("first read: got %+v", events)
+	}
+
+	// Partial line is held back until complete
+	appendLine("session-closed gt-gastown-wit")
+	if events := w.readTmuxEvents(); len(events) != 0 {
+		t.Fatalf("partial line: got %+v", events)
+	}
+	appendLine("ness\n")
+	events = w.readTmuxEvents()
+	if len(events) != 1 || events[0].Session != "gt-gastown-witness" {
+		t.Fatalf("completed line: got %+v", events)
+	}
+}
+
+func TestReadTmuxEventsRotates(t *testing.T) {
+	townRoot := t.TempDir()
+	eventsFile := TmuxEventsFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(eventsFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	line := "session-closed gt-gastown-Toast\n"
+	count := tmuxEventsRotateSize/len(line) + 1
+	if err := os.WriteFile(eventsFile, []byte(strings.Repeat(line, count)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewSessionWatcher(townRoot, t.Logf)
+	if events := w.readTmuxEvents(); len(events) != count {
+		t.Fatalf("read %d events, want %d", len(events), count)
+	}
+	if _, err := os.Stat(eventsFile); !os.IsNotExist(err) {
+		t.Errorf("consumed log not rotated: %v", err)
+	}
+	if _, err := os.Stat(eventsFile + ".1"); err != nil {
+		t.Errorf("rotated log missing: %v", err)
+	}
+
+	// The hook recreates the log on its next append
+	if err := os.WriteFile(eventsFile, []byte("session-closed gt-gastown-witness\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events := w.readTmuxEvents()
+	if len(events) != 1 || events[0].Session != "gt-gastown-witness" {
+		t.Errorf("read after rotation: got %+v", events)
+	}
+}
+
+func TestRecoveryHeartbeatInterval(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name string
+		cfg  *config.DaemonConfig
+		want time.Duration
+	}{
+		{"nil config uses default", nil, defaultRecoveryHeartbeatInterval},
+		{"event watch disabled", &config.DaemonConfig{EventWatch: &disabled}, defaultRecoveryHeartbeatInterval},
+		{"explicit interval", &config.DaemonConfig{HeartbeatInterval: "90s"}, 90 * time.Second},
+		{"invalid interval falls back", &config.DaemonConfig{HeartbeatInterval: "soon"}, defaultRecoveryHeartbeatInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recoveryHeartbeatInterval(tt.cfg); got != tt.want {
+				t.Errorf("recoveryHeartbeatInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLivenessPollDue(t *testing.T) {
+	disabled := false
+
+	d := &Daemon{sessionEventsLive: true}
+	if !d.livenessPollDue() {
+		t.Fatal("first heartbeat didn't poll liveness")
+	}
+	if d.livenessPollDue() {
+		t.Error("event-driven heartbeat polled liveness again right away")
+	}
+	d.lastLivenessPoll = time.Now().Add(-defaultLivenessInterval)
+	if !d.livenessPollDue() {
+		t.Errorf("liveness not polled after %v", defaultLivenessInterval)
+	}
+
+	// The interval is configurable
+	d.daemonConfig = &config.DaemonConfig{LivenessInterval: "30m"}
+	d.lastLivenessPoll = time.Now().Add(-defaultLivenessInterval)
+	if d.livenessPollDue() {
+		t.Error("liveness polled before the configured 30m")
+	}
+
+	// Event watching enabled but not running (watcher or hook failed):
+	// every heartbeat polls
+	d.sessionEventsLive = false
+	if !d.livenessPollDue() || !d.livenessPollDue() {
+		t.Error("heartbeat without live session events skipped liveness polling")
+	}
+
+	// Likewise with event watching disabled
+	d.daemonConfig = &config.DaemonConfig{EventWatch: &disabled}
+	if !d.livenessPollDue() || !d.livenessPollDue() {
+		t.Error("heartbeat without event watching skipped liveness polling")
+	}
+}
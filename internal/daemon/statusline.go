@@ -0,0 +1,333 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/statusline"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Statusline refresher timing
+const (
+	// statuslineEventPollInterval is how often the refresher checks
+	// .events.jsonl for new events.
+	statuslineEventPollInterval = time.Second
+
+	// statuslineEventDebounce is the minimum gap between event-triggered
+	// refreshes, so a burst of events (a convoy of slings) costs one.
+	statuslineEventDebounce = 2 * time.Second
+)
+
+// statuslineEventTypes are the events that change what a status line shows:
+// mail previews, hooked work, the merge queue and which agents are running.
+var statuslineEventTypes = map[string]bool{
+	events.TypeMail:         true,
+	events.TypeSling:        true,
+	events.TypeHook:         true,
+	events.TypeUnhook:       true,
+	events.TypeHandoff:      true,
+	events.TypeDone:         true,
+	events.TypeSpawn:        true,
+	events.TypeKill:         true,
+	events.TypeSessionStart: true,
+	events.TypeSessionEnd:   true,
+	events.TypeSessionDeath: true,
+	events.TypeMRSubmitted:  true,
+	events.TypeMergeStarted: true,
+	events.TypeMerged:       true,
+	events.TypeMergeFailed:  true,
+	events.TypeMergeSkipped: true,
+}
+
+// statuslineRefreshInterval returns the statusline refresh interval from
+// mayor/config.json, and false if the cache is turned off.
+func statuslineRefreshInterval(cfg *config.DaemonConfig) (time.Duration, bool) {
+	if cfg == nil || cfg.StatuslineInterval == "" {
+		return statusline.DefaultRefreshInterval, true
+	}
+	if cfg.StatuslineInterval == "off" {
+		return 0, false
+	}
+	if interval, err := time.ParseDuration(cfg.StatuslineInterval); err == nil && interval > 0 {
+		return interval, true
+	}
+	return statusline.DefaultRefreshInterval, true
+}
+
+// StatuslineRefresher keeps the statusline cache current: it re-renders
+// every Gas Town session's tmux status line on a timer, and soon after an
+// event re-renders just the sessions that event touches. gt status-line then
+// serves the cached line instead of querying tmux and beads on every tmux
+// redraw.
+//
+// Lines are rendered in-process, and the sessions of one pass share a
+// statusline.Renderer, so the town-wide queries (mail, hooked beads, merge
+// queues, the session list) are made once per pass rather than per session.
+type StatuslineRefresher struct {
+	townRoot string
+	interval time.Duration
+	tmux     *tmux.Tmux
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	logger   func(format string, args ...interface{})
+
+	// render renders one session's line into the cache.
+	// Swappable for tests.
+	render func(renderer *statusline.Renderer, session string) error
+
+	// eventsOffset is how far into the events log we have read.
+	// Only accessed from the run goroutine.
+	eventsOffset int64
+}
+
+// NewStatuslineRefresher creates a refresher that re-renders every interval.
+func NewStatuslineRefresher(townRoot string, interval time.Duration, logger func(format string, args ...interface{})) *StatuslineRefresher {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &StatuslineRefresher{
+		townRoot: townRoot,
+		interval: interval,
+		tmux:     tmux.NewTmux(),
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger,
+	}
+	r.render = r.renderSession
+	return r
+}
+
+// Start begins the refresher goroutine.
+func (r *StatuslineRefresher) Start() error {
+	if err := os.MkdirAll(statusline.Dir(r.townRoot), 0755); err != nil {
+		return err
+	}
+
+	// Skip events written before we started - the first refresh covers them
+	if info, err := os.Stat(filepath.Join(r.townRoot, events.EventsFile)); err == nil {
+		r.eventsOffset = info.Size()
+	}
+
+	r.wg.Add(1)
+	go r.run()
+	return nil
+}
+
+// Stop gracefully stops the refresher.
+func (r *StatuslineRefresher) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// run is the refresh loop. Refreshes never overlap, so a slow bd can't
+// pile up renders.
+func (r *StatuslineRefresher) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	poll := time.NewTicker(statuslineEventPollInterval)
+	defer poll.Stop()
+
+	r.refresh(nil)
+	var lastEventRefresh time.Time
+	pending := make(map[string]bool)
+	pendingAll := false
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(nil)
+			pending = make(map[string]bool)
+			pendingAll = false
+		case <-poll.C:
+			touched, all := r.readEvents()
+			for s := range touched {
+				pending[s] = true
+			}
+			pendingAll = pendingAll || all
+			if (pendingAll || len(pending) > 0) && time.Since(lastEventRefresh) >= statuslineEventDebounce {
+				if pendingAll {
+					r.refresh(nil)
+				} else {
+					r.refresh(pending)
+				}
+				lastEventRefresh = time.Now()
+				pending = make(map[string]bool)
+				pendingAll = false
+			}
+		}
+	}
+}
+
+// refresh re-renders the status lines of the running Gas Town sessions in
+// only, or of every one when only is nil. A full refresh also drops cached
+// lines for sessions that are gone.
+func (r *StatuslineRefresher) refresh(only map[string]bool) {
+	sessions, err := r.tmux.ListSessions()
+	if err != nil {
+		// No tmux server means no status lines to serve
+		return
+	}
+
+	renderer := statusline.NewRenderer(r.townRoot)
+	live := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		if _, err := session.ParseSessionName(s); err != nil {
+			continue
+		}
+		live[s] = true
+		if only != nil && !only[s] {
+			continue
+		}
+
+		if err := r.render(renderer, s); err != nil {
+			r.logger("statusline: rendering %s failed: %v", s, err)
+		}
+		if r.ctx.Err() != nil {
+			return
+		}
+	}
+
+	if only != nil {
+		return
+	}
+	if err := statusline.Prune(r.townRoot, live); err != nil {
+		r.logger("statusline: pruning cache failed: %v", err)
+	}
+}
+
+// renderSession renders a session's line and stores it in the cache.
+func (r *StatuslineRefresher) renderSession(renderer *statusline.Renderer, sessionName string) error {
+	var line strings.Builder
+	if err := renderer.Render(&line, sessionName); err != nil {
+		return err
+	}
+	return statusline.Write(r.townRoot, sessionName, line.String(), 3*r.interval)
+}
+
+// readEvents reads events appended to the events log since the last read
+// and returns the sessions whose status lines they change. all is true when
+// an event's sessions can't be narrowed down.
+func (r *StatuslineRefresher) readEvents() (touched map[string]bool, all bool) {
+	touched = make(map[string]bool)
+	f, err := os.Open(filepath.Join(r.townRoot, events.EventsFile))
+	if err != nil {
+		return touched, false
+	}
+	defer f.Close()
+
+	// Log was truncated or rotated - start over
+	if info, err := f.Stat(); err == nil && info.Size() < r.eventsOffset {
+		r.eventsOffset = 0
+	}
+	if _, err := f.Seek(r.eventsOffset, io.SeekStart); err != nil {
+		return touched, false
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// Leave partial lines for the next read
+			break
+		}
+		r.eventsOffset += int64(len(line))
+
+		var ev events.Event
+		if json.Unmarshal([]byte(line), &ev) != nil || !statuslineEventTypes[ev.Type] {
+			continue
+		}
+		sessions, ok := statuslineSessions(ev)
+		if !ok {
+			all = true
+			continue
+		}
+		for _, s := range sessions {
+			touched[s] = true
+		}
+	}
+	return touched, all
+}
+
+// statuslineSessions returns the sessions whose status lines an event
+// changes, and false if they can't be worked out from the event.
+func statuslineSessions(ev events.Event) ([]string, bool) {
+	payload := func(key string) string {
+		v, _ := ev.Payload[key].(string)
+		return v
+	}
+
+	switch ev.Type {
+	case events.TypeMail:
+		// The recipient's mail preview; lists, queues and channels fan out
+		to := agentIdentity(payload("to"))
+		if to == nil {
+			return nil, false
+		}
+		return []string{to.SessionName()}, true
+
+	case events.TypeSling, events.TypeHook, events.TypeUnhook, events.TypeHandoff, events.TypeDone:
+		// The hooked work of the agents involved
+		var sessions []string
+		for _, name := range []string{ev.Actor, payload("target")} {
+			if agent := agentIdentity(name); agent != nil {
+				sessions = append(sessions, agent.SessionName())
+			}
+		}
+		return sessions, len(sessions) > 0
+
+	case events.TypeSpawn, events.TypeKill, events.TypeSessionStart, events.TypeSessionEnd, events.TypeSessionDeath:
+		// Agent counts: the mayor and deacon count across the town, a
+		// witness counts its rig's crew
+		sessions := []string{session.MayorSessionName(), session.DeaconSessionName()}
+		rigName := payload("rig")
+		for _, name := range []string{ev.Actor, payload("session"), payload("target")} {
+			if agent := agentIdentity(name); agent != nil {
+				sessions = append(sessions, agent.SessionName())
+				if rigName == "" {
+					rigName = agent.Rig
+				}
+			}
+		}
+		if rigName != "" {
+			sessions = append(sessions, session.WitnessSessionName(rigName))
+		}
+		return sessions, true
+
+	default:
+		// Merge queue events change the rig's refinery line
+		for _, name := range []string{ev.Actor, payload("worker")} {
+			if agent := agentIdentity(name); agent != nil && agent.Rig != "" {
+				return []string{session.RefinerySessionName(agent.Rig)}, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// agentIdentity parses a session name or mail address into an agent, or
+// returns nil if it names neither.
+func agentIdentity(name string) *session.AgentIdentity {
+	if name == "" {
+		return nil
+	}
+	if agent, err := session.ParseSessionName(name); err == nil {
+		return agent
+	}
+	if agent, err := session.ParseAddress(name); err == nil && agent.SessionName() != "" {
+		return agent
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/statusline"
+)
+
+func TestStatuslineRefreshInterval(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     time.Duration
+		wantOK   bool
+	}{
+		{"", statusline.DefaultRefreshInterval, true},
+		{"30s", 30 * time.Second, true},
+		{"off", 0, false},
+		{"bogus", statusline.DefaultRefreshInterval, true},
+		{"-5s", statusline.DefaultRefreshInterval, true},
+	}
+	for _, tt := range tests {
+		got, ok := statuslineRefreshInterval(&config.DaemonConfig{StatuslineInterval: tt.interval})
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("statuslineRefreshInterval(%q) = %v, %v; want %v, %v", tt.interval, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if got, ok := statuslineRefreshInterval(nil); got != statusline.DefaultRefreshInterval || !ok {
+		t.Errorf("statuslineRefreshInterval(nil) = %v, %v", got, ok)
+	}
+}
+
+func TestStatuslineReadEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
+	appendEvents := func(lines ...string) {
+		t.Helper()
+		f, err := os.OpenFile(eventsPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		for _, line := range lines {
+			if _, err := f.WriteString(line); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	read := func(r *StatuslineRefresher) []string {
+		t.Helper()
+		touched, all := r.readEvents()
+		if all {
+			return []string{"*"}
+		}
+		var sessions []string
+		for s := range touched {
+			sessions = append(sessions, s)
+		}
+		sort.Strings(sessions)
+		return sessions
+	}
+
+	// Events from before the refresher started are covered by its first refresh
+	appendEvents(`{"type":"mail","actor":"mayor","payload":{"to":"gastown/Toast"}}` + "\n")
+	r := NewStatuslineRefresher(townRoot, time.Minute, t.Logf)
+	r.render = func(*statusline.Renderer, string) error { return nil }
+	if err := r.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	r.Stop()
+	if got := read(r); len(got) != 0 {
+		t.Errorf("old event touched %v", got)
+	}
+
+	appendEvents(`{"type":"patrol_started","actor":"gastown/witness"}` + "\n")
+	if got := read(r); len(got) != 0 {
+		t.Errorf("patrol event touched %v", got)
+	}
+
+	// A partial line waits for the rest
+	appendEvents(`{"type":"hook",`)
+	if got := read(r); len(got) != 0 {
+		t.Errorf("partial line touched %v", got)
+	}
+	appendEvents(`"actor":"gastown/Toast"}` + "\n")
+	if got := read(r); !reflect.DeepEqual(got, []string{"gt-gastown-Toast"}) {
+		t.Errorf("hook event touched %v, want [gt-gastown-Toast]", got)
+	}
+	if got := read(r); len(got) != 0 {
+		t.Errorf("hook event touched %v a second time", got)
+	}
+
+	// A rotated log is read from the start
+	if err := os.WriteFile(eventsPath, []byte(`{"type":"mail","actor":"mayor","payload":{"to":"deacon/"}}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(r); !reflect.DeepEqual(got, []string{"hq-deacon"}) {
+		t.Errorf("event in rotated log touched %v, want [hq-deacon]", got)
+	}
+}
+
+func TestStatuslineSessions(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  events.Event
+		want   []string
+		wantOK bool
+	}{
+		{
+			name:   "mail to an agent",
+			event:  events.Event{Type: events.TypeMail, Actor: "mayor", Payload: events.MailPayload("gastown/crew/max", "hi")},
+			want:   []string{"gt-gastown-crew-max"},
+			wantOK: true,
+		},
+		{
+			name:  "mail to a list",
+			event: events.Event{Type: events.TypeMail, Actor: "mayor", Payload: events.MailPayload("list:oncall", "hi")},
+		},
+		{
+			name:   "sling",
+			event:  events.Event{Type: events.TypeSling, Actor: "mayor", Payload: events.SlingPayload("gt-abc", "gastown/Toast")},
+			want:   []string{"hq-mayor", "gt-gastown-Toast"},
+			wantOK: true,
+		},
+		{
+			name:   "spawn",
+			event:  events.Event{Type: events.TypeSpawn, Actor: "gt", Payload: events.SpawnPayload("gastown", "Toast")},
+			want:   []string{"hq-mayor", "hq-deacon", "gt-gastown-witness"},
+			wantOK: true,
+		},
+		{
+			name:   "session death",
+			event:  events.Event{Type: events.TypeSessionDeath, Actor: "gt-gastown-crew-max", Payload: map[string]interface{}{"session": "gt-gastown-crew-max"}},
+			want:   []string{"hq-mayor", "hq-deacon", "gt-gastown-crew-max", "gt-gastown-crew-max", "gt-gastown-witness"},
+			wantOK: true,
+		},
+		{
+			name:   "merged",
+			event:  events.Event{Type: events.TypeMerged, Actor: "gastown/refinery"},
+			want:   []string{"gt-gastown-refinery"},
+			wantOK: true,
+		},
+		{
+			name:   "MR submitted by a polecat",
+			event:  events.Event{Type: events.TypeMRSubmitted, Actor: "gastown/Toast"},
+			want:   []string{"gt-gastown-refinery"},
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := statuslineSessions(tt.event)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statuslineSessions() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
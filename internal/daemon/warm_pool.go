@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// replenishWarmPools tops up the warm polecat pool of each rig that has one
+// configured. Creating worktrees and running setup hooks can take minutes,
+// so each rig is replenished in its own goroutine; a rig whose previous
+// replenishment is still running is skipped.
+func (d *Daemon) replenishWarmPools() {
+	for _, rigName := range d.getKnownRigs() {
+		r := &rig.Rig{
+			Name: rigName,
+			Path: filepath.Join(d.config.TownRoot, rigName),
+		}
+		mgr := polecat.NewManager(r, git.NewGit(r.Path), d.tmux)
+		if mgr.WarmPoolConfig() == nil {
+			continue
+		}
+		if operational, reason := d.isRigOperational(rigName); !operational {
+			d.logger.Printf("Skipping warm pool for %s: %s", rigName, reason)
+			continue
+		}
+
+		d.warmPoolMu.Lock()
+		if d.warmPoolBusy[rigName] {
+			d.warmPoolMu.Unlock()
+			continue
+		}
+		d.warmPoolBusy[rigName] = true
+		d.warmPoolMu.Unlock()
+
+		go func(rigName string, mgr *polecat.Manager) {
+			defer func() {
+				d.warmPoolMu.Lock()
+				delete(d.warmPoolBusy, rigName)
+				d.warmPoolMu.Unlock()
+			}()
+
+			created, recycled, err := mgr.ReplenishWarmPool()
+			if err != nil {
+				d.logger.Printf("Error replenishing warm pool for %s: %v", rigName, err)
+			}
+			if created > 0 || recycled > 0 {
+				d.logger.Printf("Warm pool for %s: %d created, %d recycled", rigName, created, recycled)
+			}
+		}(rigName, mgr)
+	}
+}
@@ -54,6 +54,9 @@ const (
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
+	// Daemon recovery events
+	TypeGUPPViolation = "gupp_violation" // Agent has work on hook but isn't progressing
+
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
 	TypePolecatChecked  = "polecat_checked"
@@ -63,11 +66,22 @@ const (
 	TypeEscalationClosed = "escalation_closed"
 	TypePatrolComplete   = "patrol_complete"
 
-	// Merge queue events (emitted by refinery)
+	// Merge queue events (emitted by refinery, except mr_submitted from gt done)
+	TypeMRSubmitted  = "mr_submitted"
 	TypeMergeStarted = "merge_started"
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Convoy events
+	TypeConvoyCreated = "convoy_created"
+	TypeConvoyClosed  = "convoy_closed"
+
+	// Guard events (gt tap guard eval)
+	TypeGuardBlocked = "guard_blocked"
+
+	// Account events
+	TypeAccountLimited = "account_limited" // Account hit a usage limit
 )
 
 // EventsFile is the name of the raw events log.
@@ -196,6 +210,20 @@ func BootPayload(rig string, agents []string) map[string]interface{} {
 	}
 }
 
+// GUPPViolationPayload creates a payload for GUPP violation events.
+// rig: rig the agent belongs to
+// agent: agent bead ID of the stuck agent
+// hookBead: bead on the agent's hook
+// stuck: how long the agent has gone without progress
+func GUPPViolationPayload(rig, agent, hookBead string, stuck time.Duration) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":       rig,
+		"agent":     agent,
+		"hook_bead": hookBead,
+		"stuck":     stuck.Round(time.Second).String(),
+	}
+}
+
 // MergePayload creates a payload for merge queue events.
 // mrID: merge request ID
 // worker: polecat name that submitted the work
@@ -213,6 +241,76 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// MRSubmittedPayload creates a payload for merge request submission events.
+// mrID: merge request bead ID
+// beadID: source issue the MR delivers
+// branch: source branch
+// target: target branch
+func MRSubmittedPayload(mrID, beadID, branch, target string) map[string]interface{} {
+	return map[string]interface{}{
+		"mr":     mrID,
+		"bead":   beadID,
+		"branch": branch,
+		"target": target,
+	}
+}
+
+// ConvoyCreatedPayload creates a payload for convoy create events.
+// template is the convoy template it was instantiated from, if any.
+func ConvoyCreatedPayload(convoyID, title, template string, tracked []string) map[string]interface{} {
+	payload := map[string]interface{}{
+		"convoy":  convoyID,
+		"title":   title,
+		"tracked": tracked,
+	}
+	if template != "" {
+		payload["template"] = template
+	}
+	return payload
+}
+
+// ConvoyClosedPayload creates a payload for convoy close events.
+// tracked lists the issue IDs the convoy was tracking, so a bead's
+// lifecycle trace can find the convoy that shipped it.
+func ConvoyClosedPayload(convoyID, title, reason string, tracked []string) map[string]interface{} {
+	return map[string]interface{}{
+		"convoy":  convoyID,
+		"title":   title,
+		"reason":  reason,
+		"tracked": tracked,
+	}
+}
+
+// GuardBlockedPayload creates a payload for tool calls blocked by a guard policy.
+// rule: name of the policy rule that blocked the call
+// tool: Claude Code tool name (e.g., "Bash", "Edit")
+// subject: the command or path that was blocked
+// source: policy file the rule came from
+func GuardBlockedPayload(rule, tool, subject, source string) map[string]interface{} {
+	return map[string]interface{}{
+		"rule":    rule,
+		"tool":    tool,
+		"subject": subject,
+		"source":  source,
+	}
+}
+
+// AccountLimitedPayload creates a payload for account limit events.
+// rotatedTo is the account the session was moved to, or empty if none was
+// available.
+func AccountLimitedPayload(account, session, reason string, resetsAt time.Time, rotatedTo string) map[string]interface{} {
+	p := map[string]interface{}{
+		"account":   account,
+		"session":   session,
+		"reason":    reason,
+		"resets_at": resetsAt.UTC().Format(time.RFC3339),
+	}
+	if rotatedTo != "" {
+		p["rotated_to"] = rotatedTo
+	}
+	return p
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -0,0 +1,368 @@
+// Package forge talks to code hosts (GitHub, GitLab) on behalf of the
+// refinery when a rig lands work through pull requests instead of pushing
+// directly to the target branch.
+//
+// A Forge opens a pull request for an MR's branch, reports the merge gate
+// (checks and approvals), merges through the API, and lists the review
+// comments that should go back to the polecat as rework.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// PR states, normalized across providers.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+	StateMerged = "merged"
+)
+
+// requestTimeout bounds each API call.
+const requestTimeout = 30 * time.Second
+
+// maxPages bounds how many pages a list fetch follows, so a misbehaving
+// server can't keep it paging forever.
+const maxPages = 50
+
+// ErrNotMergeable is returned by Merge when the host refuses the merge
+// (conflicts, unmet branch protection, or a head that moved).
+var ErrNotMergeable = errors.New("pull request not mergeable")
+
+// PRRequest describes a pull request to open.
+type PRRequest struct {
+	Title string
+	Body  string
+	Head  string // Source branch
+	Base  string // Target branch
+}
+
+// PR is a pull request (GitHub) or merge request (GitLab).
+type PR struct {
+	Number      int    // PR number (GitHub) or MR IID (GitLab)
+	URL         string // Web URL
+	State       string // StateOpen, StateClosed or StateMerged
+	HeadSHA     string // Current head commit
+	Conflicted  bool   // The host reports merge conflicts with the base
+	MergeCommit string // Set once merged
+}
+
+// Check is one CI check or commit status on a PR's head commit.
+type Check struct {
+	Name  string
+	State string // "pending", "success" or "failure"
+}
+
+// Check states.
+const (
+	CheckPending = "pending"
+	CheckSuccess = "success"
+	CheckFailure = "failure"
+)
+
+// Gate is what stands between an open PR and its merge.
+type Gate struct {
+	Checks           []Check
+	Approvals        int      // Distinct approving reviewers
+	ChangesRequested []string // Reviewers whose latest review requests changes
+}
+
+// Comment is a review comment that asks for changes.
+type Comment struct {
+	ID        string
+	Author    string
+	Body      string
+	Path      string // File the comment is on, if any
+	Line      int    // Line in Path, if any
+	CreatedAt time.Time
+}
+
+// Location returns "path:line", "path", or "" for general comments.
+func (c Comment) Location() string {
+	switch {
+	case c.Path == "":
+		return ""
+	case c.Line > 0:
+		return fmt.Sprintf("%s:%d", c.Path, c.Line)
+	default:
+		return c.Path
+	}
+}
+
+// Forge is a code host API.
+type Forge interface {
+	// Provider returns the provider name ("github" or "gitlab").
+	Provider() string
+
+	// OpenPR returns the open PR from req.Head into req.Base, creating it
+	// if there is none.
+	OpenPR(ctx context.Context, req PRRequest) (*PR, error)
+
+	// GetPR fetches a PR by number.
+	GetPR(ctx context.Context, number int) (*PR, error)
+
+	// Gate reports the checks and reviews on pr's head commit.
+	Gate(ctx context.Context, pr *PR) (*Gate, error)
+
+	// Merge merges pr with the configured method and returns the resulting
+	// commit SHA. Returns an error wrapping ErrNotMergeable if the host
+	// refuses.
+	Merge(ctx context.Context, pr *PR, commitTitle string) (string, error)
+
+	// ReviewComments lists the comments on a PR that ask for changes:
+	// line comments and change-request reviews on GitHub, unresolved
+	// discussion notes on GitLab. Oldest first.
+	ReviewComments(ctx context.Context, number int) ([]Comment, error)
+}
+
+// New returns the Forge for cfg.
+func New(cfg *config.ForgeConfig) (Forge, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no forge configured")
+	}
+	if cfg.Repo == "" {
+		return nil, fmt.Errorf("forge repo not set")
+	}
+
+	tokenEnv := cfg.TokenEnv
+	switch cfg.Provider {
+	case config.ForgeGitHub:
+		if tokenEnv == "" {
+			tokenEnv = "GITHUB_TOKEN"
+		}
+		c := newClient(cfg.APIURL, "https://api.github.com")
+		if token := os.Getenv(tokenEnv); token != "" {
+			c.header.Set("Authorization", "Bearer "+token)
+		}
+		c.header.Set("Accept", "application/vnd.github+json")
+		c.nextPage = c.ghNextPage
+		return &gitHub{cfg: cfg, c: c}, nil
+	case config.ForgeGitLab:
+		if tokenEnv == "" {
+			tokenEnv = "GITLAB_TOKEN"
+		}
+		c := newClient(cfg.APIURL, "https://gitlab.com/api/v4")
+		if token := os.Getenv(tokenEnv); token != "" {
+			c.header.Set("PRIVATE-TOKEN", token)
+		}
+		c.nextPage = glNextPage
+		return &gitLab{cfg: cfg, c: c}, nil
+	default:
+		return nil, fmt.Errorf("unknown forge provider %q", cfg.Provider)
+	}
+}
+
+// Ready reports whether the gate allows merging: every required check
+// passed (every reported check, if required is empty), at least approvals
+// reviewers approved, and nobody's latest review requests changes. If not,
+// reason says what is outstanding and failed says whether a check failed
+// (as opposed to still running).
+func (g *Gate) Ready(required []string, approvals int) (ready bool, reason string, failed bool) {
+	var pending, failing []string
+	states := make(map[string]string, len(g.Checks))
+	for _, c := range g.Checks {
+		states[c.Name] = mergeCheckState(states[c.Name], c.State)
+	}
+
+	names := required
+	if len(names) == 0 {
+		for _, c := range g.Checks {
+			names = append(names, c.Name)
+		}
+	}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		switch states[name] {
+		case CheckSuccess:
+		case CheckFailure:
+			failing = append(failing, name)
+		default:
+			// Required checks that haven't reported yet are pending
+			pending = append(pending, name)
+		}
+	}
+
+	switch {
+	case len(failing) > 0:
+		return false, "checks failed: " + strings.Join(failing, ", "), true
+	case len(pending) > 0:
+		return false, "waiting on checks: " + strings.Join(pending, ", "), false
+	case len(g.ChangesRequested) > 0:
+		return false, "changes requested by " + strings.Join(g.ChangesRequested, ", "), false
+	case g.Approvals < approvals:
+		return false, fmt.Sprintf("waiting on approvals: %d of %d", g.Approvals, approvals), false
+	}
+	return true, "", false
+}
+
+// mergeCheckState combines two reports for the same check name (reruns,
+// or a status and a check run sharing a name): any failure wins, then
+// pending, then success.
+func mergeCheckState(a, b string) string {
+	switch {
+	case a == CheckFailure || b == CheckFailure:
+		return CheckFailure
+	case a == "":
+		return b
+	case a == CheckPending || b == CheckPending:
+		return CheckPending
+	default:
+		return CheckSuccess
+	}
+}
+
+// client is a minimal JSON REST client.
+type client struct {
+	baseURL string
+	header  http.Header // Sent with every request (auth, Accept)
+	http    *http.Client
+
+	// nextPage returns the path of the list page after the one at path,
+	// given its response headers, or "" on the last page.
+	nextPage func(path string, h http.Header) string
+}
+
+func newClient(apiURL, defaultURL string) *client {
+	if apiURL == "" {
+		apiURL = defaultURL
+	}
+	return &client{
+		baseURL: strings.TrimRight(apiURL, "/"),
+		header:  http.Header{"Accept": {"application/json"}},
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// apiError is a non-2xx API response.
+type apiError struct {
+	Method  string
+	Path    string
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.Status, e.Message)
+}
+
+// do sends a JSON request and decodes a JSON response into out (if non-nil).
+func (c *client) do(ctx context.Context, method, path string, body, out any) error {
+	_, err := c.send(ctx, method, path, body, out)
+	return err
+}
+
+// getAll fetches every page of a JSON list, following the provider's
+// pagination headers for at most maxPages pages.
+func getAll[T any](ctx context.Context, c *client, path string) ([]T, error) {
+	var all []T
+	for page := 0; path != "" && page < maxPages; page++ {
+		var items []T
+		header, err := c.send(ctx, http.MethodGet, path, nil, &items)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if c.nextPage == nil {
+			break
+		}
+		path = c.nextPage(path, header)
+	}
+	return all, nil
+}
+
+// send is do, also returning the response headers.
+func (c *client) send(ctx context.Context, method, path string, body, out any) (http.Header, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: reading response: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &apiError{Method: method, Path: path, Status: resp.StatusCode, Message: errorMessage(data)}
+	}
+	if out == nil || len(data) == 0 {
+		return resp.Header, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return resp.Header, nil
+}
+
+// errorMessage extracts the message from an API error body.
+func errorMessage(data []byte) string {
+	var body struct {
+		Message any    `json:"message"` // GitHub: string; GitLab: string, list or map
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		if body.Message != nil {
+			return fmt.Sprint(body.Message)
+		}
+		if body.Error != "" {
+			return body.Error
+		}
+	}
+	msg := strings.TrimSpace(string(data))
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+	return msg
+}
+
+// statusOf returns the HTTP status of an apiError, or 0.
+func statusOf(err error) int {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	return 0
+}
+
+// mergeMethod returns the configured merge method, defaulting to squash.
+func mergeMethod(cfg *config.ForgeConfig) string {
+	if cfg.MergeMethod == "" {
+		return "squash"
+	}
+	return cfg.MergeMethod
+}
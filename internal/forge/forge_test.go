@@ -0,0 +1,225 @@
+package forge_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+)
+
+// providers runs fn against both API dialects of a fresh fake forge.
+func providers(t *testing.T, fn func(t *testing.T, srv *forgetest.Server, f forge.Forge)) {
+	for _, provider := range []string{config.ForgeGitHub, config.ForgeGitLab} {
+		t.Run(provider, func(t *testing.T) {
+			srv := forgetest.NewServer(t, "acme/widgets")
+			cfg := srv.GitHubConfig()
+			if provider == config.ForgeGitLab {
+				cfg = srv.GitLabConfig()
+			}
+			f, err := forge.New(cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if f.Provider() != provider {
+				t.Errorf("Provider() = %q, want %q", f.Provider(), provider)
+			}
+			fn(t, srv, f)
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := forge.New(nil); err == nil {
+		t.Error("New(nil) succeeded")
+	}
+	if _, err := forge.New(&config.ForgeConfig{Provider: config.ForgeGitHub}); err == nil {
+		t.Error("New without repo succeeded")
+	}
+	if _, err := forge.New(&config.ForgeConfig{Provider: "gitea", Repo: "a/b"}); err == nil {
+		t.Error("New with unknown provider succeeded")
+	}
+}
+
+func TestOpenPR_Idempotent(t *testing.T) {
+	providers(t, func(t *testing.T, srv *forgetest.Server, f forge.Forge) {
+		ctx := context.Background()
+		req := forge.PRRequest{Title: "Fix widgets", Body: "details", Head: "polecat/nux", Base: "main"}
+
+		pr, err := f.OpenPR(ctx, req)
+		if err != nil {
+			t.Fatalf("OpenPR: %v", err)
+		}
+		if pr.Number != 1 || pr.State != forge.StateOpen || pr.URL == "" || pr.HeadSHA == "" {
+			t.Errorf("OpenPR = %+v", pr)
+		}
+
+		again, err := f.OpenPR(ctx, req)
+		if err != nil {
+			t.Fatalf("OpenPR again: %v", err)
+		}
+		if again.Number != pr.Number || srv.PRs() != 1 {
+			t.Errorf("second OpenPR opened #%d (%d total), want the existing #%d", again.Number, srv.PRs(), pr.Number)
+		}
+		if got := srv.PR(1); got.Title != "Fix widgets" || got.Head != "polecat/nux" || got.Base != "main" {
+			t.Errorf("server PR = %+v", got)
+		}
+	})
+}
+
+func TestGate(t *testing.T) {
+	providers(t, func(t *testing.T, srv *forgetest.Server, f forge.Forge) {
+		ctx := context.Background()
+		pr, err := f.OpenPR(ctx, forge.PRRequest{Title: "t", Head: "polecat/nux", Base: "main"})
+		if err != nil {
+			t.Fatalf("OpenPR: %v", err)
+		}
+
+		ready := func() (bool, string, bool) {
+			t.Helper()
+			g, err := f.Gate(ctx, pr)
+			if err != nil {
+				t.Fatalf("Gate: %v", err)
+			}
+			return g.Ready([]string{"ci"}, 1)
+		}
+
+		if ok, reason, failed := ready(); ok || failed || !strings.Contains(reason, "waiting on checks: ci") {
+			t.Errorf("no checks: ready=%v reason=%q failed=%v", ok, reason, failed)
+		}
+
+		srv.SetCheck(1, "ci", forge.CheckFailure)
+		if ok, reason, failed := ready(); ok || !failed || !strings.Contains(reason, "checks failed: ci") {
+			t.Errorf("failed check: ready=%v reason=%q failed=%v", ok, reason, failed)
+		}
+
+		srv.SetCheck(1, "ci", forge.CheckSuccess)
+		if ok, reason, _ := ready(); ok || !strings.Contains(reason, "approvals: 0 of 1") {
+			t.Errorf("unapproved: ready=%v reason=%q", ok, reason)
+		}
+
+		srv.RequestChanges(1, "alice", "Please add tests")
+		srv.Approve(1, "bob")
+		if ok, reason, _ := ready(); ok || !strings.Contains(reason, "changes requested by alice") {
+			t.Errorf("changes requested: ready=%v reason=%q", ok, reason)
+		}
+
+		srv.ResolveComments(1)
+		if ok, reason, _ := ready(); !ok {
+			t.Errorf("approved and green: not ready (%s)", reason)
+		}
+	})
+}
+
+func TestMerge(t *testing.T) {
+	providers(t, func(t *testing.T, srv *forgetest.Server, f forge.Forge) {
+		ctx := context.Background()
+		pr, err := f.OpenPR(ctx, forge.PRRequest{Title: "t", Head: "polecat/nux", Base: "main"})
+		if err != nil {
+			t.Fatalf("OpenPR: %v", err)
+		}
+
+		srv.SetConflicted(1, true)
+		if _, err := f.Merge(ctx, pr, "Fix widgets"); !errors.Is(err, forge.ErrNotMergeable) {
+			t.Errorf("Merge conflicted = %v, want ErrNotMergeable", err)
+		}
+
+		// A push after the gate was read moves the head out from under us
+		srv.SetConflicted(1, false)
+		srv.Push(1, strings.Repeat("b", 40))
+		if _, err := f.Merge(ctx, pr, "Fix widgets"); !errors.Is(err, forge.ErrNotMergeable) {
+			t.Errorf("Merge stale head = %v, want ErrNotMergeable", err)
+		}
+
+		pr, err = f.GetPR(ctx, 1)
+		if err != nil {
+			t.Fatalf("GetPR: %v", err)
+		}
+		sha, err := f.Merge(ctx, pr, "Fix widgets")
+		if err != nil {
+			t.Fatalf("Merge: %v", err)
+		}
+		got := srv.PR(1)
+		if got.State != forge.StateMerged || sha != got.MergeCommit {
+			t.Errorf("after merge: state=%q sha=%q, server commit %q", got.State, sha, got.MergeCommit)
+		}
+		if got.MergeMethod != "squash" || got.MergeTitle != "Fix widgets" {
+			t.Errorf("merged with method=%q title=%q, want squash and the given title", got.MergeMethod, got.MergeTitle)
+		}
+
+		merged, err := f.GetPR(ctx, 1)
+		if err != nil {
+			t.Fatalf("GetPR: %v", err)
+		}
+		if merged.State != forge.StateMerged || merged.MergeCommit != sha {
+			t.Errorf("GetPR after merge = %+v", merged)
+		}
+	})
+}
+
+func TestReviewComments(t *testing.T) {
+	providers(t, func(t *testing.T, srv *forgetest.Server, f forge.Forge) {
+		ctx := context.Background()
+		if _, err := f.OpenPR(ctx, forge.PRRequest{Title: "t", Head: "polecat/nux", Base: "main"}); err != nil {
+			t.Fatalf("OpenPR: %v", err)
+		}
+
+		srv.Comment(1, "alice", "widget.go", 12, "Handle the nil case")
+		srv.Approve(1, "bob") // Approvals carry no body and aren't comments
+		srv.RequestChanges(1, "carol", "Needs a test")
+
+		comments, err := f.ReviewComments(ctx, 1)
+		if err != nil {
+			t.Fatalf("ReviewComments: %v", err)
+		}
+		if len(comments) != 2 {
+			t.Fatalf("got %d comments, want 2: %+v", len(comments), comments)
+		}
+		if c := comments[0]; c.Author != "alice" || c.Location() != "widget.go:12" || c.Body != "Handle the nil case" {
+			t.Errorf("comments[0] = %+v", c)
+		}
+		if c := comments[1]; c.Author != "carol" || c.Location() != "" || c.Body != "Needs a test" {
+			t.Errorf("comments[1] = %+v", c)
+		}
+		if !comments[0].CreatedAt.Before(comments[1].CreatedAt) {
+			t.Error("comments not oldest first")
+		}
+
+		srv.ResolveComments(1)
+		comments, err = f.ReviewComments(ctx, 1)
+		if err != nil {
+			t.Fatalf("ReviewComments: %v", err)
+		}
+		if len(comments) != 0 {
+			t.Errorf("after resolving, got %+v", comments)
+		}
+	})
+}
+
+func TestReviewComments_Paginated(t *testing.T) {
+	providers(t, func(t *testing.T, srv *forgetest.Server, f forge.Forge) {
+		ctx := context.Background()
+		if _, err := f.OpenPR(ctx, forge.PRRequest{Title: "t", Head: "polecat/nux", Base: "main"}); err != nil {
+			t.Fatalf("OpenPR: %v", err)
+		}
+
+		// More than one page of 100
+		for i := 1; i <= 250; i++ {
+			srv.Comment(1, "alice", "widget.go", i, "nit")
+		}
+
+		comments, err := f.ReviewComments(ctx, 1)
+		if err != nil {
+			t.Fatalf("ReviewComments: %v", err)
+		}
+		if len(comments) != 250 {
+			t.Fatalf("got %d comments, want 250", len(comments))
+		}
+		if last := comments[249]; last.Location() != "widget.go:250" {
+			t.Errorf("last comment = %+v", last)
+		}
+	})
+}
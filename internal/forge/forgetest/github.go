@@ -0,0 +1,168 @@
+package forgetest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// serveGitHub handles /repos/{owner}/{repo}/... requests.
+func (s *Server) serveGitHub(w http.ResponseWriter, r *http.Request, rest string) {
+	seg := route(rest)
+	switch {
+	case len(seg) == 1 && seg[0] == "pulls" && r.Method == http.MethodGet:
+		head := r.URL.Query().Get("head")
+		if _, branch, ok := strings.Cut(head, ":"); ok {
+			head = branch
+		}
+		out := []map[string]any{}
+		for _, pr := range s.find(head, r.URL.Query().Get("base")) {
+			out = append(out, ghPull(pr))
+		}
+		writeJSON(w, http.StatusOK, out)
+
+	case len(seg) == 1 && seg[0] == "pulls" && r.Method == http.MethodPost:
+		var req struct {
+			Title, Body, Head, Base string
+		}
+		if err := decode(r, &req); err != nil || req.Head == "" || req.Base == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		if len(s.find(req.Head, req.Base)) > 0 {
+			writeError(w, http.StatusUnprocessableEntity, "A pull request already exists for "+req.Head)
+			return
+		}
+		writeJSON(w, http.StatusCreated, ghPull(s.open(req.Title, req.Body, req.Head, req.Base)))
+
+	case len(seg) >= 2 && seg[0] == "pulls":
+		n, ok := pullNumber(seg[1])
+		pr := s.prs[n]
+		if !ok || pr == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		s.serveGitHubPull(w, r, pr, seg[2:])
+
+	case len(seg) == 3 && seg[0] == "commits" && r.Method == http.MethodGet:
+		pr := s.byHead(seg[1])
+		switch seg[2] {
+		case "check-runs":
+			runs := []map[string]any{}
+			if pr != nil {
+				for _, name := range sortedKeys(pr.Checks) {
+					run := map[string]any{"name": name, "status": "in_progress", "conclusion": nil}
+					switch pr.Checks[name] {
+					case forge.CheckSuccess:
+						run["status"], run["conclusion"] = "completed", "success"
+					case forge.CheckFailure:
+						run["status"], run["conclusion"] = "completed", "failure"
+					}
+					runs = append(runs, run)
+				}
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"total_count": len(runs), "check_runs": runs})
+		case "status":
+			// Checks are all reported through the Checks API
+			writeJSON(w, http.StatusOK, map[string]any{"state": "pending", "statuses": []any{}})
+		default:
+			writeError(w, http.StatusNotFound, "Not Found")
+		}
+
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// serveGitHubPull handles /pulls/{n}/... requests.
+func (s *Server) serveGitHubPull(w http.ResponseWriter, r *http.Request, pr *PullRequest, seg []string) {
+	switch {
+	case len(seg) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, ghPull(pr))
+
+	case len(seg) == 1 && seg[0] == "reviews" && r.Method == http.MethodGet:
+		out := []map[string]any{}
+		for _, rv := range pr.Reviews {
+			out = append(out, map[string]any{
+				"id":           rv.ID,
+				"user":         map[string]string{"login": rv.User},
+				"state":        rv.State,
+				"body":         rv.Body,
+				"submitted_at": rv.At,
+			})
+		}
+		ghWriteList(w, r, out)
+
+	case len(seg) == 1 && seg[0] == "comments" && r.Method == http.MethodGet:
+		out := []map[string]any{}
+		for _, c := range pr.Comments {
+			if c.Resolved {
+				// GitHub keeps resolved threads; dropping them here keeps
+				// the fake's two dialects consistent
+				continue
+			}
+			out = append(out, map[string]any{
+				"id":         c.ID,
+				"user":       map[string]string{"login": c.User},
+				"body":       c.Body,
+				"path":       c.Path,
+				"line":       c.Line,
+				"created_at": c.At,
+			})
+		}
+		ghWriteList(w, r, out)
+
+	case len(seg) == 1 && seg[0] == "merge" && r.Method == http.MethodPut:
+		var req struct {
+			MergeMethod string `json:"merge_method"`
+			CommitTitle string `json:"commit_title"`
+			SHA         string `json:"sha"`
+		}
+		_ = decode(r, &req)
+		if status, msg := s.merge(pr, req.SHA, req.MergeMethod, req.CommitTitle); status != 0 {
+			writeError(w, status, msg)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"sha": pr.MergeCommit, "merged": true, "message": "Pull Request successfully merged"})
+
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// ghWriteList writes one page of items with a Link header to the next.
+func ghWriteList(w http.ResponseWriter, r *http.Request, items []map[string]any) {
+	out, next := paginate(r, items)
+	if next != 0 {
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next", <%s>; rel="first"`, pageURL(r, next), pageURL(r, 1)))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func ghPull(pr *PullRequest) map[string]any {
+	state := "open"
+	if pr.State != forge.StateOpen {
+		state = "closed"
+	}
+	mergeable := "clean"
+	if pr.Conflicted {
+		mergeable = "dirty"
+	}
+	out := map[string]any{
+		"number":          pr.Number,
+		"html_url":        "https://github.example/pull/" + itoa(pr.Number),
+		"title":           pr.Title,
+		"body":            pr.Body,
+		"state":           state,
+		"merged":          pr.State == forge.StateMerged,
+		"mergeable_state": mergeable,
+		"head":            map[string]string{"ref": pr.Head, "sha": pr.HeadSHA},
+		"base":            map[string]string{"ref": pr.Base},
+	}
+	if pr.MergeCommit != "" {
+		out["merge_commit_sha"] = pr.MergeCommit
+	}
+	return out
+}
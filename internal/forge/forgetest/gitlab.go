@@ -0,0 +1,201 @@
+package forgetest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// serveGitLab handles /api/v4/projects/{id}/... requests.
+func (s *Server) serveGitLab(w http.ResponseWriter, r *http.Request, rest string) {
+	seg := route(rest)
+	switch {
+	case len(seg) == 1 && seg[0] == "merge_requests" && r.Method == http.MethodGet:
+		q := r.URL.Query()
+		out := []map[string]any{}
+		for _, pr := range s.find(q.Get("source_branch"), q.Get("target_branch")) {
+			out = append(out, glMergeRequest(pr))
+		}
+		writeJSON(w, http.StatusOK, out)
+
+	case len(seg) == 1 && seg[0] == "merge_requests" && r.Method == http.MethodPost:
+		var req struct {
+			Title        string `json:"title"`
+			Description  string `json:"description"`
+			SourceBranch string `json:"source_branch"`
+			TargetBranch string `json:"target_branch"`
+		}
+		if err := decode(r, &req); err != nil || req.SourceBranch == "" || req.TargetBranch == "" {
+			writeError(w, http.StatusBadRequest, "source_branch and target_branch are required")
+			return
+		}
+		if len(s.find(req.SourceBranch, req.TargetBranch)) > 0 {
+			writeError(w, http.StatusConflict, "Another open merge request already exists for this source branch")
+			return
+		}
+		writeJSON(w, http.StatusCreated, glMergeRequest(s.open(req.Title, req.Description, req.SourceBranch, req.TargetBranch)))
+
+	case len(seg) >= 2 && seg[0] == "merge_requests":
+		n, ok := pullNumber(seg[1])
+		pr := s.prs[n]
+		if !ok || pr == nil {
+			writeError(w, http.StatusNotFound, "404 Not found")
+			return
+		}
+		s.serveGitLabMR(w, r, pr, seg[2:])
+
+	case len(seg) == 3 && seg[0] == "pipelines" && seg[2] == "jobs" && r.Method == http.MethodGet:
+		n, _ := pullNumber(seg[1])
+		pr := s.prs[n] // Pipeline IDs are PR numbers
+		jobs := []map[string]any{}
+		if pr != nil {
+			for _, name := range sortedKeys(pr.Checks) {
+				jobs = append(jobs, map[string]any{"name": name, "status": glStatus(pr.Checks[name]), "allow_failure": false})
+			}
+		}
+		writeJSON(w, http.StatusOK, jobs)
+
+	default:
+		writeError(w, http.StatusNotFound, "404 Not found")
+	}
+}
+
+// serveGitLabMR handles /merge_requests/{iid}/... requests.
+func (s *Server) serveGitLabMR(w http.ResponseWriter, r *http.Request, pr *PullRequest, seg []string) {
+	switch {
+	case len(seg) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, glMergeRequest(pr))
+
+	case len(seg) == 1 && seg[0] == "approvals" && r.Method == http.MethodGet:
+		// Latest review per user decides, as on GitHub
+		latest := make(map[string]string)
+		for _, rv := range pr.Reviews {
+			latest[rv.User] = rv.State
+		}
+		approvedBy := []map[string]any{}
+		for _, user := range sortedKeys(latest) {
+			if latest[user] == "APPROVED" {
+				approvedBy = append(approvedBy, map[string]any{"user": map[string]string{"username": user}})
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"approved_by": approvedBy})
+
+	case len(seg) == 1 && seg[0] == "notes" && r.Method == http.MethodGet:
+		// Change requests and line comments are both resolvable threads
+		var notes []map[string]any
+		for _, rv := range pr.Reviews {
+			if rv.Body == "" || (rv.State != "CHANGES_REQUESTED" && rv.State != "DISMISSED") {
+				continue
+			}
+			notes = append(notes, map[string]any{
+				"id": rv.ID, "body": rv.Body, "author": map[string]string{"username": rv.User},
+				"created_at": rv.At, "system": false, "resolvable": true, "resolved": rv.State == "DISMISSED",
+			})
+		}
+		for _, c := range pr.Comments {
+			notes = append(notes, map[string]any{
+				"id": c.ID, "body": c.Body, "author": map[string]string{"username": c.User},
+				"created_at": c.At, "system": false, "resolvable": true, "resolved": c.Resolved,
+				"position": map[string]any{"new_path": c.Path, "new_line": c.Line},
+			})
+		}
+		sort.Slice(notes, func(i, j int) bool { return notes[i]["id"].(int64) < notes[j]["id"].(int64) })
+		out, next := paginate(r, notes)
+		w.Header().Set("X-Next-Page", "") // GitLab sends it empty on the last page
+		if next != 0 {
+			w.Header().Set("X-Next-Page", itoa(next))
+		}
+		writeJSON(w, http.StatusOK, out)
+
+	case len(seg) == 1 && seg[0] == "merge" && r.Method == http.MethodPut:
+		var req struct {
+			SHA                 string `json:"sha"`
+			Squash              bool   `json:"squash"`
+			SquashCommitMessage string `json:"squash_commit_message"`
+			MergeCommitMessage  string `json:"merge_commit_message"`
+		}
+		_ = decode(r, &req)
+		method, title := "merge", req.MergeCommitMessage
+		if req.Squash {
+			method, title = "squash", req.SquashCommitMessage
+		}
+		if status, msg := s.merge(pr, req.SHA, method, title); status != 0 {
+			if status == http.StatusMethodNotAllowed {
+				// GitLab reports unmergeable MRs as 406
+				status = http.StatusNotAcceptable
+			}
+			writeError(w, status, msg)
+			return
+		}
+		writeJSON(w, http.StatusOK, glMergeRequest(pr))
+
+	default:
+		writeError(w, http.StatusNotFound, "404 Not found")
+	}
+}
+
+func glMergeRequest(pr *PullRequest) map[string]any {
+	state := "opened"
+	switch pr.State {
+	case forge.StateMerged:
+		state = "merged"
+	case forge.StateClosed:
+		state = "closed"
+	}
+	out := map[string]any{
+		"iid":           pr.Number,
+		"web_url":       "https://gitlab.example/-/merge_requests/" + itoa(pr.Number),
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"state":         state,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+		"sha":           pr.HeadSHA,
+		"has_conflicts": pr.Conflicted,
+	}
+	if pr.MergeCommit != "" {
+		if pr.MergeMethod == "squash" {
+			out["squash_commit_sha"] = pr.MergeCommit
+		} else {
+			out["merge_commit_sha"] = pr.MergeCommit
+		}
+	}
+	if len(pr.Checks) > 0 {
+		status := "success"
+		for _, state := range pr.Checks {
+			switch {
+			case state == forge.CheckFailure:
+				status = "failed"
+			case state == forge.CheckPending && status != "failed":
+				status = "running"
+			}
+		}
+		out["head_pipeline"] = map[string]any{"id": pr.Number, "status": status}
+	}
+	return out
+}
+
+// glStatus maps a check state to a GitLab job status.
+func glStatus(state string) string {
+	switch state {
+	case forge.CheckSuccess:
+		return "success"
+	case forge.CheckFailure:
+		return "failed"
+	default:
+		return "running"
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func itoa(n int) string { return strconv.Itoa(n) }
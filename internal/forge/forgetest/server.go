@@ -0,0 +1,348 @@
+// Package forgetest provides a local HTTP stand-in for the GitHub and GitLab
+// APIs, for testing the refinery's pull request merge mode without network
+// access:
+//
+//	srv := forgetest.NewServer(t, "acme/widgets")
+//	f, _ := forge.New(srv.GitHubConfig())
+//	pr, _ := f.OpenPR(ctx, forge.PRRequest{Head: "polecat/nux", Base: "main"})
+//	srv.SetCheck(pr.Number, "ci", forge.CheckSuccess)
+//	srv.Approve(pr.Number, "alice")
+//
+// Both API dialects share one set of pull requests: the GitHub API is served
+// at URL and the GitLab API at URL+"/api/v4". It covers the endpoints the
+// forge package uses; anything else returns 404.
+package forgetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// epoch is the fake clock's start; each event advances it a second so
+// timestamps are distinct and ordered.
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// PullRequest is the server's record of one pull request.
+type PullRequest struct {
+	Number      int
+	Title       string
+	Body        string
+	Head        string // Source branch
+	Base        string // Target branch
+	HeadSHA     string
+	State       string // forge.StateOpen, StateClosed or StateMerged
+	Conflicted  bool
+	MergeCommit string
+	MergeMethod string // Method requested by the merge call
+	MergeTitle  string // Commit title requested by the merge call
+	Checks      map[string]string
+	Reviews     []Review
+	Comments    []LineComment
+}
+
+// Review is a pull request review.
+type Review struct {
+	ID    int64
+	User  string
+	State string // "APPROVED", "CHANGES_REQUESTED" or "COMMENTED"
+	Body  string
+	At    time.Time
+}
+
+// LineComment is a review comment on a line of the diff.
+type LineComment struct {
+	ID       int64
+	User     string
+	Body     string
+	Path     string
+	Line     int
+	At       time.Time
+	Resolved bool
+}
+
+// Server is the fake forge. Methods are safe for concurrent use.
+type Server struct {
+	// URL is the GitHub API base URL; the GitLab API is at URL+"/api/v4".
+	URL  string
+	Repo string
+
+	mu    sync.Mutex
+	prs   map[int]*PullRequest
+	seq   int64
+	clock time.Time
+}
+
+// NewServer starts a fake forge hosting repo ("owner/name" or a GitLab
+// project path), stopped when the test ends.
+func NewServer(t testing.TB, repo string) *Server {
+	t.Helper()
+	s := &Server{Repo: repo, prs: make(map[int]*PullRequest), clock: epoch}
+	ts := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(ts.Close)
+	s.URL = ts.URL
+	return s
+}
+
+// GitHubConfig returns a forge config pointing at the server's GitHub API.
+func (s *Server) GitHubConfig() *config.ForgeConfig {
+	return &config.ForgeConfig{Provider: config.ForgeGitHub, Repo: s.Repo, APIURL: s.URL}
+}
+
+// GitLabConfig returns a forge config pointing at the server's GitLab API.
+func (s *Server) GitLabConfig() *config.ForgeConfig {
+	return &config.ForgeConfig{Provider: config.ForgeGitLab, Repo: s.Repo, APIURL: s.URL + "/api/v4"}
+}
+
+// PR returns a copy of pull request n, or nil if it doesn't exist.
+func (s *Server) PR(n int) *PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pr, ok := s.prs[n]
+	if !ok {
+		return nil
+	}
+	cp := *pr
+	cp.Checks = make(map[string]string, len(pr.Checks))
+	for k, v := range pr.Checks {
+		cp.Checks[k] = v
+	}
+	cp.Reviews = append([]Review(nil), pr.Reviews...)
+	cp.Comments = append([]LineComment(nil), pr.Comments...)
+	return &cp
+}
+
+// PRs returns the number of pull requests opened.
+func (s *Server) PRs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.prs)
+}
+
+// update runs fn on pull request n, panicking if it doesn't exist (a test bug).
+func (s *Server) update(n int, fn func(*PullRequest)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pr, ok := s.prs[n]
+	if !ok {
+		panic(fmt.Sprintf("forgetest: no pull request %d", n))
+	}
+	fn(pr)
+}
+
+// tick advances the clock and returns a new ID and timestamp. Caller holds mu.
+func (s *Server) tick() (int64, time.Time) {
+	s.seq++
+	s.clock = s.clock.Add(time.Second)
+	return s.seq, s.clock
+}
+
+// SetCheck reports check name on n's head commit as state
+// (forge.CheckPending, CheckSuccess or CheckFailure).
+func (s *Server) SetCheck(n int, name, state string) {
+	s.update(n, func(pr *PullRequest) { pr.Checks[name] = state })
+}
+
+// Approve adds an approving review from user.
+func (s *Server) Approve(n int, user string) {
+	s.addReview(n, user, "APPROVED", "")
+}
+
+// RequestChanges adds a change-requesting review from user.
+func (s *Server) RequestChanges(n int, user, body string) {
+	s.addReview(n, user, "CHANGES_REQUESTED", body)
+}
+
+func (s *Server) addReview(n int, user, state, body string) {
+	s.update(n, func(pr *PullRequest) {
+		id, at := s.tick()
+		pr.Reviews = append(pr.Reviews, Review{ID: id, User: user, State: state, Body: body, At: at})
+	})
+}
+
+// Comment adds a review comment from user on path:line.
+func (s *Server) Comment(n int, user, path string, line int, body string) {
+	s.update(n, func(pr *PullRequest) {
+		id, at := s.tick()
+		pr.Comments = append(pr.Comments, LineComment{ID: id, User: user, Body: body, Path: path, Line: line, At: at})
+	})
+}
+
+// ResolveComments marks every review comment on n resolved and dismisses
+// its change requests, as a reviewer does after rework lands.
+func (s *Server) ResolveComments(n int) {
+	s.update(n, func(pr *PullRequest) {
+		for i := range pr.Comments {
+			pr.Comments[i].Resolved = true
+		}
+		for i := range pr.Reviews {
+			if pr.Reviews[i].State == "CHANGES_REQUESTED" {
+				pr.Reviews[i].State = "DISMISSED"
+			}
+		}
+	})
+}
+
+// Push moves n's head to sha, as a push to the source branch does. Checks
+// reset, since they ran against the old head.
+func (s *Server) Push(n int, sha string) {
+	s.update(n, func(pr *PullRequest) {
+		pr.HeadSHA = sha
+		pr.Checks = make(map[string]string)
+	})
+}
+
+// SetConflicted marks n as conflicting (or not) with its base.
+func (s *Server) SetConflicted(n int, conflicted bool) {
+	s.update(n, func(pr *PullRequest) { pr.Conflicted = conflicted })
+}
+
+// Close closes n without merging.
+func (s *Server) Close(n int) {
+	s.update(n, func(pr *PullRequest) { pr.State = forge.StateClosed })
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	if rest, ok := strings.CutPrefix(path, "/api/v4/projects/"+url.PathEscape(s.Repo)); ok {
+		s.serveGitLab(w, r, rest)
+		return
+	}
+	if rest, ok := strings.CutPrefix(path, "/repos/"+s.Repo); ok {
+		s.serveGitHub(w, r, rest)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// route splits a path after the repo prefix into segments.
+func route(rest string) []string {
+	return strings.Split(strings.Trim(rest, "/"), "/")
+}
+
+// open creates a pull request. Caller holds mu.
+func (s *Server) open(title, body, head, base string) *PullRequest {
+	n := len(s.prs) + 1
+	pr := &PullRequest{
+		Number:  n,
+		Title:   title,
+		Body:    body,
+		Head:    head,
+		Base:    base,
+		HeadSHA: fmt.Sprintf("%040x", n),
+		State:   forge.StateOpen,
+		Checks:  make(map[string]string),
+	}
+	s.prs[n] = pr
+	return pr
+}
+
+// find returns the open pull requests matching head and base. Caller holds mu.
+func (s *Server) find(head, base string) []*PullRequest {
+	var out []*PullRequest
+	for _, pr := range s.sorted() {
+		if pr.State == forge.StateOpen && pr.Head == head && (base == "" || pr.Base == base) {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// sorted returns pull requests by number. Caller holds mu.
+func (s *Server) sorted() []*PullRequest {
+	out := make([]*PullRequest, 0, len(s.prs))
+	for _, pr := range s.prs {
+		out = append(out, pr)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Number < out[j].Number })
+	return out
+}
+
+// byHead returns the pull request whose head is sha. Caller holds mu.
+func (s *Server) byHead(sha string) *PullRequest {
+	for _, pr := range s.prs {
+		if pr.HeadSHA == sha {
+			return pr
+		}
+	}
+	return nil
+}
+
+// merge merges pr if possible, returning an HTTP status on failure.
+// Caller holds mu.
+func (s *Server) merge(pr *PullRequest, sha, method, title string) (int, string) {
+	switch {
+	case pr.State != forge.StateOpen:
+		return http.StatusMethodNotAllowed, "Pull Request is not open"
+	case pr.Conflicted:
+		return http.StatusMethodNotAllowed, "Pull Request is not mergeable"
+	case sha != "" && sha != pr.HeadSHA:
+		return http.StatusConflict, "Head branch was modified"
+	}
+	pr.State = forge.StateMerged
+	pr.MergeCommit = fmt.Sprintf("%040x", 0xfeed0000+pr.Number)
+	pr.MergeMethod = method
+	pr.MergeTitle = title
+	return 0, ""
+}
+
+func pullNumber(seg string) (int, bool) {
+	n, err := strconv.Atoi(seg)
+	return n, err == nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// paginate returns the page of items selected by r's page and per_page
+// query parameters (default 1 and 20, as on both hosts), and the number of
+// the next page, or 0 on the last page.
+func paginate(r *http.Request, items []map[string]any) ([]map[string]any, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = 20
+	}
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	out := append([]map[string]any{}, items[start:end]...)
+	if end == len(items) {
+		return out, 0
+	}
+	return out, page + 1
+}
+
+// pageURL returns the URL of r with its page query parameter set to page.
+func pageURL(r *http.Request, page int) string {
+	q := r.URL.Query()
+	q.Set("page", strconv.Itoa(page))
+	return "http://" + r.Host + r.URL.EscapedPath() + "?" + q.Encode()
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"message": msg})
+}
+
+func decode(r *http.Request, v any) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
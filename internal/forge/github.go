@@ -0,0 +1,279 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// gitHub is the GitHub REST API (v3) adapter.
+type gitHub struct {
+	cfg *config.ForgeConfig
+	c   *client
+}
+
+func (g *gitHub) Provider() string { return config.ForgeGitHub }
+
+// GitHub API response shapes (only the fields we use).
+type (
+	ghUser struct {
+		Login string `json:"login"`
+	}
+
+	ghPull struct {
+		Number         int    `json:"number"`
+		HTMLURL        string `json:"html_url"`
+		State          string `json:"state"`
+		Merged         bool   `json:"merged"`
+		MergeCommitSHA string `json:"merge_commit_sha"`
+		MergeableState string `json:"mergeable_state"`
+		Head           struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+
+	ghCheckRuns struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+
+	ghCombinedStatus struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+
+	ghReview struct {
+		ID          int64     `json:"id"`
+		User        ghUser    `json:"user"`
+		State       string    `json:"state"`
+		Body        string    `json:"body"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	}
+
+	ghReviewComment struct {
+		ID        int64     `json:"id"`
+		User      ghUser    `json:"user"`
+		Body      string    `json:"body"`
+		Path      string    `json:"path"`
+		Line      int       `json:"line"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+func (g *gitHub) repoPath(format string, args ...any) string {
+	return "/repos/" + g.cfg.Repo + fmt.Sprintf(format, args...)
+}
+
+func (p *ghPull) pr() *PR {
+	pr := &PR{
+		Number:     p.Number,
+		URL:        p.HTMLURL,
+		State:      StateOpen,
+		HeadSHA:    p.Head.SHA,
+		Conflicted: p.MergeableState == "dirty",
+	}
+	switch {
+	case p.Merged:
+		pr.State = StateMerged
+		pr.MergeCommit = p.MergeCommitSHA
+	case p.State == "closed":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+func (g *gitHub) OpenPR(ctx context.Context, req PRRequest) (*PR, error) {
+	owner, _, _ := strings.Cut(g.cfg.Repo, "/")
+	q := url.Values{
+		"state": {"open"},
+		"head":  {owner + ":" + req.Head},
+		"base":  {req.Base},
+	}
+	var existing []ghPull
+	if err := g.c.do(ctx, http.MethodGet, g.repoPath("/pulls?%s", q.Encode()), nil, &existing); err != nil {
+		return nil, fmt.Errorf("listing pull requests: %w", err)
+	}
+	if len(existing) > 0 {
+		return existing[0].pr(), nil
+	}
+
+	body := map[string]any{
+		"title": req.Title,
+		"body":  req.Body,
+		"head":  req.Head,
+		"base":  req.Base,
+	}
+	var created ghPull
+	if err := g.c.do(ctx, http.MethodPost, g.repoPath("/pulls"), body, &created); err != nil {
+		return nil, fmt.Errorf("creating pull request: %w", err)
+	}
+	return created.pr(), nil
+}
+
+func (g *gitHub) GetPR(ctx context.Context, number int) (*PR, error) {
+	var pull ghPull
+	if err := g.c.do(ctx, http.MethodGet, g.repoPath("/pulls/%d", number), nil, &pull); err != nil {
+		return nil, fmt.Errorf("fetching pull request #%d: %w", number, err)
+	}
+	return pull.pr(), nil
+}
+
+func (g *gitHub) Gate(ctx context.Context, pr *PR) (*Gate, error) {
+	gate := &Gate{}
+
+	// Checks come from both the Checks API (Actions, most apps) and the
+	// older commit status API (many external CI systems)
+	var runs ghCheckRuns
+	if err := g.c.do(ctx, http.MethodGet, g.repoPath("/commits/%s/check-runs?per_page=100", pr.HeadSHA), nil, &runs); err != nil {
+		return nil, fmt.Errorf("fetching check runs: %w", err)
+	}
+	for _, r := range runs.CheckRuns {
+		state := CheckPending
+		if r.Status == "completed" {
+			switch r.Conclusion {
+			case "success", "neutral", "skipped":
+				state = CheckSuccess
+			default:
+				state = CheckFailure
+			}
+		}
+		gate.Checks = append(gate.Checks, Check{Name: r.Name, State: state})
+	}
+
+	var status ghCombinedStatus
+	if err := g.c.do(ctx, http.MethodGet, g.repoPath("/commits/%s/status?per_page=100", pr.HeadSHA), nil, &status); err != nil {
+		return nil, fmt.Errorf("fetching commit status: %w", err)
+	}
+	for _, s := range status.Statuses {
+		state := CheckPending
+		switch s.State {
+		case "success":
+			state = CheckSuccess
+		case "failure", "error":
+			state = CheckFailure
+		}
+		gate.Checks = append(gate.Checks, Check{Name: s.Context, State: state})
+	}
+
+	reviews, err := g.reviews(ctx, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	// Only each reviewer's latest approving or change-requesting review counts
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		switch r.State {
+		case "APPROVED", "CHANGES_REQUESTED":
+			latest[r.User.Login] = r.State
+		case "DISMISSED":
+			delete(latest, r.User.Login)
+		}
+	}
+	for user, state := range latest {
+		if state == "APPROVED" {
+			gate.Approvals++
+		} else {
+			gate.ChangesRequested = append(gate.ChangesRequested, user)
+		}
+	}
+	sort.Strings(gate.ChangesRequested)
+	return gate, nil
+}
+
+func (g *gitHub) reviews(ctx context.Context, number int) ([]ghReview, error) {
+	reviews, err := getAll[ghReview](ctx, g.c, g.repoPath("/pulls/%d/reviews?per_page=100", number))
+	if err != nil {
+		return nil, fmt.Errorf("fetching reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+func (g *gitHub) Merge(ctx context.Context, pr *PR, commitTitle string) (string, error) {
+	body := map[string]any{
+		"merge_method": mergeMethod(g.cfg),
+		"sha":          pr.HeadSHA, // Refuse if the head moved since the gate passed
+	}
+	if commitTitle != "" {
+		body["commit_title"] = commitTitle
+	}
+	var out struct {
+		SHA    string `json:"sha"`
+		Merged bool   `json:"merged"`
+	}
+	err := g.c.do(ctx, http.MethodPut, g.repoPath("/pulls/%d/merge", pr.Number), body, &out)
+	switch status := statusOf(err); {
+	case status == http.StatusMethodNotAllowed || status == http.StatusConflict:
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	case err != nil:
+		return "", fmt.Errorf("merging pull request #%d: %w", pr.Number, err)
+	case !out.Merged:
+		return "", fmt.Errorf("%w: pull request #%d was not merged", ErrNotMergeable, pr.Number)
+	}
+	return out.SHA, nil
+}
+
+func (g *gitHub) ReviewComments(ctx context.Context, number int) ([]Comment, error) {
+	lineComments, err := getAll[ghReviewComment](ctx, g.c, g.repoPath("/pulls/%d/comments?per_page=100", number))
+	if err != nil {
+		return nil, fmt.Errorf("fetching review comments: %w", err)
+	}
+	reviews, err := g.reviews(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []Comment
+	for _, c := range lineComments {
+		comments = append(comments, Comment{
+			ID:        "c" + strconv.FormatInt(c.ID, 10),
+			Author:    c.User.Login,
+			Body:      c.Body,
+			Path:      c.Path,
+			Line:      c.Line,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	for _, r := range reviews {
+		// Approvals and plain comments with a summary body aren't rework;
+		// their line comments (if any) are already listed above
+		if r.State != "CHANGES_REQUESTED" || strings.TrimSpace(r.Body) == "" {
+			continue
+		}
+		comments = append(comments, Comment{
+			ID:        "r" + strconv.FormatInt(r.ID, 10),
+			Author:    r.User.Login,
+			Body:      r.Body,
+			CreatedAt: r.SubmittedAt,
+		})
+	}
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+	return comments, nil
+}
+
+// ghNextPage follows the Link header's rel="next" URL. Links off the API
+// base URL aren't followed, so the token never goes to another host.
+func (c *client) ghNextPage(_ string, h http.Header) string {
+	for _, link := range strings.Split(h.Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		target = strings.Trim(strings.TrimSpace(target), "<>")
+		if path, ok := strings.CutPrefix(target, c.baseURL+"/"); ok {
+			return "/" + path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,279 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// gitLab is the GitLab REST API (v4) adapter.
+type gitLab struct {
+	cfg *config.ForgeConfig
+	c   *client
+}
+
+func (g *gitLab) Provider() string { return config.ForgeGitLab }
+
+// GitLab API response shapes (only the fields we use).
+type (
+	glUser struct {
+		Username string `json:"username"`
+	}
+
+	glMergeRequest struct {
+		IID             int    `json:"iid"`
+		WebURL          string `json:"web_url"`
+		State           string `json:"state"`
+		SHA             string `json:"sha"`
+		MergeCommitSHA  string `json:"merge_commit_sha"`
+		SquashCommitSHA string `json:"squash_commit_sha"`
+		HasConflicts    bool   `json:"has_conflicts"`
+		HeadPipeline    *struct {
+			ID     int    `json:"id"`
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+
+	glJob struct {
+		Name         string `json:"name"`
+		Status       string `json:"status"`
+		AllowFailure bool   `json:"allow_failure"`
+	}
+
+	glApprovals struct {
+		ApprovedBy []struct {
+			User glUser `json:"user"`
+		} `json:"approved_by"`
+	}
+
+	glNote struct {
+		ID         int64     `json:"id"`
+		Body       string    `json:"body"`
+		Author     glUser    `json:"author"`
+		CreatedAt  time.Time `json:"created_at"`
+		System     bool      `json:"system"`
+		Resolvable bool      `json:"resolvable"`
+		Resolved   bool      `json:"resolved"`
+		Position   *struct {
+			NewPath string `json:"new_path"`
+			NewLine int    `json:"new_line"`
+		} `json:"position"`
+	}
+)
+
+func (g *gitLab) projectPath(format string, args ...any) string {
+	return "/projects/" + url.PathEscape(g.cfg.Repo) + fmt.Sprintf(format, args...)
+}
+
+func (m *glMergeRequest) pr() *PR {
+	pr := &PR{
+		Number:     m.IID,
+		URL:        m.WebURL,
+		State:      StateOpen,
+		HeadSHA:    m.SHA,
+		Conflicted: m.HasConflicts,
+	}
+	switch m.State {
+	case "merged":
+		pr.State = StateMerged
+		pr.MergeCommit = m.SquashCommitSHA
+		if pr.MergeCommit == "" {
+			pr.MergeCommit = m.MergeCommitSHA
+		}
+		if pr.MergeCommit == "" {
+			// Fast-forward merges create no new commit
+			pr.MergeCommit = m.SHA
+		}
+	case "closed", "locked":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+func (g *gitLab) OpenPR(ctx context.Context, req PRRequest) (*PR, error) {
+	q := url.Values{
+		"state":         {"opened"},
+		"source_branch": {req.Head},
+		"target_branch": {req.Base},
+	}
+	var existing []glMergeRequest
+	if err := g.c.do(ctx, http.MethodGet, g.projectPath("/merge_requests?%s", q.Encode()), nil, &existing); err != nil {
+		return nil, fmt.Errorf("listing merge requests: %w", err)
+	}
+	if len(existing) > 0 {
+		return existing[0].pr(), nil
+	}
+
+	body := map[string]any{
+		"title":         req.Title,
+		"description":   req.Body,
+		"source_branch": req.Head,
+		"target_branch": req.Base,
+	}
+	var created glMergeRequest
+	if err := g.c.do(ctx, http.MethodPost, g.projectPath("/merge_requests"), body, &created); err != nil {
+		return nil, fmt.Errorf("creating merge request: %w", err)
+	}
+	return created.pr(), nil
+}
+
+func (g *gitLab) GetPR(ctx context.Context, number int) (*PR, error) {
+	mr, err := g.mergeRequest(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return mr.pr(), nil
+}
+
+func (g *gitLab) mergeRequest(ctx context.Context, iid int) (*glMergeRequest, error) {
+	var mr glMergeRequest
+	if err := g.c.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d", iid), nil, &mr); err != nil {
+		return nil, fmt.Errorf("fetching merge request !%d: %w", iid, err)
+	}
+	return &mr, nil
+}
+
+func (g *gitLab) Gate(ctx context.Context, pr *PR) (*Gate, error) {
+	mr, err := g.mergeRequest(ctx, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	gate := &Gate{}
+	if p := mr.HeadPipeline; p != nil {
+		// Each job is a check; the pipeline as a whole is the "pipeline" check
+		gate.Checks = append(gate.Checks, Check{Name: "pipeline", State: glCheckState(p.Status)})
+		var jobs []glJob
+		if err := g.c.do(ctx, http.MethodGet, g.projectPath("/pipelines/%d/jobs?per_page=100", p.ID), nil, &jobs); err != nil {
+			return nil, fmt.Errorf("fetching pipeline jobs: %w", err)
+		}
+		for _, j := range jobs {
+			state := glCheckState(j.Status)
+			if state == CheckFailure && j.AllowFailure {
+				state = CheckSuccess
+			}
+			gate.Checks = append(gate.Checks, Check{Name: j.Name, State: state})
+		}
+	}
+
+	var approvals glApprovals
+	if err := g.c.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d/approvals", pr.Number), nil, &approvals); err != nil {
+		return nil, fmt.Errorf("fetching approvals: %w", err)
+	}
+	gate.Approvals = len(approvals.ApprovedBy)
+
+	// GitLab has no "request changes" review; unresolved threads play that role
+	notes, err := g.unresolvedNotes(ctx, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, n := range notes {
+		if !seen[n.Author.Username] {
+			seen[n.Author.Username] = true
+			gate.ChangesRequested = append(gate.ChangesRequested, n.Author.Username)
+		}
+	}
+	sort.Strings(gate.ChangesRequested)
+	return gate, nil
+}
+
+// glCheckState maps a GitLab pipeline or job status to a check state.
+func glCheckState(status string) string {
+	switch status {
+	case "success", "skipped", "manual":
+		return CheckSuccess
+	case "failed", "canceled":
+		return CheckFailure
+	default:
+		return CheckPending
+	}
+}
+
+func (g *gitLab) unresolvedNotes(ctx context.Context, iid int) ([]glNote, error) {
+	notes, err := getAll[glNote](ctx, g.c, g.projectPath("/merge_requests/%d/notes?sort=asc&order_by=created_at&per_page=100", iid))
+	if err != nil {
+		return nil, fmt.Errorf("fetching notes: %w", err)
+	}
+	var out []glNote
+	for _, n := range notes {
+		if !n.System && n.Resolvable && !n.Resolved {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+func (g *gitLab) Merge(ctx context.Context, pr *PR, commitTitle string) (string, error) {
+	body := map[string]any{
+		"sha": pr.HeadSHA, // Refuse if the head moved since the gate passed
+	}
+	switch mergeMethod(g.cfg) {
+	case "squash":
+		body["squash"] = true
+		if commitTitle != "" {
+			body["squash_commit_message"] = commitTitle
+		}
+	case "merge":
+		if commitTitle != "" {
+			body["merge_commit_message"] = commitTitle
+		}
+	}
+	// "rebase" uses the project's merge method (fast-forward projects rebase)
+
+	var merged glMergeRequest
+	err := g.c.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d/merge", pr.Number), body, &merged)
+	switch status := statusOf(err); {
+	case status == http.StatusMethodNotAllowed || status == http.StatusConflict || status == http.StatusNotAcceptable || status == http.StatusUnprocessableEntity:
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	case err != nil:
+		return "", fmt.Errorf("merging merge request !%d: %w", pr.Number, err)
+	}
+	result := merged.pr()
+	if result.State != StateMerged {
+		return "", fmt.Errorf("%w: merge request !%d is %s", ErrNotMergeable, pr.Number, merged.State)
+	}
+	return result.MergeCommit, nil
+}
+
+func (g *gitLab) ReviewComments(ctx context.Context, number int) ([]Comment, error) {
+	notes, err := g.unresolvedNotes(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]Comment, 0, len(notes))
+	for _, n := range notes {
+		c := Comment{
+			ID:        "n" + strconv.FormatInt(n.ID, 10),
+			Author:    n.Author.Username,
+			Body:      strings.TrimSpace(n.Body),
+			CreatedAt: n.CreatedAt,
+		}
+		if n.Position != nil {
+			c.Path = n.Position.NewPath
+			c.Line = n.Position.NewLine
+		}
+		comments = append(comments, c)
+	}
+	return comments, nil
+}
+
+// glNextPage sets the page query parameter to the X-Next-Page header, which
+// GitLab leaves empty on the last page.
+func glNextPage(path string, h http.Header) string {
+	next := h.Get("X-Next-Page")
+	if next == "" {
+		return ""
+	}
+	base, query, _ := strings.Cut(path, "?")
+	q, _ := url.ParseQuery(query)
+	q.Set("page", next)
+	return base + "?" + q.Encode()
+}
@@ -3,6 +3,7 @@ package git
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -614,6 +615,12 @@ func (g *Git) Rebase(onto string) error {
 	return err
 }
 
+// ResetHard resets the current branch, index and working tree to ref.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	return err
+}
+
 // AbortMerge aborts a merge in progress.
 func (g *Git) AbortMerge() error {
 	_, err := g.run("merge", "--abort")
@@ -657,6 +664,42 @@ func (g *Git) CheckConflicts(source, target string) ([]string, error) {
 	return nil, nil
 }
 
+// MergeTreeConflicts reports the files that would conflict when merging
+// source into target, without touching the index or working tree. It runs
+// `git merge-tree --write-tree`, which only writes objects to the object
+// store, so it is safe to call in a worktree that is in active use.
+func (g *Git) MergeTreeConflicts(source, target string) ([]string, error) {
+	args := []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", target, source}
+	if g.gitDir != "" {
+		args = append([]string{"--git-dir=" + g.gitDir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.workDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return nil, nil
+	}
+	// Exit status 1 means the merge has conflicts; the first line is the
+	// tree OID and the conflicted paths follow
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		return nil, g.wrapError(err, stdout.String(), stderr.String(), args)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	var conflicts []string
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" {
+			conflicts = append(conflicts, line)
+		}
+	}
+	return conflicts, nil
+}
+
 // runMergeCheck runs a git merge command and returns error info from both stdout and stderr.
 // ZFC: Returns GitError with raw output for agent observation.
 func (g *Git) runMergeCheck(args ...string) (string, error) {
@@ -1133,6 +1176,18 @@ func (g *Git) StashCount() (int, error) {
 	return count, nil
 }
 
+// StashPush stashes uncommitted changes, including untracked files.
+func (g *Git) StashPush(message string) error {
+	_, err := g.run("stash", "push", "--include-untracked", "-m", message)
+	return err
+}
+
+// StashPop applies and drops the most recent stash.
+func (g *Git) StashPop() error {
+	_, err := g.run("stash", "pop")
+	return err
+}
+
 // UnpushedCommits returns the number of commits that are not pushed to the remote.
 // It checks if the current branch has an upstream and counts commits ahead.
 // Returns 0 if there is no upstream configured.
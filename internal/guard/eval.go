@@ -0,0 +1,458 @@
+package guard
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Vars are the values substituted for $VARIABLES in rule patterns.
+type Vars struct {
+	Worktree      string
+	DefaultBranch string
+	Rig           string
+	Role          string
+	TownRoot      string
+}
+
+// Request is a tool call to evaluate.
+type Request struct {
+	Tool    string   // Claude Code tool name (Bash, Edit, Write, ...)
+	Command string   // Bash command line, if any
+	Paths   []string // Files the tool touches (absolute or relative to Cwd)
+	Cwd     string   // Working directory of the tool call
+	Role    string   // Agent role type, or "human"
+	Rig     string   // Rig name, if any
+	Vars    Vars
+}
+
+// Decision is the outcome of evaluating a request.
+type Decision struct {
+	Action string // deny, warn or allow
+	Rule   *Rule  // Matching rule (nil if none matched)
+
+	// Unresolved lists the $VARIABLES the deny rule uses that have no value
+	// for this request. The rule couldn't be fully evaluated, so the call
+	// was denied on its other conditions alone.
+	Unresolved []string
+}
+
+// Blocked reports whether the tool call must be blocked.
+func (d Decision) Blocked() bool {
+	return d.Action == ActionDeny
+}
+
+// Evaluate returns the decision of the first matching rule.
+// Requests no rule matches are allowed.
+//
+// A rule that uses a variable with no value (e.g. $WORKTREE outside a git
+// worktree) can't be matched as written: the empty value would widen or
+// narrow its patterns ("$WORKTREE/**" would become "/**"). A deny rule
+// fails closed, denying the call if its conditions match with each
+// unresolved pattern treated as matching anything; other rules are skipped.
+func (p *Policy) Evaluate(req Request) Decision {
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if missing := rule.Match.unresolvedVars(req.Vars); len(missing) > 0 {
+			if rule.Action != ActionDeny {
+				continue
+			}
+			if relaxed := rule.Match.withWildcards(missing); relaxed.matches(req) {
+				return Decision{Action: ActionDeny, Rule: rule, Unresolved: missing}
+			}
+			continue
+		}
+		if rule.Match.matches(req) {
+			return Decision{Action: rule.Action, Rule: rule}
+		}
+	}
+	return Decision{Action: ActionAllow}
+}
+
+// hookInput is the PreToolUse payload Claude Code writes to the hook's stdin.
+type hookInput struct {
+	ToolName  string          `json:"tool_name"`
+	ToolInput json.RawMessage `json:"tool_input"`
+	Cwd       string          `json:"cwd"`
+}
+
+// toolInput holds the tool_input fields guards look at.
+type toolInput struct {
+	Command      string `json:"command"`
+	FilePath     string `json:"file_path"`
+	NotebookPath string `json:"notebook_path"`
+	Path         string `json:"path"`
+}
+
+// ParseHookInput builds a request from PreToolUse hook JSON.
+// Role, rig and vars are left for the caller to fill in.
+func ParseHookInput(data []byte) (Request, error) {
+	var in hookInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		return Request{}, err
+	}
+
+	req := Request{Tool: in.ToolName, Cwd: in.Cwd}
+	var ti toolInput
+	if len(in.ToolInput) > 0 {
+		// tool_input is an object; tolerate anything else as "no input"
+		_ = json.Unmarshal(in.ToolInput, &ti)
+	}
+	req.Command = ti.Command
+	for _, p := range []string{ti.FilePath, ti.NotebookPath, ti.Path} {
+		if p != "" {
+			req.Paths = append(req.Paths, p)
+		}
+	}
+	return req, nil
+}
+
+// applies reports whether the rule's tool, role and rig selectors match.
+func (m *Match) applies(req Request) bool {
+	if len(m.Tools) > 0 && !anyGlob(m.Tools, req.Tool) {
+		return false
+	}
+	if len(m.Roles) > 0 && !anyGlob(m.Roles, req.Role) {
+		return false
+	}
+	if len(m.Rigs) > 0 && !anyGlob(m.Rigs, req.Rig) {
+		return false
+	}
+	return true
+}
+
+// unresolvedVars returns the variables the rule's patterns reference that
+// have no value in vars.
+func (m *Match) unresolvedVars(vars Vars) []string {
+	patterns := append([]string{m.Command}, m.Argv...)
+	patterns = append(patterns, m.Args...)
+	patterns = append(patterns, m.Paths...)
+	patterns = append(patterns, m.PathsOutside...)
+
+	values := vars.values()
+	var missing []string
+	for _, name := range varNames {
+		if values[name] != "" {
+			continue
+		}
+		for _, pattern := range patterns {
+			if strings.Contains(pattern, name) {
+				missing = append(missing, name)
+				break
+			}
+		}
+	}
+	return missing
+}
+
+// withWildcards returns a copy of m with every pattern that references one
+// of the missing variables replaced by one that matches anything. The
+// conditions still require a command, argument or path to be present.
+func (m *Match) withWildcards(missing []string) Match {
+	uses := func(pattern string) bool {
+		for _, name := range missing {
+			if strings.Contains(pattern, name) {
+				return true
+			}
+		}
+		return false
+	}
+	anyUses := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if uses(pattern) {
+				return true
+			}
+		}
+		return false
+	}
+
+	out := *m
+	if uses(m.Command) {
+		out.Command = ".*"
+	}
+	out.Argv = make([]string, len(m.Argv))
+	for i, pattern := range m.Argv {
+		out.Argv[i] = pattern
+		if uses(pattern) {
+			out.Argv[i] = "*"
+		}
+	}
+	if anyUses(m.Args) {
+		out.Args = []string{"*"}
+	}
+	if anyUses(m.Paths) {
+		out.Paths = []string{"**"}
+	}
+	if anyUses(m.PathsOutside) {
+		// Whether a path is outside an unknown set can't be told
+		out.PathsOutside = nil
+		if len(out.Paths) == 0 {
+			out.Paths = []string{"**"}
+		}
+	}
+	return out
+}
+
+func (m *Match) matches(req Request) bool {
+	if !m.applies(req) {
+		return false
+	}
+	if m.Command != "" || len(m.Argv) > 0 || len(m.Args) > 0 {
+		if !m.matchesCommand(req) {
+			return false
+		}
+	}
+	if len(m.Paths) > 0 || len(m.PathsOutside) > 0 {
+		if !m.matchesPaths(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesCommand reports whether any segment of the command line
+// (split on ;, &&, || and |) satisfies the command conditions.
+func (m *Match) matchesCommand(req Request) bool {
+	if req.Command == "" {
+		return false
+	}
+
+	var re *regexp.Regexp
+	if m.Command != "" {
+		var err error
+		if re, err = regexp.Compile(expandVars(m.Command, req.Vars, true)); err != nil {
+			return false
+		}
+	}
+
+	for _, segment := range splitCommand(req.Command) {
+		if re != nil && !re.MatchString(segment) {
+			continue
+		}
+		argv := tokenize(segment)
+		if len(m.Argv) > len(argv) {
+			continue
+		}
+		prefix := true
+		for i, pattern := range m.Argv {
+			if !globMatch(expandVars(pattern, req.Vars, false), argv[i]) {
+				prefix = false
+				break
+			}
+		}
+		if !prefix {
+			continue
+		}
+		if len(m.Args) > 0 && !anyArg(m.Args, argv[len(m.Argv):], req.Vars) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func anyArg(patterns, args []string, vars Vars) bool {
+	for _, arg := range args {
+		for _, pattern := range patterns {
+			if globMatch(expandVars(pattern, vars, false), arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesPaths reports whether a touched path satisfies the path conditions.
+func (m *Match) matchesPaths(req Request) bool {
+	for _, p := range req.Paths {
+		if !filepath.IsAbs(p) && req.Cwd != "" {
+			p = filepath.Join(req.Cwd, p)
+		}
+		p = filepath.ToSlash(filepath.Clean(p))
+
+		if len(m.Paths) > 0 && !anyPath(m.Paths, p, req.Vars) {
+			continue
+		}
+		if len(m.PathsOutside) > 0 && anyPath(m.PathsOutside, p, req.Vars) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func anyPath(patterns []string, p string, vars Vars) bool {
+	for _, pattern := range patterns {
+		for _, alt := range strings.Split(expandVars(pattern, vars, false), "|") {
+			re, err := globRegexp(alt)
+			if err == nil && re.MatchString(p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyGlob(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if globMatch(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches s against a glob; "a|b" matches either alternative.
+func globMatch(pattern, s string) bool {
+	for _, alt := range strings.Split(pattern, "|") {
+		if ok, err := path.Match(alt, s); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// varNames are the $VARIABLES patterns may use.
+var varNames = []string{"$WORKTREE", "$DEFAULT_BRANCH", "$RIG", "$ROLE", "$TOWN_ROOT"}
+
+func (v Vars) values() map[string]string {
+	return map[string]string{
+		"$WORKTREE":       v.Worktree,
+		"$DEFAULT_BRANCH": v.DefaultBranch,
+		"$RIG":            v.Rig,
+		"$ROLE":           v.Role,
+		"$TOWN_ROOT":      v.TownRoot,
+	}
+}
+
+// expandVars substitutes $VARIABLES. For regexes the values are quoted.
+func expandVars(pattern string, vars Vars, quote bool) string {
+	for name, value := range vars.values() {
+		if quote {
+			value = regexp.QuoteMeta(value)
+		}
+		pattern = strings.ReplaceAll(pattern, name, value)
+	}
+	return pattern
+}
+
+// globRegexp compiles a path glob: "**" matches across directories, "*" and
+// "?" within one. Relative patterns match at any depth.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = filepath.ToSlash(pattern)
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.HasPrefix(pattern, "/") {
+		b.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			i++
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				// "**/" matches zero or more directories
+				i++
+				b.WriteString("(.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// A directory pattern also covers everything beneath it
+	b.WriteString("(/.*)?$")
+	return regexp.Compile(b.String())
+}
+
+// splitCommand splits a shell command line into simple commands on
+// ;, &&, || and |, ignoring separators inside quotes.
+func splitCommand(cmd string) []string {
+	var segments []string
+	var cur strings.Builder
+	var quote byte
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			segments = append(segments, s)
+		}
+		cur.Reset()
+	}
+
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			cur.WriteByte(c)
+		case c == '\'' || c == '"':
+			quote = c
+			cur.WriteByte(c)
+		case c == '&' && ((i > 0 && cmd[i-1] == '>') || (i+1 < len(cmd) && cmd[i+1] == '>')):
+			// Redirections like 2>&1 and &>file
+			cur.WriteByte(c)
+		case c == ';' || c == '\n' || c == '|' || c == '&':
+			if (c == '|' || c == '&') && i+1 < len(cmd) && cmd[i+1] == c {
+				i++
+			}
+			flush()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	flush()
+	return segments
+}
+
+// tokenize splits a simple command into argv, honoring quotes and
+// dropping leading VAR=value assignments.
+func tokenize(segment string) []string {
+	var argv []string
+	var cur strings.Builder
+	var quote byte
+	inToken := false
+
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inToken = true
+		case c == '\\' && i+1 < len(segment):
+			i++
+			cur.WriteByte(segment[i])
+			inToken = true
+		case c == ' ' || c == '\t':
+			if inToken {
+				argv = append(argv, cur.String())
+				cur.Reset()
+				inToken = false
+			}
+		default:
+			cur.WriteByte(c)
+			inToken = true
+		}
+	}
+	if inToken {
+		argv = append(argv, cur.String())
+	}
+
+	for len(argv) > 0 && strings.Contains(argv[0], "=") && !strings.HasPrefix(argv[0], "-") {
+		argv = argv[1:]
+	}
+	return argv
+}
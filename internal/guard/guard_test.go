@@ -0,0 +1,181 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testPolicy = `{
+  "version": 1,
+  "rules": [
+    {"name": "no-force-push-main", "action": "deny",
+     "match": {"tools": ["Bash"], "argv": ["git", "push"], "args": ["-f", "--force", "+*"], "command": "\\b$DEFAULT_BRANCH\\b"}},
+    {"name": "stay-in-worktree", "action": "deny",
+     "match": {"tools": ["Edit|Write"], "roles": ["polecat"], "paths_outside": ["$WORKTREE/**", "/tmp/**"]}},
+    {"name": "no-migrations", "action": "deny",
+     "match": {"tools": ["Edit", "Write"], "paths": ["migrations/**"]}},
+    {"name": "no-network", "action": "warn", "message": "network",
+     "match": {"roles": ["polecat"], "argv": ["curl|wget"]}}
+  ]
+}`
+
+func loadTestPolicy(t *testing.T) *Policy {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(PolicyPath(dir), []byte(testPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(dir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return p
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	p := loadTestPolicy(t)
+	vars := Vars{Worktree: "/gt/gastown/polecats/nux/gastown", DefaultBranch: "main"}
+
+	tests := []struct {
+		name string
+		req  Request
+		want string // rule name, "" for no match
+	}{
+		{"force push main", Request{Tool: "Bash", Command: "cd x && git push --force origin main"}, "no-force-push-main"},
+		{"force refspec", Request{Tool: "Bash", Command: "git push origin +main"}, "no-force-push-main"},
+		{"plain push main", Request{Tool: "Bash", Command: "git push origin main"}, ""},
+		{"force push branch", Request{Tool: "Bash", Command: "git push -f origin polecat/nux"}, ""},
+		{"quoted separator", Request{Tool: "Bash", Command: `echo "a; git push -f origin main"`}, ""},
+		{"edit outside worktree", Request{Tool: "Edit", Role: "polecat", Paths: []string{"/gt/gastown/mayor/rig/x.go"}}, "stay-in-worktree"},
+		{"edit inside worktree", Request{Tool: "Edit", Role: "polecat", Cwd: "/gt/gastown/polecats/nux/gastown", Paths: []string{"internal/x.go"}}, ""},
+		{"crew may edit outside", Request{Tool: "Edit", Role: "crew", Paths: []string{"/etc/hosts"}}, ""},
+		{"migrations", Request{Tool: "Write", Role: "crew", Paths: []string{"/repo/db/migrations/001.sql"}}, "no-migrations"},
+		{"network", Request{Tool: "Bash", Role: "polecat", Command: "FOO=1 wget http://x"}, "no-network"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Vars = vars
+			d := p.Evaluate(tt.req)
+			got := ""
+			if d.Rule != nil {
+				got = d.Rule.Name
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() rule = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_EvaluateUnresolvedVar(t *testing.T) {
+	p := loadTestPolicy(t)
+	// Outside a git worktree $WORKTREE has no value
+	vars := Vars{DefaultBranch: "main"}
+
+	d := p.Evaluate(Request{Tool: "Edit", Role: "polecat", Paths: []string{"/etc/hosts"}, Vars: vars})
+	if !d.Blocked() || d.Rule == nil || d.Rule.Name != "stay-in-worktree" {
+		t.Fatalf("Evaluate() = %+v, want stay-in-worktree to deny", d)
+	}
+	if !reflect.DeepEqual(d.Unresolved, []string{"$WORKTREE"}) {
+		t.Errorf("Unresolved = %v, want [$WORKTREE]", d.Unresolved)
+	}
+
+	// Rules that don't apply to the call aren't affected
+	d = p.Evaluate(Request{Tool: "Edit", Role: "crew", Paths: []string{"/etc/hosts"}, Vars: vars})
+	if d.Blocked() || d.Unresolved != nil {
+		t.Errorf("crew edit = %+v, want allowed", d)
+	}
+}
+
+func TestPolicy_EvaluateUnresolvedVarOtherConditions(t *testing.T) {
+	p := &Policy{Rules: []Rule{
+		{Name: "no-push-rig", Action: ActionDeny, Match: Match{Tools: []string{"Bash"}, Argv: []string{"git", "push"}, Args: []string{"$RIG/*"}}},
+		{Name: "warn-rig-rm", Action: ActionWarn, Match: Match{Tools: []string{"Bash"}, Argv: []string{"rm"}, Args: []string{"$RIG/*"}}},
+	}}
+	for _, tt := range []struct {
+		command    string
+		action     string
+		unresolved bool
+	}{
+		{"git push origin main", ActionDeny, true}, // Fails closed on the argv it can check
+		{"go test ./...", ActionAllow, false},      // Doesn't match the deny rule's other conditions
+		{"ls", ActionAllow, false},
+		{"rm -rf x", ActionAllow, false}, // Warn rules with unresolved variables are skipped
+	} {
+		d := p.Evaluate(Request{Tool: "Bash", Command: tt.command})
+		if d.Action != tt.action || (d.Unresolved != nil) != tt.unresolved {
+			t.Errorf("Evaluate(%q) = %+v, want %s", tt.command, d, tt.action)
+		}
+	}
+
+	// With the variable set, the warn rule matches as written
+	d := p.Evaluate(Request{Tool: "Bash", Command: "rm -rf gastown/x", Vars: Vars{Rig: "gastown"}})
+	if d.Action != ActionWarn {
+		t.Errorf("Evaluate(rm) with $RIG = %+v, want warn", d)
+	}
+}
+
+func TestLoad_RigRulesFirst(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+	for dir, content := range map[string]string{
+		town: `{"rules": [{"name": "town-deny", "action": "deny", "match": {"argv": ["rm"]}}]}`,
+		rig:  `{"rules": [{"name": "rig-allow", "action": "allow", "match": {"argv": ["rm"]}}]}`,
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, "settings"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(PolicyPath(dir), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := Load(town, rig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := p.Evaluate(Request{Tool: "Bash", Command: "rm -rf build"})
+	if d.Blocked() || d.Rule == nil || d.Rule.Name != "rig-allow" {
+		t.Errorf("Evaluate() = %+v, want rig-allow", d)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guards.json")
+	for _, content := range []string{
+		`{"rules": [{"name": "x", "action": "block"}]}`,
+		`{"rules": [{"name": "x", "action": "deny", "match": {"command": "("}}]}`,
+		`not json`,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("LoadFile(%s) expected error", content)
+		}
+	}
+}
+
+func TestParseHookInput(t *testing.T) {
+	req, err := ParseHookInput([]byte(`{"tool_name": "Edit", "cwd": "/w", "tool_input": {"file_path": "a.go", "old_string": "x"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Tool != "Edit" || req.Cwd != "/w" || !reflect.DeepEqual(req.Paths, []string{"a.go"}) {
+		t.Errorf("ParseHookInput() = %+v", req)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize(`GIT_DIR=x git commit -m "fix: a b" it\'s`)
+	want := []string{"git", "commit", "-m", "fix: a b", "it's"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize() = %q, want %q", got, want)
+	}
+}
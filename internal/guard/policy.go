@@ -0,0 +1,159 @@
+// Package guard evaluates declarative tool-use policies for gt tap guard.
+//
+// Policies are ordered rule lists loaded from the rig's and the town's
+// settings/guards.json. Each rule matches on the tool being used, the Bash
+// command and its argv, the file paths a tool touches, and the agent's role
+// and rig; the first matching rule decides whether the tool call is allowed,
+// allowed with a warning, or denied.
+package guard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Rule actions.
+const (
+	// ActionDeny blocks the tool call.
+	ActionDeny = "deny"
+
+	// ActionWarn allows the tool call but prints the rule's message.
+	ActionWarn = "warn"
+
+	// ActionAllow allows the tool call and stops evaluation, so a rig rule
+	// can carve out an exception to a town rule.
+	ActionAllow = "allow"
+)
+
+// PolicyFile is the guard policy file name within a settings directory.
+const PolicyFile = "guards.json"
+
+// PolicyPath returns the guard policy path for a town or rig directory.
+func PolicyPath(dir string) string {
+	return filepath.Join(dir, "settings", PolicyFile)
+}
+
+// Match describes which tool calls a rule applies to.
+// All non-empty conditions must hold; list conditions match if any entry does.
+//
+// Globs may list alternatives separated by "|" (e.g. "curl|wget").
+// Patterns may reference $WORKTREE, $DEFAULT_BRANCH, $RIG, $ROLE and
+// $TOWN_ROOT, which are expanded from the request before matching. A deny
+// rule using a variable with no value denies on its other conditions; other
+// rules using one are skipped (see Policy.Evaluate).
+type Match struct {
+	// Tools are tool name globs (e.g. "Bash", "Edit", "mcp__*").
+	Tools []string `json:"tools,omitempty"`
+
+	// Command is a regex matched against each Bash command segment.
+	Command string `json:"command,omitempty"`
+
+	// Argv is a glob per leading argv token, e.g. ["git", "push"].
+	Argv []string `json:"argv,omitempty"`
+
+	// Args are globs; at least one argument after Argv must match one.
+	Args []string `json:"args,omitempty"`
+
+	// Paths are globs; at least one touched path must match one.
+	// "**" spans directories; relative patterns match at any depth.
+	Paths []string `json:"paths,omitempty"`
+
+	// PathsOutside are globs; at least one touched path must match none of them.
+	PathsOutside []string `json:"paths_outside,omitempty"`
+
+	// Roles are agent role types (polecat, crew, witness, refinery, mayor,
+	// deacon). "human" matches calls made outside an agent session.
+	Roles []string `json:"roles,omitempty"`
+
+	// Rigs are rig name globs.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// Rule is one policy entry.
+type Rule struct {
+	Name    string `json:"name"`
+	Action  string `json:"action"`
+	Message string `json:"message,omitempty"`
+	Match   Match  `json:"match"`
+
+	// Source is the policy file the rule came from.
+	Source string `json:"-"`
+}
+
+// Policy is an ordered list of rules. The first matching rule wins.
+type Policy struct {
+	Version int    `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// LoadFile loads and validates a policy file. A missing file yields an
+// empty policy.
+func LoadFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally or given by the operator
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Policy{}, nil
+		}
+		return nil, fmt.Errorf("reading guard policy: %w", err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing guard policy %s: %w", path, err)
+	}
+	for i := range p.Rules {
+		p.Rules[i].Source = path
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid guard policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Load loads the rig policy followed by the town policy, so rig rules are
+// evaluated first. rigPath may be empty outside a rig.
+func Load(townRoot, rigPath string) (*Policy, error) {
+	merged := &Policy{Version: 1}
+	for _, dir := range []string{rigPath, townRoot} {
+		if dir == "" {
+			continue
+		}
+		p, err := LoadFile(PolicyPath(dir))
+		if err != nil {
+			return nil, err
+		}
+		merged.Rules = append(merged.Rules, p.Rules...)
+	}
+	return merged, nil
+}
+
+// Validate checks rule actions and patterns.
+func (p *Policy) Validate() error {
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		switch r.Action {
+		case ActionDeny, ActionWarn, ActionAllow:
+		default:
+			return fmt.Errorf("rule %s: invalid action %q (want deny, warn or allow)", r.Name, r.Action)
+		}
+		if r.Match.Command != "" {
+			// Variables expand to quoted literals, so a placeholder compiles the same way.
+			if _, err := regexp.Compile(expandVars(r.Match.Command, Vars{}, true)); err != nil {
+				return fmt.Errorf("rule %s: command: %w", r.Name, err)
+			}
+		}
+		for _, pattern := range append(append([]string{}, r.Match.Paths...), r.Match.PathsOutside...) {
+			if _, err := globRegexp(expandVars(pattern, Vars{}, false)); err != nil {
+				return fmt.Errorf("rule %s: path %q: %w", r.Name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package mail
 
 import (
-	"bytes"
-	"os/exec"
+	"context"
+	"os"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 // bdError represents an error from running a bd command.
@@ -40,23 +42,24 @@ func (e *bdError) ContainsError(substr string) bool {
 // extraEnv contains additional environment variables to set (e.g., "BD_IDENTITY=...").
 // Returns stdout bytes on success, or a *bdError on failure.
 func runBdCommand(args []string, workDir, beadsDir string, extraEnv ...string) ([]byte, error) {
-	cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Dir = workDir
-
-	env := append(cmd.Environ(), "BEADS_DIR="+beadsDir)
+	env := os.Environ()
+	if workDir != "" {
+		env = append(env, "PWD="+workDir)
+	}
+	env = append(env, "BEADS_DIR="+beadsDir)
 	env = append(env, extraEnv...)
-	cmd.Env = env
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	stdout, stderr, err := beads.Exec(context.Background(), beads.Command{
+		Dir:  workDir,
+		Args: args,
+		Env:  env,
+	})
+	if err != nil {
 		return nil, &bdError{
 			Err:    err,
-			Stderr: strings.TrimSpace(stderr.String()),
+			Stderr: strings.TrimSpace(string(stderr)),
 		}
 	}
 
-	return stdout.Bytes(), nil
+	return stdout, nil
 }
@@ -211,7 +211,8 @@ func (m *Mailbox) identityVariants() []string {
 }
 
 // queryMessages runs a bd list query with the given filter flag and value.
-func (m *Mailbox) queryMessages(beadsDir, filterFlag, filterValue, status string) ([]*Message, error) {
+// extra flags are appended to the query.
+func (m *Mailbox) queryMessages(beadsDir, filterFlag, filterValue, status string, extra ...string) ([]*Message, error) {
 	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
 		return nil, fmt.Errorf("ensuring custom types: %w", err)
 	}
@@ -222,6 +223,7 @@ func (m *Mailbox) queryMessages(beadsDir, filterFlag, filterValue, status string
 		"--status", status,
 		"--json",
 	}
+	args = append(args, extra...)
 
 	stdout, err := runBdCommand(args, m.workDir, beadsDir)
 	if err != nil {
@@ -300,6 +302,70 @@ func (m *Mailbox) ListUnread() ([]*Message, error) {
 	return unread, nil
 }
 
+// History returns the messages the mailbox has received since the given
+// time: open, hooked, read (closed) and archived, newest first. Unlike List
+// it includes mail that has already been handled, for callers that look
+// back over past requests. A zero since returns the whole history.
+func (m *Mailbox) History(since time.Time) ([]*Message, error) {
+	var messages []*Message
+	if m.legacy {
+		inbox, err := m.listLegacy()
+		if err != nil {
+			return nil, err
+		}
+		messages = inbox
+	} else {
+		extra := []string{"--limit=0"}
+		if !since.IsZero() {
+			extra = append(extra, "--created-after="+since.UTC().Format(time.RFC3339))
+		}
+		seen := make(map[string]bool)
+		for _, identity := range m.identityVariants() {
+			msgs, err := m.queryMessages(m.beadsDir, "--assignee", identity, "all", extra...)
+			if err != nil {
+				return nil, err
+			}
+			for _, msg := range msgs {
+				if !seen[msg.ID] {
+					seen[msg.ID] = true
+					messages = append(messages, msg)
+				}
+			}
+		}
+	}
+
+	// The archive is shared by every mailbox in the beads directory
+	archived, err := m.ListArchived()
+	if err != nil {
+		return nil, err
+	}
+	variants := m.identityVariants()
+	for _, msg := range archived {
+		to := AddressToIdentity(msg.To)
+		for _, v := range variants {
+			if to == v {
+				messages = append(messages, msg)
+				break
+			}
+		}
+	}
+
+	if !since.IsZero() {
+		recent := messages[:0]
+		for _, msg := range messages {
+			if !msg.Timestamp.Before(since) {
+				recent = append(recent, msg)
+			}
+		}
+		messages = recent
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Timestamp.After(messages[j].Timestamp)
+	})
+	return messages, nil
+}
+
 // Get returns a message by ID.
 func (m *Mailbox) Get(id string) (*Message, error) {
 	if m.legacy {
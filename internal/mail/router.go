@@ -358,24 +358,11 @@ func agentBeadToAddress(bead *agentBead) string {
 }
 
 // parseAgentAddressFromDescription extracts agent address from description metadata.
-// Looks for "role_type: X" and "rig: Y" patterns in the description.
+// Uses the agent fields' role_type and rig.
 func parseAgentAddressFromDescription(desc string) string {
-	var roleType, rig string
-
-	for _, line := range strings.Split(desc, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "role_type:") {
-			roleType = strings.TrimSpace(strings.TrimPrefix(line, "role_type:"))
-		} else if strings.HasPrefix(line, "rig:") {
-			rig = strings.TrimSpace(strings.TrimPrefix(line, "rig:"))
-		}
-	}
-
-	// Handle null values from description
-	if rig == "null" || rig == "" {
-		rig = ""
-	}
-	if roleType == "null" || roleType == "" {
+	fields := beads.ParseAgentFields(desc)
+	roleType, rig := fields.RoleType, fields.Rig
+	if roleType == "" {
 		return ""
 	}
 
@@ -472,8 +459,7 @@ func (r *Router) resolveAgentsByRole(roleType, rig string) ([]string, error) {
 	for _, agent := range agents {
 		// Filter by rig if specified
 		if rig != "" {
-			// Check if agent's description contains matching rig
-			if !strings.Contains(agent.Description, "rig: "+rig) {
+			if beads.ParseAgentFields(agent.Description).Rig != rig {
 				continue
 			}
 		}
@@ -5,6 +5,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
 )
 
 func TestDetectTownRoot(t *testing.T) {
@@ -963,3 +966,53 @@ func TestValidateRecipient(t *testing.T) {
 		})
 	}
 }
+
+// TestSendAndReceiveWithFakeBeads runs the send → inbox flow against the
+// in-memory bd, so it doesn't need the bd binary.
+func TestSendAndReceiveWithFakeBeads(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-mayor", Title: "Mayor agent", Type: "agent"},
+		beads.Issue{ID: "gt-testrig-crew-alice", Title: "Test crew alice", Type: "agent"},
+	)
+
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := NewMessage("mayor/", "testrig/alice", "Status?", "How is the build going?")
+	msg.CC = []string{"mayor/"}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if err := r.Send(NewMessage("mayor/", "testrig/nobody", "Hello", "")); err == nil {
+		t.Error("Send to unknown recipient succeeded")
+	}
+
+	inbox, err := NewMailboxWithBeadsDir("testrig/alice", townRoot, beadsDir).List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(inbox) != 1 || inbox[0].Subject != "Status?" || inbox[0].From != "mayor/" {
+		t.Fatalf("alice inbox = %+v", inbox)
+	}
+
+	// CC recipients see the message too
+	ccInbox, err := NewMailboxWithBeadsDir("mayor/", townRoot, beadsDir).List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(ccInbox) != 1 || ccInbox[0].ID != inbox[0].ID {
+		t.Errorf("mayor inbox = %+v", ccInbox)
+	}
+
+	for _, c := range fake.Calls() {
+		if c.BeadsDir != beadsDir {
+			t.Errorf("bd %v used BEADS_DIR %q, want town beads %q", c.Args, c.BeadsDir, beadsDir)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// UnreadIndex holds every unread message in a beads directory, so callers
+// that preview many mailboxes at once (the statusline refresher) make two
+// bd queries in total instead of several per mailbox.
+type UnreadIndex struct {
+	messages []indexedMessage
+}
+
+type indexedMessage struct {
+	msg      *Message
+	assignee string
+	cc       []string
+	hooked   bool
+}
+
+// LoadUnreadIndex reads all open and hooked messages from the beads
+// directory that workDir resolves to, like NewMailboxFromAddress.
+func LoadUnreadIndex(workDir string) (*UnreadIndex, error) {
+	beadsDir := beads.ResolveBeadsDir(workDir)
+	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
+		return nil, fmt.Errorf("ensuring custom types: %w", err)
+	}
+
+	idx := &UnreadIndex{}
+	for _, status := range []string{"open", "hooked"} {
+		stdout, err := runBdCommand([]string{"list",
+			"--type", "message",
+			"--status", status,
+			"--limit=0",
+			"--json",
+		}, workDir, beadsDir)
+		if err != nil {
+			return nil, err
+		}
+		if len(stdout) == 0 || string(stdout) == "null" {
+			continue
+		}
+		var beadsMsgs []BeadsMessage
+		if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
+			return nil, err
+		}
+		for i := range beadsMsgs {
+			bm := &beadsMsgs[i]
+			msg := bm.ToMessage()
+			if msg.Read {
+				continue
+			}
+			idx.messages = append(idx.messages, indexedMessage{
+				msg:      msg,
+				assignee: bm.Assignee,
+				cc:       bm.GetCC(),
+				hooked:   status == "hooked",
+			})
+		}
+	}
+	return idx, nil
+}
+
+// Unread returns the unread messages for an address, newest first, matching
+// the mailbox rules: assigned to the identity (open or hooked), or CC'd on
+// an open message.
+func (idx *UnreadIndex) Unread(address string) []*Message {
+	m := &Mailbox{identity: AddressToIdentity(address)}
+	variants := m.identityVariants()
+	matches := func(identity string) bool {
+		for _, v := range variants {
+			if identity == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	var unread []*Message
+	for _, im := range idx.messages {
+		if matches(im.assignee) {
+			unread = append(unread, im.msg)
+			continue
+		}
+		if im.hooked {
+			continue
+		}
+		for _, cc := range im.cc {
+			if matches(cc) {
+				unread = append(unread, im.msg)
+				break
+			}
+		}
+	}
+	sort.Slice(unread, func(i, j int) bool {
+		return unread[i].Timestamp.After(unread[j].Timestamp)
+	})
+	return unread
+}
@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+)
+
+// TestUnreadIndexMatchesMailboxes checks that one index answers the same
+// unread queries as per-mailbox listing, with two bd list calls in total.
+func TestUnreadIndexMatchesMailboxes(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-mayor", Title: "Mayor agent", Type: "agent"},
+		beads.Issue{ID: "gt-testrig-crew-alice", Title: "Test crew alice", Type: "agent"},
+		beads.Issue{ID: "gt-testrig-Toast", Title: "Test polecat Toast", Type: "agent"},
+	)
+
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	first := NewMessage("mayor/", "testrig/alice", "First", "")
+	first.CC = []string{"testrig/Toast"}
+	if err := r.Send(first); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := r.Send(NewMessage("testrig/Toast", "mayor/", "Done", "")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	before := fake.Count("list")
+	idx, err := LoadUnreadIndex(townRoot)
+	if err != nil {
+		t.Fatalf("LoadUnreadIndex: %v", err)
+	}
+	if got := fake.Count("list") - before; got != 2 {
+		t.Errorf("LoadUnreadIndex made %d bd list calls, want 2", got)
+	}
+
+	for _, address := range []string{"mayor/", "testrig/alice", "testrig/crew/alice", "testrig/Toast", "testrig/nobody"} {
+		want, err := NewMailboxFromAddress(address, townRoot).ListUnread()
+		if err != nil {
+			t.Fatalf("ListUnread(%s): %v", address, err)
+		}
+		got := idx.Unread(address)
+		if address == "testrig/alice" && len(got) != 1 {
+			t.Errorf("Unread(%s) = %d messages, want 1", address, len(got))
+		}
+		if len(got) != len(want) {
+			t.Errorf("Unread(%s) = %d messages, mailbox has %d", address, len(got), len(want))
+			continue
+		}
+		for i := range got {
+			if got[i].ID != want[i].ID {
+				t.Errorf("Unread(%s)[%d] = %s, mailbox has %s", address, i, got[i].ID, want[i].ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Collector gathers town health metrics from on-disk and tmux state.
+type Collector struct {
+	townRoot string
+
+	// Overridable for tests.
+	now               func() time.Time
+	listSessions      func() ([]string, error)
+	listMergeRequests func(rigPath string) ([]*beads.Issue, error)
+	costsLogPath      string
+}
+
+// NewCollector creates a collector for the given town.
+func NewCollector(townRoot string) *Collector {
+	costsLog := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		costsLog = filepath.Join(home, ".gt", "costs.jsonl")
+	}
+
+	return &Collector{
+		townRoot:     townRoot,
+		now:          time.Now,
+		listSessions: tmux.NewTmux().ListSessions,
+		listMergeRequests: func(rigPath string) ([]*beads.Issue, error) {
+			return beads.New(rigPath).List(beads.ListOptions{
+				Type:     "merge-request",
+				Status:   "open",
+				Priority: -1,
+			})
+		},
+		costsLogPath: costsLog,
+	}
+}
+
+// Collect gathers all metric families. Sources that are unavailable
+// (no tmux server, bd failure, missing files) contribute no samples
+// rather than failing the whole scrape.
+func (c *Collector) Collect() []*Family {
+	rigs := c.rigNames()
+
+	var families []*Family
+	families = append(families, c.collectSessions(rigs)...)
+	families = append(families, c.collectMergeQueue(rigs)...)
+	families = append(families, c.collectEvents()...)
+	families = append(families, c.collectCosts())
+	families = append(families, c.collectDeacon()...)
+	return families
+}
+
+// rigNames returns registered rig names in sorted order.
+func (c *Collector) rigNames() []string {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(c.townRoot))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectSessions reports live tmux sessions by role and active polecats per rig.
+func (c *Collector) collectSessions(rigs []string) []*Family {
+	sessions := NewFamily("gastown_sessions", Gauge, "Live Gas Town tmux sessions by role.")
+	polecats := NewFamily("gastown_polecats_active", Gauge, "Polecats with a live tmux session, per rig.")
+
+	for _, rigName := range rigs {
+		polecats.Set(0, "rig", rigName)
+	}
+
+	names, err := c.listSessions()
+	if err != nil {
+		return []*Family{sessions, polecats}
+	}
+
+	for _, name := range names {
+		identity, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		sessions.Inc(1, "role", string(identity.Role))
+		if identity.Role == session.RolePolecat {
+			polecats.Inc(1, "rig", identity.Rig)
+		}
+	}
+
+	return []*Family{sessions, polecats}
+}
+
+// collectMergeQueue reports open merge requests and the age of the oldest, per rig.
+func (c *Collector) collectMergeQueue(rigs []string) []*Family {
+	depth := NewFamily("gastown_merge_queue_depth", Gauge, "Open merge requests in the refinery queue, per rig.")
+	oldest := NewFamily("gastown_merge_queue_oldest_age_seconds", Gauge, "Age of the oldest open merge request, per rig.")
+
+	now := c.now()
+	for _, rigName := range rigs {
+		mrs, err := c.listMergeRequests(filepath.Join(c.townRoot, rigName))
+		if err != nil {
+			continue
+		}
+
+		var maxAge time.Duration
+		for _, mr := range mrs {
+			created, err := time.Parse(time.RFC3339, mr.CreatedAt)
+			if err != nil {
+				continue
+			}
+			if age := now.Sub(created); age > maxAge {
+				maxAge = age
+			}
+		}
+
+		depth.Set(float64(len(mrs)), "rig", rigName)
+		oldest.Set(maxAge.Seconds(), "rig", rigName)
+	}
+
+	return []*Family{depth, oldest}
+}
+
+// collectEvents derives counters from the raw events log.
+func (c *Collector) collectEvents() []*Family {
+	merges := NewFamily("gastown_merges_total", Counter, "Refinery merge attempts by rig and result.")
+	deaths := NewFamily("gastown_session_deaths_total", Counter, "Agent session deaths, per rig and reason (crash, zombie, orphan, done, shutdown or other).")
+	massDeaths := NewFamily("gastown_mass_deaths_total", Counter, "Mass death events (several sessions dying at once).")
+	gupp := NewFamily("gastown_gupp_violations_total", Counter, "Agents detected with work on hook but no progress, per rig.")
+	escalations := NewFamily("gastown_escalations_total", Counter, "Escalations sent, by severity.")
+
+	families := []*Family{merges, deaths, massDeaths, gupp, escalations}
+
+	f, err := os.Open(filepath.Join(c.townRoot, events.EventsFile))
+	if err != nil {
+		return families
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+
+		switch ev.Type {
+		case events.TypeMerged:
+			merges.Inc(1, "rig", eventRig(ev), "result", "success")
+		case events.TypeMergeFailed:
+			merges.Inc(1, "rig", eventRig(ev), "result", "failure")
+		case events.TypeSessionDeath:
+			deaths.Inc(1, "rig", eventRig(ev), "reason", deathReason(ev))
+		case events.TypeMassDeath:
+			massDeaths.Inc(1)
+		case events.TypeGUPPViolation:
+			gupp.Inc(1, "rig", eventRig(ev))
+		case events.TypeEscalationSent:
+			severity, _ := ev.Payload["severity"].(string)
+			if severity == "" {
+				severity = "unknown"
+			}
+			escalations.Inc(1, "severity", severity)
+		}
+	}
+
+	return families
+}
+
+// eventRig extracts the rig an event belongs to: an explicit "rig" payload
+// field, else the first segment of the agent address or actor.
+func eventRig(ev events.Event) string {
+	if rig, ok := ev.Payload["rig"].(string); ok && rig != "" {
+		return rig
+	}
+	addr := ev.Actor
+	if agent, ok := ev.Payload["agent"].(string); ok && agent != "" {
+		addr = agent
+	}
+	identity, err := session.ParseAddress(addr)
+	if err != nil {
+		return ""
+	}
+	return identity.Rig
+}
+
+// deathReason classifies a session_death event. Deaths are logged for clean
+// exits too (gt done, gt down), so the reason label is what separates crashes
+// and cleanups from normal session ends.
+func deathReason(ev events.Event) string {
+	caller, _ := ev.Payload["caller"].(string)
+	reason, _ := ev.Payload["reason"].(string)
+	reason = strings.ToLower(reason)
+
+	switch {
+	case caller == "gt done":
+		return "done"
+	case caller == "gt down" || strings.Contains(reason, "shutdown"):
+		return "shutdown"
+	case strings.Contains(reason, "zombie"):
+		return "zombie"
+	case strings.Contains(reason, "orphan"):
+		return "orphan"
+	case strings.Contains(reason, "crash"):
+		return "crash"
+	default:
+		return "other"
+	}
+}
+
+// costLogEntry mirrors the fields of ~/.gt/costs.jsonl used here.
+type costLogEntry struct {
+	Rig     string  `json:"rig,omitempty"`
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// collectCosts sums undigested session costs per rig.
+// This is a gauge: `gt costs digest` moves entries into digest beads.
+func (c *Collector) collectCosts() *Family {
+	costs := NewFamily("gastown_session_cost_usd", Gauge, "Recorded session cost not yet rolled into a digest, per rig (empty rig = town-level agents).")
+
+	if c.costsLogPath == "" {
+		return costs
+	}
+	f, err := os.Open(c.costsLogPath)
+	if err != nil {
+		return costs
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry costLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		costs.Inc(entry.CostUSD, "rig", entry.Rig)
+	}
+
+	return costs
+}
+
+// collectDeacon reports Deacon heartbeat freshness and agent health checks.
+func (c *Collector) collectDeacon() []*Family {
+	age := NewFamily("gastown_deacon_heartbeat_age_seconds", Gauge, "Seconds since the Deacon last wrote its heartbeat.")
+	cycle := NewFamily("gastown_deacon_cycle", Gauge, "Deacon patrol cycle number from the last heartbeat.")
+	agents := NewFamily("gastown_agents", Gauge, "Agents by health as reported in the Deacon heartbeat.")
+	failures := NewFamily("gastown_agent_health_check_failures", Gauge, "Consecutive failed health checks, per agent.")
+
+	if hb := deacon.ReadHeartbeat(c.townRoot); hb != nil {
+		age.Set(c.now().Sub(hb.Timestamp).Seconds())
+		cycle.Set(float64(hb.Cycle))
+		agents.Set(float64(hb.HealthyAgents), "health", "healthy")
+		agents.Set(float64(hb.UnhealthyAgents), "health", "unhealthy")
+	}
+
+	if state, err := deacon.LoadHealthCheckState(c.townRoot); err == nil {
+		for id, agent := range state.Agents {
+			if agent == nil || strings.TrimSpace(id) == "" {
+				continue
+			}
+			failures.Set(float64(agent.ConsecutiveFailures), "agent", id)
+		}
+	}
+
+	return []*Family{age, cycle, agents, failures}
+}
@@ -0,0 +1,151 @@
+// Package metrics exports Gas Town health as Prometheus text exposition format.
+//
+// There is no long-running metrics registry: every scrape calls Collect, which
+// reads the town's existing state (tmux sessions, merge-request beads, the
+// events log, the cost log and Deacon heartbeat files) and renders it fresh.
+// Counters are derived from .events.jsonl, so they reset when KRC prunes it;
+// Prometheus handles that like a process restart.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the HTTP Content-Type for the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Type is a Prometheus metric type.
+type Type string
+
+const (
+	// Gauge is a value that can go up and down.
+	Gauge Type = "gauge"
+
+	// Counter is a monotonically increasing value.
+	Counter Type = "counter"
+)
+
+// Sample is a single labeled value within a metric family.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Family is a named metric with help text and zero or more samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// NewFamily creates an empty metric family.
+func NewFamily(name string, typ Type, help string) *Family {
+	return &Family{Name: name, Type: typ, Help: help}
+}
+
+// Set records a sample. labels are alternating key, value pairs.
+func (f *Family) Set(value float64, labels ...string) {
+	f.Samples = append(f.Samples, Sample{Labels: labelMap(labels), Value: value})
+}
+
+// Inc adds delta to the sample with the given labels, creating it if needed.
+func (f *Family) Inc(delta float64, labels ...string) {
+	want := labelMap(labels)
+	for i := range f.Samples {
+		if sameLabels(f.Samples[i].Labels, want) {
+			f.Samples[i].Value += delta
+			return
+		}
+	}
+	f.Samples = append(f.Samples, Sample{Labels: want, Value: delta})
+}
+
+// Write renders families in Prometheus text exposition format.
+// Families are written in the order given; samples are sorted by labels
+// so output is stable between scrapes.
+func Write(w io.Writer, families []*Family) error {
+	for _, f := range families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.Name, escapeHelp(f.Help), f.Name, f.Type); err != nil {
+			return err
+		}
+
+		samples := make([]Sample, len(f.Samples))
+		copy(samples, f.Samples)
+		sort.Slice(samples, func(i, j int) bool {
+			return formatLabels(samples[i].Labels) < formatLabels(samples[j].Labels)
+		})
+
+		for _, s := range samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.Name, formatLabels(s.Labels), formatValue(s.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func labelMap(pairs []string) map[string]string {
+	if len(pairs) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		m[pairs[i]] = pairs[i+1]
+	}
+	return m
+}
+
+func sameLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=\"%s\"", k, escapeLabelValue(labels[k])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string       { return helpEscaper.Replace(s) }
+func escapeLabelValue(s string) string { return labelEscaper.Replace(s) }
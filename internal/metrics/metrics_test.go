@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestWrite_Format(t *testing.T) {
+	f := NewFamily("gastown_test_total", Counter, "A test counter.\nSecond line.")
+	f.Inc(1, "rig", "b")
+	f.Inc(2, "rig", "a")
+	f.Inc(3, "rig", "b")
+	f.Set(1.5, "rig", `quote"d`)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []*Family{f}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP gastown_test_total A test counter.\nSecond line.
+# TYPE gastown_test_total counter
+gastown_test_total{rig="a"} 2
+gastown_test_total{rig="b"} 4
+gastown_test_total{rig="quote\"d"} 1.5
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Write output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWrite_NoLabels(t *testing.T) {
+	f := NewFamily("gastown_up", Gauge, "Up.")
+	f.Set(1)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []*Family{f}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\ngastown_up 1\n") {
+		t.Errorf("expected unlabeled sample, got:\n%s", buf.String())
+	}
+}
+
+func TestCollector_Collect(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	writeFile(t, filepath.Join(townRoot, "mayor", "rigs.json"),
+		`{"version":1,"rigs":{"gastown":{"git_url":"x"},"beads":{"git_url":"y"}}}`)
+
+	writeFile(t, filepath.Join(townRoot, ".events.jsonl"), strings.Join([]string{
+		`{"ts":"2026-01-02T10:00:00Z","type":"merged","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`{"ts":"2026-01-02T10:01:00Z","type":"merged","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`{"ts":"2026-01-02T10:02:00Z","type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`{"ts":"2026-01-02T10:03:00Z","type":"session_death","actor":"gt-beads-nux","payload":{"agent":"beads/polecats/nux","reason":"crash detected","caller":"daemon"}}`,
+		`{"ts":"2026-01-02T10:03:30Z","type":"session_death","actor":"beads/polecats/nux","payload":{"agent":"beads/polecats/nux","reason":"self-clean: done means gone","caller":"gt done"}}`,
+		`{"ts":"2026-01-02T10:03:40Z","type":"session_death","actor":"gt-beads-slit","payload":{"agent":"unknown","reason":"zombie cleanup","caller":"gt doctor"}}`,
+		`{"ts":"2026-01-02T10:04:00Z","type":"escalation_sent","actor":"gastown/witness","payload":{"severity":"high"}}`,
+		`{"ts":"2026-01-02T10:05:00Z","type":"gupp_violation","actor":"daemon","payload":{"rig":"beads"}}`,
+		`not json`,
+	}, "\n")+"\n")
+
+	costsLog := filepath.Join(townRoot, "costs.jsonl")
+	writeFile(t, costsLog, `{"rig":"gastown","cost_usd":1.25}`+"\n"+`{"rig":"gastown","cost_usd":0.75}`+"\n")
+
+	c := &Collector{
+		townRoot: townRoot,
+		now:      func() time.Time { return now },
+		listSessions: func() ([]string, error) {
+			return []string{"hq-deacon", "gt-gastown-witness", "gt-gastown-Toast", "gt-gastown-nux", "unrelated"}, nil
+		},
+		listMergeRequests: func(rigPath string) ([]*beads.Issue, error) {
+			if filepath.Base(rigPath) != "gastown" {
+				return nil, nil
+			}
+			return []*beads.Issue{
+				{ID: "gt-mr1", CreatedAt: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+				{ID: "gt-mr2", CreatedAt: now.Add(-10 * time.Minute).Format(time.RFC3339)},
+			}, nil
+		},
+		costsLogPath: costsLog,
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, c.Collect()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`gastown_polecats_active{rig="beads"} 0`,
+		`gastown_polecats_active{rig="gastown"} 2`,
+		`gastown_sessions{role="deacon"} 1`,
+		`gastown_merge_queue_depth{rig="gastown"} 2`,
+		`gastown_merge_queue_oldest_age_seconds{rig="gastown"} 7200`,
+		`gastown_merges_total{result="success",rig="gastown"} 2`,
+		`gastown_merges_total{result="failure",rig="gastown"} 1`,
+		`gastown_session_deaths_total{reason="crash",rig="beads"} 1`,
+		`gastown_session_deaths_total{reason="done",rig="beads"} 1`,
+		`gastown_session_deaths_total{reason="zombie",rig=""} 1`,
+		`gastown_gupp_violations_total{rig="beads"} 1`,
+		`gastown_escalations_total{severity="high"} 1`,
+		`gastown_session_cost_usd{rig="gastown"} 2`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -62,22 +62,13 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 
 	// Try to load rig settings for namepool config
 	settingsPath := filepath.Join(r.Path, "settings", "config.json")
-	var pool *NamePool
-
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err == nil && settings.Namepool != nil {
-		// Use configured namepool settings
-		pool = NewNamePoolWithConfig(
-			r.Path,
-			r.Name,
-			settings.Namepool.Style,
-			settings.Namepool.Names,
-			settings.Namepool.MaxBeforeNumbering,
-		)
-	} else {
-		// Use defaults
-		pool = NewNamePool(r.Path, r.Name)
+	var namepoolCfg *config.NamepoolConfig
+	if settings, err := config.LoadRigSettings(settingsPath); err == nil {
+		namepoolCfg = settings.Namepool
 	}
+	// Non-fatal: the pool falls back to defaults and the themes that did
+	// load. gt namepool reports the error.
+	pool, _ := NewNamePoolFromSettings(r.Path, r.Name, namepoolCfg)
 	_ = pool.Load() // non-fatal: state file may not exist for new rigs
 
 	return &Manager{
@@ -214,6 +205,23 @@ func (m *Manager) clonePath(name string) string {
 	return newPath
 }
 
+// OverlayVars returns the overlay template variables for a polecat.
+// hookBead is the polecat's hook bead, empty for warm polecats.
+func (m *Manager) OverlayVars(name, hookBead string) rig.OverlayVars {
+	vars := rig.OverlayVars{
+		Rig:      m.rig.Name,
+		Role:     rig.OverlayRolePolecat,
+		Name:     name,
+		Worktree: m.clonePath(name),
+		BeadID:   hookBead,
+	}
+	if ports := PortsFor(m.rig.Path, name); ports != nil {
+		vars.PortBase = ports.Base
+		vars.Ports = ports.Services
+	}
+	return vars
+}
+
 // exists checks if a polecat exists.
 func (m *Manager) exists(name string) bool {
 	_, err := os.Stat(m.polecatDir(name))
@@ -222,7 +230,8 @@ func (m *Manager) exists(name string) bool {
 
 // AddOptions configures polecat creation.
 type AddOptions struct {
-	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+	AgentState string // Initial agent_state for the agent bead (default "spawning")
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -363,6 +372,13 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		return nil, fmt.Errorf("creating polecat dir: %w", err)
 	}
 
+	// Reserve the polecat's port block (if the rig allocates ports) before
+	// overlay templates and the session need it
+	if _, err := m.ReservePorts(name); err != nil {
+		_ = os.RemoveAll(polecatDir)
+		return nil, fmt.Errorf("reserving ports: %w", err)
+	}
+
 	// Get the repo base (bare repo or mayor/rig)
 	repoGit, err := m.repoBase()
 	if err != nil {
@@ -423,9 +439,9 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		fmt.Printf("Warning: could not provision PRIME.md: %v\n", err)
 	}
 
-	// Copy overlay files from .runtime/overlay/ to polecat root.
-	// This allows services to have .env and other config files at their root.
-	if err := rig.CopyOverlay(m.rig.Path, clonePath); err != nil {
+	// Copy overlay files from .runtime/overlay/ into the polecat worktree.
+	// This allows services to have .env and other config files in place.
+	if err := rig.ApplyOverlay(m.rig.Path, clonePath, m.OverlayVars(name, opts.HookBead)); err != nil {
 		// Non-fatal - log warning but continue
 		fmt.Printf("Warning: could not copy overlay files: %v\n", err)
 	}
@@ -449,11 +465,15 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	// State starts as "spawning" - will be updated to "working" when Claude starts.
 	// HookBead is set atomically at creation time if provided (avoids cross-beads routing issues).
 	// Uses CreateOrReopenAgentBead to handle re-spawning with same name (GH #332).
+	agentState := opts.AgentState
+	if agentState == "" {
+		agentState = "spawning"
+	}
 	agentID := m.agentBeadID(name)
 	_, err = m.beads.CreateOrReopenAgentBead(agentID, agentID, &beads.AgentFields{
 		RoleType:   "polecat",
 		Rig:        m.rig.Name,
-		AgentState: "spawning",
+		AgentState: agentState,
 		HookBead:   opts.HookBead, // Set atomically at spawn time
 	})
 	if err != nil {
@@ -594,6 +614,9 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 	m.namePool.Release(name)
 	_ = m.namePool.Save()
 
+	// Free the polecat's port block (non-fatal: stale blocks are reclaimed on reserve)
+	_ = m.ReleasePorts(name)
+
 	// Close agent bead (non-fatal: may not exist or beads may not be available)
 	// NOTE: We use CloseAndClearAgentBead instead of DeleteAgentBead because bd delete --hard
 	// creates tombstones that cannot be reopened.
@@ -634,6 +657,14 @@ func (m *Manager) ReleaseName(name string) {
 	_ = m.namePool.Save() // non-fatal: state file update
 }
 
+// RetireName keeps a removed polecat's name out of rotation for the rig's
+// namepool retire_cooldown. Returns false if retirement is disabled.
+func (m *Manager) RetireName(name string) bool {
+	retired := m.namePool.Retire(name)
+	_ = m.namePool.Save() // non-fatal: state file update
+	return retired
+}
+
 // RepairWorktree repairs a stale polecat by removing it and creating a fresh worktree.
 // This is NOT for normal operation - it handles reconciliation when AllocateName
 // returns a name that unexpectedly already exists (stale state recovery).
@@ -744,8 +775,8 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		fmt.Printf("Warning: could not set up shared beads: %v\n", err)
 	}
 
-	// Copy overlay files from .runtime/overlay/ to polecat root.
-	if err := rig.CopyOverlay(m.rig.Path, newClonePath); err != nil {
+	// Copy overlay files from .runtime/overlay/ into the polecat worktree.
+	if err := rig.ApplyOverlay(m.rig.Path, newClonePath, m.OverlayVars(name, opts.HookBead)); err != nil {
 		fmt.Printf("Warning: could not copy overlay files: %v\n", err)
 	}
 
@@ -867,7 +898,7 @@ func (m *Manager) List() ([]*Polecat, error) {
 		return nil, fmt.Errorf("reading polecats dir: %w", err)
 	}
 
-	var polecats []*Polecat
+	var names, assignees []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -875,12 +906,19 @@ func (m *Manager) List() ([]*Polecat, error) {
 		if strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
-
-		polecat, err := m.Get(entry.Name())
-		if err != nil {
+		if !m.exists(entry.Name()) {
 			continue // Skip invalid polecats
 		}
-		polecats = append(polecats, polecat)
+		names = append(names, entry.Name())
+		assignees = append(assignees, m.assigneeID(entry.Name()))
+	}
+
+	// One batched beads query for all polecats instead of one per polecat
+	issues, beadsErr := m.beads.AssignedIssues(assignees)
+
+	var polecats []*Polecat
+	for i, name := range names {
+		polecats = append(polecats, m.loadPolecat(name, issues[assignees[i]], beadsErr))
 	}
 
 	return polecats, nil
@@ -1002,6 +1040,14 @@ func (m *Manager) ClearIssue(name string) error {
 // Transient polecats should always have work; no work means ready for Witness cleanup.
 // We don't interpret issue status (ZFC: Go is transport, not decision-maker).
 func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
+	// Query beads for assigned issue
+	issue, beadsErr := m.beads.GetAssignedIssue(m.assigneeID(name))
+	return m.loadPolecat(name, issue, beadsErr), nil
+}
+
+// loadPolecat builds a Polecat from its worktree and the issue assigned to
+// it. beadsErr is the error from looking up the issue, if any.
+func (m *Manager) loadPolecat(name string, issue *beads.Issue, beadsErr error) *Polecat {
 	// Use clonePath which handles both new (polecats/<name>/<rigname>/)
 	// and old (polecats/<name>/) structures
 	clonePath := m.clonePath(name)
@@ -1014,9 +1060,6 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 		branchName = fmt.Sprintf("polecat/%s", name)
 	}
 
-	// Query beads for assigned issue
-	assignee := m.assigneeID(name)
-	issue, beadsErr := m.beads.GetAssignedIssue(assignee)
 	if beadsErr != nil {
 		// If beads query fails, return basic polecat info as working
 		// (assume polecat is doing something if it exists)
@@ -1026,12 +1069,16 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 			State:     StateWorking,
 			ClonePath: clonePath,
 			Branch:    branchName,
-		}, nil
+		}
 	}
 
 	// Transient model: has issue = working, no issue = done (ready for cleanup)
-	// Polecats without work should be nuked by the Witness
+	// Polecats without work should be nuked by the Witness, except warm pool
+	// polecats, which are idle by design until claimed.
 	state := StateDone
+	if m.IsWarm(name) {
+		state = StateWarm
+	}
 	issueID := ""
 	if issue != nil {
 		issueID = issue.ID
@@ -1045,7 +1092,7 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 		ClonePath: clonePath,
 		Branch:    branchName,
 		Issue:     issueID,
-	}, nil
+	}
 }
 
 // setupSharedBeads creates a redirect file so the polecat uses the rig's shared .beads database.
@@ -1227,8 +1274,9 @@ func assessStaleness(info *StalenessInfo, threshold int) (bool, string) {
 	// Check for reasons to keep it:
 
 	// Check for non-observable states that indicate intentional pause
-	// (stuck, awaiting-gate are still stored in beads per gt-zecmc)
-	if info.AgentState == "stuck" || info.AgentState == "awaiting-gate" {
+	// (stuck, awaiting-gate are still stored in beads per gt-zecmc).
+	// Warm pool polecats are idle until claimed; the pool recycles them.
+	if info.AgentState == "stuck" || info.AgentState == "awaiting-gate" || info.AgentState == AgentStateWarm {
 		return false, fmt.Sprintf("agent_state=%s (intentional pause)", info.AgentState)
 	}
 
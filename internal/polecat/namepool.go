@@ -2,12 +2,18 @@ package polecat
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -77,7 +83,8 @@ var BuiltinThemes = map[string][]string{
 // for work. When a polecat is nuked, its name slot becomes available for the next
 // freshly-spawned polecat.
 //
-// Names are drawn from a themed pool (mad-max by default).
+// Names are drawn from a themed pool (mad-max by default), either built-in
+// or defined in a theme file under settings/namepool/.
 // When the pool is exhausted, overflow names use rigname-N format.
 type NamePool struct {
 	mu sync.RWMutex
@@ -91,6 +98,18 @@ type NamePool struct {
 	// CustomNames allows overriding the built-in theme names.
 	CustomNames []string `json:"custom_names,omitempty"`
 
+	// Strategy is the allocation strategy (StrategySequential, StrategyRandom
+	// or StrategyWeighted). Empty uses the theme's strategy, or sequential.
+	Strategy string `json:"strategy,omitempty"`
+
+	// RetireCooldown is how long a retired name stays out of rotation.
+	// Zero disables retirement.
+	RetireCooldown time.Duration `json:"-"`
+
+	// Retired maps names of failed polecats to when they may be reused.
+	// Persisted in the state file so retirement survives restarts.
+	Retired map[string]time.Time `json:"-"`
+
 	// InUse tracks which pool names are currently in use.
 	// Key is the name itself, value is true if in use.
 	// ZFC: This is transient state derived from filesystem via Reconcile().
@@ -106,6 +125,13 @@ type NamePool struct {
 
 	// stateFile is the path to persist pool state.
 	stateFile string
+
+	// themes are the themes available to this pool, including theme files.
+	// Nil means built-in themes only.
+	themes map[string]*Theme
+
+	// randIntN picks a random index for the random and weighted strategies.
+	randIntN func(int) int
 }
 
 // NewNamePool creates a new name pool for a rig.
@@ -114,6 +140,7 @@ func NewNamePool(rigPath, rigName string) *NamePool {
 		RigName:      rigName,
 		Theme:        ThemeForRig(rigName),
 		InUse:        make(map[string]bool),
+		Retired:      make(map[string]time.Time),
 		OverflowNext: DefaultPoolSize + 1,
 		MaxSize:      DefaultPoolSize,
 		stateFile:    filepath.Join(rigPath, ".runtime", "namepool-state.json"),
@@ -134,12 +161,72 @@ func NewNamePoolWithConfig(rigPath, rigName, theme string, customNames []string,
 		Theme:        theme,
 		CustomNames:  customNames,
 		InUse:        make(map[string]bool),
+		Retired:      make(map[string]time.Time),
 		OverflowNext: maxSize + 1,
 		MaxSize:      maxSize,
 		stateFile:    filepath.Join(rigPath, ".runtime", "namepool-state.json"),
 	}
 }
 
+// NewNamePoolFromSettings creates a rig's name pool from its namepool
+// settings (nil for defaults), with the town's and rig's theme files
+// available alongside the built-in themes. The town root is the rig's
+// parent directory. Invalid settings and unreadable theme files are reported
+// as an error; the returned pool falls back to defaults for those settings
+// and to the themes that did load.
+func NewNamePoolFromSettings(rigPath, rigName string, cfg *config.NamepoolConfig) (*NamePool, error) {
+	var pool *NamePool
+	var errs []error
+	if cfg != nil {
+		pool = NewNamePoolWithConfig(rigPath, rigName, cfg.Style, cfg.Names, cfg.MaxBeforeNumbering)
+		if cfg.Strategy != "" && !validStrategy(cfg.Strategy) {
+			errs = append(errs, fmt.Errorf("invalid namepool strategy %q (want %s, %s or %s)",
+				cfg.Strategy, StrategySequential, StrategyRandom, StrategyWeighted))
+		} else {
+			pool.Strategy = cfg.Strategy
+		}
+		if cfg.RetireCooldown != "" {
+			d, err := time.ParseDuration(cfg.RetireCooldown)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid namepool retire_cooldown %q: %w", cfg.RetireCooldown, err))
+			} else {
+				pool.RetireCooldown = d
+			}
+		}
+	} else {
+		pool = NewNamePool(rigPath, rigName)
+	}
+
+	themes, err := LoadThemes(filepath.Dir(rigPath), rigPath)
+	pool.themes = themes
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return pool, errors.Join(errs...)
+}
+
+// theme returns the pool's theme definition, or nil if it isn't known.
+func (p *NamePool) theme() *Theme {
+	if p.themes != nil {
+		return p.themes[p.Theme]
+	}
+	if names, ok := BuiltinThemes[p.Theme]; ok {
+		return &Theme{Name: p.Theme, Names: names, Source: ThemeSourceBuiltin}
+	}
+	return nil
+}
+
+// strategy returns the effective allocation strategy.
+func (p *NamePool) strategy() string {
+	if validStrategy(p.Strategy) {
+		return p.Strategy
+	}
+	if t := p.theme(); t != nil && t.Strategy != "" && len(p.CustomNames) == 0 {
+		return t.Strategy
+	}
+	return StrategySequential
+}
+
 // getNames returns the list of names to use for the pool.
 // Reserved infrastructure agent names are filtered out.
 func (p *NamePool) getNames() []string {
@@ -148,6 +235,8 @@ func (p *NamePool) getNames() []string {
 	// Custom names take precedence
 	if len(p.CustomNames) > 0 {
 		names = p.CustomNames
+	} else if t := p.theme(); t != nil {
+		names = t.Names
 	} else if themeNames, ok := BuiltinThemes[p.Theme]; ok {
 		// Look up built-in theme
 		names = themeNames
@@ -181,6 +270,7 @@ func (p *NamePool) Load() error {
 		if os.IsNotExist(err) {
 			// Initialize with empty state
 			p.InUse = make(map[string]bool)
+			p.Retired = make(map[string]time.Time)
 			p.OverflowNext = p.MaxSize + 1
 			return nil
 		}
@@ -197,6 +287,15 @@ func (p *NamePool) Load() error {
 
 	p.InUse = make(map[string]bool)
 
+	// Retirements that have run out are dropped
+	p.Retired = make(map[string]time.Time)
+	now := time.Now()
+	for name, until := range loaded.Retired {
+		if until.After(now) {
+			p.Retired[name] = until
+		}
+	}
+
 	p.OverflowNext = loaded.OverflowNext
 	if p.OverflowNext < p.MaxSize+1 {
 		p.OverflowNext = p.MaxSize + 1
@@ -211,13 +310,14 @@ func (p *NamePool) Load() error {
 // namePoolState is the subset of NamePool that is persisted to the state file.
 // Only runtime state is saved, not configuration (Theme, CustomNames come from settings).
 type namePoolState struct {
-	RigName      string `json:"rig_name"`
-	OverflowNext int    `json:"overflow_next"`
-	MaxSize      int    `json:"max_size"`
+	RigName      string               `json:"rig_name"`
+	OverflowNext int                  `json:"overflow_next"`
+	MaxSize      int                  `json:"max_size"`
+	Retired      map[string]time.Time `json:"retired,omitempty"`
 }
 
 // Save persists the pool state to disk using atomic write.
-// Only runtime state (OverflowNext, MaxSize) is saved - configuration like
+// Only runtime state (OverflowNext, MaxSize, Retired) is saved - configuration like
 // Theme and CustomNames come from settings/config.json and are not persisted here.
 func (p *NamePool) Save() error {
 	p.mu.RLock()
@@ -233,27 +333,37 @@ func (p *NamePool) Save() error {
 		RigName:      p.RigName,
 		OverflowNext: p.OverflowNext,
 		MaxSize:      p.MaxSize,
+		Retired:      p.Retired,
 	}
 
 	return util.AtomicWriteJSON(p.stateFile, state)
 }
 
 // Allocate returns a name from the pool.
-// It prefers names in order from the theme list, and falls back to overflow names
-// when the pool is exhausted.
+// It picks a free, unretired name from the theme list using the pool's
+// strategy (by default the first in theme order), and falls back to
+// overflow names when the pool is exhausted.
 func (p *NamePool) Allocate() (string, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	names := p.getNames()
+	now := time.Now()
 
-	// Try to find first available name from the theme
+	var free []string
 	for i := 0; i < len(names) && i < p.MaxSize; i++ {
 		name := names[i]
-		if !p.InUse[name] {
-			p.InUse[name] = true
-			return name, nil
+		if p.InUse[name] || p.Retired[name].After(now) {
+			continue
 		}
+		free = append(free, name)
+	}
+
+	if len(free) > 0 {
+		name := p.pick(free)
+		p.InUse[name] = true
+		delete(p.Retired, name)
+		return name, nil
 	}
 
 	// Pool exhausted, use overflow naming
@@ -262,6 +372,44 @@ func (p *NamePool) Allocate() (string, error) {
 	return name, nil
 }
 
+// pick chooses one of the free names according to the pool's strategy.
+func (p *NamePool) pick(free []string) string {
+	randIntN := p.randIntN
+	if randIntN == nil {
+		randIntN = rand.IntN
+	}
+
+	switch p.strategy() {
+	case StrategyRandom:
+		return free[randIntN(len(free))]
+	case StrategyWeighted:
+		var weights map[string]int
+		if t := p.theme(); t != nil && len(p.CustomNames) == 0 {
+			weights = t.Weights
+		}
+		total := 0
+		for _, name := range free {
+			total += nameWeight(weights, name)
+		}
+		r := randIntN(total)
+		for _, name := range free {
+			r -= nameWeight(weights, name)
+			if r < 0 {
+				return name
+			}
+		}
+	}
+	return free[0]
+}
+
+// nameWeight returns a name's weight, defaulting to 1.
+func nameWeight(weights map[string]int, name string) int {
+	if w := weights[name]; w > 0 {
+		return w
+	}
+	return 1
+}
+
 // Release returns a name slot to the available pool.
 // Called when a polecat is nuked - the name becomes available for new polecats.
 // NOTE: This releases the NAME, not the polecat. The polecat is gone (nuked).
@@ -277,6 +425,59 @@ func (p *NamePool) Release(name string) {
 	// Overflow names are not reusable, so we don't track them
 }
 
+// Retire releases a name and keeps it out of rotation for RetireCooldown.
+// Called when a polecat is nuked with its work unfinished, so the next
+// polecat doesn't inherit a name associated with a failure in logs, mail
+// and the feed. Returns false (and just releases the name) when retirement
+// is disabled or the name isn't a themed name.
+func (p *NamePool) Retire(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.isThemedName(name) {
+		return false
+	}
+	delete(p.InUse, name)
+	if p.RetireCooldown <= 0 {
+		return false
+	}
+	if p.Retired == nil {
+		p.Retired = make(map[string]time.Time)
+	}
+	p.Retired[name] = time.Now().Add(p.RetireCooldown)
+	return true
+}
+
+// RetiredNames returns the currently retired names and when each may be
+// reused, sorted by name.
+func (p *NamePool) RetiredNames() []RetiredName {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	now := time.Now()
+	var retired []RetiredName
+	for name, until := range p.Retired {
+		if until.After(now) {
+			retired = append(retired, RetiredName{Name: name, Until: until})
+		}
+	}
+	sort.Slice(retired, func(i, j int) bool { return retired[i].Name < retired[j].Name })
+	return retired
+}
+
+// RetiredName is a name kept out of rotation until a given time.
+type RetiredName struct {
+	Name  string    `json:"name"`
+	Until time.Time `json:"until"`
+}
+
+// GetStrategy returns the effective allocation strategy.
+func (p *NamePool) GetStrategy() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.strategy()
+}
+
 // isThemedName checks if a name is in the theme pool.
 func (p *NamePool) isThemedName(name string) bool {
 	names := p.getNames()
@@ -293,6 +494,27 @@ func (p *NamePool) IsPoolName(name string) bool {
 	return p.isThemedName(name)
 }
 
+// Index returns a stable, 1-based number for a polecat name: its position in
+// the theme for themed names, or its sequence number for overflow names.
+// Overlay templates use it to give each polecat its own port range.
+// Returns 0 for names that are not from this pool.
+func (p *NamePool) Index(name string) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for i, n := range p.getNames() {
+		if n == name {
+			return i + 1
+		}
+	}
+	if suffix, ok := strings.CutPrefix(name, p.RigName+"-"); ok {
+		if seq, err := strconv.Atoi(suffix); err == nil && p.formatOverflowName(seq) == name {
+			return seq
+		}
+	}
+	return 0
+}
+
 // ActiveCount returns the number of names currently in use from the pool.
 func (p *NamePool) ActiveCount() int {
 	p.mu.RLock()
@@ -358,12 +580,21 @@ func (p *NamePool) SetTheme(theme string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if _, ok := BuiltinThemes[theme]; !ok {
-		return fmt.Errorf("unknown theme: %s (available: mad-max, minerals, wasteland)", theme)
+	themes := p.themes
+	if themes == nil {
+		themes, _ = LoadThemes("", "")
+	}
+	t, ok := themes[theme]
+	if !ok {
+		available := make([]string, 0, len(themes))
+		for _, th := range SortedThemes(themes) {
+			available = append(available, th.Name)
+		}
+		return fmt.Errorf("unknown theme: %s (available: %s)", theme, strings.Join(available, ", "))
 	}
 
 	// Preserve names that exist in both themes
-	newNames := BuiltinThemes[theme]
+	newNames := t.Names
 	newInUse := make(map[string]bool)
 	for name := range p.InUse {
 		for _, n := range newNames {
@@ -433,5 +664,6 @@ func (p *NamePool) Reset() {
 	defer p.mu.Unlock()
 
 	p.InUse = make(map[string]bool)
+	p.Retired = make(map[string]time.Time)
 	p.OverflowNext = p.MaxSize + 1
 }
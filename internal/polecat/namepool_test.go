@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func TestNamePool_Allocate(t *testing.T) {
@@ -256,6 +259,27 @@ func TestNamePool_IsPoolName(t *testing.T) {
 	}
 }
 
+func TestNamePool_Index(t *testing.T) {
+	pool := NewNamePoolWithConfig(t.TempDir(), "testrig", "mad-max", nil, DefaultPoolSize)
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"furiosa", 1},
+		{"nux", 2},
+		{"testrig-51", 51},
+		{"testrig-abc", 0},
+		{"otherrig-3", 0},
+		{"unknown", 0},
+	}
+	for _, tt := range tests {
+		if got := pool.Index(tt.name); got != tt.want {
+			t.Errorf("Index(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestNamePool_ActiveNames(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "namepool-test-*")
 	if err != nil {
@@ -525,3 +549,119 @@ func TestNamePool_ReservedNamesInCustomNames(t *testing.T) {
 		t.Errorf("expected alpha, beta, gamma to be allocated, got %v", allocated)
 	}
 }
+
+func TestNamePool_FileTheme(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	writeThemeFile(t, townRoot, "norse.txt", "odin\nthor\n")
+
+	pool, err := NewNamePoolFromSettings(rigPath, "testrig", &config.NamepoolConfig{Style: "norse"})
+	if err != nil {
+		t.Fatalf("NewNamePoolFromSettings: %v", err)
+	}
+
+	for _, want := range []string{"odin", "thor", "testrig-51"} {
+		name, err := pool.Allocate()
+		if err != nil {
+			t.Fatalf("Allocate: %v", err)
+		}
+		if name != want {
+			t.Errorf("Allocate() = %s, want %s", name, want)
+		}
+	}
+}
+
+func TestNamePool_Strategies(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	writeThemeFile(t, townRoot, "norse.txt", "odin\nthor 3\nfreya\n")
+
+	tests := []struct {
+		strategy string
+		pick     int
+		want     string
+	}{
+		{StrategySequential, 2, "odin"},
+		{StrategyRandom, 2, "freya"},
+		// Weights odin=1, thor=3, freya=1: indexes 1-3 land on thor
+		{StrategyWeighted, 0, "odin"},
+		{StrategyWeighted, 3, "thor"},
+		{StrategyWeighted, 4, "freya"},
+	}
+	for _, tt := range tests {
+		pool, err := NewNamePoolFromSettings(rigPath, "testrig", &config.NamepoolConfig{Style: "norse", Strategy: tt.strategy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.randIntN = func(n int) int { return tt.pick }
+
+		name, _ := pool.Allocate()
+		if name != tt.want {
+			t.Errorf("%s with pick %d: got %s, want %s", tt.strategy, tt.pick, name, tt.want)
+		}
+	}
+}
+
+func TestNewNamePoolFromSettings_InvalidStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	pool, err := NewNamePoolFromSettings(tmpDir, "testrig", &config.NamepoolConfig{Style: "mad-max", Strategy: "shuffle"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown strategy")
+	}
+	if got := pool.GetStrategy(); got != StrategySequential {
+		t.Errorf("GetStrategy() = %q, want fallback %q", got, StrategySequential)
+	}
+}
+
+func TestNamePool_Retire(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pool, err := NewNamePoolFromSettings(tmpDir, "testrig", &config.NamepoolConfig{Style: "mad-max", RetireCooldown: "24h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, _ := pool.Allocate()
+	if name != "furiosa" {
+		t.Fatalf("expected furiosa, got %s", name)
+	}
+
+	if !pool.Retire("furiosa") {
+		t.Fatal("expected furiosa to be retired")
+	}
+	if pool.Retire("testrig-60") {
+		t.Error("overflow names should not be retired")
+	}
+	if name, _ := pool.Allocate(); name != "nux" {
+		t.Errorf("expected retired furiosa to be skipped, got %s", name)
+	}
+
+	// Retirement survives a reload
+	if err := pool.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, _ := NewNamePoolFromSettings(tmpDir, "testrig", &config.NamepoolConfig{Style: "mad-max", RetireCooldown: "24h"})
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if retired := reloaded.RetiredNames(); len(retired) != 1 || retired[0].Name != "furiosa" {
+		t.Fatalf("RetiredNames() = %v", retired)
+	}
+
+	// Once the cooldown has passed the name is back in rotation
+	reloaded.Retired["furiosa"] = time.Now().Add(-time.Minute)
+	if name, _ := reloaded.Allocate(); name != "furiosa" {
+		t.Errorf("expected furiosa after cooldown, got %s", name)
+	}
+}
+
+func TestNamePool_RetireDisabled(t *testing.T) {
+	pool := NewNamePoolWithConfig(t.TempDir(), "testrig", "mad-max", nil, DefaultPoolSize)
+	_, _ = pool.Allocate()
+
+	if pool.Retire("furiosa") {
+		t.Error("expected retirement to be disabled without a cooldown")
+	}
+	if name, _ := pool.Allocate(); name != "furiosa" {
+		t.Errorf("expected furiosa to be released, got %s", name)
+	}
+}
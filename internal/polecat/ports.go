@@ -0,0 +1,291 @@
+package polecat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultPortBlockSize is the number of ports reserved per polecat when
+// polecat_ports.block_size is not set.
+const DefaultPortBlockSize = 10
+
+// ErrPortsExhausted is returned when every port block in the range is taken.
+var ErrPortsExhausted = errors.New("no free port block in range")
+
+// portsLockTimeout bounds how long reservation waits for a concurrent one.
+const portsLockTimeout = 5 * time.Second
+
+// PortBlock is a polecat's reserved range of ports.
+type PortBlock struct {
+	Base     int            `json:"base"`
+	Count    int            `json:"count"`
+	Services map[string]int `json:"services,omitempty"` // Service name -> port
+}
+
+// End returns the last port in the block.
+func (b *PortBlock) End() int {
+	return b.Base + b.Count - 1
+}
+
+// Env returns the block's environment variables (see config.PortEnv).
+func (b *PortBlock) Env() map[string]string {
+	if b == nil {
+		return nil
+	}
+	return config.PortEnv(b.Base, b.Count, b.Services)
+}
+
+// sortedServices returns a block's service names ordered by port.
+func (b *PortBlock) sortedServices() []string {
+	names := make([]string, 0, len(b.Services))
+	for name := range b.Services {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return b.Services[names[i]] < b.Services[names[j]] })
+	return names
+}
+
+// String formats a block as "20010-20019 (web=20010, db=20011)".
+func (b *PortBlock) String() string {
+	s := fmt.Sprintf("%d-%d", b.Base, b.End())
+	if len(b.Services) == 0 {
+		return s
+	}
+	var parts []string
+	for _, name := range b.sortedServices() {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, b.Services[name]))
+	}
+	return s + " (" + strings.Join(parts, ", ") + ")"
+}
+
+// portAllocations is the persisted allocation state: polecat name -> block index.
+type portAllocations struct {
+	Blocks map[string]int `json:"blocks"`
+}
+
+// portLayout is a parsed polecat_ports config.
+type portLayout struct {
+	start, end int
+	blockSize  int
+	services   []string
+}
+
+// parsePortsConfig validates a polecat_ports config.
+func parsePortsConfig(cfg *config.PolecatPortsConfig) (*portLayout, error) {
+	lo, hi, ok := strings.Cut(cfg.Range, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid port range %q: want START-END", cfg.Range)
+	}
+	start, err1 := strconv.Atoi(strings.TrimSpace(lo))
+	end, err2 := strconv.Atoi(strings.TrimSpace(hi))
+	if err1 != nil || err2 != nil || start < 1 || end > 65535 || start > end {
+		return nil, fmt.Errorf("invalid port range %q", cfg.Range)
+	}
+
+	l := &portLayout{start: start, end: end, blockSize: cfg.BlockSize, services: cfg.Services}
+	if l.blockSize <= 0 {
+		l.blockSize = DefaultPortBlockSize
+	}
+	if l.blockSize < len(l.services) {
+		l.blockSize = len(l.services)
+	}
+	if l.blocks() == 0 {
+		return nil, fmt.Errorf("port range %q is smaller than one block of %d", cfg.Range, l.blockSize)
+	}
+	return l, nil
+}
+
+func (l *portLayout) blocks() int {
+	return (l.end - l.start + 1) / l.blockSize
+}
+
+func (l *portLayout) block(index int) *PortBlock {
+	b := &PortBlock{Base: l.start + index*l.blockSize, Count: l.blockSize}
+	if len(l.services) > 0 {
+		b.Services = make(map[string]int, len(l.services))
+		for i, name := range l.services {
+			b.Services[name] = b.Base + i
+		}
+	}
+	return b
+}
+
+// loadPortLayout returns the rig's port layout, or nil if ports aren't allocated.
+func loadPortLayout(rigPath string) (*portLayout, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.PolecatPorts == nil {
+		return nil, nil
+	}
+	return parsePortsConfig(settings.PolecatPorts)
+}
+
+func portsStatePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "polecat-ports.json")
+}
+
+func loadPortAllocations(rigPath string) (*portAllocations, error) {
+	state := &portAllocations{Blocks: make(map[string]int)}
+	data, err := os.ReadFile(portsStatePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing port allocations: %w", err)
+	}
+	if state.Blocks == nil {
+		state.Blocks = make(map[string]int)
+	}
+	return state, nil
+}
+
+// lockPortAllocations serializes reservations across concurrent slings.
+func lockPortAllocations(rigPath string) (*flock.Flock, error) {
+	lockPath := portsStatePath(rigPath) + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("creating lock directory: %w", err)
+	}
+
+	lock := flock.New(lockPath)
+	ctx, cancel := context.WithTimeout(context.Background(), portsLockTimeout)
+	defer cancel()
+
+	locked, err := lock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring lock: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("timeout waiting for port allocation lock")
+	}
+	return lock, nil
+}
+
+// PortsFor returns a polecat's reserved port block, or nil if it has none.
+// The block is recomputed from the current config, so changing services
+// takes effect on the next session start.
+func PortsFor(rigPath, name string) *PortBlock {
+	layout, err := loadPortLayout(rigPath)
+	if err != nil || layout == nil {
+		return nil
+	}
+	state, err := loadPortAllocations(rigPath)
+	if err != nil {
+		return nil
+	}
+	index, ok := state.Blocks[name]
+	if !ok || index >= layout.blocks() {
+		return nil
+	}
+	return layout.block(index)
+}
+
+// ReservePorts reserves a port block for a polecat. The block is
+// deterministic: a polecat gets the block matching its name pool index
+// when it is free, so a recreated polecat usually gets its old ports back.
+// Reserving for a polecat that already has a block returns that block.
+// Returns nil without error if the rig doesn't allocate ports.
+func (m *Manager) ReservePorts(name string) (*PortBlock, error) {
+	layout, err := loadPortLayout(m.rig.Path)
+	if err != nil {
+		return nil, err
+	}
+	if layout == nil {
+		return nil, nil
+	}
+
+	lock, err := lockPortAllocations(m.rig.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	state, err := loadPortAllocations(m.rig.Path)
+	if err != nil {
+		return nil, err
+	}
+	if index, ok := state.Blocks[name]; ok && index < layout.blocks() {
+		return layout.block(index), nil
+	}
+
+	// Drop allocations of polecats that no longer exist (nuked without Remove)
+	taken := make(map[int]bool)
+	for polecat, index := range state.Blocks {
+		if polecat != name && !m.exists(polecat) {
+			delete(state.Blocks, polecat)
+			continue
+		}
+		taken[index] = true
+	}
+
+	index, err := freeBlock(layout.blocks(), m.preferredBlock(name), taken)
+	if err != nil {
+		return nil, err
+	}
+	state.Blocks[name] = index
+	if err := util.AtomicWriteJSON(portsStatePath(m.rig.Path), state); err != nil {
+		return nil, fmt.Errorf("saving port allocations: %w", err)
+	}
+	return layout.block(index), nil
+}
+
+// ReleasePorts frees a polecat's port block.
+func (m *Manager) ReleasePorts(name string) error {
+	lock, err := lockPortAllocations(m.rig.Path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	state, err := loadPortAllocations(m.rig.Path)
+	if err != nil {
+		return err
+	}
+	if _, ok := state.Blocks[name]; !ok {
+		return nil
+	}
+	delete(state.Blocks, name)
+	return util.AtomicWriteJSON(portsStatePath(m.rig.Path), state)
+}
+
+// preferredBlock returns the block index a polecat should get if free:
+// its name pool position, or a hash of the name for names outside the pool.
+func (m *Manager) preferredBlock(name string) int {
+	if index := m.namePool.Index(name); index > 0 {
+		return index - 1
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return int(h.Sum32() & 0x7fffffff)
+}
+
+// freeBlock returns the first free block at or after preferred, wrapping around.
+func freeBlock(blocks, preferred int, taken map[int]bool) (int, error) {
+	for i := 0; i < blocks; i++ {
+		index := (preferred + i) % blocks
+		if !taken[index] {
+			return index, nil
+		}
+	}
+	return 0, ErrPortsExhausted
+}
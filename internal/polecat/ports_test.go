@@ -0,0 +1,173 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// newPortsManager returns a manager for a temp rig with the given port config.
+func newPortsManager(t *testing.T, ports *config.PolecatPortsConfig) *Manager {
+	t.Helper()
+	root := t.TempDir()
+	settings := config.NewRigSettings()
+	settings.PolecatPorts = ports
+	if err := config.SaveRigSettings(config.RigSettingsPath(root), settings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	r := &rig.Rig{Name: "test-rig", Path: root}
+	return NewManager(r, git.NewGit(root), nil)
+}
+
+// mkPolecat creates a polecat dir so the allocator treats it as existing.
+func mkPolecat(t *testing.T, m *Manager, name string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(m.rig.Path, "polecats", name), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+}
+
+func TestParsePortsConfig(t *testing.T) {
+	tests := []struct {
+		cfg       config.PolecatPortsConfig
+		wantErr   bool
+		blockSize int
+		blocks    int
+	}{
+		{config.PolecatPortsConfig{Range: "20000-20099"}, false, 10, 10},
+		{config.PolecatPortsConfig{Range: "20000-20099", BlockSize: 25}, false, 25, 4},
+		{config.PolecatPortsConfig{Range: "20000-20009", BlockSize: 2, Services: []string{"a", "b", "c"}}, false, 3, 3},
+		{config.PolecatPortsConfig{Range: "20000"}, true, 0, 0},
+		{config.PolecatPortsConfig{Range: "20099-20000"}, true, 0, 0},
+		{config.PolecatPortsConfig{Range: "0-100"}, true, 0, 0},
+		{config.PolecatPortsConfig{Range: "20000-20004"}, true, 0, 0},
+	}
+	for _, tt := range tests {
+		l, err := parsePortsConfig(&tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePortsConfig(%+v) error = %v, wantErr %v", tt.cfg, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if l.blockSize != tt.blockSize || l.blocks() != tt.blocks {
+			t.Errorf("parsePortsConfig(%+v) = size %d blocks %d, want size %d blocks %d",
+				tt.cfg, l.blockSize, l.blocks(), tt.blockSize, tt.blocks)
+		}
+	}
+}
+
+func TestReservePorts_NoConfig(t *testing.T) {
+	root := t.TempDir()
+	m := NewManager(&rig.Rig{Name: "test-rig", Path: root}, git.NewGit(root), nil)
+
+	block, err := m.ReservePorts("furiosa")
+	if err != nil || block != nil {
+		t.Errorf("ReservePorts without config = %v, %v; want nil, nil", block, err)
+	}
+}
+
+func TestReservePorts_UnreadableSettings(t *testing.T) {
+	root := t.TempDir()
+	path := config.RigSettingsPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(&rig.Rig{Name: "test-rig", Path: root}, git.NewGit(root), nil)
+
+	if block, err := m.ReservePorts("furiosa"); err == nil {
+		t.Errorf("ReservePorts with unreadable settings = %v, want error", block)
+	}
+}
+
+func TestReservePorts_Deterministic(t *testing.T) {
+	m := newPortsManager(t, &config.PolecatPortsConfig{
+		Range:    "20000-20099",
+		Services: []string{"web", "db"},
+	})
+	mkPolecat(t, m, "nux")
+
+	// nux is the second name in the default theme, so it prefers block 1
+	block, err := m.ReservePorts("nux")
+	if err != nil {
+		t.Fatalf("ReservePorts: %v", err)
+	}
+	if block.Base != 20010 || block.End() != 20019 {
+		t.Errorf("nux block = %s, want 20010-20019", block)
+	}
+	if block.Services["web"] != 20010 || block.Services["db"] != 20011 {
+		t.Errorf("nux services = %v, want web=20010 db=20011", block.Services)
+	}
+
+	// Reserving again returns the same block
+	again, err := m.ReservePorts("nux")
+	if err != nil || again.Base != block.Base {
+		t.Errorf("second ReservePorts = %v, %v; want base %d", again, err, block.Base)
+	}
+	if got := PortsFor(m.rig.Path, "nux"); got == nil || got.Base != block.Base {
+		t.Errorf("PortsFor(nux) = %v, want base %d", got, block.Base)
+	}
+}
+
+func TestReservePorts_CollisionAndRelease(t *testing.T) {
+	m := newPortsManager(t, &config.PolecatPortsConfig{Range: "20000-20019"})
+	for _, name := range []string{"furiosa", "nux", "slit"} {
+		mkPolecat(t, m, name)
+	}
+
+	a, err := m.ReservePorts("furiosa")
+	if err != nil {
+		t.Fatalf("ReservePorts(furiosa): %v", err)
+	}
+	b, err := m.ReservePorts("nux")
+	if err != nil {
+		t.Fatalf("ReservePorts(nux): %v", err)
+	}
+	if a.Base == b.Base {
+		t.Fatalf("furiosa and nux share block %d", a.Base)
+	}
+
+	// Two blocks, both taken
+	if _, err := m.ReservePorts("slit"); err != ErrPortsExhausted {
+		t.Errorf("ReservePorts(slit) error = %v, want ErrPortsExhausted", err)
+	}
+
+	if err := m.ReleasePorts("furiosa"); err != nil {
+		t.Fatalf("ReleasePorts: %v", err)
+	}
+	if PortsFor(m.rig.Path, "furiosa") != nil {
+		t.Error("PortsFor(furiosa) after release should be nil")
+	}
+	c, err := m.ReservePorts("slit")
+	if err != nil {
+		t.Fatalf("ReservePorts(slit) after release: %v", err)
+	}
+	if c.Base != a.Base {
+		t.Errorf("slit block = %d, want freed block %d", c.Base, a.Base)
+	}
+}
+
+func TestReservePorts_ReclaimsRemovedPolecats(t *testing.T) {
+	m := newPortsManager(t, &config.PolecatPortsConfig{Range: "20000-20009"})
+	mkPolecat(t, m, "furiosa")
+	if _, err := m.ReservePorts("furiosa"); err != nil {
+		t.Fatalf("ReservePorts(furiosa): %v", err)
+	}
+
+	// furiosa's dir disappears without ReleasePorts (e.g. rm -rf)
+	if err := os.RemoveAll(filepath.Join(m.rig.Path, "polecats", "furiosa")); err != nil {
+		t.Fatal(err)
+	}
+	mkPolecat(t, m, "nux")
+	if _, err := m.ReservePorts("nux"); err != nil {
+		t.Errorf("ReservePorts(nux) should reclaim furiosa's block: %v", err)
+	}
+}
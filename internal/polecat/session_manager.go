@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -64,6 +65,14 @@ type SessionStartOptions struct {
 	// RuntimeConfigDir is resolved config directory for the runtime account.
 	// If set, this is injected as an environment variable.
 	RuntimeConfigDir string
+
+	// TraceID is the lifecycle trace ID assigned by gt sling.
+	// If set, it is exported to the session as GT_TRACE_ID.
+	TraceID string
+
+	// Warm starts the session for a warm pool polecat: the agent is told to
+	// wait for work instead of running its hook.
+	Warm bool
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -183,10 +192,14 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Build startup command with beacon for predecessor discovery.
 	// Topic "assigned" already includes instructions in FormatStartupBeacon.
 	address := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+	topic := "assigned"
+	if opts.Warm {
+		topic = "warm"
+	}
 	beacon := session.FormatStartupBeacon(session.BeaconConfig{
 		Recipient: address,
 		Sender:    "witness",
-		Topic:     "assigned",
+		Topic:     topic,
 		MolID:     opts.Issue,
 	})
 
@@ -198,6 +211,21 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
+	// The agent process starts before SetEnvironment below, so the trace ID
+	// must be on the command line for gt done to see it.
+	if opts.TraceID != "" {
+		command = config.PrependEnv(command, map[string]string{"GT_TRACE_ID": opts.TraceID})
+	}
+	// Same for the polecat's reserved ports: dev servers started by the agent
+	// inherit them from the agent process.
+	ports := PortsFor(m.rig.Path, polecat)
+	command = config.PrependEnv(command, ports.Env())
+
+	// Apply the rig's resource policy (cgroup limits, network namespace)
+	command, err = m.applyResourcePolicy(command, polecat)
+	if err != nil {
+		return err
+	}
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
@@ -215,6 +243,8 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.RuntimeConfigDir,
 		BeadsNoDaemon:    true,
+		TraceID:          opts.TraceID,
+		Ports:            ports.Env(),
 	})
 	for k, v := range envVars {
 		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
@@ -452,6 +482,35 @@ func (m *SessionManager) StopAll(force bool) error {
 	return lastErr
 }
 
+// applyResourcePolicy wraps the startup command with the rig's polecat
+// resource limits.
+func (m *SessionManager) applyResourcePolicy(command, polecat string) (string, error) {
+	return ApplyResourcePolicy(command, m.rig.Path, m.rig.Name, polecat, func(format string, args ...interface{}) {
+		fmt.Printf("Warning: "+format+"\n", args...)
+	})
+}
+
+// ApplyResourcePolicy wraps a polecat startup command with the rig's
+// resource limits. It is shared by every path that starts a polecat agent,
+// including daemon restarts, so limits survive a crash or account rotation.
+// When the host can't apply the limits the polecat starts unconstrained and
+// warnf is told why, unless the policy marks them required.
+func ApplyResourcePolicy(command, rigPath, rigName, polecat string, warnf func(format string, args ...interface{})) (string, error) {
+	res, err := sandbox.LoadPolicy(rigPath)
+	if err != nil {
+		return "", fmt.Errorf("loading resource policy: %w", err)
+	}
+	wrapped, err := sandbox.Wrap(command, sandbox.UnitName(rigName, polecat), res, sandbox.Detect())
+	if err != nil {
+		if res.Required || !errors.Is(err, sandbox.ErrUnsupported) {
+			return "", fmt.Errorf("applying resource policy: %w", err)
+		}
+		warnf("starting %s without resource limits: %v", polecat, err)
+		return command, nil
+	}
+	return wrapped, nil
+}
+
 // validateIssue checks that an issue exists and is not tombstoned.
 // This must be called before starting a session to avoid CPU spin loops
 // from agents retrying work on invalid issues.
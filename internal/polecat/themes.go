@@ -0,0 +1,224 @@
+package polecat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Allocation strategies for picking a free name from a theme.
+const (
+	// StrategySequential takes the first free name in theme order.
+	StrategySequential = "sequential"
+
+	// StrategyRandom takes a free name uniformly at random.
+	StrategyRandom = "random"
+
+	// StrategyWeighted takes a free name at random, in proportion to the
+	// weights given in the theme file. Names without a weight count as 1.
+	StrategyWeighted = "weighted"
+)
+
+// Theme sources, as reported by Theme.Source.
+const (
+	ThemeSourceBuiltin = "built-in"
+	ThemeSourceTown    = "town"
+	ThemeSourceRig     = "rig"
+)
+
+// ThemesDir is the directory under a town's or rig's settings/ that holds
+// theme files.
+const ThemesDir = "namepool"
+
+// Theme is a named list of polecat names.
+type Theme struct {
+	Name string
+
+	// Names in allocation order.
+	Names []string
+
+	// Weights for StrategyWeighted, keyed by name.
+	Weights map[string]int
+
+	// Strategy is the theme's preferred allocation strategy, used when the
+	// rig's namepool settings don't choose one.
+	Strategy string
+
+	// Description is a one-line summary from the theme file.
+	Description string
+
+	// Source is where the theme was defined (ThemeSourceBuiltin, Town or Rig).
+	Source string
+
+	// Path is the theme file, empty for built-in themes.
+	Path string
+}
+
+// themeFile is the TOML theme file format:
+//
+//	description = "Norse gods"
+//	strategy = "weighted"
+//	names = ["odin", "thor", "freya"]
+//
+//	[weights]
+//	thor = 3
+type themeFile struct {
+	Description string         `toml:"description"`
+	Strategy    string         `toml:"strategy"`
+	Names       []string       `toml:"names"`
+	Weights     map[string]int `toml:"weights"`
+}
+
+// LoadThemeFile reads a theme from a .toml file, or from a plain text file
+// with one name per line. Text lines may carry a weight after the name
+// ("thor 3"); blank lines and lines starting with # are ignored.
+func LoadThemeFile(path string) (*Theme, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the settings directory
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	theme := &Theme{Name: name, Path: path, Weights: make(map[string]int)}
+
+	if filepath.Ext(path) == ".toml" {
+		var tf themeFile
+		if _, err := toml.Decode(string(data), &tf); err != nil {
+			return nil, fmt.Errorf("parsing theme %s: %w", path, err)
+		}
+		theme.Description = tf.Description
+		theme.Strategy = tf.Strategy
+		theme.Names = tf.Names
+		for n, w := range tf.Weights {
+			theme.Weights[n] = w
+		}
+	} else {
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.Fields(line)
+			if len(fields) > 2 {
+				return nil, fmt.Errorf("%s:%d: expected a name and an optional weight", path, lineNum)
+			}
+			theme.Names = append(theme.Names, fields[0])
+			if len(fields) == 2 {
+				w, err := strconv.Atoi(fields[1])
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: invalid weight %q", path, lineNum, fields[1])
+				}
+				theme.Weights[fields[0]] = w
+			}
+		}
+	}
+
+	if err := theme.validate(); err != nil {
+		return nil, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// validate checks a theme's names, weights and strategy.
+func (t *Theme) validate() error {
+	if len(t.Names) == 0 {
+		return fmt.Errorf("no names")
+	}
+	seen := make(map[string]bool, len(t.Names))
+	for _, n := range t.Names {
+		if strings.ContainsAny(n, "/\\. ") {
+			return fmt.Errorf("invalid name %q", n)
+		}
+		if seen[n] {
+			return fmt.Errorf("duplicate name %q", n)
+		}
+		seen[n] = true
+	}
+	for n, w := range t.Weights {
+		if !seen[n] {
+			return fmt.Errorf("weight for unknown name %q", n)
+		}
+		if w < 1 {
+			return fmt.Errorf("weight for %q must be at least 1", n)
+		}
+	}
+	if t.Strategy != "" && !validStrategy(t.Strategy) {
+		return fmt.Errorf("unknown strategy %q", t.Strategy)
+	}
+	return nil
+}
+
+// validStrategy reports whether s names an allocation strategy.
+func validStrategy(s string) bool {
+	return s == StrategySequential || s == StrategyRandom || s == StrategyWeighted
+}
+
+// LoadThemes returns the built-in themes plus the theme files defined for a
+// town and rig. Files are read from settings/namepool/ (*.toml, *.txt); rig
+// themes override town themes, which override built-ins of the same name.
+// An empty townRoot or rigPath skips that level. A file that can't be read
+// or parsed is skipped and reported in the returned error; the other themes
+// still load.
+func LoadThemes(townRoot, rigPath string) (map[string]*Theme, error) {
+	themes := make(map[string]*Theme, len(BuiltinThemes))
+	for name, names := range BuiltinThemes {
+		themes[name] = &Theme{Name: name, Names: names, Source: ThemeSourceBuiltin}
+	}
+
+	levels := []struct {
+		root, source string
+	}{
+		{townRoot, ThemeSourceTown},
+		{rigPath, ThemeSourceRig},
+	}
+	var errs []error
+	for _, level := range levels {
+		if level.root == "" {
+			continue
+		}
+		dir := filepath.Join(level.root, constants.DirSettings, ThemesDir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			errs = append(errs, err)
+			continue
+		}
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if e.IsDir() || (ext != ".toml" && ext != ".txt") {
+				continue
+			}
+			theme, err := LoadThemeFile(filepath.Join(dir, e.Name()))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			theme.Source = level.source
+			themes[theme.Name] = theme
+		}
+	}
+	return themes, errors.Join(errs...)
+}
+
+// SortedThemes returns themes sorted by name.
+func SortedThemes(themes map[string]*Theme) []*Theme {
+	sorted := make([]*Theme, 0, len(themes))
+	for _, t := range themes {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
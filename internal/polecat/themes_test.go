@@ -0,0 +1,127 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeThemeFile(t *testing.T, root, name, content string) string {
+	t.Helper()
+	dir := filepath.Join(root, "settings", ThemesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadThemeFileText(t *testing.T) {
+	path := writeThemeFile(t, t.TempDir(), "norse.txt", "# Norse gods\nodin\n\nthor 3\n  freya  \n")
+
+	theme, err := LoadThemeFile(path)
+	if err != nil {
+		t.Fatalf("LoadThemeFile: %v", err)
+	}
+	if theme.Name != "norse" {
+		t.Errorf("Name = %q, want norse", theme.Name)
+	}
+	if strings.Join(theme.Names, ",") != "odin,thor,freya" {
+		t.Errorf("Names = %v", theme.Names)
+	}
+	if theme.Weights["thor"] != 3 || theme.Weights["odin"] != 0 {
+		t.Errorf("Weights = %v", theme.Weights)
+	}
+}
+
+func TestLoadThemeFileTOML(t *testing.T) {
+	path := writeThemeFile(t, t.TempDir(), "birds.toml", `description = "Birds of prey"
+strategy = "weighted"
+names = ["kestrel", "harrier", "osprey"]
+
+[weights]
+osprey = 5
+`)
+
+	theme, err := LoadThemeFile(path)
+	if err != nil {
+		t.Fatalf("LoadThemeFile: %v", err)
+	}
+	if theme.Name != "birds" || theme.Strategy != StrategyWeighted || theme.Description != "Birds of prey" {
+		t.Errorf("theme = %+v", theme)
+	}
+	if len(theme.Names) != 3 || theme.Weights["osprey"] != 5 {
+		t.Errorf("names/weights = %v %v", theme.Names, theme.Weights)
+	}
+}
+
+func TestLoadThemeFileInvalid(t *testing.T) {
+	tests := map[string]string{
+		"empty.txt":     "# nothing here\n",
+		"dupe.txt":      "odin\nodin\n",
+		"weight.txt":    "odin heavy\n",
+		"zero.txt":      "odin 0\n",
+		"path.txt":      "../odin\n",
+		"strategy.toml": "strategy = \"round-robin\"\nnames = [\"odin\"]\n",
+		"unknown.toml":  "names = [\"odin\"]\n[weights]\nthor = 2\n",
+	}
+	root := t.TempDir()
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeThemeFile(t, root, name, content)
+			if _, err := LoadThemeFile(path); err == nil {
+				t.Errorf("expected error for %s", name)
+			}
+		})
+	}
+}
+
+func TestLoadThemesOverrides(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	writeThemeFile(t, townRoot, "norse.txt", "odin\nthor\n")
+	writeThemeFile(t, townRoot, "minerals.txt", "quartz\n")
+	writeThemeFile(t, rigPath, "norse.txt", "loki\n")
+	writeThemeFile(t, rigPath, "README.md", "not a theme\n")
+
+	themes, err := LoadThemes(townRoot, rigPath)
+	if err != nil {
+		t.Fatalf("LoadThemes: %v", err)
+	}
+
+	if th := themes["mad-max"]; th == nil || th.Source != ThemeSourceBuiltin {
+		t.Errorf("expected built-in mad-max, got %+v", th)
+	}
+	if th := themes["minerals"]; th == nil || th.Source != ThemeSourceTown || len(th.Names) != 1 {
+		t.Errorf("expected town to override built-in minerals, got %+v", th)
+	}
+	if th := themes["norse"]; th == nil || th.Source != ThemeSourceRig || th.Names[0] != "loki" {
+		t.Errorf("expected rig to override town norse, got %+v", th)
+	}
+	if _, ok := themes["README"]; ok {
+		t.Error("non-theme file loaded as a theme")
+	}
+}
+
+func TestLoadThemesSkipsBadFiles(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	writeThemeFile(t, townRoot, "broken.toml", "names = [")
+	writeThemeFile(t, townRoot, "birds.txt", "hawk\n")
+	writeThemeFile(t, rigPath, "norse.txt", "loki\n")
+
+	themes, err := LoadThemes(townRoot, rigPath)
+	if err == nil || !strings.Contains(err.Error(), "broken.toml") {
+		t.Errorf("LoadThemes error = %v, want one naming broken.toml", err)
+	}
+	if th := themes["birds"]; th == nil || th.Source != ThemeSourceTown {
+		t.Errorf("expected town birds theme, got %+v", th)
+	}
+	if th := themes["norse"]; th == nil || th.Source != ThemeSourceRig {
+		t.Errorf("expected rig norse theme after a bad town file, got %+v", th)
+	}
+}
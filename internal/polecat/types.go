@@ -26,7 +26,8 @@ type State string
 const (
 	// StateWorking means the polecat session is actively working on an issue.
 	// This is the initial and primary state for transient polecats.
-	// Working is the ONLY healthy operating state - there is no idle pool.
+	// Working is the ONLY healthy operating state for a polecat with work;
+	// idle polecats exist only in the opt-in warm pool (StateWarm).
 	StateWorking State = "working"
 
 	// StateDone means the polecat has completed its assigned work and called
@@ -40,6 +41,11 @@ const (
 	// Different from "stalled" (detected externally when session stops working).
 	StateStuck State = "stuck"
 
+	// StateWarm means the polecat is in the rig's warm pool: its worktree and
+	// setup are ready but it has no work yet. gt sling claims warm polecats
+	// before creating new ones. This is the only idle state.
+	StateWarm State = "warm"
+
 	// StateActive is deprecated: use StateWorking.
 	// Kept only for backward compatibility with existing data.
 	StateActive State = "active"
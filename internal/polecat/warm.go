@@ -0,0 +1,365 @@
+package polecat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// AgentStateWarm is the agent_state recorded for warm pool polecats.
+const AgentStateWarm = "warm"
+
+// DefaultWarmMaxAge is how long a warm polecat waits before it is recycled.
+const DefaultWarmMaxAge = 24 * time.Hour
+
+// warmMarker marks a polecat as part of the warm pool. It lives in the
+// polecat's home dir (polecats/<name>/), outside the worktree. Claiming a
+// warm polecat renames the marker, which is atomic, so two concurrent
+// slings can never be handed the same polecat.
+const warmMarker = ".warm"
+
+// ErrNoWarmPolecat is returned by ClaimWarm when the pool is empty.
+var ErrNoWarmPolecat = errors.New("no warm polecat available")
+
+// WarmInfo is the content of a warm polecat's marker file.
+type WarmInfo struct {
+	CreatedAt time.Time `json:"created_at"`
+	Branch    string    `json:"branch"`
+}
+
+// WarmPoolStatus summarizes a rig's warm pool.
+type WarmPoolStatus struct {
+	Size     int      `json:"size"`     // Configured pool size
+	Ready    []string `json:"ready"`    // Warm polecats ready to claim, oldest first
+	Sessions int      `json:"sessions"` // Warm polecats with a pre-started session
+}
+
+// WarmPoolConfig returns the rig's warm pool settings, or nil if the pool
+// is not configured.
+func (m *Manager) WarmPoolConfig() *config.PolecatPoolConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || settings.PolecatPool == nil || settings.PolecatPool.Size <= 0 {
+		return nil
+	}
+	return settings.PolecatPool
+}
+
+// warmMaxAge returns the configured max age for warm polecats.
+func warmMaxAge(cfg *config.PolecatPoolConfig) time.Duration {
+	if cfg != nil && cfg.MaxAge != "" {
+		if d, err := time.ParseDuration(cfg.MaxAge); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultWarmMaxAge
+}
+
+func (m *Manager) warmMarkerPath(name string) string {
+	return filepath.Join(m.polecatDir(name), warmMarker)
+}
+
+// IsWarm reports whether a polecat is in the warm pool.
+func (m *Manager) IsWarm(name string) bool {
+	_, err := os.Stat(m.warmMarkerPath(name))
+	return err == nil
+}
+
+// readWarmInfo reads a warm polecat's marker.
+func (m *Manager) readWarmInfo(name string) (*WarmInfo, error) {
+	data, err := os.ReadFile(m.warmMarkerPath(name)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var info WarmInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing warm marker for %s: %w", name, err)
+	}
+	return &info, nil
+}
+
+// WarmPolecats returns the names of warm polecats, oldest first.
+func (m *Manager) WarmPolecats() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.rig.Path, "polecats"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading polecats dir: %w", err)
+	}
+
+	type warm struct {
+		name    string
+		created time.Time
+	}
+	var pool []warm
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name()[0] == '.' {
+			continue
+		}
+		info, err := m.readWarmInfo(entry.Name())
+		if err != nil {
+			continue
+		}
+		pool = append(pool, warm{entry.Name(), info.CreatedAt})
+	}
+	sort.Slice(pool, func(i, j int) bool { return pool[i].created.Before(pool[j].created) })
+
+	names := make([]string, len(pool))
+	for i, w := range pool {
+		names[i] = w.name
+	}
+	return names, nil
+}
+
+// WarmPoolStatus returns the rig's warm pool status.
+func (m *Manager) WarmPoolStatus() (*WarmPoolStatus, error) {
+	status := &WarmPoolStatus{}
+	if cfg := m.WarmPoolConfig(); cfg != nil {
+		status.Size = cfg.Size
+	}
+	ready, err := m.WarmPolecats()
+	if err != nil {
+		return nil, err
+	}
+	status.Ready = ready
+	if m.tmux != nil {
+		for _, name := range ready {
+			if running, _ := m.tmux.HasSession(fmt.Sprintf("gt-%s-%s", m.rig.Name, name)); running {
+				status.Sessions++
+			}
+		}
+	}
+	return status, nil
+}
+
+// AddWarm creates a polecat for the warm pool: a worktree on a fresh branch
+// with overlay, shared beads and setup hooks in place, but no work.
+func (m *Manager) AddWarm() (*Polecat, error) {
+	name, err := m.AllocateName()
+	if err != nil {
+		return nil, fmt.Errorf("allocating polecat name: %w", err)
+	}
+
+	p, err := m.AddWithOptions(name, AddOptions{AgentState: AgentStateWarm})
+	if err != nil {
+		m.ReleaseName(name)
+		return nil, err
+	}
+
+	data, err := json.Marshal(WarmInfo{CreatedAt: time.Now(), Branch: p.Branch})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(m.warmMarkerPath(name), data, 0644); err != nil { //nolint:gosec // G306: marker is not sensitive
+		_ = m.RemoveWithOptions(name, true, true)
+		return nil, fmt.Errorf("writing warm marker: %w", err)
+	}
+
+	p.State = StateWarm
+	return p, nil
+}
+
+// claimMarker atomically takes a polecat out of the warm pool.
+// It returns false if another process claimed it first.
+func (m *Manager) claimMarker(name string) bool {
+	claimed := m.warmMarkerPath(name) + fmt.Sprintf(".claimed-%d", os.Getpid())
+	if err := os.Rename(m.warmMarkerPath(name), claimed); err != nil {
+		return false
+	}
+	_ = os.Remove(claimed)
+	return true
+}
+
+// ClaimWarm takes the oldest warm polecat out of the pool and prepares it
+// for work: the worktree moves to a fresh branch from the latest
+// origin/<default-branch> and the agent bead gets the hook bead.
+// Returns ErrNoWarmPolecat if the pool is empty.
+//
+// If the polecat has a pre-started session, the caller only needs to nudge
+// it. The session predates the sling: it runs without an account config dir
+// and without GT_TRACE_ID, so callers that need either must restart it or
+// set the session environment.
+func (m *Manager) ClaimWarm(opts AddOptions) (*Polecat, error) {
+	names, err := m.WarmPolecats()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if !m.claimMarker(name) {
+			continue // Claimed by a concurrent sling
+		}
+
+		p, err := m.prepareClaimed(name, opts)
+		if err != nil {
+			fmt.Printf("Warning: discarding warm polecat %s: %v\n", name, err)
+			_ = m.RemoveWithOptions(name, true, true)
+			continue
+		}
+		return p, nil
+	}
+	return nil, ErrNoWarmPolecat
+}
+
+// prepareClaimed moves a claimed warm polecat onto a fresh work branch
+// and assigns its hook bead.
+func (m *Manager) prepareClaimed(name string, opts AddOptions) (*Polecat, error) {
+	clonePath := m.clonePath(name)
+	polecatGit := git.NewGit(clonePath)
+
+	// Fetch through the shared repo base so the new branch starts from the
+	// latest default branch, not from when the polecat was warmed.
+	if repoGit, err := m.repoBase(); err == nil {
+		if err := repoGit.Fetch("origin"); err != nil {
+			fmt.Printf("Warning: could not fetch origin: %v\n", err)
+		}
+	}
+
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+
+	oldBranch, _ := polecatGit.CurrentBranch()
+	branchName := m.buildBranchName(name, opts.HookBead)
+	if err := polecatGit.CreateBranchFrom(branchName, "origin/"+defaultBranch); err != nil {
+		return nil, fmt.Errorf("creating branch %s: %w", branchName, err)
+	}
+	// Local changes from setup (overlay files, .gitignore patterns) carry over
+	// to the new branch; a conflict with upstream changes discards the polecat.
+	if err := polecatGit.Checkout(branchName); err != nil {
+		_ = polecatGit.DeleteBranch(branchName, true)
+		return nil, fmt.Errorf("checking out %s: %w", branchName, err)
+	}
+	if oldBranch != "" && oldBranch != branchName {
+		_ = polecatGit.DeleteBranch(oldBranch, true) // never pushed; safe to drop
+	}
+
+	if err := m.setupSharedBeads(clonePath, opts.HookBead); err != nil {
+		fmt.Printf("Warning: could not set up shared beads: %v\n", err)
+	}
+
+	// Re-render the overlay: templated files may use the hook bead.
+	if err := rig.ApplyOverlay(m.rig.Path, clonePath, m.OverlayVars(name, opts.HookBead)); err != nil {
+		fmt.Printf("Warning: could not copy overlay files: %v\n", err)
+	}
+
+	agentState := opts.AgentState
+	if agentState == "" {
+		agentState = "spawning"
+	}
+	agentID := m.agentBeadID(name)
+	if _, err := m.beads.CreateOrReopenAgentBead(agentID, agentID, &beads.AgentFields{
+		RoleType:   "polecat",
+		Rig:        m.rig.Name,
+		AgentState: agentState,
+		HookBead:   opts.HookBead,
+	}); err != nil {
+		fmt.Printf("Warning: could not update agent bead: %v\n", err)
+	}
+
+	now := time.Now()
+	return &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking,
+		ClonePath: clonePath,
+		Branch:    branchName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// ReplenishWarmPool recycles expired warm polecats and creates new ones
+// until the pool reaches its configured size. When the pool is configured
+// with start_sessions, warm polecats without a session get one started.
+// Returns the number of polecats created and recycled.
+func (m *Manager) ReplenishWarmPool() (created, recycled int, err error) {
+	cfg := m.WarmPoolConfig()
+	if cfg == nil {
+		return 0, 0, nil
+	}
+
+	ready, err := m.WarmPolecats()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Recycle expired polecats (claim first so a sling can't grab one mid-removal)
+	maxAge := warmMaxAge(cfg)
+	var live []string
+	for _, name := range ready {
+		info, err := m.readWarmInfo(name)
+		if err == nil && time.Since(info.CreatedAt) > maxAge && m.claimMarker(name) {
+			m.stopWarmSession(name)
+			if err := m.RemoveWithOptions(name, true, true); err == nil {
+				recycled++
+				continue
+			}
+		}
+		live = append(live, name)
+	}
+
+	for len(live) < cfg.Size {
+		p, err := m.AddWarm()
+		if err != nil {
+			return created, recycled, fmt.Errorf("warming polecat: %w", err)
+		}
+		live = append(live, p.Name)
+		created++
+	}
+
+	if cfg.StartSessions && m.tmux != nil {
+		sessMgr := NewSessionManager(m.tmux, m.rig)
+		for _, name := range live {
+			if running, _ := sessMgr.IsRunning(name); running || !m.IsWarm(name) {
+				continue
+			}
+			if err := sessMgr.Start(name, SessionStartOptions{Warm: true}); err != nil {
+				return created, recycled, fmt.Errorf("starting warm session for %s: %w", name, err)
+			}
+		}
+	}
+
+	return created, recycled, nil
+}
+
+// DrainWarmPool removes every warm polecat. Returns the number removed.
+func (m *Manager) DrainWarmPool() (int, error) {
+	names, err := m.WarmPolecats()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, name := range names {
+		if !m.claimMarker(name) {
+			continue
+		}
+		m.stopWarmSession(name)
+		if err := m.RemoveWithOptions(name, true, true); err != nil {
+			return removed, fmt.Errorf("removing %s: %w", name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// stopWarmSession kills a warm polecat's pre-started session, if any.
+func (m *Manager) stopWarmSession(name string) {
+	if m.tmux == nil {
+		return
+	}
+	sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, name)
+	if running, _ := m.tmux.HasSession(sessionName); running {
+		_ = m.tmux.KillSessionWithProcesses(sessionName)
+	}
+}
@@ -0,0 +1,124 @@
+package polecat
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// writeWarm creates a polecat dir with a warm marker created at the given time.
+func writeWarm(t *testing.T, root, name string, created time.Time) {
+	t.Helper()
+	dir := filepath.Join(root, "polecats", name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data, _ := json.Marshal(WarmInfo{CreatedAt: created, Branch: "polecat/" + name})
+	if err := os.WriteFile(filepath.Join(dir, warmMarker), data, 0644); err != nil {
+		t.Fatalf("write marker: %v", err)
+	}
+}
+
+func TestWarmPolecatsOldestFirst(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	writeWarm(t, root, "Toast", now.Add(-time.Hour))
+	writeWarm(t, root, "Cheedo", now.Add(-3*time.Hour))
+	writeWarm(t, root, "Nux", now.Add(-2*time.Hour))
+	// A working polecat (no marker) is not part of the pool
+	if err := os.MkdirAll(filepath.Join(root, "polecats", "Furiosa"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	r := &rig.Rig{Name: "test-rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+
+	names, err := m.WarmPolecats()
+	if err != nil {
+		t.Fatalf("WarmPolecats: %v", err)
+	}
+	want := []string{"Cheedo", "Nux", "Toast"}
+	if len(names) != len(want) {
+		t.Fatalf("WarmPolecats = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("WarmPolecats[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+
+	if !m.IsWarm("Toast") {
+		t.Error("IsWarm(Toast) = false, want true")
+	}
+	if m.IsWarm("Furiosa") {
+		t.Error("IsWarm(Furiosa) = true, want false")
+	}
+}
+
+func TestClaimMarkerOnce(t *testing.T) {
+	root := t.TempDir()
+	writeWarm(t, root, "Toast", time.Now())
+
+	r := &rig.Rig{Name: "test-rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+
+	if !m.claimMarker("Toast") {
+		t.Fatal("first claimMarker = false, want true")
+	}
+	if m.claimMarker("Toast") {
+		t.Error("second claimMarker = true, want false")
+	}
+	if m.IsWarm("Toast") {
+		t.Error("IsWarm after claim = true, want false")
+	}
+}
+
+func TestClaimWarmEmptyPool(t *testing.T) {
+	root := t.TempDir()
+	r := &rig.Rig{Name: "test-rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+
+	if _, err := m.ClaimWarm(AddOptions{}); err != ErrNoWarmPolecat {
+		t.Errorf("ClaimWarm on empty pool = %v, want ErrNoWarmPolecat", err)
+	}
+}
+
+func TestWarmPoolConfig(t *testing.T) {
+	root := t.TempDir()
+	r := &rig.Rig{Name: "test-rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+
+	if cfg := m.WarmPoolConfig(); cfg != nil {
+		t.Errorf("WarmPoolConfig without settings = %+v, want nil", cfg)
+	}
+
+	settings := config.NewRigSettings()
+	settings.PolecatPool = &config.PolecatPoolConfig{Size: 2, MaxAge: "6h"}
+	if err := config.SaveRigSettings(config.RigSettingsPath(root), settings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cfg := m.WarmPoolConfig()
+	if cfg == nil || cfg.Size != 2 {
+		t.Fatalf("WarmPoolConfig = %+v, want size 2", cfg)
+	}
+	if got := warmMaxAge(cfg); got != 6*time.Hour {
+		t.Errorf("warmMaxAge = %v, want 6h", got)
+	}
+	if got := warmMaxAge(&config.PolecatPoolConfig{MaxAge: "bogus"}); got != DefaultWarmMaxAge {
+		t.Errorf("warmMaxAge(bogus) = %v, want %v", got, DefaultWarmMaxAge)
+	}
+}
+
+func TestAssessStalenessWarm(t *testing.T) {
+	info := &StalenessInfo{AgentState: AgentStateWarm, CommitsBehind: 100}
+	if stale, _ := assessStaleness(info, 20); stale {
+		t.Error("warm polecat assessed as stale")
+	}
+}
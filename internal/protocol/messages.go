@@ -44,6 +44,9 @@ func formatMergeReadyBody(p MergeReadyPayload) string {
 	if p.Verified != "" {
 		sb.WriteString(fmt.Sprintf("Verified: %s\n", p.Verified))
 	}
+	if p.TraceID != "" {
+		sb.WriteString(fmt.Sprintf("Trace-ID: %s\n", p.TraceID))
+	}
 	return sb.String()
 }
 
@@ -86,6 +89,9 @@ func formatMergedBody(p MergedPayload) string {
 	if p.MergeCommit != "" {
 		sb.WriteString(fmt.Sprintf("Merge-Commit: %s\n", p.MergeCommit))
 	}
+	if p.TraceID != "" {
+		sb.WriteString(fmt.Sprintf("Trace-ID: %s\n", p.TraceID))
+	}
 	return sb.String()
 }
 
@@ -128,6 +134,9 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	if p.TraceID != "" {
+		sb.WriteString(fmt.Sprintf("Trace-ID: %s\n", p.TraceID))
+	}
 	return sb.String()
 }
 
@@ -159,6 +168,35 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 	return msg
 }
 
+// NewPRReworkRequestMessage creates a REWORK_REQUEST protocol message for
+// changes asked for on a branch's pull request. reason is ReworkReview or
+// ReworkChecks; details lists the review comments or failed checks.
+func NewPRReworkRequestMessage(rig, polecat, branch, issue, targetBranch, reason, prURL, taskID, details string) *mail.Message {
+	payload := ReworkRequestPayload{
+		Branch:       branch,
+		Issue:        issue,
+		Polecat:      polecat,
+		Rig:          rig,
+		RequestedAt:  time.Now(),
+		TargetBranch: targetBranch,
+		Reason:       reason,
+		PullRequest:  prURL,
+		Task:         taskID,
+		Instructions: formatPRReworkInstructions(branch, taskID, details),
+	}
+
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/refinery", rig),
+		fmt.Sprintf("%s/witness", rig),
+		fmt.Sprintf("REWORK_REQUEST %s", polecat),
+		formatReworkRequestBody(payload),
+	)
+	msg.Priority = mail.PriorityHigh
+	msg.Type = mail.TypeTask
+
+	return msg
+}
+
 // formatReworkRequestBody formats the body of a REWORK_REQUEST message.
 func formatReworkRequestBody(p ReworkRequestPayload) string {
 	var sb strings.Builder
@@ -172,6 +210,18 @@ func formatReworkRequestBody(p ReworkRequestPayload) string {
 	if len(p.ConflictFiles) > 0 {
 		sb.WriteString(fmt.Sprintf("Conflict-Files: %s\n", strings.Join(p.ConflictFiles, ", ")))
 	}
+	if p.Reason != "" {
+		sb.WriteString(fmt.Sprintf("Reason: %s\n", p.Reason))
+	}
+	if p.PullRequest != "" {
+		sb.WriteString(fmt.Sprintf("Pull-Request: %s\n", p.PullRequest))
+	}
+	if p.Task != "" {
+		sb.WriteString(fmt.Sprintf("Task: %s\n", p.Task))
+	}
+	if p.TraceID != "" {
+		sb.WriteString(fmt.Sprintf("Trace-ID: %s\n", p.TraceID))
+	}
 
 	sb.WriteString("\n")
 	sb.WriteString(p.Instructions)
@@ -191,6 +241,27 @@ func formatRebaseInstructions(targetBranch string) string {
 The Refinery will retry the merge after rebase is complete.`, targetBranch, targetBranch)
 }
 
+// formatPRReworkInstructions returns instructions for addressing pull
+// request feedback.
+func formatPRReworkInstructions(branch, taskID, details string) string {
+	closeTask := ""
+	if taskID != "" {
+		closeTask = fmt.Sprintf("\n  bd close %s", taskID)
+	}
+	return fmt.Sprintf(`Changes were requested on your pull request:
+
+%s
+
+Address them on the same branch:
+
+  git checkout %s
+  # Make the changes
+  git push%s
+
+The pull request updates itself; the Refinery merges it once the
+reviewers and checks are satisfied.`, strings.TrimSpace(details), branch, closeTask)
+}
+
 // ParseMergeReadyPayload parses a MERGE_READY message body into a payload.
 func ParseMergeReadyPayload(body string) *MergeReadyPayload {
 	return &MergeReadyPayload{
@@ -200,6 +271,7 @@ func ParseMergeReadyPayload(body string) *MergeReadyPayload {
 		Rig:       parseField(body, "Rig"),
 		Verified:  parseField(body, "Verified"),
 		Timestamp: time.Now(), // Use current time if not parseable
+		TraceID:   parseField(body, "Trace-ID"),
 	}
 }
 
@@ -212,6 +284,7 @@ func ParseMergedPayload(body string) *MergedPayload {
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
 		MergeCommit:  parseField(body, "Merge-Commit"),
+		TraceID:      parseField(body, "Trace-ID"),
 	}
 
 	// Parse timestamp
@@ -234,6 +307,7 @@ func ParseMergeFailedPayload(body string) *MergeFailedPayload {
 		TargetBranch: parseField(body, "Target"),
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
+		TraceID:      parseField(body, "Trace-ID"),
 	}
 
 	// Parse timestamp
@@ -254,6 +328,7 @@ func ParseReworkRequestPayload(body string) *ReworkRequestPayload {
 		Polecat:      parseField(body, "Polecat"),
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
+		TraceID:      parseField(body, "Trace-ID"),
 	}
 
 	// Parse timestamp
@@ -268,9 +343,31 @@ func ParseReworkRequestPayload(body string) *ReworkRequestPayload {
 		payload.ConflictFiles = strings.Split(files, ", ")
 	}
 
+	payload.Reason = parseField(body, "Reason")
+	payload.PullRequest = parseField(body, "Pull-Request")
+	payload.Task = parseField(body, "Task")
+
+	// Instructions follow the header fields after a blank line
+	if _, instructions, ok := strings.Cut(body, "\n\n"); ok {
+		payload.Instructions = strings.TrimSpace(instructions)
+	}
+
 	return payload
 }
 
+// SetTraceID tags a protocol message with the lifecycle trace ID of the work
+// it concerns, so the receiver can carry the trace forward (see gt trace).
+// Parse*Payload functions read it back into the payload's TraceID.
+func SetTraceID(msg *mail.Message, traceID string) {
+	if msg == nil || traceID == "" {
+		return
+	}
+	if msg.Body != "" && !strings.HasSuffix(msg.Body, "\n") {
+		msg.Body += "\n"
+	}
+	msg.Body += fmt.Sprintf("Trace-ID: %s\n", traceID)
+}
+
 // parseField extracts a field value from a key-value body format.
 // Format: "Key: value"
 func parseField(body, key string) string {
@@ -197,6 +197,27 @@ Merge-Commit: abc123`
 	}
 }
 
+func TestSetTraceID(t *testing.T) {
+	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed")
+	SetTraceID(msg, "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	payload := ParseMergeFailedPayload(msg.Body)
+	if payload.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %q, want trace ID", payload.TraceID)
+	}
+	if payload.Error != "Test failed" {
+		t.Errorf("Error = %q, want %q", payload.Error, "Test failed")
+	}
+
+	// No trace ID leaves the body untouched
+	msg = NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+	body := msg.Body
+	SetTraceID(msg, "")
+	if msg.Body != body {
+		t.Errorf("SetTraceID with empty ID changed body")
+	}
+}
+
 func TestHandlerRegistry(t *testing.T) {
 	registry := NewHandlerRegistry()
 
@@ -35,7 +35,8 @@ const (
 	TypeMergeFailed MessageType = "MERGE_FAILED"
 
 	// TypeReworkRequest is sent from Refinery to Witness when a polecat's
-	// branch needs rebasing due to conflicts with the target branch.
+	// branch needs rebasing due to conflicts with the target branch, or
+	// changes asked for on its pull request (review comments, failed checks).
 	// Subject format: "REWORK_REQUEST <polecat-name>"
 	TypeReworkRequest MessageType = "REWORK_REQUEST"
 )
@@ -82,6 +83,9 @@ type MergeReadyPayload struct {
 
 	// Timestamp is when the message was created.
 	Timestamp time.Time `json:"timestamp"`
+
+	// TraceID is the lifecycle trace ID of the work (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // MergedPayload contains the data for a MERGED message.
@@ -107,6 +111,9 @@ type MergedPayload struct {
 
 	// TargetBranch is the branch merged into (e.g., "main").
 	TargetBranch string `json:"target_branch"`
+
+	// TraceID is the lifecycle trace ID of the work (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // MergeFailedPayload contains the data for a MERGE_FAILED message.
@@ -135,6 +142,9 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// TraceID is the lifecycle trace ID of the work (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
@@ -163,8 +173,29 @@ type ReworkRequestPayload struct {
 
 	// Instructions provides specific rebase instructions.
 	Instructions string `json:"instructions,omitempty"`
+
+	// TraceID is the lifecycle trace ID of the work (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
+
+	// Reason is why rework is needed: ReworkConflict (the default),
+	// ReworkReview or ReworkChecks.
+	Reason string `json:"reason,omitempty"`
+
+	// PullRequest is the URL of the branch's pull request, in pull request
+	// merge mode.
+	PullRequest string `json:"pull_request,omitempty"`
+
+	// Task is the rework task the MR is blocked on, if any.
+	Task string `json:"task,omitempty"`
 }
 
+// Rework reasons.
+const (
+	ReworkConflict = "conflict"
+	ReworkReview   = "review"
+	ReworkChecks   = "checks"
+)
+
 // IsProtocolMessage returns true if the subject matches a known protocol type.
 func IsProtocolMessage(subject string) bool {
 	return ParseMessageType(subject) != ""
@@ -176,6 +176,10 @@ Please fix the issue and resubmit your work with 'gt done'.`,
 
 // notifyPolecatRebase sends a rebase request notification to a polecat.
 func (h *DefaultWitnessHandler) notifyPolecatRebase(payload *ReworkRequestPayload) error {
+	if payload.Reason == ReworkReview || payload.Reason == ReworkChecks {
+		return h.notifyPolecatPRRework(payload)
+	}
+
 	conflictInfo := ""
 	if len(payload.ConflictFiles) > 0 {
 		conflictInfo = fmt.Sprintf("\nConflicting files:\n")
@@ -214,5 +218,33 @@ Then run 'gt done' to resubmit for merge.`,
 	return h.Router.Send(msg)
 }
 
+// notifyPolecatPRRework forwards pull request feedback to a polecat.
+func (h *DefaultWitnessHandler) notifyPolecatPRRework(payload *ReworkRequestPayload) error {
+	subject := "Rework required - review comments"
+	if payload.Reason == ReworkChecks {
+		subject = "Rework required - checks failed"
+	}
+
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", h.Rig),
+		fmt.Sprintf("%s/%s", h.Rig, payload.Polecat),
+		subject,
+		fmt.Sprintf(`Branch: %s
+Issue: %s
+Pull request: %s
+
+%s`,
+			payload.Branch,
+			payload.Issue,
+			payload.PullRequest,
+			payload.Instructions,
+		),
+	)
+	msg.Priority = mail.PriorityHigh
+	msg.Type = mail.TypeTask
+
+	return h.Router.Send(msg)
+}
+
 // Ensure DefaultWitnessHandler implements WitnessHandler.
 var _ WitnessHandler = (*DefaultWitnessHandler)(nil)
@@ -0,0 +1,166 @@
+// Package quota tracks Claude account usage limits.
+//
+// When an account hits its usage limit, every session on it stalls until the
+// limit resets. The daemon detects limit messages in session panes and
+// transcripts, marks the account as cooling down until its reset time, and
+// restarts affected polecats on the next available account.
+package quota
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCooldown is how long an account cools down when the reset time in
+// the limit banner can't be parsed.
+const DefaultCooldown = time.Hour
+
+// transcriptTail is how much of the end of a transcript is scanned.
+const transcriptTail = 64 * 1024
+
+// Limit is a detected account usage limit.
+type Limit struct {
+	// Reason is the matched limit message.
+	Reason string
+
+	// ResetsAt is when the limit lifts.
+	ResetsAt time.Time
+}
+
+var (
+	// Claude Code transcripts record the limit as the whole text of an
+	// assistant message: "Claude AI usage limit reached|<unix seconds>".
+	epochPattern = regexp.MustCompile(`"text":\s*"Claude AI usage limit reached\|(\d{9,})"`)
+
+	// Claude Code's pane banner is a line of its own that starts with the
+	// limit and names the reset time: "Claude usage limit reached. Your limit
+	// will reset at 5pm (Europe/London).", "5-hour limit reached ∙ resets 5pm",
+	// "You've hit your limit · resets 3:30am (UTC)". Only the banner's own
+	// leading glyphs may precede it, so the same words in logs, grep output
+	// or source code don't match.
+	bannerPattern = regexp.MustCompile(`(?m)^[\s⎿│>●]*((?:Claude (?:AI )?usage limit reached|(?:\d+-hour|Weekly|Opus|Session) limit reached|You've hit your limit)\b.*\breset.*)$`)
+	resetPattern  = regexp.MustCompile(`(?i)resets?(?: at)? (\d{1,2})(?::(\d{2}))?\s*(am|pm)(?:\s*\(([^)]+)\))?`)
+)
+
+// Detect looks for a Claude Code usage limit banner in pane output or a
+// limit marker in transcript lines. Returns nil if there is none, or if the
+// limit it names has already reset. Transient API rate limits (HTTP 429)
+// are not usage limits: Claude Code retries them itself.
+func Detect(text string, now time.Time) *Limit {
+	if m := epochPattern.FindStringSubmatch(text); m != nil {
+		secs, err := strconv.ParseInt(m[1], 10, 64)
+		if err == nil {
+			resets := time.Unix(secs, 0)
+			if !resets.After(now) {
+				return nil
+			}
+			return &Limit{Reason: "usage limit reached", ResetsAt: resets}
+		}
+	}
+
+	if m := bannerPattern.FindStringSubmatch(text); m != nil {
+		line := strings.TrimSpace(m[1])
+		limit := &Limit{Reason: line, ResetsAt: now.Add(DefaultCooldown)}
+		if r := resetPattern.FindStringSubmatch(line); r != nil {
+			if resets, ok := nextReset(r, now); ok {
+				limit.ResetsAt = resets
+			}
+		}
+		return limit
+	}
+	return nil
+}
+
+// nextReset returns the next occurrence of a "resets 5pm (Zone)" time.
+func nextReset(m []string, now time.Time) (time.Time, bool) {
+	hour, err := strconv.Atoi(m[1])
+	if err != nil || hour < 1 || hour > 12 {
+		return time.Time{}, false
+	}
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	hour %= 12
+	if strings.EqualFold(m[3], "pm") {
+		hour += 12
+	}
+
+	loc := now.Location()
+	if m[4] != "" {
+		if l, err := time.LoadLocation(m[4]); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+	resets := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !resets.After(now) {
+		resets = resets.Add(24 * time.Hour)
+	}
+	return resets, true
+}
+
+// DetectTranscript checks the last entries of a transcript for a limit
+// message. Only the final few lines count: a limit the session has since
+// moved past is not current.
+func DetectTranscript(path string, now time.Time) *Limit {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a discovered transcript
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	offset := info.Size() - transcriptTail
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	if len(lines) > 3 {
+		lines = lines[len(lines)-3:]
+	}
+	return Detect(string(bytes.Join(lines, []byte("\n"))), now)
+}
+
+// LatestTranscript returns the most recently written Claude Code transcript
+// for a working directory under an account's config dir, or "" if none.
+func LatestTranscript(configDir, workDir string) string {
+	projectDir := filepath.Join(configDir, "projects", strings.ReplaceAll(workDir, "/", "-"))
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		return ""
+	}
+	var latest string
+	var latestTime time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latestTime) {
+			latestTime = info.ModTime()
+			latest = filepath.Join(projectDir, e.Name())
+		}
+	}
+	return latest
+}
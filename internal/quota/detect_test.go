@@ -0,0 +1,147 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetect(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	future := now.Add(3 * time.Hour)
+
+	tests := []struct {
+		name     string
+		text     string
+		wantNil  bool
+		wantTime time.Time
+	}{
+		{
+			name:     "transcript epoch",
+			text:     `{"type":"assistant","text":"Claude AI usage limit reached|` + strconv.FormatInt(future.Unix(), 10) + `"}`,
+			wantTime: future,
+		},
+		{
+			name:    "expired epoch",
+			text:    `{"text":"Claude AI usage limit reached|` + strconv.FormatInt(now.Add(-time.Minute).Unix(), 10) + `"}`,
+			wantNil: true,
+		},
+		{
+			name:     "pane reset time with zone",
+			text:     "  ⎿  5-hour limit reached ∙ resets 5pm (UTC)\n> ",
+			wantTime: time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "reset time earlier today is tomorrow",
+			text:     "You've hit your limit · resets 3:30am (UTC)",
+			wantTime: time.Date(2026, 3, 11, 3, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "classic banner",
+			text:     "Claude usage limit reached. Your limit will reset at 5pm (UTC).",
+			wantTime: time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "banner without reset time",
+			text:    "Weekly limit reached",
+			wantNil: true,
+		},
+		{
+			name:    "rate limit is retried, not rotated",
+			text:    "API Error: 429 {\"type\":\"error\",\"error\":{\"type\":\"rate_limit_error\"}}",
+			wantNil: true,
+		},
+		{
+			name:    "grep output",
+			text:    "internal/quota/detect.go:47: You've hit your limit · resets 3:30am",
+			wantNil: true,
+		},
+		{
+			name:    "quoted in source",
+			text:    "\t\t\ttext: \"5-hour limit reached ∙ resets 5pm (UTC)\",",
+			wantNil: true,
+		},
+		{
+			name:    "epoch marker in tool output",
+			text:    `{"type":"tool_result","content":"log: Claude AI usage limit reached|` + strconv.FormatInt(future.Unix(), 10) + ` seen"}`,
+			wantNil: true,
+		},
+		{
+			name:    "no limit",
+			text:    "Running tests...\nok  	github.com/example/pkg",
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.text, now)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("Detect() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("Detect() = nil, want a limit")
+			}
+			if !got.ResetsAt.Equal(tt.wantTime) {
+				t.Errorf("ResetsAt = %v, want %v", got.ResetsAt, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestDetectTranscriptOnlyChecksTail(t *testing.T) {
+	now := time.Now()
+	limitLine := `{"text":"Claude AI usage limit reached|` + strconv.FormatInt(now.Add(time.Hour).Unix(), 10) + `"}`
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	if err := os.WriteFile(path, []byte(limitLine+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if DetectTranscript(path, now) == nil {
+		t.Fatal("expected limit as the last entry to be detected")
+	}
+
+	// The session moved past the limit
+	more := strings.Repeat(`{"text":"working"}`+"\n", 5)
+	if err := os.WriteFile(path, []byte(limitLine+"\n"+more), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := DetectTranscript(path, now); got != nil {
+		t.Errorf("expected old limit to be ignored, got %+v", got)
+	}
+}
+
+func TestLatestTranscript(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := "/town/gastown/polecats/Toast/gastown"
+	projectDir := filepath.Join(configDir, "projects", "-town-gastown-polecats-Toast-gastown")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := LatestTranscript(configDir, "/elsewhere"); got != "" {
+		t.Errorf("LatestTranscript(unknown) = %q, want empty", got)
+	}
+
+	old := filepath.Join(projectDir, "old.jsonl")
+	recent := filepath.Join(projectDir, "recent.jsonl")
+	for _, p := range []string{old, recent} {
+		if err := os.WriteFile(p, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := LatestTranscript(configDir, workDir); got != recent {
+		t.Errorf("LatestTranscript() = %q, want %q", got, recent)
+	}
+}
@@ -0,0 +1,165 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// stateLockTimeout bounds how long an update waits for a concurrent one.
+const stateLockTimeout = 5 * time.Second
+
+// AccountState is the limit state of one account.
+type AccountState struct {
+	// LimitedAt is when the limit was detected.
+	LimitedAt time.Time `json:"limited_at"`
+
+	// ResetsAt is when the account becomes available again.
+	ResetsAt time.Time `json:"resets_at"`
+
+	// Reason is the detected limit message.
+	Reason string `json:"reason,omitempty"`
+
+	// Session is the session the limit was detected in.
+	Session string `json:"session,omitempty"`
+}
+
+// State is the persisted limit state of all accounts, keyed by handle.
+// Accounts without an entry (or whose entry has reset) are available.
+type State struct {
+	Accounts map[string]*AccountState `json:"accounts"`
+}
+
+// StatePath returns the path of a town's quota state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "account-quota.json")
+}
+
+// Load reads a town's quota state. A missing file is an empty state.
+func Load(townRoot string) (*State, error) {
+	s := &State{Accounts: make(map[string]*AccountState)}
+	data, err := os.ReadFile(StatePath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing quota state: %w", err)
+	}
+	if s.Accounts == nil {
+		s.Accounts = make(map[string]*AccountState)
+	}
+	return s, nil
+}
+
+// Update applies fn to the quota state under a file lock and saves it.
+func Update(townRoot string, fn func(*State)) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), stateLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("timeout waiting for quota state lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	fn(s)
+	return util.AtomicWriteJSON(path, s)
+}
+
+// Cooling returns an account's limit state if it is cooling down at now,
+// or nil if the account is available.
+func (s *State) Cooling(handle string, now time.Time) *AccountState {
+	a := s.Accounts[handle]
+	if a == nil || !a.ResetsAt.After(now) {
+		return nil
+	}
+	return a
+}
+
+// MarkLimited records that an account hit a limit. An existing later reset
+// time is kept: a second detection never shortens a cooldown.
+func (s *State) MarkLimited(handle string, limit *Limit, session string, now time.Time) {
+	if prev := s.Cooling(handle, now); prev != nil && prev.ResetsAt.After(limit.ResetsAt) {
+		return
+	}
+	s.Accounts[handle] = &AccountState{
+		LimitedAt: now,
+		ResetsAt:  limit.ResetsAt,
+		Reason:    limit.Reason,
+		Session:   session,
+	}
+}
+
+// Clear marks an account as available.
+func (s *State) Clear(handle string) {
+	delete(s.Accounts, handle)
+}
+
+// NextAvailable returns the account to use instead of exclude: the default
+// account if it is available, otherwise the first available account by
+// handle. Returns "" if every other account is cooling down.
+func NextAvailable(cfg *config.AccountsConfig, s *State, exclude string, now time.Time) string {
+	if cfg.Default != "" && cfg.Default != exclude && s.Cooling(cfg.Default, now) == nil {
+		return cfg.Default
+	}
+	handles := make([]string, 0, len(cfg.Accounts))
+	for handle := range cfg.Accounts {
+		handles = append(handles, handle)
+	}
+	sort.Strings(handles)
+	for _, handle := range handles {
+		if handle != exclude && s.Cooling(handle, now) == nil {
+			return handle
+		}
+	}
+	return ""
+}
+
+// HandleForConfigDir returns the account whose config dir is dir, or "".
+func HandleForConfigDir(cfg *config.AccountsConfig, dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir = filepath.Clean(ExpandHome(dir))
+	for handle, acct := range cfg.Accounts {
+		if filepath.Clean(ExpandHome(acct.ConfigDir)) == dir {
+			return handle
+		}
+	}
+	return ""
+}
+
+// ExpandHome expands a leading ~/ in an account config dir.
+func ExpandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
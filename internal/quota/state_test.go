@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testAccounts() *config.AccountsConfig {
+	return &config.AccountsConfig{
+		Version: 1,
+		Default: "work",
+		Accounts: map[string]config.Account{
+			"work":     {ConfigDir: "/accounts/work"},
+			"personal": {ConfigDir: "/accounts/personal"},
+			"backup":   {ConfigDir: "/accounts/backup"},
+		},
+	}
+}
+
+func TestMarkLimitedNeverShortensCooldown(t *testing.T) {
+	now := time.Now()
+	s := &State{Accounts: make(map[string]*AccountState)}
+
+	s.MarkLimited("work", &Limit{Reason: "weekly limit reached", ResetsAt: now.Add(5 * time.Hour)}, "gt-gastown-Toast", now)
+	s.MarkLimited("work", &Limit{Reason: "rate_limit_error", ResetsAt: now.Add(10 * time.Minute)}, "gt-gastown-Nux", now)
+
+	a := s.Cooling("work", now)
+	if a == nil {
+		t.Fatal("expected work to be cooling")
+	}
+	if a.Reason != "weekly limit reached" || !a.ResetsAt.Equal(now.Add(5*time.Hour)) {
+		t.Errorf("cooldown was replaced: %+v", a)
+	}
+
+	if s.Cooling("work", now.Add(6*time.Hour)) != nil {
+		t.Error("expected work to be available after reset")
+	}
+}
+
+func TestNextAvailable(t *testing.T) {
+	cfg := testAccounts()
+	now := time.Now()
+	s := &State{Accounts: make(map[string]*AccountState)}
+
+	if got := NextAvailable(cfg, s, "personal", now); got != "work" {
+		t.Errorf("NextAvailable() = %q, want default account", got)
+	}
+	if got := NextAvailable(cfg, s, "work", now); got != "backup" {
+		t.Errorf("NextAvailable(exclude default) = %q, want first by handle", got)
+	}
+
+	s.MarkLimited("backup", &Limit{ResetsAt: now.Add(time.Hour)}, "", now)
+	if got := NextAvailable(cfg, s, "work", now); got != "personal" {
+		t.Errorf("NextAvailable() = %q, want personal", got)
+	}
+
+	s.MarkLimited("personal", &Limit{ResetsAt: now.Add(time.Hour)}, "", now)
+	if got := NextAvailable(cfg, s, "work", now); got != "" {
+		t.Errorf("NextAvailable() = %q, want none", got)
+	}
+}
+
+func TestHandleForConfigDir(t *testing.T) {
+	cfg := testAccounts()
+	if got := HandleForConfigDir(cfg, "/accounts/personal/"); got != "personal" {
+		t.Errorf("HandleForConfigDir() = %q, want personal", got)
+	}
+	if got := HandleForConfigDir(cfg, "/somewhere/else"); got != "" {
+		t.Errorf("HandleForConfigDir(unknown) = %q, want empty", got)
+	}
+	if got := HandleForConfigDir(cfg, ""); got != "" {
+		t.Errorf("HandleForConfigDir(empty) = %q, want empty", got)
+	}
+}
+
+func TestUpdateRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	resets := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	err := Update(townRoot, func(s *State) {
+		s.MarkLimited("work", &Limit{Reason: "usage limit reached", ResetsAt: resets}, "gt-gastown-Toast", time.Now())
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	s, err := Load(townRoot)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	a := s.Cooling("work", time.Now())
+	if a == nil || !a.ResetsAt.Equal(resets) || a.Session != "gt-gastown-Toast" {
+		t.Fatalf("loaded state = %+v", a)
+	}
+
+	if err := Update(townRoot, func(s *State) { s.Clear("work") }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	s, _ = Load(townRoot)
+	if s.Cooling("work", time.Now()) != nil {
+		t.Error("expected work to be cleared")
+	}
+}
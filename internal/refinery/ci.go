@@ -0,0 +1,168 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ci"
+)
+
+// SetCI sets the external CI gate, instead of one built from the rig's ci
+// config. This is useful for testing.
+func (e *Engineer) SetCI(p ci.Provider) {
+	e.ci = p
+}
+
+// getCI returns the rig's CI gate, creating it from config on first use.
+func (e *Engineer) getCI() (ci.Provider, error) {
+	if e.ci == nil {
+		p, err := ci.New(e.config.CI, e.rig.Path)
+		if err != nil {
+			return nil, err
+		}
+		e.ci = p
+	}
+	return e.ci, nil
+}
+
+// processCI advances an MR one step through the external CI gate. The
+// first pass squash-merges the branch onto the target as doMerge would,
+// but pushes the result to a candidate ref instead of the target and
+// returns Waiting. Later passes poll CI: a pass pushes the tested candidate
+// to the target, a failure (or no verdict within the timeout) fails the
+// MR as failed tests would. The candidate is recorded on the MR bead, so
+// gt mq list can show the MR as waiting on CI.
+func (e *Engineer) processCI(ctx context.Context, mr *MRInfo) ProcessResult {
+	p, err := e.getCI()
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("CI gate: %v", err)}
+	}
+
+	bead, err := e.beads.Show(mr.ID)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to fetch MR bead %s: %v", mr.ID, err)}
+	}
+	fields := beads.ParseMRFields(bead)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+
+	if fields.CISHA == "" {
+		return e.submitCandidate(ctx, p, mr, bead, fields)
+	}
+
+	cand := ci.Candidate{MR: mr.ID, Branch: mr.Branch, Target: mr.Target, Ref: fields.CIRef, SHA: fields.CISHA}
+	status, err := p.Status(ctx, cand)
+	if err != nil {
+		// An unreachable status endpoint isn't a verdict; the timeout
+		// still bounds the wait
+		status = &ci.Status{State: ci.StatePending, Detail: fmt.Sprintf("status check failed: %v", err)}
+	}
+	if status.URL != "" && status.URL != fields.CIURL {
+		fields.CIURL = status.URL
+		e.updateMRFields(mr.ID, bead, fields)
+	}
+
+	switch status.State {
+	case ci.StatePassed:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] CI passed for %s, pushing to origin/%s...\n", shortSHA(cand.SHA), mr.Target)
+		// Not forced: if the target moved while CI ran, the candidate is
+		// stale and the push is rejected
+		if err := e.git.Push("origin", cand.SHA+":refs/heads/"+mr.Target, false); err != nil {
+			e.clearCandidate(mr.ID, bead, fields)
+			return ProcessResult{Waiting: fmt.Sprintf("origin/%s moved while CI ran; building a new candidate next cycle", mr.Target)}
+		}
+		e.clearCandidate(mr.ID, bead, fields)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", shortSHA(cand.SHA))
+		return ProcessResult{Success: true, MergeCommit: cand.SHA}
+
+	case ci.StateFailed:
+		e.clearCandidate(mr.ID, bead, fields)
+		msg := fmt.Sprintf("CI failed for candidate %s", shortSHA(cand.SHA))
+		if status.Detail != "" {
+			msg += ": " + status.Detail
+		}
+		if status.URL != "" {
+			msg += " (" + status.URL + ")"
+		}
+		return ProcessResult{TestsFailed: true, Error: msg}
+	}
+
+	// A start time that can't be read can't bound the wait, so it counts
+	// as timed out
+	timeout := ci.Timeout(e.config.CI)
+	if started, err := time.Parse(time.RFC3339, fields.CIStartedAt); err != nil || time.Since(started) > timeout {
+		e.clearCandidate(mr.ID, bead, fields)
+		return ProcessResult{TestsFailed: true, Error: fmt.Sprintf("CI gave no verdict on %s within %s", shortSHA(cand.SHA), timeout)}
+	}
+
+	waiting := "waiting on CI"
+	if status.Detail != "" {
+		waiting += " (" + status.Detail + ")"
+	}
+	return ProcessResult{Waiting: waiting}
+}
+
+// submitCandidate builds an MR's merge candidate, pushes it to its
+// candidate ref, records it on the MR bead and tells CI about it.
+func (e *Engineer) submitCandidate(ctx context.Context, p ci.Provider, mr *MRInfo, bead *beads.Issue, fields *beads.MRFields) ProcessResult {
+	sha, failed := e.squashMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	if failed != nil {
+		return *failed
+	}
+	// The candidate lives on its ref until CI passes it; keep the local
+	// target in step with origin meanwhile
+	if err := e.git.ResetHard("origin/" + mr.Target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after building candidate: %v\n", mr.Target, err)
+	}
+
+	ref := ci.RefFor(e.config.CI, mr.ID)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing candidate %s to %s...\n", shortSHA(sha), ref)
+	if err := e.git.Push("origin", sha+":"+ref, true); err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to push CI candidate: %v", err)}
+	}
+
+	cand := ci.Candidate{MR: mr.ID, Branch: mr.Branch, Target: mr.Target, Ref: ref, SHA: sha}
+	if err := p.Submit(ctx, cand); err != nil {
+		_ = e.git.DeleteRemoteBranch("origin", ref)
+		return ProcessResult{Error: fmt.Sprintf("failed to submit candidate to CI: %v", err)}
+	}
+
+	fields.CIStatus = ci.StatePending
+	fields.CIRef = ref
+	fields.CISHA = sha
+	fields.CIStartedAt = time.Now().UTC().Format(time.RFC3339)
+	fields.CIURL = ""
+	e.updateMRFields(mr.ID, bead, fields)
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Candidate %s submitted to %s CI\n", shortSHA(sha), p.Name())
+	return ProcessResult{Waiting: "waiting on CI"}
+}
+
+// clearCandidate deletes an MR's candidate ref and forgets it, so the next
+// pass builds a fresh one.
+func (e *Engineer) clearCandidate(mrID string, bead *beads.Issue, fields *beads.MRFields) {
+	if fields.CIRef != "" {
+		if err := e.git.DeleteRemoteBranch("origin", fields.CIRef); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete candidate ref %s: %v\n", fields.CIRef, err)
+		}
+	}
+	fields.CIStatus = ""
+	fields.CIRef = ""
+	fields.CISHA = ""
+	fields.CIStartedAt = ""
+	fields.CIURL = ""
+	e.updateMRFields(mrID, bead, fields)
+}
+
+// updateMRFields writes fields back to an MR bead, warning on failure.
+func (e *Engineer) updateMRFields(mrID string, bead *beads.Issue, fields *beads.MRFields) {
+	newDesc := beads.SetMRFields(bead, fields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s: %v\n", mrID, err)
+		return
+	}
+	bead.Description = newDesc
+}
@@ -0,0 +1,208 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// runGit runs git in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupCIRig creates a rig whose refinery worktree tracks a bare origin
+// with a main branch, plus a local polecat/nux branch one commit ahead.
+// Returns the rig path and the origin path.
+func setupCIRig(t *testing.T) (string, string) {
+	t.Helper()
+	rigPath := t.TempDir()
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	runGit(t, rigPath, "init", "--bare", "-b", "main", origin)
+
+	work := filepath.Join(rigPath, "refinery", "rig")
+	runGit(t, rigPath, "clone", origin, work)
+	runGit(t, work, "config", "user.email", "test@test.com")
+	runGit(t, work, "config", "user.name", "Test User")
+	runGit(t, work, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "initial")
+	runGit(t, work, "push", "origin", "main")
+
+	runGit(t, work, "checkout", "-b", "polecat/nux")
+	if err := os.WriteFile(filepath.Join(work, "widget.go"), []byte("package widget\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "feat: add widget")
+	runGit(t, work, "checkout", "main")
+	return rigPath, origin
+}
+
+func newCIEngineer(t *testing.T, rigPath string) (*Engineer, *beadstest.Fake) {
+	t.Helper()
+	fake := beadstest.Install(t)
+	fake.Add(beads.Issue{ID: "gt-mr-1", Title: "Merge: gt-abc", Type: "merge-request", Description: beads.FormatMRFields(&beads.MRFields{
+		Branch: "polecat/nux",
+		Target: "main",
+		Worker: "nux",
+	})})
+
+	e := NewEngineer(&rig.Rig{Name: "testrig", Path: rigPath})
+	e.SetOutput(&bytes.Buffer{})
+	e.config.RunTests = false
+	e.config.CI = &config.CIConfig{Provider: config.CIProviderStatusFile, StatusFile: "ci/{mr}"}
+	return e, fake
+}
+
+func writeCIStatus(t *testing.T, rigPath, status string) {
+	t.Helper()
+	path := filepath.Join(rigPath, "ci", "gt-mr-1")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(status), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mrFields(t *testing.T, fake *beadstest.Fake) *beads.MRFields {
+	t.Helper()
+	bead, ok := fake.Issue("gt-mr-1")
+	if !ok {
+		t.Fatal("MR bead missing")
+	}
+	return beads.ParseMRFields(bead)
+}
+
+func TestProcessCI_Pass(t *testing.T) {
+	rigPath, origin := setupCIRig(t)
+	e, fake := newCIEngineer(t, rigPath)
+	ctx := context.Background()
+	mainBefore := runGit(t, origin, "rev-parse", "main")
+
+	mr, err := e.GetMRInfo("gt-mr-1")
+	if err != nil {
+		t.Fatalf("GetMRInfo: %v", err)
+	}
+
+	// First pass pushes a candidate, not the target
+	result := e.ProcessMRInfo(ctx, mr)
+	if result.Waiting == "" {
+		t.Fatalf("first pass = %+v, want waiting on CI", result)
+	}
+	fields := mrFields(t, fake)
+	if fields.CIStatus != "pending" || fields.CIRef != "refs/heads/gt-ci/gt-mr-1" || fields.CISHA == "" || fields.CIStartedAt == "" {
+		t.Fatalf("MR fields after submit = %+v", fields)
+	}
+	if got := runGit(t, origin, "rev-parse", fields.CIRef); got != fields.CISHA {
+		t.Errorf("candidate ref = %s, want %s", got, fields.CISHA)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != mainBefore {
+		t.Error("origin/main moved before CI passed")
+	}
+	work := filepath.Join(rigPath, "refinery", "rig")
+	if got := runGit(t, work, "rev-parse", "main"); got != mainBefore {
+		t.Error("local main kept the untested candidate")
+	}
+
+	// No verdict yet: still waiting, same candidate
+	result = e.ProcessMRInfo(ctx, mr)
+	if result.Waiting == "" || mrFields(t, fake).CISHA != fields.CISHA {
+		t.Fatalf("second pass = %+v, want waiting on the same candidate", result)
+	}
+
+	writeCIStatus(t, rigPath, "success")
+	result = e.ProcessMRInfo(ctx, mr)
+	if !result.Success || result.MergeCommit != fields.CISHA {
+		t.Fatalf("after CI passed = %+v, want success with the candidate", result)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != fields.CISHA {
+		t.Errorf("origin/main = %s, want the tested candidate %s", got, fields.CISHA)
+	}
+	if refs := runGit(t, origin, "for-each-ref", "refs/heads/gt-ci/"); refs != "" {
+		t.Errorf("candidate ref not deleted: %s", refs)
+	}
+	if f := mrFields(t, fake); f.CIStatus != "" || f.CISHA != "" {
+		t.Errorf("CI fields not cleared: %+v", f)
+	}
+}
+
+func TestProcessCI_FailAndTimeout(t *testing.T) {
+	rigPath, origin := setupCIRig(t)
+	e, fake := newCIEngineer(t, rigPath)
+	ctx := context.Background()
+	mainBefore := runGit(t, origin, "rev-parse", "main")
+
+	mr, err := e.GetMRInfo("gt-mr-1")
+	if err != nil {
+		t.Fatalf("GetMRInfo: %v", err)
+	}
+	if result := e.ProcessMRInfo(ctx, mr); result.Waiting == "" {
+		t.Fatalf("first pass = %+v, want waiting on CI", result)
+	}
+
+	writeCIStatus(t, rigPath, "failed\nunit tests: 2 failures")
+	result := e.ProcessMRInfo(ctx, mr)
+	if !result.TestsFailed || !strings.Contains(result.Error, "unit tests: 2 failures") {
+		t.Fatalf("after CI failed = %+v, want tests failed", result)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != mainBefore {
+		t.Error("origin/main moved after CI failed")
+	}
+	if f := mrFields(t, fake); f.CISHA != "" {
+		t.Errorf("CI fields not cleared: %+v", f)
+	}
+
+	// A resubmitted candidate that CI never reports on times out
+	if err := os.Remove(filepath.Join(rigPath, "ci", "gt-mr-1")); err != nil {
+		t.Fatal(err)
+	}
+	e.config.CI.Timeout = "1m"
+	if result := e.ProcessMRInfo(ctx, mr); result.Waiting == "" {
+		t.Fatalf("resubmit = %+v, want waiting on CI", result)
+	}
+	bead, _ := fake.Issue("gt-mr-1")
+	fields := beads.ParseMRFields(bead)
+	fields.CIStartedAt = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	e.updateMRFields("gt-mr-1", bead, fields)
+
+	result = e.ProcessMRInfo(ctx, mr)
+	if !result.TestsFailed || !strings.Contains(result.Error, "no verdict") {
+		t.Fatalf("after timeout = %+v, want tests failed", result)
+	}
+
+	// So does one whose start time can't be read
+	if result := e.ProcessMRInfo(ctx, mr); result.Waiting == "" {
+		t.Fatalf("resubmit = %+v, want waiting on CI", result)
+	}
+	bead, _ = fake.Issue("gt-mr-1")
+	fields = beads.ParseMRFields(bead)
+	fields.CIStartedAt = "yesterday"
+	e.updateMRFields("gt-mr-1", bead, fields)
+
+	result = e.ProcessMRInfo(ctx, mr)
+	if !result.TestsFailed || !strings.Contains(result.Error, "no verdict") {
+		t.Fatalf("after unreadable start time = %+v, want tests failed", result)
+	}
+}
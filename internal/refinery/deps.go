@@ -0,0 +1,173 @@
+package refinery
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// MRGraph is the merge-order graph of a rig's open merge requests.
+//
+// An MR waits on another open MR when it names it (or its source issue) in
+// its depends_on_mr field, or when its source issue depends on the other
+// MR's source issue in beads: polecat B's branch, built on polecat A's
+// bead, must not land before A's. Scores order MRs only among those with
+// nothing left to wait on.
+type MRGraph struct {
+	mrs     map[string]*MRInfo  // Open MRs by ID
+	prereqs map[string][]string // MR ID → open MR IDs it names as prerequisites
+}
+
+// LoadMRGraph builds the merge-order graph of the open MRs in b.
+func LoadMRGraph(b *beads.Beads) (*MRGraph, error) {
+	issues, err := b.ListMRs(beads.ListOptions{
+		Status:   "open",
+		Label:    "gt:merge-request",
+		Priority: -1, // No priority filter
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
+	}
+
+	g := &MRGraph{
+		mrs:     make(map[string]*MRInfo, len(issues)),
+		prereqs: make(map[string][]string),
+	}
+	fields := make(map[string]*beads.MRFields, len(issues))
+	bySource := make(map[string]string) // Source issue → MR ID
+	var sources []string
+	for _, issue := range issues {
+		f := beads.ParseMRFields(issue)
+		fields[issue.ID] = f
+		g.mrs[issue.ID] = newMRInfo(issue, f)
+		if f.SourceIssue != "" {
+			bySource[f.SourceIssue] = issue.ID
+			sources = append(sources, f.SourceIssue)
+		}
+	}
+
+	// Source issues carry the bead dependency edges; fetch them in one call
+	sourceIssues, err := b.ShowMultiple(sources)
+	if err != nil {
+		return nil, fmt.Errorf("fetching source issues: %w", err)
+	}
+
+	for id, mr := range g.mrs {
+		seen := make(map[string]bool)
+		add := func(target string) {
+			if _, ok := g.mrs[target]; !ok {
+				target = bySource[target]
+			}
+			if target == "" || target == id || seen[target] {
+				return
+			}
+			seen[target] = true
+			g.prereqs[id] = append(g.prereqs[id], target)
+		}
+
+		for _, dep := range fields[id].DependsOnMRs() {
+			add(dep)
+		}
+		if src := sourceIssues[mr.SourceIssue]; src != nil {
+			for _, dep := range src.Dependencies {
+				// Only blocking edges order work; parent-child and
+				// related links don't
+				if dep.DependencyType != "" && dep.DependencyType != "blocks" {
+					continue
+				}
+				if target := bySource[dep.ID]; target != "" {
+					add(target)
+				}
+			}
+		}
+		sort.Strings(g.prereqs[id])
+	}
+
+	return g, nil
+}
+
+// MR returns the open MR with the given ID, or nil.
+func (g *MRGraph) MR(id string) *MRInfo {
+	return g.mrs[id]
+}
+
+// WaitingOn returns the open MRs that must merge before id. Prerequisites
+// in a dependency cycle with id are left out, since holding either MR
+// would hold both forever; CycleWith reports them.
+func (g *MRGraph) WaitingOn(id string) []string {
+	var out []string
+	for _, p := range g.prereqs[id] {
+		if !g.reaches(p, id) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// CycleWith returns id's prerequisites that (transitively) wait on id.
+func (g *MRGraph) CycleWith(id string) []string {
+	var out []string
+	for _, p := range g.prereqs[id] {
+		if g.reaches(p, id) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Dependents returns the open MRs waiting on id.
+func (g *MRGraph) Dependents(id string) []string {
+	var out []string
+	for other := range g.mrs {
+		for _, p := range g.WaitingOn(other) {
+			if p == id {
+				out = append(out, other)
+				break
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Chain returns id followed by the MRs it waits on, each the first
+// prerequisite of the one before: [C, B, A] when C waits on B, which waits
+// on A. A chain of one means id waits on nothing.
+func (g *MRGraph) Chain(id string) []string {
+	chain := []string{id}
+	seen := map[string]bool{id: true}
+	for cur := id; ; {
+		waiting := g.WaitingOn(cur)
+		if len(waiting) == 0 || seen[waiting[0]] {
+			return chain
+		}
+		cur = waiting[0]
+		seen[cur] = true
+		chain = append(chain, cur)
+	}
+}
+
+// reaches reports whether from waits on to, directly or transitively.
+func (g *MRGraph) reaches(from, to string) bool {
+	seen := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == to {
+			return true
+		}
+		if seen[cur] {
+			continue
+		}
+		seen[cur] = true
+		stack = append(stack, g.prereqs[cur]...)
+	}
+	return false
+}
+
+// MRGraph loads the merge-order graph of the rig's open MRs.
+func (e *Engineer) MRGraph() (*MRGraph, error) {
+	return LoadMRGraph(e.beads)
+}
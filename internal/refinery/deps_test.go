@@ -0,0 +1,107 @@
+package refinery
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// addMR adds an open merge-request bead for source issue src.
+func addMR(fake *beadstest.Fake, id, src, dependsOn string) {
+	fake.Add(beads.Issue{
+		ID:     id,
+		Title:  "Merge: " + src,
+		Type:   "merge-request",
+		Labels: []string{"gt:merge-request"},
+		Description: beads.FormatMRFields(&beads.MRFields{
+			Branch:      "polecat/" + src,
+			Target:      "main",
+			SourceIssue: src,
+			DependsOnMR: dependsOn,
+		}),
+	})
+}
+
+func mrIDs(mrs []*MRInfo) []string {
+	var ids []string
+	for _, mr := range mrs {
+		ids = append(ids, mr.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestMRGraph_Ordering(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-a", Title: "A"},
+		beads.Issue{ID: "gt-b", Title: "B"},
+		beads.Issue{ID: "gt-c", Title: "C"},
+		beads.Issue{ID: "gt-d", Title: "D"},
+		beads.Issue{ID: "gt-e", Title: "E"},
+		beads.Issue{ID: "gt-f", Title: "F"},
+	)
+	fake.AddDependency("gt-b", "gt-a", "")             // B's bead builds on A's
+	fake.AddDependency("gt-d", "gt-a", "parent-child") // Not an ordering edge
+	addMR(fake, "gt-mr-a", "gt-a", "")
+	addMR(fake, "gt-mr-b", "gt-b", "")
+	addMR(fake, "gt-mr-c", "gt-c", "gt-mr-b") // Explicit, by MR ID
+	addMR(fake, "gt-mr-d", "gt-d", "")
+	addMR(fake, "gt-mr-e", "gt-e", "gt-f") // E and F wait on each other, by issue ID
+	addMR(fake, "gt-mr-f", "gt-f", "gt-e")
+
+	e := NewEngineer(&rig.Rig{Name: "testrig", Path: t.TempDir()})
+
+	ready, err := e.ListReadyMRs()
+	if err != nil {
+		t.Fatalf("ListReadyMRs: %v", err)
+	}
+	// A cycle can't be ordered, so E and F fall back to scoring
+	if got, want := mrIDs(ready), []string{"gt-mr-a", "gt-mr-d", "gt-mr-e", "gt-mr-f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ready = %v, want %v", got, want)
+	}
+
+	blocked, err := e.ListBlockedMRs()
+	if err != nil {
+		t.Fatalf("ListBlockedMRs: %v", err)
+	}
+	if got, want := mrIDs(blocked), []string{"gt-mr-b", "gt-mr-c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("blocked = %v, want %v", got, want)
+	}
+	for _, mr := range blocked {
+		want := map[string]string{"gt-mr-b": "gt-mr-a", "gt-mr-c": "gt-mr-b"}[mr.ID]
+		if mr.BlockedBy != want || !reflect.DeepEqual(mr.DependsOn, []string{want}) {
+			t.Errorf("%s: BlockedBy=%q DependsOn=%v, want %s", mr.ID, mr.BlockedBy, mr.DependsOn, want)
+		}
+	}
+
+	graph, err := e.MRGraph()
+	if err != nil {
+		t.Fatalf("MRGraph: %v", err)
+	}
+	if got, want := graph.Chain("gt-mr-c"), []string{"gt-mr-c", "gt-mr-b", "gt-mr-a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Chain(c) = %v, want %v", got, want)
+	}
+	if got, want := graph.Dependents("gt-mr-a"), []string{"gt-mr-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependents(a) = %v, want %v", got, want)
+	}
+	if got, want := graph.CycleWith("gt-mr-e"), []string{"gt-mr-f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CycleWith(e) = %v, want %v", got, want)
+	}
+
+	// Once A merges, B is next; C still waits on B
+	if err := e.beads.Close("gt-mr-a"); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	ready, err = e.ListReadyMRs()
+	if err != nil {
+		t.Fatalf("ListReadyMRs: %v", err)
+	}
+	if got, want := mrIDs(ready), []string{"gt-mr-b", "gt-mr-d", "gt-mr-e", "gt-mr-f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ready after A merged = %v, want %v", got, want)
+	}
+}
@@ -14,11 +14,16 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ci"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/trace"
 )
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -52,6 +57,16 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// MergeMode is "local" (squash-merge and push) or "pr" (merge through
+	// a pull request on Forge).
+	MergeMode string `json:"merge_mode"`
+
+	// Forge is the code host used in "pr" merge mode.
+	Forge *config.ForgeConfig `json:"forge,omitempty"`
+
+	// CI gates local merges on an external CI system (nil: no CI gate).
+	CI *config.CIConfig `json:"ci,omitempty"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -67,6 +82,7 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		RetryFlakyTests:      1,
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
+		MergeMode:            config.MergeModeLocal,
 	}
 }
 
@@ -87,6 +103,8 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	DependsOn       []string   // Open MRs that must merge first (see MRGraph)
+	TraceID         string     // Lifecycle trace ID (see gt trace)
 }
 
 // Engineer is the merge queue processor that polls for ready merge-requests
@@ -99,6 +117,8 @@ type Engineer struct {
 	workDir string
 	output  io.Writer    // Output destination for user-facing messages
 	router  *mail.Router // Mail router for sending protocol messages
+	forge   forge.Forge  // Code host for "pr" merge mode (created on first use)
+	ci      ci.Provider  // External CI gate (created on first use)
 
 	// stopCh is used for graceful shutdown
 	stopCh chan struct{}
@@ -164,16 +184,19 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool   `json:"enabled"`
-		TargetBranch         *string `json:"target_branch"`
-		IntegrationBranches  *bool   `json:"integration_branches"`
-		OnConflict           *string `json:"on_conflict"`
-		RunTests             *bool   `json:"run_tests"`
-		TestCommand          *string `json:"test_command"`
-		DeleteMergedBranches *bool   `json:"delete_merged_branches"`
-		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
-		PollInterval         *string `json:"poll_interval"`
-		MaxConcurrent        *int    `json:"max_concurrent"`
+		Enabled              *bool               `json:"enabled"`
+		TargetBranch         *string             `json:"target_branch"`
+		IntegrationBranches  *bool               `json:"integration_branches"`
+		OnConflict           *string             `json:"on_conflict"`
+		RunTests             *bool               `json:"run_tests"`
+		TestCommand          *string             `json:"test_command"`
+		DeleteMergedBranches *bool               `json:"delete_merged_branches"`
+		RetryFlakyTests      *int                `json:"retry_flaky_tests"`
+		PollInterval         *string             `json:"poll_interval"`
+		MaxConcurrent        *int                `json:"max_concurrent"`
+		MergeMode            *string             `json:"merge_mode"`
+		Forge                *config.ForgeConfig `json:"forge"`
+		CI                   *config.CIConfig    `json:"ci"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -215,6 +238,34 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.PollInterval = dur
 	}
+	if mqRaw.MergeMode != nil {
+		e.config.MergeMode = *mqRaw.MergeMode
+	}
+	if mqRaw.Forge != nil {
+		e.config.Forge = mqRaw.Forge
+	}
+	if mqRaw.CI != nil {
+		e.config.CI = mqRaw.CI
+	}
+	switch e.config.MergeMode {
+	case "", config.MergeModeLocal:
+	case config.MergeModePR:
+		if e.config.Forge == nil {
+			return fmt.Errorf("merge_mode %q requires a forge section", config.MergeModePR)
+		}
+	default:
+		return fmt.Errorf("invalid merge_mode %q", e.config.MergeMode)
+	}
+	if e.config.CI != nil {
+		if e.config.MergeMode == config.MergeModePR {
+			return fmt.Errorf("merge_queue.ci gates local merges; in merge_mode %q use forge.required_checks", config.MergeModePR)
+		}
+		if e.config.CI.Timeout != "" {
+			if _, err := time.ParseDuration(e.config.CI.Timeout); err != nil {
+				return fmt.Errorf("invalid ci.timeout %q: %w", e.config.CI.Timeout, err)
+			}
+		}
+	}
 
 	return nil
 }
@@ -231,6 +282,15 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+
+	// Waiting means a gate (pull request checks and approvals, or external
+	// CI) has no verdict yet; the MR stays queued and is retried later.
+	Waiting string
+
+	// Pull request merge mode only
+	PRURL    string          // The MR's pull request
+	Rework   bool            // The polecat must change the branch (review comments, failed checks)
+	Comments []forge.Comment // New review comments to send back as rework
 }
 
 // ProcessMR processes a single merge request from a beads issue.
@@ -256,17 +316,42 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 // doMerge performs the actual git merge operation.
 // This is the core merge logic shared by ProcessMR and ProcessMRFromQueue.
 func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+	mergeCommit, failed := e.squashMerge(ctx, branch, target, sourceIssue)
+	if failed != nil {
+		return *failed
+	}
+
+	// Step 7: Push to origin
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
+	if err := e.git.Push("origin", target, false); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to push to origin: %v", err),
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
+	return ProcessResult{
+		Success:     true,
+		MergeCommit: mergeCommit,
+	}
+}
+
+// squashMerge squash-merges branch onto an up-to-date local target (steps
+// 1-6 of doMerge) and returns the merge commit, without pushing it. On
+// failure it returns the result to report instead.
+func (e *Engineer) squashMerge(ctx context.Context, branch, target, sourceIssue string) (string, *ProcessResult) {
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
 	if err != nil {
-		return ProcessResult{
+		return "", &ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("failed to check branch %s: %v", branch, err),
 		}
 	}
 	if !exists {
-		return ProcessResult{
+		return "", &ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("branch %s not found locally", branch),
 		}
@@ -275,7 +360,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	// Step 2: Checkout the target branch
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
 	if err := e.git.Checkout(target); err != nil {
-		return ProcessResult{
+		return "", &ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("failed to checkout target %s: %v", target, err),
 		}
@@ -291,14 +376,14 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
 	conflicts, err := e.git.CheckConflicts(branch, target)
 	if err != nil {
-		return ProcessResult{
+		return "", &ProcessResult{
 			Success:  false,
 			Conflict: true,
 			Error:    fmt.Sprintf("conflict check failed: %v", err),
 		}
 	}
 	if len(conflicts) > 0 {
-		return ProcessResult{
+		return "", &ProcessResult{
 			Success:  false,
 			Conflict: true,
 			Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
@@ -310,7 +395,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
 		result := e.runTests(ctx)
 		if !result.Success {
-			return ProcessResult{
+			return "", &ProcessResult{
 				Success:     false,
 				TestsFailed: true,
 				Error:       result.Error,
@@ -338,13 +423,13 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		conflicts, conflictErr := e.git.GetConflictingFiles()
 		if conflictErr == nil && len(conflicts) > 0 {
 			_ = e.git.AbortMerge()
-			return ProcessResult{
+			return "", &ProcessResult{
 				Success:  false,
 				Conflict: true,
 				Error:    "merge conflict during actual merge",
 			}
 		}
-		return ProcessResult{
+		return "", &ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("merge failed: %v", err),
 		}
@@ -353,26 +438,13 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	// Step 6: Get the merge commit SHA
 	mergeCommit, err := e.git.Rev("HEAD")
 	if err != nil {
-		return ProcessResult{
+		return "", &ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("failed to get merge commit SHA: %v", err),
 		}
 	}
 
-	// Step 7: Push to origin
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
-	if err := e.git.Push("origin", target, false); err != nil {
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("failed to push to origin: %v", err),
-		}
-	}
-
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
-	return ProcessResult{
-		Success:     true,
-		MergeCommit: mergeCommit,
-	}
+	return mergeCommit, nil
 }
 
 // runTests runs the configured test command and returns the result.
@@ -516,6 +588,13 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	if e.config.MergeMode == config.MergeModePR {
+		return e.processPR(ctx, mr)
+	}
+	if e.config.CI != nil {
+		return e.processCI(ctx, mr)
+	}
+
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
@@ -597,6 +676,12 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	}
 
 	// 3. Log success
+	payload := events.MergePayload(mr.ID, mr.Worker, mr.Branch, "")
+	payload["rig"] = e.rig.Name
+	if mr.SourceIssue != "" {
+		payload["bead"] = mr.SourceIssue
+	}
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery", trace.Annotate(payload, mr.TraceID))
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
@@ -604,6 +689,12 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 // For conflicts, creates a resolution task and blocks the MR until resolved.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
+	// A pull request waiting on its gate hasn't failed; check again next cycle
+	if result.Waiting != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ⏳ Waiting: %s - %s\n", mr.ID, result.Waiting)
+		return
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -611,12 +702,21 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		failureType = "conflict"
 	} else if result.TestsFailed {
 		failureType = "tests"
+	} else if result.Rework {
+		failureType = "review"
 	}
-	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
-	if err := e.router.Send(msg); err != nil {
-		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
+	if result.Rework {
+		// Pull request feedback goes back to the polecat as a rework
+		// request rather than a bare failure
+		e.requestRework(mr, result)
 	} else {
-		fmt.Fprintf(e.output, "[Engineer] Notified witness of merge failure for %s\n", mr.Worker)
+		msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
+		protocol.SetTraceID(msg, mr.TraceID)
+		if err := e.router.Send(msg); err != nil {
+			fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
+		} else {
+			fmt.Fprintf(e.output, "[Engineer] Notified witness of merge failure for %s\n", mr.Worker)
+		}
 	}
 
 	// If this was a conflict, create a conflict-resolution task for dispatch
@@ -637,9 +737,15 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 
 	// Log the failure - MR stays in queue but may be blocked
+	payload := events.MergePayload(mr.ID, mr.Worker, mr.Branch, failureType)
+	payload["rig"] = e.rig.Name
+	if mr.SourceIssue != "" {
+		payload["bead"] = mr.SourceIssue
+	}
+	_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery", trace.Annotate(payload, mr.TraceID))
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 	if mr.BlockedBy != "" {
-		_, _ = fmt.Fprintln(e.output, "[Engineer] MR blocked pending conflict resolution - queue continues to next MR")
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR blocked pending resolution - queue continues to next MR")
 	} else {
 		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue for retry")
 	}
@@ -776,6 +882,7 @@ func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
 // ListReadyMRs returns MRs that are ready for processing:
 // - Not claimed by another worker (checked via assignee field)
 // - Not blocked by an open task (handled by bd ready)
+// - Not waiting on another open MR to merge first (see MRGraph)
 // Sorted by priority (highest first).
 //
 // This queries beads for merge-request wisps.
@@ -786,6 +893,11 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
 	}
 
+	graph, err := e.MRGraph()
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert beads issues to MRInfo
 	var mrs []*MRInfo
 	for _, issue := range issues {
@@ -794,6 +906,11 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue
 		}
 
+		// Hold MRs until the MRs they build on have merged
+		if len(graph.WaitingOn(issue.ID)) > 0 {
+			continue
+		}
+
 		fields := beads.ParseMRFields(issue)
 		if fields == nil {
 			continue // Skip issues without MR fields
@@ -805,44 +922,14 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue
 		}
 
-		// Parse convoy created_at if present
-		var convoyCreatedAt *time.Time
-		if fields.ConvoyCreatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
-				convoyCreatedAt = &t
-			}
-		}
-
-		// Parse issue created_at
-		var createdAt time.Time
-		if issue.CreatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
-				createdAt = t
-			}
-		}
-
-		mr := &MRInfo{
-			ID:              issue.ID,
-			Branch:          fields.Branch,
-			Target:          fields.Target,
-			SourceIssue:     fields.SourceIssue,
-			Worker:          fields.Worker,
-			Rig:             fields.Rig,
-			Title:           issue.Title,
-			Priority:        issue.Priority,
-			AgentBead:       fields.AgentBead,
-			RetryCount:      fields.RetryCount,
-			ConvoyID:        fields.ConvoyID,
-			ConvoyCreatedAt: convoyCreatedAt,
-			CreatedAt:       createdAt,
-		}
-		mrs = append(mrs, mr)
+		mrs = append(mrs, newMRInfo(issue, fields))
 	}
 
 	return mrs, nil
 }
 
-// ListBlockedMRs returns MRs that are blocked by open tasks.
+// ListBlockedMRs returns MRs that are blocked by open tasks, or held until
+// the MRs they depend on merge (DependsOn set, BlockedBy the first of them).
 // Useful for monitoring/reporting.
 //
 // This queries beads for blocked merge-request issues.
@@ -857,9 +944,27 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
 	}
 
+	graph, err := e.MRGraph()
+	if err != nil {
+		return nil, err
+	}
+
 	// Filter for blocked issues (those with open blockers)
 	var mrs []*MRInfo
 	for _, issue := range issues {
+		waiting := graph.WaitingOn(issue.ID)
+		if len(waiting) > 0 {
+			fields := beads.ParseMRFields(issue)
+			if fields == nil {
+				continue
+			}
+			mr := newMRInfo(issue, fields)
+			mr.BlockedBy = waiting[0]
+			mr.DependsOn = waiting
+			mrs = append(mrs, mr)
+			continue
+		}
+
 		// Skip if not blocked
 		if len(issue.BlockedBy) == 0 {
 			continue
@@ -883,54 +988,72 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 			continue
 		}
 
-		// Parse convoy created_at if present
-		var convoyCreatedAt *time.Time
-		if fields.ConvoyCreatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
-				convoyCreatedAt = &t
-			}
-		}
-
-		// Parse issue created_at
-		var createdAt time.Time
-		if issue.CreatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
-				createdAt = t
-			}
-		}
+		mr := newMRInfo(issue, fields)
 
 		// Use the first open blocker as BlockedBy
-		blockedBy := ""
 		for _, blockerID := range issue.BlockedBy {
 			isOpen, err := e.IsBeadOpen(blockerID)
 			if err == nil && isOpen {
-				blockedBy = blockerID
+				mr.BlockedBy = blockerID
 				break
 			}
 		}
 
-		mr := &MRInfo{
-			ID:              issue.ID,
-			Branch:          fields.Branch,
-			Target:          fields.Target,
-			SourceIssue:     fields.SourceIssue,
-			Worker:          fields.Worker,
-			Rig:             fields.Rig,
-			Title:           issue.Title,
-			Priority:        issue.Priority,
-			AgentBead:       fields.AgentBead,
-			RetryCount:      fields.RetryCount,
-			ConvoyID:        fields.ConvoyID,
-			ConvoyCreatedAt: convoyCreatedAt,
-			CreatedAt:       createdAt,
-			BlockedBy:       blockedBy,
-		}
 		mrs = append(mrs, mr)
 	}
 
 	return mrs, nil
 }
 
+// GetMRInfo returns the MRInfo for a merge-request bead.
+func (e *Engineer) GetMRInfo(id string) (*MRInfo, error) {
+	issue, err := e.beads.Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("fetching MR %s: %w", id, err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return nil, fmt.Errorf("%s has no merge-request fields", id)
+	}
+	return newMRInfo(issue, fields), nil
+}
+
+// newMRInfo builds an MRInfo from a merge-request bead and its fields.
+func newMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
+	// Parse convoy created_at if present
+	var convoyCreatedAt *time.Time
+	if fields.ConvoyCreatedAt != "" {
+		if t, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
+			convoyCreatedAt = &t
+		}
+	}
+
+	// Parse issue created_at
+	var createdAt time.Time
+	if issue.CreatedAt != "" {
+		if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+			createdAt = t
+		}
+	}
+
+	return &MRInfo{
+		ID:              issue.ID,
+		Branch:          fields.Branch,
+		Target:          fields.Target,
+		SourceIssue:     fields.SourceIssue,
+		Worker:          fields.Worker,
+		Rig:             fields.Rig,
+		Title:           issue.Title,
+		Priority:        issue.Priority,
+		AgentBead:       fields.AgentBead,
+		RetryCount:      fields.RetryCount,
+		ConvoyID:        fields.ConvoyID,
+		ConvoyCreatedAt: convoyCreatedAt,
+		CreatedAt:       createdAt,
+		TraceID:         fields.TraceID,
+	}
+}
+
 // ClaimMR claims an MR for processing by setting the assignee field.
 // This replaces mrqueue.Claim() for beads-based MRs.
 // The workerID is typically the refinery's identifier (e.g., "gastown/refinery").
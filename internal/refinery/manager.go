@@ -56,6 +56,15 @@ func (m *Manager) SessionName() string {
 	return fmt.Sprintf("gt-%s-refinery", m.rig.Name)
 }
 
+// OverlayVars returns the overlay template variables for the refinery worktree.
+func (m *Manager) OverlayVars() rig.OverlayVars {
+	return rig.OverlayVars{
+		Rig:      m.rig.Name,
+		Role:     rig.OverlayRoleRefinery,
+		Worktree: filepath.Join(m.rig.Path, "refinery", "rig"),
+	}
+}
+
 // IsRunning checks if the refinery session is active.
 // ZFC: tmux session existence is the source of truth.
 func (m *Manager) IsRunning() (bool, error) {
@@ -125,6 +134,14 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		refineryRigDir = filepath.Join(m.rig.Path, "mayor", "rig")
 	}
 
+	// Refresh overlay files (.env etc.) in the refinery worktree. The overlay
+	// usually doesn't exist yet when the rig is added, so apply it on start.
+	if refineryRigDir == m.OverlayVars().Worktree {
+		if err := rig.ApplyOverlay(m.rig.Path, refineryRigDir, m.OverlayVars()); err != nil {
+			_, _ = fmt.Fprintf(m.output, "Warning: could not copy overlay files: %v\n", err)
+		}
+	}
+
 	// Ensure runtime settings exist in refinery/ (not refinery/rig/) so we don't
 	// write into the source repo. Runtime walks up the tree to find settings.
 	refineryParentDir := filepath.Join(m.rig.Path, "refinery")
@@ -0,0 +1,308 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/protocol"
+)
+
+// SetForge sets the code host used in "pr" merge mode, instead of one built
+// from the rig's forge config. This is useful for testing.
+func (e *Engineer) SetForge(f forge.Forge) {
+	e.forge = f
+}
+
+// getForge returns the rig's code host, creating it from config on first use.
+func (e *Engineer) getForge() (forge.Forge, error) {
+	if e.forge == nil {
+		f, err := forge.New(e.config.Forge)
+		if err != nil {
+			return nil, err
+		}
+		e.forge = f
+	}
+	return e.forge, nil
+}
+
+// processPR advances an MR one step through the pull request merge mode.
+// It opens the MR's pull request if there isn't one yet, then treats the
+// PR as a gate: new review comments or failed checks come back as Rework,
+// unmet checks or approvals as Waiting, and a PR that passes its gate is
+// merged through the API. It never touches the local worktree; polecats
+// push their branches before gt done.
+func (e *Engineer) processPR(ctx context.Context, mr *MRInfo) ProcessResult {
+	f, err := e.getForge()
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("pull request mode: %v", err)}
+	}
+
+	bead, err := e.beads.Show(mr.ID)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to fetch MR bead %s: %v", mr.ID, err)}
+	}
+	fields := beads.ParseMRFields(bead)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+
+	var pr *forge.PR
+	if fields.PRNumber == 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Opening pull request %s → %s on %s...\n", mr.Branch, mr.Target, f.Provider())
+		pr, err = f.OpenPR(ctx, forge.PRRequest{
+			Title: e.prTitle(mr),
+			Body:  e.prBody(mr),
+			Head:  mr.Branch,
+			Base:  mr.Target,
+		})
+		if err != nil {
+			return ProcessResult{Error: fmt.Sprintf("failed to open pull request: %v", err)}
+		}
+		fields.PRNumber = pr.Number
+		fields.PRURL = pr.URL
+		newDesc := beads.SetMRFields(bead, fields)
+		if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record PR on MR %s: %v\n", mr.ID, err)
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Opened pull request #%d: %s\n", pr.Number, pr.URL)
+	} else {
+		pr, err = f.GetPR(ctx, fields.PRNumber)
+		if err != nil {
+			return ProcessResult{Error: fmt.Sprintf("failed to fetch pull request #%d: %v", fields.PRNumber, err)}
+		}
+	}
+
+	switch pr.State {
+	case forge.StateMerged:
+		// Someone merged it by hand; finish the bookkeeping
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Pull request #%d already merged\n", pr.Number)
+		return ProcessResult{Success: true, MergeCommit: pr.MergeCommit, PRURL: pr.URL}
+	case forge.StateClosed:
+		return ProcessResult{Error: fmt.Sprintf("pull request #%d was closed without merging", pr.Number), PRURL: pr.URL}
+	}
+
+	// Review comments first: a reviewer asking for changes outranks
+	// whatever CI is doing
+	comments, err := f.ReviewComments(ctx, pr.Number)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to fetch review comments: %v", err), PRURL: pr.URL}
+	}
+	if fresh := commentsAfter(comments, fields.ReviewCursor); len(fresh) > 0 {
+		return ProcessResult{
+			Rework:   true,
+			Comments: fresh,
+			PRURL:    pr.URL,
+			Error:    fmt.Sprintf("%d new review comment(s) on pull request #%d", len(fresh), pr.Number),
+		}
+	}
+
+	if pr.Conflicted {
+		return ProcessResult{
+			Conflict: true,
+			PRURL:    pr.URL,
+			Error:    fmt.Sprintf("pull request #%d conflicts with %s", pr.Number, mr.Target),
+		}
+	}
+
+	gate, err := f.Gate(ctx, pr)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to check pull request gate: %v", err), PRURL: pr.URL}
+	}
+	ready, reason, failed := gate.Ready(e.config.Forge.RequiredChecks, e.config.Forge.RequiredApprovals)
+	switch {
+	case failed:
+		return ProcessResult{TestsFailed: true, Rework: true, PRURL: pr.URL, Error: reason}
+	case !ready:
+		return ProcessResult{Waiting: reason, PRURL: pr.URL}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Gate passed, merging pull request #%d...\n", pr.Number)
+	sha, err := f.Merge(ctx, pr, e.prTitle(mr))
+	if errors.Is(err, forge.ErrNotMergeable) {
+		// Usually branch protection stricter than our gate, or a push that
+		// landed after the gate check; either way, look again next cycle
+		return ProcessResult{Waiting: err.Error(), PRURL: pr.URL}
+	}
+	if err != nil {
+		return ProcessResult{Error: err.Error(), PRURL: pr.URL}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merged pull request #%d: %s\n", pr.Number, shortSHA(sha))
+	return ProcessResult{Success: true, MergeCommit: sha, PRURL: pr.URL}
+}
+
+// prTitle is the pull request (and squash commit) title for an MR: the
+// source issue's title and ID when available.
+func (e *Engineer) prTitle(mr *MRInfo) string {
+	if mr.SourceIssue != "" {
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil && issue.Title != "" {
+			return fmt.Sprintf("%s (%s)", issue.Title, mr.SourceIssue)
+		}
+	}
+	if mr.Title != "" {
+		return mr.Title
+	}
+	return fmt.Sprintf("Merge %s", mr.Branch)
+}
+
+// prBody is the pull request description for an MR.
+func (e *Engineer) prBody(mr *MRInfo) string {
+	var sb strings.Builder
+	if mr.SourceIssue != "" {
+		sb.WriteString(fmt.Sprintf("Issue: %s\n", mr.SourceIssue))
+	}
+	if mr.Worker != "" {
+		sb.WriteString(fmt.Sprintf("Worker: %s\n", mr.Worker))
+	}
+	sb.WriteString(fmt.Sprintf("MR: %s\n", mr.ID))
+	sb.WriteString(fmt.Sprintf("\nOpened by the %s refinery. It merges this pull request once checks and approvals pass; review comments are sent back to the worker as rework.\n", e.rig.Name))
+	return sb.String()
+}
+
+// commentsAfter returns the comments created after cursor (an RFC 3339
+// time; empty means all).
+func commentsAfter(comments []forge.Comment, cursor string) []forge.Comment {
+	if cursor == "" {
+		return comments
+	}
+	since, err := time.Parse(time.RFC3339Nano, cursor)
+	if err != nil {
+		return comments
+	}
+	var out []forge.Comment
+	for _, c := range comments {
+		if c.CreatedAt.After(since) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// requestRework sends pull request feedback back to the MR's polecat: it
+// creates a rework task, blocks the MR on it (so the queue moves on until
+// the task closes), and sends a REWORK_REQUEST to the witness.
+func (e *Engineer) requestRework(mr *MRInfo, result ProcessResult) {
+	taskID, err := e.createReworkTaskForMR(mr, result)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to create rework task: %v\n", err)
+	} else if err := e.beads.AddDependency(mr.ID, taskID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to block MR on task: %v\n", err)
+	} else {
+		mr.BlockedBy = taskID
+		_, _ = fmt.Fprintf(e.output, "[Engineer] MR %s blocked on rework task %s\n", mr.ID, taskID)
+	}
+
+	reason := protocol.ReworkReview
+	if result.TestsFailed {
+		reason = protocol.ReworkChecks
+	}
+	msg := protocol.NewPRReworkRequestMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target,
+		reason, result.PRURL, taskID, reworkDetails(result))
+	protocol.SetTraceID(msg, mr.TraceID)
+	if err := e.router.Send(msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to send REWORK_REQUEST to witness: %v\n", err)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Sent rework request for %s\n", mr.Worker)
+	}
+}
+
+// reworkDetails lists the failed checks and review comments in result as
+// markdown sections.
+func reworkDetails(result ProcessResult) string {
+	var sb strings.Builder
+	if result.TestsFailed {
+		sb.WriteString(fmt.Sprintf("## Failed checks\n%s\n", result.Error))
+	}
+	if len(result.Comments) > 0 {
+		sb.WriteString("## Review comments\n")
+		for _, c := range result.Comments {
+			where := ""
+			if loc := c.Location(); loc != "" {
+				where = " on " + loc
+			}
+			sb.WriteString(fmt.Sprintf("\n### %s%s\n%s\n", c.Author, where, strings.TrimSpace(c.Body)))
+		}
+	}
+	return sb.String()
+}
+
+// createReworkTaskForMR creates a task asking for changes to an MR's branch,
+// carrying the pull request's review comments or failed checks, and records
+// it (and the newest comment handled) on the MR bead so the same comments
+// aren't sent twice. Returns the task ID for blocking the MR.
+func (e *Engineer) createReworkTaskForMR(mr *MRInfo, result ProcessResult) (string, error) {
+	originalTitle := mr.SourceIssue
+	if mr.SourceIssue != "" {
+		if sourceIssue, err := e.beads.Show(mr.SourceIssue); err == nil && sourceIssue != nil {
+			originalTitle = sourceIssue.Title
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Rework branch %s for its pull request\n\n", mr.Branch))
+	sb.WriteString("## Metadata\n")
+	sb.WriteString(fmt.Sprintf("- Original MR: %s\n", mr.ID))
+	sb.WriteString(fmt.Sprintf("- Pull request: %s\n", result.PRURL))
+	sb.WriteString(fmt.Sprintf("- Branch: %s\n", mr.Branch))
+	sb.WriteString(fmt.Sprintf("- Original issue: %s\n", mr.SourceIssue))
+	if mr.Worker != "" {
+		sb.WriteString(fmt.Sprintf("- Worker: %s\n", mr.Worker))
+	}
+	sb.WriteString("\n" + reworkDetails(result))
+	sb.WriteString(fmt.Sprintf(`
+## Instructions
+1. Check out the branch: git checkout %s
+2. Address the items above
+3. Commit and push to the same branch (the pull request updates itself)
+4. Close this task: bd close <this-task-id>
+
+The Refinery will re-check the pull request after this task closes.`, mr.Branch))
+
+	kind := "review comments"
+	if result.TestsFailed {
+		kind = "failed checks"
+	}
+	task, err := e.beads.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Address %s: %s", kind, originalTitle),
+		Type:        "task",
+		Priority:    mr.Priority,
+		Description: sb.String(),
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating rework task: %w", err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Created rework task: %s (P%d)\n", task.ID, task.Priority)
+
+	if bead, err := e.beads.Show(mr.ID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+	} else {
+		fields := beads.ParseMRFields(bead)
+		if fields == nil {
+			fields = &beads.MRFields{}
+		}
+		fields.ReworkTaskID = task.ID
+		if n := len(result.Comments); n > 0 {
+			fields.ReviewCursor = result.Comments[n-1].CreatedAt.UTC().Format(time.RFC3339Nano)
+		}
+		newDesc := beads.SetMRFields(bead, fields)
+		if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record rework task on MR %s: %v\n", mr.ID, err)
+		}
+	}
+
+	return task.ID, nil
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
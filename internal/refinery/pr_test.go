@@ -0,0 +1,134 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// TestProcessPR walks one MR through the pull request merge mode: open,
+// wait on the gate, send review comments back as rework, then merge.
+func TestProcessPR(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-abc", Title: "Fix widgets"},
+		beads.Issue{ID: "gt-mr-1", Title: "Merge: gt-abc", Type: "merge-request", Description: beads.FormatMRFields(&beads.MRFields{
+			Branch:      "polecat/nux",
+			Target:      "main",
+			SourceIssue: "gt-abc",
+			Worker:      "nux",
+			Rig:         "testrig",
+		})},
+	)
+	srv := forgetest.NewServer(t, "acme/widgets")
+
+	e := NewEngineer(&rig.Rig{Name: "testrig", Path: t.TempDir()})
+	var out bytes.Buffer
+	e.SetOutput(&out)
+	e.config.MergeMode = config.MergeModePR
+	e.config.Forge = srv.GitHubConfig()
+	e.config.Forge.RequiredChecks = []string{"ci"}
+	e.config.Forge.RequiredApprovals = 1
+
+	ctx := context.Background()
+	mr, err := e.GetMRInfo("gt-mr-1")
+	if err != nil {
+		t.Fatalf("GetMRInfo: %v", err)
+	}
+
+	// First pass opens the PR and waits on CI
+	result := e.ProcessMRInfo(ctx, mr)
+	if result.Waiting == "" || result.Success || result.Error != "" {
+		t.Fatalf("first pass = %+v, want waiting", result)
+	}
+	pr := srv.PR(1)
+	if pr == nil || pr.Head != "polecat/nux" || pr.Base != "main" || pr.Title != "Fix widgets (gt-abc)" {
+		t.Fatalf("opened PR = %+v", pr)
+	}
+	bead, _ := fake.Issue("gt-mr-1")
+	if fields := beads.ParseMRFields(bead); fields.PRNumber != 1 || fields.PRURL != result.PRURL {
+		t.Errorf("MR fields after open = %+v", fields)
+	}
+
+	// Review comments go back as a rework task that blocks the MR
+	srv.SetCheck(1, "ci", forge.CheckSuccess)
+	srv.Comment(1, "alice", "widget.go", 7, "Handle the nil case")
+	result = e.ProcessMRInfo(ctx, mr)
+	if !result.Rework || len(result.Comments) != 1 {
+		t.Fatalf("after comment = %+v, want rework", result)
+	}
+	e.HandleMRInfoFailure(mr, result)
+	if mr.BlockedBy == "" {
+		t.Fatalf("MR not blocked on a rework task; output:\n%s", out.String())
+	}
+	task, ok := fake.Issue(mr.BlockedBy)
+	if !ok || !strings.HasPrefix(task.Title, "Address review comments: Fix widgets") ||
+		!strings.Contains(task.Description, "widget.go:7") {
+		t.Errorf("rework task = %+v", task)
+	}
+	bead, _ = fake.Issue("gt-mr-1")
+	if fields := beads.ParseMRFields(bead); fields.ReworkTaskID != mr.BlockedBy || fields.ReviewCursor == "" {
+		t.Errorf("MR fields after rework = %+v", fields)
+	}
+	if srv.PRs() != 1 {
+		t.Errorf("opened %d PRs, want 1", srv.PRs())
+	}
+
+	// Handled comments aren't sent again; the gate now waits on approval
+	result = e.ProcessMRInfo(ctx, mr)
+	if result.Rework || !strings.Contains(result.Waiting, "approvals") {
+		t.Fatalf("after rework = %+v, want waiting on approvals", result)
+	}
+
+	srv.Approve(1, "bob")
+	result = e.ProcessMRInfo(ctx, mr)
+	if !result.Success {
+		t.Fatalf("after approval = %+v, want success", result)
+	}
+	if pr := srv.PR(1); pr.State != forge.StateMerged || result.MergeCommit != pr.MergeCommit {
+		t.Errorf("PR after merge = %+v, result commit %q", pr, result.MergeCommit)
+	}
+}
+
+func TestProcessPR_FailedChecks(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(beads.Issue{ID: "gt-mr-1", Title: "Merge", Type: "merge-request", Description: beads.FormatMRFields(&beads.MRFields{
+		Branch: "polecat/nux",
+		Target: "main",
+		Worker: "nux",
+	})})
+	srv := forgetest.NewServer(t, "acme/widgets")
+
+	e := NewEngineer(&rig.Rig{Name: "testrig", Path: t.TempDir()})
+	e.SetOutput(&bytes.Buffer{})
+	e.config.MergeMode = config.MergeModePR
+	e.config.Forge = srv.GitLabConfig()
+	e.config.Forge.RequiredChecks = []string{"test"}
+
+	mr, err := e.GetMRInfo("gt-mr-1")
+	if err != nil {
+		t.Fatalf("GetMRInfo: %v", err)
+	}
+	if result := e.ProcessMRInfo(context.Background(), mr); result.Waiting == "" {
+		t.Fatalf("first pass = %+v, want waiting", result)
+	}
+
+	srv.SetCheck(1, "test", forge.CheckFailure)
+	result := e.ProcessMRInfo(context.Background(), mr)
+	if !result.TestsFailed || !result.Rework || !strings.Contains(result.Error, "test") {
+		t.Fatalf("failed checks = %+v, want tests-failed rework", result)
+	}
+	e.HandleMRInfoFailure(mr, result)
+	task, ok := fake.Issue(mr.BlockedBy)
+	if !ok || !strings.HasPrefix(task.Title, "Address failed checks") {
+		t.Errorf("rework task = %+v", task)
+	}
+}
@@ -0,0 +1,27 @@
+package refinery
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain runs the package from an empty temp directory so feed events
+// emitted by the code under test never resolve a town root inside the
+// source tree (the internal/mayor package looks like a town marker).
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gt-refinery-test-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create temp dir: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "chdir: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
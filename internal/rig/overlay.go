@@ -1,61 +1,266 @@
 package rig
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/template"
 )
 
-// CopyOverlay copies files from <rigPath>/.runtime/overlay/ to the destination path.
-// This allows storing gitignored files (like .env) that services need at their root.
-// The overlay is copied non-recursively - only files, not subdirectories.
-// File permissions from the source are preserved.
+// Overlay roles. Files in <overlay>/<role>/ are applied only to worktrees
+// of that role, on top of (and overriding) the shared overlay files.
+const (
+	OverlayRolePolecat  = "polecat"
+	OverlayRoleCrew     = "crew"
+	OverlayRoleRefinery = "refinery"
+)
+
+// overlayRoles are the reserved top-level subdirectories of the overlay.
+var overlayRoles = []string{OverlayRolePolecat, OverlayRoleCrew, OverlayRoleRefinery}
+
+// overlayTemplateExt marks overlay files rendered as Go templates.
+// The extension is stripped from the destination name.
+const overlayTemplateExt = ".tmpl"
+
+// OverlayVars are the variables available to templated overlay files.
+//
+//	PORT={{.PortBase}}
+//	DEBUG_PORT={{add .PortBase 1}}
+//	DB_PORT={{.Ports.db}}
+//	DATABASE_URL=postgres://localhost/{{.Rig}}_{{.Name}}
+type OverlayVars struct {
+	Rig      string // Rig name
+	Role     string // polecat, crew or refinery
+	Name     string // Polecat or crew member name (empty for refinery)
+	Worktree string // Absolute path of the destination worktree
+	BeadID   string // Hook bead at spawn time (polecats only)
+
+	// PortBase and Ports are the polecat's reserved port block and its named
+	// service ports, when the rig sets polecat_ports.
+	PortBase int
+	Ports    map[string]int
+}
+
+// overlayFuncs are the functions available to templated overlay files.
+// There is deliberately no env: overlays land in every worktree, and the
+// daemon's environment holds forge and API tokens.
+var overlayFuncs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+}
+
+// OverlayDir returns the overlay directory for a rig.
+func OverlayDir(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "overlay")
+}
+
+// overlayFile is one file to place in a worktree.
+type overlayFile struct {
+	rel      string // Destination path relative to the worktree
+	src      string // Source path in the overlay
+	template bool   // Render src as a Go template
+}
+
+// planOverlay lists the overlay files for a role: the shared files first,
+// then the role's own files, which replace shared files with the same path.
+func planOverlay(rigPath, role string) ([]overlayFile, error) {
+	overlayDir := OverlayDir(rigPath)
+	if _, err := os.Stat(overlayDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading overlay dir: %w", err)
+	}
+
+	byRel := make(map[string]overlayFile)
+	if err := collectOverlay(overlayDir, true, byRel); err != nil {
+		return nil, err
+	}
+	if role != "" {
+		if err := collectOverlay(filepath.Join(overlayDir, role), false, byRel); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]overlayFile, 0, len(byRel))
+	for _, f := range byRel {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].rel < files[j].rel })
+	return files, nil
+}
+
+// collectOverlay walks dir and adds its files to byRel. At the overlay root
+// (skipRoles), the per-role subdirectories are skipped.
+func collectOverlay(dir string, skipRoles bool, byRel map[string]overlayFile) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipRoles && path != dir && isOverlayRole(rel) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		f := overlayFile{rel: rel, src: path}
+		if strings.HasSuffix(rel, overlayTemplateExt) {
+			f.rel = strings.TrimSuffix(rel, overlayTemplateExt)
+			f.template = true
+		}
+		byRel[f.rel] = f
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("walking overlay dir: %w", err)
+	}
+	return nil
+}
+
+func isOverlayRole(rel string) bool {
+	for _, role := range overlayRoles {
+		if rel == role {
+			return true
+		}
+	}
+	return false
+}
+
+// render returns the content an overlay file should have in the worktree.
+func (f overlayFile) render(vars OverlayVars) ([]byte, error) {
+	data, err := os.ReadFile(f.src) //nolint:gosec // G304: path is from the rig's overlay dir
+	if err != nil {
+		return nil, err
+	}
+	if !f.template {
+		return data, nil
+	}
+	tmpl, err := template.New(f.rel).Funcs(overlayFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("rendering template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// CopyOverlay copies the shared overlay files from <rigPath>/.runtime/overlay/
+// to the destination path, without role overlays or template variables.
+// See ApplyOverlay.
+func CopyOverlay(rigPath, destPath string) error {
+	return ApplyOverlay(rigPath, destPath, OverlayVars{Worktree: destPath})
+}
+
+// ApplyOverlay copies files from <rigPath>/.runtime/overlay/ to the destination
+// path. This allows storing gitignored files (like .env or .vscode/settings.json)
+// that services need in their worktree. The overlay is copied recursively and
+// file permissions from the source are preserved.
 //
 // Structure:
 //
 //	rig/
 //	  .runtime/
 //	    overlay/
-//	      .env          <- Copied to destPath
-//	      config.json   <- Copied to destPath
+//	      .env                   <- Copied to destPath/.env
+//	      .vscode/settings.json  <- Copied to destPath/.vscode/settings.json
+//	      config/local.yaml.tmpl <- Rendered to destPath/config/local.yaml
+//	      polecat/               <- Applied to polecats only (also crew/, refinery/)
+//	        .env                 <- Overrides the shared .env for polecats
+//
+// Files ending in .tmpl are rendered as Go templates with OverlayVars.
 //
 // Returns nil if the overlay directory doesn't exist (nothing to copy).
 // Individual file copy failures are logged as warnings but don't stop the process.
-func CopyOverlay(rigPath, destPath string) error {
-	overlayDir := filepath.Join(rigPath, ".runtime", "overlay")
-
-	// Check if overlay directory exists
-	entries, err := os.ReadDir(overlayDir)
+func ApplyOverlay(rigPath, destPath string, vars OverlayVars) error {
+	files, err := planOverlay(rigPath, vars.Role)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// No overlay directory - not an error, just nothing to copy
-			return nil
-		}
-		return fmt.Errorf("reading overlay dir: %w", err)
+		return err
 	}
 
-	// Copy each file (not directories) from overlay to destination
-	for _, entry := range entries {
-		if entry.IsDir() {
-			// Skip subdirectories - only copy files at overlay root
+	for _, f := range files {
+		dstPath := filepath.Join(destPath, f.rel)
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			fmt.Printf("Warning: could not copy overlay file %s: %v\n", f.rel, err)
 			continue
 		}
 
-		srcPath := filepath.Join(overlayDir, entry.Name())
-		dstPath := filepath.Join(destPath, entry.Name())
-
-		if err := copyFilePreserveMode(srcPath, dstPath); err != nil {
-			// Log warning but continue - don't fail spawn for overlay issues
-			fmt.Printf("Warning: could not copy overlay file %s: %v\n", entry.Name(), err)
+		if !f.template {
+			if err := copyFilePreserveMode(f.src, dstPath); err != nil {
+				// Log warning but continue - don't fail spawn for overlay issues
+				fmt.Printf("Warning: could not copy overlay file %s: %v\n", f.rel, err)
+			}
 			continue
 		}
+
+		content, err := f.render(vars)
+		if err == nil {
+			err = writeFilePreserveMode(f.src, dstPath, content)
+		}
+		if err != nil {
+			fmt.Printf("Warning: could not render overlay file %s: %v\n", f.rel, err)
+		}
 	}
 
 	return nil
 }
 
+// Overlay drift statuses.
+const (
+	OverlayMissing  = "missing"  // Overlay file absent from the worktree
+	OverlayModified = "modified" // Worktree file differs from the overlay
+	OverlayError    = "error"    // Overlay file could not be read or rendered
+)
+
+// OverlayDrift describes a worktree file that no longer matches the overlay.
+type OverlayDrift struct {
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Expected []byte `json:"-"`
+	Error    string `json:"error,omitempty"`
+}
+
+// DiffOverlay compares a worktree against the overlay it would receive now
+// and returns the files that differ. Files matching the overlay are omitted.
+func DiffOverlay(rigPath, destPath string, vars OverlayVars) ([]OverlayDrift, error) {
+	files, err := planOverlay(rigPath, vars.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []OverlayDrift
+	for _, f := range files {
+		expected, err := f.render(vars)
+		if err != nil {
+			drift = append(drift, OverlayDrift{Path: f.rel, Status: OverlayError, Error: err.Error()})
+			continue
+		}
+		actual, err := os.ReadFile(filepath.Join(destPath, f.rel)) //nolint:gosec // G304: path is within the worktree
+		switch {
+		case os.IsNotExist(err):
+			drift = append(drift, OverlayDrift{Path: f.rel, Status: OverlayMissing, Expected: expected})
+		case err != nil:
+			drift = append(drift, OverlayDrift{Path: f.rel, Status: OverlayError, Error: err.Error()})
+		case !bytes.Equal(actual, expected):
+			drift = append(drift, OverlayDrift{Path: f.rel, Status: OverlayModified, Expected: expected})
+		}
+	}
+	return drift, nil
+}
+
 // EnsureGitignorePatterns ensures the .gitignore has required Gas Town patterns.
 // This is called after cloning to add patterns that may be missing from the source repo.
 func EnsureGitignorePatterns(worktreePath string) error {
@@ -154,3 +359,16 @@ func copyFilePreserveMode(src, dst string) error {
 
 	return nil
 }
+
+// writeFilePreserveMode writes content to dst with the permissions of src.
+func writeFilePreserveMode(src, dst string, content []byte) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("stat source: %w", err)
+	}
+	if err := os.WriteFile(dst, content, srcInfo.Mode().Perm()); err != nil {
+		return fmt.Errorf("write destination: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file; match the source.
+	return os.Chmod(dst, srcInfo.Mode().Perm())
+}
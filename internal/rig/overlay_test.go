@@ -98,51 +98,188 @@ func TestCopyOverlay_PreservesPermissions(t *testing.T) {
 	}
 }
 
-func TestCopyOverlay_SkipsSubdirectories(t *testing.T) {
+func TestCopyOverlay_CopiesSubdirectories(t *testing.T) {
 	rigDir := t.TempDir()
 	destDir := t.TempDir()
 
-	// Create overlay directory with a subdirectory
+	// Create overlay directory with nested subdirectories
 	overlayDir := filepath.Join(rigDir, ".runtime", "overlay")
+	if err := os.MkdirAll(filepath.Join(overlayDir, ".vscode"), 0755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(overlayDir, "config", "local"), 0755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+
+	files := map[string]string{
+		"test.txt":                "content",
+		".vscode/settings.json":   "{}",
+		"config/local/local.yaml": "debug: true",
+	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(overlayDir, rel), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", rel, err)
+		}
+	}
+
+	// Copy overlay
+	if err := CopyOverlay(rigDir, destDir); err != nil {
+		t.Fatalf("CopyOverlay() error = %v", err)
+	}
+
+	// Verify every file was copied to the same relative path
+	for rel, want := range files {
+		got, err := os.ReadFile(filepath.Join(destDir, rel))
+		if err != nil {
+			t.Errorf("%s was not copied: %v", rel, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s content = %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestApplyOverlay_RoleOverlay(t *testing.T) {
+	rigDir := t.TempDir()
+	overlayDir := filepath.Join(rigDir, ".runtime", "overlay")
+	for _, dir := range []string{"polecat", "crew"} {
+		if err := os.MkdirAll(filepath.Join(overlayDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	writes := map[string]string{
+		".env":         "SHARED=1",
+		"shared.txt":   "shared",
+		"polecat/.env": "POLECAT=1",
+		"crew/.env":    "CREW=1",
+	}
+	for rel, content := range writes {
+		if err := os.WriteFile(filepath.Join(overlayDir, rel), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", rel, err)
+		}
+	}
+
+	destDir := t.TempDir()
+	if err := ApplyOverlay(rigDir, destDir, OverlayVars{Role: OverlayRolePolecat}); err != nil {
+		t.Fatalf("ApplyOverlay() error = %v", err)
+	}
+
+	// Role file overrides the shared file
+	if got, _ := os.ReadFile(filepath.Join(destDir, ".env")); string(got) != "POLECAT=1" {
+		t.Errorf(".env = %q, want polecat override", got)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, "shared.txt")); err != nil {
+		t.Error("shared.txt should be copied for polecats")
+	}
+	// Role directories are never copied as-is
+	for _, dir := range []string{"polecat", "crew"} {
+		if _, err := os.Stat(filepath.Join(destDir, dir)); err == nil {
+			t.Errorf("role directory %s should not be copied", dir)
+		}
+	}
+}
+
+func TestApplyOverlay_Template(t *testing.T) {
+	rigDir := t.TempDir()
+	overlayDir := filepath.Join(rigDir, ".runtime", "overlay", "config")
 	if err := os.MkdirAll(overlayDir, 0755); err != nil {
 		t.Fatalf("Failed to create overlay dir: %v", err)
 	}
-
-	// Create a subdirectory
-	subDir := filepath.Join(overlayDir, "subdir")
-	if err := os.MkdirAll(subDir, 0755); err != nil {
-		t.Fatalf("Failed to create subdirectory: %v", err)
+	tmpl := "rig: {{.Rig}}\nname: {{.Name}}\nbead: {{.BeadID}}\nport: {{.PortBase}}\ndebug: {{add .PortBase 1}}\ndb: {{.Ports.db}}\n"
+	if err := os.WriteFile(filepath.Join(overlayDir, "local.yaml.tmpl"), []byte(tmpl), 0600); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
 	}
 
-	// Create a file in the overlay root
-	testFile := filepath.Join(overlayDir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("content"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
+	destDir := t.TempDir()
+	vars := OverlayVars{Rig: "gastown", Role: OverlayRolePolecat, Name: "Toast", BeadID: "gt-abc",
+		PortBase: 20000, Ports: map[string]int{"db": 20002}}
+	if err := ApplyOverlay(rigDir, destDir, vars); err != nil {
+		t.Fatalf("ApplyOverlay() error = %v", err)
 	}
 
-	// Create a file in the subdirectory
-	subFile := filepath.Join(subDir, "sub.txt")
-	if err := os.WriteFile(subFile, []byte("subcontent"), 0644); err != nil {
-		t.Fatalf("Failed to create sub file: %v", err)
+	dst := filepath.Join(destDir, "config", "local.yaml")
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("rendered file missing: %v", err)
+	}
+	want := "rig: gastown\nname: Toast\nbead: gt-abc\nport: 20000\ndebug: 20001\ndb: 20002\n"
+	if string(got) != want {
+		t.Errorf("rendered = %q, want %q", got, want)
+	}
+	if info, err := os.Stat(dst); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("rendered mode = %o, want 600", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(destDir, "config", "local.yaml.tmpl")); err == nil {
+		t.Error("template source should not be copied")
 	}
 
-	// Copy overlay
-	err := CopyOverlay(rigDir, destDir)
+	// Templates can't read the environment (and its tokens)
+	if err := os.WriteFile(filepath.Join(overlayDir, "token.tmpl"), []byte(`{{env "HOME"}}`), 0600); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	drift, err := DiffOverlay(rigDir, destDir, vars)
 	if err != nil {
-		t.Fatalf("CopyOverlay() error = %v", err)
+		t.Fatalf("DiffOverlay() error = %v", err)
+	}
+	if len(drift) != 1 || drift[0].Path != filepath.Join("config", "token") || drift[0].Status != OverlayError {
+		t.Errorf("drift = %+v, want env template to fail", drift)
+	}
+}
+
+func TestDiffOverlay(t *testing.T) {
+	rigDir := t.TempDir()
+	overlayDir := filepath.Join(rigDir, ".runtime", "overlay")
+	if err := os.MkdirAll(overlayDir, 0755); err != nil {
+		t.Fatalf("Failed to create overlay dir: %v", err)
+	}
+	for rel, content := range map[string]string{
+		"same.txt":      "same",
+		"changed.txt":   "original",
+		"missing.txt":   "missing",
+		"name.txt.tmpl": "{{.Name}}",
+	} {
+		if err := os.WriteFile(filepath.Join(overlayDir, rel), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", rel, err)
+		}
 	}
 
-	// Verify root file was copied
-	if _, err := os.Stat(filepath.Join(destDir, "test.txt")); err != nil {
-		t.Error("Root file should be copied")
+	destDir := t.TempDir()
+	vars := OverlayVars{Name: "Toast"}
+	if err := ApplyOverlay(rigDir, destDir, vars); err != nil {
+		t.Fatalf("ApplyOverlay() error = %v", err)
+	}
+	if drift, err := DiffOverlay(rigDir, destDir, vars); err != nil || len(drift) != 0 {
+		t.Fatalf("DiffOverlay() after apply = %+v, %v; want no drift", drift, err)
 	}
 
-	// Verify subdirectory was NOT copied
-	if _, err := os.Stat(filepath.Join(destDir, "subdir")); err == nil {
-		t.Error("Subdirectory should not be copied")
+	if err := os.WriteFile(filepath.Join(destDir, "changed.txt"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
 	}
-	if _, err := os.Stat(filepath.Join(destDir, "subdir", "sub.txt")); err == nil {
-		t.Error("File in subdirectory should not be copied")
+	if err := os.Remove(filepath.Join(destDir, "missing.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := DiffOverlay(rigDir, destDir, OverlayVars{Name: "Nux"})
+	if err != nil {
+		t.Fatalf("DiffOverlay() error = %v", err)
+	}
+	got := make(map[string]string)
+	for _, d := range drift {
+		got[d.Path] = d.Status
+	}
+	want := map[string]string{
+		"changed.txt": OverlayModified,
+		"missing.txt": OverlayMissing,
+		"name.txt":    OverlayModified,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DiffOverlay() = %v, want %v", got, want)
+	}
+	for path, status := range want {
+		if got[path] != status {
+			t.Errorf("drift[%s] = %q, want %q", path, got[path], status)
+		}
 	}
 }
 
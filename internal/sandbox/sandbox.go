@@ -0,0 +1,214 @@
+// Package sandbox applies per-rig resource policies to polecat sessions.
+//
+// CPU, memory and process limits are enforced by launching the agent inside
+// a transient systemd user scope, which gives it its own cgroup v2 node.
+// Disk usage of the worktree is measured, not limited: the witness warns
+// polecats whose worktree grows past the rig's threshold.
+//
+// There is no network isolation. A network namespace around the agent would
+// also cut it off from the model API and from the town's Dolt server on the
+// host's loopback, so the "none" network policy is rejected.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Network policies. Only NetworkHost is supported; see Validate.
+const (
+	NetworkHost = "host"
+	NetworkNone = "none"
+)
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// ErrUnsupported is returned when a resource policy cannot be applied on this host.
+var ErrUnsupported = errors.New("resource limits not supported on this host")
+
+// Capabilities describes which isolation mechanisms the host provides.
+type Capabilities struct {
+	CgroupV2   bool // unified cgroup hierarchy mounted
+	SystemdRun bool // systemd-run available for transient scopes
+}
+
+// Detect probes the host for isolation support.
+func Detect() Capabilities {
+	var caps Capabilities
+	if _, err := os.Stat(cgroupRoot + "/cgroup.controllers"); err == nil {
+		caps.CgroupV2 = true
+	}
+	if _, err := exec.LookPath("systemd-run"); err == nil {
+		caps.SystemdRun = true
+	}
+	return caps
+}
+
+// HasLimits reports whether the policy sets any cgroup limit.
+func HasLimits(res *config.PolecatResourcesConfig) bool {
+	return res != nil && (res.CPU != "" || res.Memory != "" || res.MaxProcesses > 0)
+}
+
+// Validate checks a resource policy for malformed values.
+func Validate(res *config.PolecatResourcesConfig) error {
+	if res == nil {
+		return nil
+	}
+	if res.CPU != "" {
+		if _, err := ParseCPU(res.CPU); err != nil {
+			return err
+		}
+	}
+	if res.Memory != "" {
+		if _, err := ParseSize(res.Memory); err != nil {
+			return fmt.Errorf("memory: %w", err)
+		}
+	}
+	if res.DiskWarn != "" {
+		if _, err := ParseSize(res.DiskWarn); err != nil {
+			return fmt.Errorf("disk_warn: %w", err)
+		}
+	}
+	if res.MaxProcesses < 0 {
+		return fmt.Errorf("max_processes: must not be negative")
+	}
+	switch res.Network {
+	case "", NetworkHost:
+	case NetworkNone:
+		// The agent needs the model API and the host's Dolt server, and a
+		// namespace with no egress reaches neither
+		return fmt.Errorf("network: %q is not supported: polecats need the model API and the town's Dolt server", res.Network)
+	default:
+		return fmt.Errorf("network: invalid policy %q (want host or none)", res.Network)
+	}
+	return nil
+}
+
+// UnitName returns the systemd scope unit for a polecat session.
+func UnitName(rigName, polecat string) string {
+	var b strings.Builder
+	b.WriteString("gt-")
+	for _, r := range rigName + "-" + polecat {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)), r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	b.WriteString(".scope")
+	return b.String()
+}
+
+// Wrap returns command wrapped so it runs under the resource policy.
+// A nil or empty policy returns command unchanged. When the host lacks the
+// required mechanisms, Wrap returns command unchanged with an error wrapping
+// ErrUnsupported, so callers can decide whether to start unconstrained.
+func Wrap(command, unit string, res *config.PolecatResourcesConfig, caps Capabilities) (string, error) {
+	if !HasLimits(res) {
+		return command, nil
+	}
+	if err := Validate(res); err != nil {
+		return command, fmt.Errorf("invalid polecat_resources: %w", err)
+	}
+
+	var missing []string
+	if !caps.CgroupV2 {
+		missing = append(missing, "cgroup v2")
+	}
+	if !caps.SystemdRun {
+		missing = append(missing, "systemd-run")
+	}
+	if len(missing) > 0 {
+		return command, fmt.Errorf("%w: missing %s", ErrUnsupported, strings.Join(missing, ", "))
+	}
+
+	argv := []string{"systemd-run", "--user", "--scope", "--quiet", "--collect", "--unit=" + unit}
+	if res.CPU != "" {
+		argv = append(argv, "-p", "CPUQuota="+strings.TrimSpace(res.CPU))
+	}
+	if res.Memory != "" {
+		argv = append(argv, "-p", "MemoryMax="+strings.TrimSpace(res.Memory))
+	}
+	if res.MaxProcesses > 0 {
+		argv = append(argv, "-p", "TasksMax="+strconv.Itoa(res.MaxProcesses))
+	}
+	argv = append(argv, "--", "sh", "-c", config.ShellQuote(command))
+	return strings.Join(argv, " "), nil
+}
+
+// ParseCPU parses a CPU quota like "150%" and returns the percentage.
+func ParseCPU(s string) (int, error) {
+	v := strings.TrimSuffix(strings.TrimSpace(s), "%")
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 || !strings.HasSuffix(strings.TrimSpace(s), "%") {
+		return 0, fmt.Errorf("cpu: invalid quota %q (want a percentage like \"200%%\")", s)
+	}
+	return n, nil
+}
+
+// ParseSize parses a byte size with an optional K, M, G or T suffix
+// (powers of 1024, as systemd interprets them).
+func ParseSize(s string) (int64, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	v = strings.TrimSuffix(v, "B")
+	mult := int64(1)
+	if v != "" {
+		switch v[len(v)-1] {
+		case 'K':
+			mult = 1 << 10
+		case 'M':
+			mult = 1 << 20
+		case 'G':
+			mult = 1 << 30
+		case 'T':
+			mult = 1 << 40
+		}
+		if mult > 1 {
+			v = v[:len(v)-1]
+		}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(f * float64(mult)), nil
+}
+
+// FormatSize renders a byte count with a binary unit suffix.
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// LoadPolicy loads the polecat resource policy from a rig's settings.
+// A rig without settings or without a policy yields nil.
+func LoadPolicy(rigPath string) (*config.PolecatResourcesConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := Validate(settings.PolecatResources); err != nil {
+		return nil, fmt.Errorf("invalid polecat_resources: %w", err)
+	}
+	return settings.PolecatResources, nil
+}
@@ -0,0 +1,181 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var allCaps = Capabilities{CgroupV2: true, SystemdRun: true}
+
+func TestWrap(t *testing.T) {
+	res := &config.PolecatResourcesConfig{CPU: "150%", Memory: "4G", MaxProcesses: 256, Network: NetworkHost}
+	got, err := Wrap("export A=1 && claude", "gt-rig-nux.scope", res, allCaps)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	want := "systemd-run --user --scope --quiet --collect --unit=gt-rig-nux.scope" +
+		" -p CPUQuota=150% -p MemoryMax=4G -p TasksMax=256 --" +
+		" sh -c 'export A=1 && claude'"
+	if got != want {
+		t.Errorf("Wrap() =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestWrap_Runs(t *testing.T) {
+	caps := Detect()
+	if !caps.CgroupV2 || !caps.SystemdRun {
+		t.Skip("host lacks cgroup v2 or systemd-run")
+	}
+	if err := exec.Command("systemd-run", "--user", "--scope", "--quiet", "--collect", "true").Run(); err != nil {
+		t.Skipf("no systemd user manager: %v", err)
+	}
+
+	res := &config.PolecatResourcesConfig{Memory: "256M", MaxProcesses: 64}
+	unit := fmt.Sprintf("gt-sandbox-test-%d.scope", os.Getpid())
+	wrapped, err := Wrap(`echo "$GT_SANDBOX_TEST" && cat /proc/self/cgroup`, unit, res, caps)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	cmd := exec.Command("sh", "-c", wrapped)
+	cmd.Env = append(os.Environ(), "GT_SANDBOX_TEST=ran")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("running %q: %v\n%s", wrapped, err, out)
+	}
+	if !strings.HasPrefix(string(out), "ran\n") || !strings.Contains(string(out), unit) {
+		t.Errorf("output = %q, want the command's output from inside %s", out, unit)
+	}
+}
+
+func TestWrap_NoPolicy(t *testing.T) {
+	for _, res := range []*config.PolecatResourcesConfig{nil, {}, {DiskWarn: "10G"}, {Network: NetworkHost}} {
+		got, err := Wrap("claude", "u.scope", res, Capabilities{})
+		if err != nil || got != "claude" {
+			t.Errorf("Wrap(%+v) = %q, %v; want unchanged", res, got, err)
+		}
+	}
+}
+
+func TestWrap_Unsupported(t *testing.T) {
+	res := &config.PolecatResourcesConfig{Memory: "1G"}
+	got, err := Wrap("claude", "u.scope", res, Capabilities{CgroupV2: true})
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Wrap() error = %v, want ErrUnsupported", err)
+	}
+	if got != "claude" {
+		t.Errorf("Wrap() = %q, want command unchanged", got)
+	}
+	if !strings.Contains(err.Error(), "systemd-run") {
+		t.Errorf("error %q should name the missing tool", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []*config.PolecatResourcesConfig{
+		{CPU: "2"},
+		{CPU: "-50%"},
+		{Memory: "lots"},
+		{DiskWarn: "10X"},
+		{MaxProcesses: -1},
+		{Network: "egress-only"},
+		{Network: NetworkNone},
+	}
+	for _, res := range bad {
+		if err := Validate(res); err == nil {
+			t.Errorf("Validate(%+v) expected error", res)
+		}
+	}
+	if err := Validate(&config.PolecatResourcesConfig{CPU: "200%", Memory: "512M", DiskWarn: "1.5G"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"0":    0,
+		"512":  512,
+		"4K":   4 << 10,
+		"512M": 512 << 20,
+		"2g":   2 << 30,
+		"1.5G": 3 << 29,
+		"1T":   1 << 40,
+		"10GB": 10 << 30,
+	}
+	for in, want := range tests {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+}
+
+func TestUnitName(t *testing.T) {
+	if got := UnitName("gastown", "nux"); got != "gt-gastown-nux.scope" {
+		t.Errorf("UnitName() = %q", got)
+	}
+	if got := UnitName("my rig", "a/b"); got != "gt-my_rig-a_b.scope" {
+		t.Errorf("UnitName() = %q", got)
+	}
+}
+
+func TestReadCgroup(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"memory.current": "4000000000\n",
+		"memory.max":     "4294967296\n",
+		"memory.events":  "low 0\nhigh 0\nmax 12\noom 1\noom_kill 1\n",
+		"pids.current":   "42\n",
+		"pids.max":       "max\n",
+		"cpu.stat":       "usage_usec 90500000\nuser_usec 80000000\nnr_throttled 7\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u, err := ReadCgroup(dir)
+	if err != nil {
+		t.Fatalf("ReadCgroup() error = %v", err)
+	}
+	if u.MemoryBytes != 4000000000 || u.MemoryMaxBytes != 4<<30 {
+		t.Errorf("memory = %d/%d", u.MemoryBytes, u.MemoryMaxBytes)
+	}
+	if u.OOMKills != 1 || u.Processes != 42 || u.ProcessesMax != 0 || u.Throttled != 7 || u.CPUSeconds != 90.5 {
+		t.Errorf("usage = %+v", u)
+	}
+
+	r := &Report{Usage: u, DiskBytes: 2 << 30, DiskWarnBytes: 1 << 30}
+	warnings := r.usageWarnings()
+	if len(warnings) != 3 {
+		t.Errorf("usageWarnings() = %v, want disk, oom and memory warnings", warnings)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a", "x"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a", "b", "y"), make([]byte, 23), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "a", "x"), filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DirSize(dir)
+	if err != nil || got != 123 {
+		t.Errorf("DirSize() = %d, %v; want 123", got, err)
+	}
+}
@@ -0,0 +1,186 @@
+package sandbox
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Usage is a snapshot of a polecat scope's cgroup counters.
+// Max values are zero when unlimited.
+type Usage struct {
+	MemoryBytes    int64   `json:"memory_bytes"`
+	MemoryMaxBytes int64   `json:"memory_max_bytes,omitempty"`
+	OOMKills       int     `json:"oom_kills,omitempty"`
+	Processes      int     `json:"processes"`
+	ProcessesMax   int     `json:"processes_max,omitempty"`
+	CPUSeconds     float64 `json:"cpu_seconds"`
+	Throttled      int     `json:"throttled_periods,omitempty"`
+}
+
+// Report describes a polecat's resource policy and current usage.
+type Report struct {
+	Limits        config.PolecatResourcesConfig `json:"limits"`
+	Unit          string                        `json:"unit,omitempty"`
+	Enforced      bool                          `json:"enforced"`
+	Usage         *Usage                        `json:"usage,omitempty"`
+	DiskBytes     int64                         `json:"disk_bytes"`
+	DiskWarnBytes int64                         `json:"disk_warn_bytes,omitempty"`
+	Warnings      []string                      `json:"warnings,omitempty"`
+}
+
+// Inspect reports a polecat's resource usage against the rig policy.
+// running says whether the polecat's session is up; a running session with
+// cgroup limits configured but no scope (Enforced false) was started
+// without them. Warnings only cover usage at or over a limit.
+func Inspect(rigName, polecat, worktree string, res *config.PolecatResourcesConfig, running bool) *Report {
+	r := &Report{}
+	if res != nil {
+		r.Limits = *res
+	}
+
+	if HasLimits(res) {
+		r.Unit = UnitName(rigName, polecat)
+		if running {
+			if cg, err := scopeCgroup(r.Unit); err == nil && cg != "" {
+				if u, err := ReadCgroup(filepath.Join(cgroupRoot, cg)); err == nil {
+					r.Enforced = true
+					r.Usage = u
+				}
+			}
+		}
+	}
+
+	if worktree != "" {
+		if n, err := DirSize(worktree); err == nil {
+			r.DiskBytes = n
+		}
+	}
+	if res != nil && res.DiskWarn != "" {
+		if limit, err := ParseSize(res.DiskWarn); err == nil {
+			r.DiskWarnBytes = limit
+		}
+	}
+
+	r.Warnings = append(r.Warnings, r.usageWarnings()...)
+	return r
+}
+
+// usageWarnings flags usage at or near the configured limits.
+func (r *Report) usageWarnings() []string {
+	var warnings []string
+	if r.DiskWarnBytes > 0 && r.DiskBytes > r.DiskWarnBytes {
+		warnings = append(warnings, fmt.Sprintf("worktree is %s, over the %s disk warning threshold",
+			FormatSize(r.DiskBytes), FormatSize(r.DiskWarnBytes)))
+	}
+	u := r.Usage
+	if u == nil {
+		return warnings
+	}
+	if u.OOMKills > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d process(es) killed for exceeding the memory limit", u.OOMKills))
+	}
+	if u.MemoryMaxBytes > 0 && u.MemoryBytes*10 >= u.MemoryMaxBytes*9 {
+		warnings = append(warnings, fmt.Sprintf("memory at %s of %s limit",
+			FormatSize(u.MemoryBytes), FormatSize(u.MemoryMaxBytes)))
+	}
+	if u.ProcessesMax > 0 && u.Processes*10 >= u.ProcessesMax*9 {
+		warnings = append(warnings, fmt.Sprintf("%d of %d processes in use", u.Processes, u.ProcessesMax))
+	}
+	return warnings
+}
+
+// scopeCgroup returns the cgroup path of a systemd user scope.
+func scopeCgroup(unit string) (string, error) {
+	out, err := exec.Command("systemctl", "--user", "show", unit, "-p", "ControlGroup", "--value").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ReadCgroup reads usage counters from a cgroup v2 directory.
+func ReadCgroup(dir string) (*Usage, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	u := &Usage{}
+	u.MemoryBytes, _ = readInt(filepath.Join(dir, "memory.current"))
+	u.MemoryMaxBytes, _ = readInt(filepath.Join(dir, "memory.max"))
+	pids, _ := readInt(filepath.Join(dir, "pids.current"))
+	u.Processes = int(pids)
+	pidsMax, _ := readInt(filepath.Join(dir, "pids.max"))
+	u.ProcessesMax = int(pidsMax)
+
+	if events, err := readKeyed(filepath.Join(dir, "memory.events")); err == nil {
+		u.OOMKills = int(events["oom_kill"])
+	}
+	if stat, err := readKeyed(filepath.Join(dir, "cpu.stat")); err == nil {
+		u.CPUSeconds = float64(stat["usage_usec"]) / 1e6
+		u.Throttled = int(stat["nr_throttled"])
+	}
+	return u, nil
+}
+
+// readInt reads a single-value cgroup file. "max" reads as zero (unlimited).
+func readInt(path string) (int64, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a cgroup file
+	if err != nil {
+		return 0, err
+	}
+	v := strings.TrimSpace(string(data))
+	if v == "max" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// readKeyed reads a flat-keyed cgroup file ("key value" per line).
+func readKeyed(path string) (map[string]int64, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a cgroup file
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]int64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values, scanner.Err()
+}
+
+// DirSize returns the total size of regular files under dir.
+// Symlinks are not followed; unreadable entries are skipped.
+func DirSize(dir string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d == nil {
+				return err
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,324 @@
+// Package search finds beads, mail, events and commits matching a query
+// across a whole town, merged into one ranked timeline.
+//
+// Each source (town beads, each rig's beads, the events log, each git
+// repo) is queried in parallel. Results from every source are filtered
+// and scored the same way, so a bead and a commit about the same fix rank
+// side by side.
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is the kind of thing a result is.
+type Kind string
+
+// Result kinds
+const (
+	KindBead   Kind = "bead"   // An issue, task, convoy, MR or other bead
+	KindMail   Kind = "mail"   // A mail message
+	KindEvent  Kind = "event"  // An entry in the town's events log
+	KindCommit Kind = "commit" // A git commit in the town or a rig
+)
+
+// Kinds lists every result kind, in display order.
+var Kinds = []Kind{KindBead, KindMail, KindEvent, KindCommit}
+
+// ParseKind parses a kind name, accepting plurals ("beads", "commits").
+func ParseKind(s string) (Kind, error) {
+	s = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "s")
+	for _, k := range Kinds {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown kind %q (want bead, mail, event or commit)", s)
+}
+
+// Query describes what to search for.
+type Query struct {
+	Text  string    // Terms to match; every term must appear (case-insensitive)
+	Rig   string    // Only results from or about this rig
+	Actor string    // Only results by this actor (partial match)
+	Since time.Time // Only results at or after this time (zero for no bound)
+	Until time.Time // Only results before this time (zero for no bound)
+	Kinds []Kind    // Only these kinds (empty for all)
+	Limit int       // Maximum results (0 for no limit)
+
+	// Chronological orders results newest first instead of by relevance.
+	Chronological bool
+}
+
+// wants reports whether q includes results of kind k.
+func (q *Query) wants(k Kind) bool {
+	if len(q.Kinds) == 0 {
+		return true
+	}
+	for _, want := range q.Kinds {
+		if want == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Result is one match.
+type Result struct {
+	Kind    Kind      `json:"kind"`
+	Source  string    `json:"source"` // Where it was found: "town", a rig name, or "events"
+	ID      string    `json:"id,omitempty"`
+	Title   string    `json:"title"`
+	Snippet string    `json:"snippet,omitempty"` // Excerpt around the first match outside the title
+	Actor   string    `json:"actor,omitempty"`
+	Rig     string    `json:"rig,omitempty"`
+	Time    time.Time `json:"time"`
+	Score   float64   `json:"score"`
+
+	// body is the searchable text beyond the ID and title.
+	body string
+}
+
+// SourceError reports a source that couldn't be searched. The other
+// sources' results are still returned.
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Source, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// source is one place to search. fetch returns candidate results; matching,
+// filtering and scoring happen afterwards, the same way for every source.
+type source struct {
+	name  string
+	kinds []Kind // Kinds this source can produce
+	fetch func(ctx context.Context, q *Query) ([]Result, error)
+}
+
+// Searcher searches a town.
+type Searcher struct {
+	townRoot string
+	rigs     []string
+}
+
+// New creates a searcher for the town at townRoot with the given rigs.
+func New(townRoot string, rigs []string) *Searcher {
+	rigs = append([]string(nil), rigs...)
+	sort.Strings(rigs)
+	return &Searcher{townRoot: townRoot, rigs: rigs}
+}
+
+// Search queries every source in parallel and returns the matches, best
+// first (or newest first if q.Chronological). Sources that fail are
+// reported as SourceErrors alongside the results of the rest.
+func (s *Searcher) Search(ctx context.Context, q Query) ([]Result, []error) {
+	terms := strings.Fields(strings.ToLower(q.Text))
+
+	type outcome struct {
+		results []Result
+		err     error
+	}
+	sources := s.sources(&q)
+	outcomes := make([]outcome, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func(i int, src source) {
+			defer wg.Done()
+			results, err := src.fetch(ctx, &q)
+			if err != nil {
+				err = &SourceError{Source: src.name, Err: err}
+			}
+			outcomes[i] = outcome{results, err}
+		}(i, src)
+	}
+	wg.Wait()
+
+	var results []Result
+	var errs []error
+	seen := make(map[string]bool)
+	now := time.Now()
+	for _, o := range outcomes {
+		if o.err != nil {
+			errs = append(errs, o.err)
+		}
+		for _, r := range o.results {
+			if !q.matches(&r) {
+				continue
+			}
+			score, ok := scoreResult(terms, &r, now)
+			if !ok {
+				continue
+			}
+			// Rigs sharing town beads through a redirect see the same beads
+			if r.ID != "" {
+				key := string(r.Kind) + ":" + r.ID
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			r.Score = score
+			r.Snippet = snippet(terms, r.body)
+			results = append(results, r)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if !q.Chronological && results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Time.After(results[j].Time)
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, errs
+}
+
+// sources returns the sources worth querying for q.
+func (s *Searcher) sources(q *Query) []source {
+	candidates := []source{
+		{name: "town beads", kinds: []Kind{KindBead, KindMail}, fetch: s.fetchBeads("town", s.townRoot, "")},
+		{name: "events", kinds: []Kind{KindEvent}, fetch: s.fetchEvents},
+		{name: "town commits", kinds: []Kind{KindCommit}, fetch: s.fetchCommits("town", s.townRoot, "")},
+	}
+	for _, rig := range s.rigs {
+		if q.Rig != "" && rig != q.Rig {
+			continue
+		}
+		candidates = append(candidates,
+			source{name: rig + " beads", kinds: []Kind{KindBead, KindMail}, fetch: s.fetchBeads(rig, s.rigPath(rig), rig)},
+			source{name: rig + " commits", kinds: []Kind{KindCommit}, fetch: s.fetchCommits(rig, s.rigRepo(rig), rig)},
+		)
+	}
+
+	var out []source
+	for _, src := range candidates {
+		for _, k := range src.kinds {
+			if q.wants(k) {
+				out = append(out, src)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// matches applies q's kind, rig, actor and time filters.
+func (q *Query) matches(r *Result) bool {
+	if !q.wants(r.Kind) {
+		return false
+	}
+	if q.Rig != "" && r.Rig != q.Rig {
+		return false
+	}
+	if q.Actor != "" && !strings.Contains(strings.ToLower(r.Actor), strings.ToLower(q.Actor)) {
+		return false
+	}
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !r.Time.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Scoring weights. A term in the ID or title counts more than one in the
+// body; recency breaks near-ties, fading over about a week.
+const (
+	titleWeight   = 3.0
+	bodyWeight    = 1.0
+	phraseWeight  = 2.0
+	idWeight      = 10.0
+	recencyWeight = 2.0
+	recencyScale  = 7 * 24 * time.Hour
+)
+
+// scoreResult scores r against terms, reporting false if any term is
+// missing. With no terms every result matches, ranked by recency alone.
+func scoreResult(terms []string, r *Result, now time.Time) (float64, bool) {
+	id := strings.ToLower(r.ID)
+	head := id + " " + strings.ToLower(r.Title)
+	body := strings.ToLower(r.body)
+
+	var score float64
+	for _, term := range terms {
+		switch {
+		case id != "" && term == id:
+			score += idWeight
+		case strings.Contains(head, term):
+			score += titleWeight
+		case strings.Contains(body, term):
+			score += bodyWeight
+		default:
+			return 0, false
+		}
+	}
+	if len(terms) > 1 && strings.Contains(head, strings.Join(terms, " ")) {
+		score += phraseWeight
+	}
+	if !r.Time.IsZero() {
+		age := now.Sub(r.Time)
+		if age < 0 {
+			age = 0
+		}
+		score += recencyWeight * math.Exp(-float64(age)/float64(recencyScale))
+	}
+	return math.Round(score*100) / 100, true
+}
+
+// snippetRadius is how much context a snippet shows around its match.
+const snippetRadius = 40
+
+// snippet returns a one-line excerpt of body around the first term found
+// in it, or "" if none is.
+func snippet(terms []string, body string) string {
+	lower := strings.ToLower(body)
+	at := -1
+	var term string
+	for _, t := range terms {
+		if i := strings.Index(lower, t); i >= 0 && (at < 0 || i < at) {
+			at, term = i, t
+		}
+	}
+	if at < 0 {
+		return ""
+	}
+
+	start, end := at-snippetRadius, at+len(term)+snippetRadius
+	prefix, suffix := "…", "…"
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(body) {
+		end, suffix = len(body), ""
+	}
+	// Don't split a UTF-8 sequence
+	for start > 0 && !utf8Start(body[start]) {
+		start--
+	}
+	for end < len(body) && !utf8Start(body[end]) {
+		end++
+	}
+	return prefix + strings.Join(strings.Fields(body[start:end]), " ") + suffix
+}
+
+// utf8Start reports whether b begins a UTF-8 sequence.
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
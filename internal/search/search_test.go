@@ -0,0 +1,197 @@
+package search
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// setupTown creates a town with beads, mail, events and a rig repo that
+// all mention the login fix.
+func setupTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	day := func(n int) string {
+		return time.Now().Add(-time.Duration(n) * 24 * time.Hour).UTC().Format(time.RFC3339)
+	}
+
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "gt-login", Title: "Fix login redirect", Description: "Users bounce back to /home", Assignee: "gastown/Toast", UpdatedAt: day(1)},
+		beads.Issue{ID: "gt-old", Title: "Tidy docs", Description: "Mention the login page in the guide", CreatedBy: "mayor", UpdatedAt: day(30)},
+		beads.Issue{ID: "gt-other", Title: "Unrelated", Description: "Nothing to see", UpdatedAt: day(1)},
+		beads.Issue{ID: "hq-msg1", Title: "Login still broken?", Type: "message", Description: "Toast, is the redirect fixed?",
+			Assignee: "gastown/Toast", Labels: []string{"from:mayor/"}, CreatedAt: day(2)},
+	)
+
+	eventsLog := strings.Join([]string{
+		`{"ts":"` + day(3) + `","type":"sling","actor":"mayor","payload":{"bead":"gt-login","target":"gastown/Toast"}}`,
+		`{"ts":"` + day(3) + `","type":"patrol_started","actor":"gastown/witness","payload":{"rig":"gastown"}}`,
+		`not json`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(eventsLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := filepath.Join(townRoot, "gastown", "mayor", "rig")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git := func(author string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@example.com", "GIT_AUTHOR_DATE="+day(1),
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@example.com", "GIT_COMMITTER_DATE="+day(1))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("", "init", "-b", "main")
+	git("Toast", "commit", "--allow-empty", "-m", "fix: login redirect loop (gt-login)")
+	git("Nux", "commit", "--allow-empty", "-m", "chore: bump deps")
+	return townRoot
+}
+
+func kindsOf(results []Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, string(r.Kind)+":"+r.ID+r.Title)
+	}
+	return out
+}
+
+func TestSearch_AllSources(t *testing.T) {
+	townRoot := setupTown(t)
+	s := New(townRoot, []string{"gastown"})
+
+	results, errs := s.Search(context.Background(), Query{Text: "login"})
+	if len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+
+	got := map[Kind]int{}
+	for _, r := range results {
+		got[r.Kind]++
+	}
+	// Beads: gt-login and gt-old (the shared fake store is seen through
+	// both town and rig beads, but each bead is reported once)
+	want := map[Kind]int{KindBead: 2, KindMail: 1, KindEvent: 1, KindCommit: 1}
+	for k, n := range want {
+		if got[k] != n {
+			t.Errorf("%s results = %d, want %d (%v)", k, got[k], n, kindsOf(results))
+		}
+	}
+
+	// Title matches outrank body matches; recency breaks the rest
+	if results[0].ID != "gt-login" && results[0].Kind != KindCommit {
+		t.Errorf("top result = %+v, want a title match", results[0])
+	}
+	last := results[len(results)-1]
+	if last.ID != "gt-old" {
+		t.Errorf("last result = %+v, want the old body-only match", last)
+	}
+	if last.Snippet == "" || !strings.Contains(last.Snippet, "login") {
+		t.Errorf("snippet = %q", last.Snippet)
+	}
+
+	for _, r := range results {
+		if r.Kind == KindMail && (r.Actor != "mayor/" || r.Rig != "gastown") {
+			t.Errorf("mail result = %+v, want from mayor about gastown", r)
+		}
+		if r.Kind == KindCommit && (r.Actor != "Toast" || r.Rig != "gastown" || r.Source != "gastown") {
+			t.Errorf("commit result = %+v", r)
+		}
+	}
+}
+
+func TestSearch_Filters(t *testing.T) {
+	townRoot := setupTown(t)
+	s := New(townRoot, []string{"gastown"})
+	ctx := context.Background()
+
+	results, _ := s.Search(ctx, Query{Text: "login", Kinds: []Kind{KindCommit, KindMail}})
+	for _, r := range results {
+		if r.Kind != KindCommit && r.Kind != KindMail {
+			t.Errorf("--kind leaked %s", r.Kind)
+		}
+	}
+	if len(results) != 2 {
+		t.Errorf("kind filter: %v", kindsOf(results))
+	}
+
+	// The bead is assigned to Toast and the commit authored by Toast; the
+	// mail to Toast was sent by the mayor
+	results, _ = s.Search(ctx, Query{Text: "login", Actor: "toast"})
+	if got := kindsOf(results); len(got) != 2 {
+		t.Errorf("actor filter: %v", got)
+	}
+	for _, r := range results {
+		if r.Kind != KindBead && r.Kind != KindCommit {
+			t.Errorf("actor filter kept %s %s", r.Kind, r.ID)
+		}
+	}
+
+	results, _ = s.Search(ctx, Query{Text: "login", Since: time.Now().Add(-36 * time.Hour)})
+	for _, r := range results {
+		if r.ID == "gt-old" || r.Kind == KindMail || r.Kind == KindEvent {
+			t.Errorf("since filter kept %s %s", r.Kind, r.ID)
+		}
+	}
+
+	results, _ = s.Search(ctx, Query{Text: "login", Until: time.Now().Add(-7 * 24 * time.Hour)})
+	if got := kindsOf(results); len(got) != 1 || results[0].ID != "gt-old" {
+		t.Errorf("until filter: %v", got)
+	}
+
+	results, _ = s.Search(ctx, Query{Text: "redirect login", Limit: 2})
+	if len(results) != 2 {
+		t.Errorf("limit: %v", kindsOf(results))
+	}
+
+	results, _ = s.Search(ctx, Query{Text: "login", Chronological: true})
+	for i := 1; i < len(results); i++ {
+		if results[i].Time.After(results[i-1].Time) {
+			t.Errorf("chronological order broken at %d: %v", i, kindsOf(results))
+		}
+	}
+}
+
+func TestSearch_AllTermsRequired(t *testing.T) {
+	townRoot := setupTown(t)
+	results, _ := New(townRoot, []string{"gastown"}).Search(context.Background(), Query{Text: "login nonexistent"})
+	if len(results) != 0 {
+		t.Errorf("results = %v, want none", kindsOf(results))
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for in, want := range map[string]Kind{"bead": KindBead, "Commits": KindCommit, "mail": KindMail, "events": KindEvent} {
+		if got, err := ParseKind(in); err != nil || got != want {
+			t.Errorf("ParseKind(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseKind("wisp"); err == nil {
+		t.Error("ParseKind(wisp) succeeded")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	body := strings.Repeat("a ", 40) + "the login page\nredirects " + strings.Repeat("b ", 40)
+	got := snippet([]string{"login"}, body)
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "the login page redirects") {
+		t.Errorf("snippet = %q", got)
+	}
+	if got := snippet([]string{"missing"}, body); got != "" {
+		t.Errorf("snippet without match = %q", got)
+	}
+}
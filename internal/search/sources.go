@@ -0,0 +1,270 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// maxCommits caps how many matching commits each repo contributes.
+const maxCommits = 200
+
+// rigPath returns a rig's directory.
+func (s *Searcher) rigPath(rig string) string {
+	return filepath.Join(s.townRoot, rig)
+}
+
+// rigRepo returns the rig's canonical clone, whose history the refinery
+// merges into.
+func (s *Searcher) rigRepo(rig string) string {
+	return filepath.Join(s.townRoot, rig, "mayor", "rig")
+}
+
+// rigOf returns the rig an agent address belongs to ("gastown/Toast" →
+// "gastown"), or "" for town-level agents and unknown rigs.
+func (s *Searcher) rigOf(address string) string {
+	first, _, _ := strings.Cut(address, "/")
+	i := sort.SearchStrings(s.rigs, first)
+	if i < len(s.rigs) && s.rigs[i] == first {
+		return first
+	}
+	return ""
+}
+
+// fetchBeads returns a source reading every bead in the database at dir.
+// Messages become mail results; everything else is a bead. Beads in a
+// rig's database belong to that rig; town beads and mail belong to the rig
+// of their sender or recipient, if any.
+func (s *Searcher) fetchBeads(name, dir, rig string) func(context.Context, *Query) ([]Result, error) {
+	return func(_ context.Context, q *Query) ([]Result, error) {
+		var b *beads.Beads
+		if rig != "" {
+			b = beads.NewWithBeadsDir(dir, beads.ResolveBeadsDir(dir))
+		} else {
+			b = beads.New(dir)
+		}
+		issues, err := b.List(beads.ListOptions{Status: "all", Priority: -1, Limit: -1})
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]Result, 0, len(issues))
+		for _, issue := range issues {
+			if issue.Type == "message" {
+				results = append(results, s.mailResult(name, rig, issue))
+				continue
+			}
+
+			actor := issue.CreatedBy
+			if actor == "" {
+				actor = issue.Assignee
+			}
+			r := Result{
+				Kind:   KindBead,
+				Source: name,
+				ID:     issue.ID,
+				Title:  issue.Title,
+				Actor:  actor,
+				Rig:    rig,
+				Time:   parseBeadsTime(issue.UpdatedAt, issue.CreatedAt),
+				body:   strings.Join(append([]string{issue.Description, issue.Assignee}, issue.Labels...), "\n"),
+			}
+			if r.Rig == "" {
+				r.Rig = s.rigOf(issue.Assignee)
+			}
+			results = append(results, r)
+		}
+		return results, nil
+	}
+}
+
+// mailResult converts a message bead to a mail result.
+func (s *Searcher) mailResult(source, rig string, issue *beads.Issue) Result {
+	var from string
+	for _, label := range issue.Labels {
+		if v, ok := strings.CutPrefix(label, "from:"); ok {
+			from = v
+			break
+		}
+	}
+	if rig == "" {
+		if rig = s.rigOf(from); rig == "" {
+			rig = s.rigOf(issue.Assignee)
+		}
+	}
+	return Result{
+		Kind:   KindMail,
+		Source: source,
+		ID:     issue.ID,
+		Title:  issue.Title,
+		Actor:  from,
+		Rig:    rig,
+		Time:   parseBeadsTime(issue.CreatedAt),
+		body:   issue.Description + "\nto " + issue.Assignee,
+	}
+}
+
+// parseBeadsTime parses the first non-empty beads timestamp that parses.
+func parseBeadsTime(values ...string) time.Time {
+	formats := []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+	for _, v := range values {
+		for _, format := range formats {
+			if t, err := time.Parse(format, v); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// fetchEvents reads the town's events log.
+func (s *Searcher) fetchEvents(_ context.Context, q *Query) ([]Result, error) {
+	f, err := os.Open(filepath.Join(s.townRoot, events.EventsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var results []Result
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		if !q.Since.IsZero() && ts.Before(q.Since) {
+			continue
+		}
+
+		rig, _ := e.Payload["rig"].(string)
+		if rig == "" {
+			rig = s.rigOf(e.Actor)
+		}
+		results = append(results, Result{
+			Kind:   KindEvent,
+			Source: "events",
+			Title:  eventTitle(e),
+			Actor:  e.Actor,
+			Rig:    rig,
+			Time:   ts,
+			body:   payloadText(e.Payload),
+		})
+	}
+	return results, scanner.Err()
+}
+
+// eventTitle summarizes an event as its type plus the payload fields that
+// identify what it was about.
+func eventTitle(e events.Event) string {
+	var subjects []string
+	for _, key := range []string{"bead", "mr", "convoy_id", "target", "to", "branch", "subject"} {
+		if v, ok := e.Payload[key].(string); ok && v != "" {
+			subjects = append(subjects, v)
+		}
+	}
+	if len(subjects) == 0 {
+		return e.Type
+	}
+	return e.Type + " " + strings.Join(subjects, " ")
+}
+
+// payloadText flattens an event payload to searchable "key: value" lines.
+func payloadText(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, k := range keys {
+		switch v := payload[k].(type) {
+		case string:
+			lines = append(lines, k+": "+v)
+		case nil:
+		default:
+			data, _ := json.Marshal(v)
+			lines = append(lines, k+": "+string(data))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Field and record separators for git log output; neither appears in
+// commit metadata or messages in practice.
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// fetchCommits returns a source reading matching commits from the git repo
+// at dir. A directory that isn't a repo has no commits.
+func (s *Searcher) fetchCommits(name, dir, rig string) func(context.Context, *Query) ([]Result, error) {
+	return func(ctx context.Context, q *Query) ([]Result, error) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			return nil, nil
+		}
+
+		args := []string{"log", "--all", "--regexp-ignore-case", "--fixed-strings", "--all-match",
+			"--format=%H" + fieldSep + "%aI" + fieldSep + "%an" + fieldSep + "%s" + fieldSep + "%b" + recordSep,
+			fmt.Sprintf("-n%d", maxCommits)}
+		// Narrow in git rather than reading the whole history
+		for _, term := range strings.Fields(q.Text) {
+			args = append(args, "--grep="+term)
+		}
+		if !q.Since.IsZero() {
+			args = append(args, "--since="+q.Since.Format(time.RFC3339))
+		}
+		if !q.Until.IsZero() {
+			args = append(args, "--until="+q.Until.Format(time.RFC3339))
+		}
+
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("git log: %s", msg)
+			}
+			return nil, fmt.Errorf("git log: %w", err)
+		}
+
+		var results []Result
+		for _, record := range strings.Split(stdout.String(), recordSep) {
+			fields := strings.SplitN(strings.TrimLeft(record, "\n"), fieldSep, 5)
+			if len(fields) < 5 {
+				continue
+			}
+			ts, _ := time.Parse(time.RFC3339, fields[1])
+			results = append(results, Result{
+				Kind:   KindCommit,
+				Source: name,
+				ID:     fields[0][:min(len(fields[0]), 8)],
+				Title:  fields[3],
+				Actor:  fields[2],
+				Rig:    rig,
+				Time:   ts,
+				body:   strings.TrimSpace(fields[4]),
+			})
+		}
+		return results, nil
+	}
+}
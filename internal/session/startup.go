@@ -70,6 +70,13 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 		beacon += "\n\nWork is on your hook. Run `gt hook` now and begin immediately."
 	}
 
+	// For warm pool sessions there is no work yet - gt sling nudges them
+	// when it hands the polecat a bead
+	if cfg.Topic == "warm" {
+		beacon += "\n\nYou are a warm standby polecat with no work yet. Do not run `gt hook` or `gt done`. " +
+			"Wait quietly; you will be nudged when work is slung to you."
+	}
+
 	return beacon
 }
 
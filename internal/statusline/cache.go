@@ -0,0 +1,114 @@
+// Package statusline caches rendered tmux status lines.
+//
+// tmux runs gt status-line every few seconds for every session, and each
+// live render queries tmux and beads. The daemon renders every session's
+// line in the background (on a timer and on mail, hook and convoy events)
+// and stores it here, so gt status-line can answer with one file read. When
+// the cache is missing or stale, gt status-line renders live as before.
+package statusline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Cache defaults
+const (
+	// DefaultRefreshInterval is how often the daemon re-renders every
+	// session's status line when nothing happens in the town.
+	DefaultRefreshInterval = 15 * time.Second
+
+	// DefaultTTL is how long a cached line is served before gt status-line
+	// falls back to rendering live. Several missed refreshes means the
+	// daemon is down or wedged.
+	DefaultTTL = 3 * DefaultRefreshInterval
+)
+
+// Entry is one session's cached status line.
+type Entry struct {
+	Text       string    `json:"text"`
+	RenderedAt time.Time `json:"rendered_at"`
+
+	// TTL is chosen by the writer, which knows how often it refreshes,
+	// so readers need no configuration of their own.
+	TTL time.Duration `json:"ttl"`
+}
+
+// Fresh reports whether the entry may still be served.
+func (e *Entry) Fresh() bool {
+	return time.Since(e.RenderedAt) <= e.TTL
+}
+
+// Dir returns the directory holding a town's cached status lines.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "statusline")
+}
+
+// entryPath returns the cache file for a session.
+func entryPath(townRoot, session string) (string, error) {
+	if session == "" || strings.ContainsAny(session, `/\`) || strings.HasPrefix(session, ".") {
+		return "", fmt.Errorf("invalid session name %q", session)
+	}
+	return filepath.Join(Dir(townRoot), session+".json"), nil
+}
+
+// Read returns a session's cached status line if there is a fresh one.
+func Read(townRoot, session string) (string, bool) {
+	path, err := entryPath(townRoot, session)
+	if err != nil {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || !e.Fresh() {
+		return "", false
+	}
+	return e.Text, true
+}
+
+// Write stores a session's rendered status line, to be served for ttl
+// (DefaultTTL if zero).
+func Write(townRoot, session, text string, ttl time.Duration) error {
+	path, err := entryPath(townRoot, session)
+	if err != nil {
+		return err
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating statusline cache dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, &Entry{Text: text, RenderedAt: time.Now(), TTL: ttl})
+}
+
+// Prune removes cached lines for sessions not in live, so a recycled
+// session name never shows its predecessor's status.
+func Prune(townRoot string, live map[string]bool) error {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		session, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || live[session] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(Dir(townRoot), entry.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
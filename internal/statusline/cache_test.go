@@ -0,0 +1,87 @@
+package statusline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+func TestWriteRead(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if _, ok := Read(townRoot, "gt-gastown-Toast"); ok {
+		t.Fatal("Read before Write returned a line")
+	}
+
+	if err := Write(townRoot, "gt-gastown-Toast", "🦨 🪝 gt-abc: Fix login |", time.Minute); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	line, ok := Read(townRoot, "gt-gastown-Toast")
+	if !ok || line != "🦨 🪝 gt-abc: Fix login |" {
+		t.Errorf("Read = %q, %v", line, ok)
+	}
+
+	// An empty line is a valid render (nothing hooked, no mail)
+	if err := Write(townRoot, "gt-gastown-crew-max", "", time.Minute); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if line, ok := Read(townRoot, "gt-gastown-crew-max"); !ok || line != "" {
+		t.Errorf("Read(empty) = %q, %v", line, ok)
+	}
+}
+
+func TestReadStale(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(Dir(townRoot), "hq-mayor.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	stale := &Entry{Text: "old", RenderedAt: time.Now().Add(-time.Minute), TTL: 30 * time.Second}
+	if err := util.AtomicWriteJSON(path, stale); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Read(townRoot, "hq-mayor"); ok {
+		t.Error("Read served a stale line")
+	}
+}
+
+func TestInvalidSession(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, session := range []string{"", "../escape", "a/b", ".hidden"} {
+		if err := Write(townRoot, session, "x", 0); err == nil {
+			t.Errorf("Write(%q) succeeded", session)
+		}
+		if _, ok := Read(townRoot, session); ok {
+			t.Errorf("Read(%q) returned a line", session)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, s := range []string{"hq-mayor", "gt-gastown-Toast", "gt-gastown-nux"} {
+		if err := Write(townRoot, s, s, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Prune(townRoot, map[string]bool{"hq-mayor": true, "gt-gastown-nux": true}); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if _, ok := Read(townRoot, "gt-gastown-Toast"); ok {
+		t.Error("dead session's line survived Prune")
+	}
+	for _, s := range []string{"hq-mayor", "gt-gastown-nux"} {
+		if _, ok := Read(townRoot, s); !ok {
+			t.Errorf("live session %s pruned", s)
+		}
+	}
+
+	// Nothing cached yet is not an error
+	if err := Prune(t.TempDir(), nil); err != nil {
+		t.Errorf("Prune(empty town): %v", err)
+	}
+}
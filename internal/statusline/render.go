@@ -0,0 +1,747 @@
+package statusline
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// roleIcons maps agent roles to the icons shown in status lines.
+var roleIcons = map[session.Role]string{
+	session.RoleMayor:    constants.EmojiMayor,
+	session.RoleDeacon:   constants.EmojiDeacon,
+	session.RoleWitness:  constants.EmojiWitness,
+	session.RoleRefinery: constants.EmojiRefinery,
+	session.RoleCrew:     constants.EmojiCrew,
+	session.RolePolecat:  constants.EmojiPolecat,
+}
+
+// Renderer renders tmux status lines by querying tmux and beads.
+//
+// Every town-wide query a line needs (the tmux session list, registered rigs
+// and their states, hooked beads, unread mail, merge queues) is made at most
+// once per Renderer and shared by all the lines it renders. The daemon uses
+// one Renderer per refresh pass, so refreshing every session costs about as
+// much as rendering one. A Renderer is not safe for concurrent use.
+type Renderer struct {
+	// townRoot is the town being rendered. When empty, each line finds the
+	// town from its session's pane directory.
+	townRoot string
+	tmux     *tmux.Tmux
+
+	sessions    []string
+	sessionsErr error
+	listed      bool
+
+	registeredRigs map[string]map[string]bool      // town root -> rig names
+	rigStates      map[string]string               // town root/rig -> state
+	hooked         map[string][]*beads.Issue       // beads dir -> hooked beads
+	inboxes        map[string]*mail.UnreadIndex    // town root -> unread mail
+	queues         map[string][]refinery.QueueItem // rig path -> merge queue
+	queueErrs      map[string]error                // rig path -> queue error
+}
+
+// NewRenderer creates a renderer for the town at townRoot. An empty townRoot
+// finds the town from each rendered session's pane directory.
+func NewRenderer(townRoot string) *Renderer {
+	return &Renderer{
+		townRoot:       townRoot,
+		tmux:           tmux.NewTmux(),
+		registeredRigs: make(map[string]map[string]bool),
+		rigStates:      make(map[string]string),
+		hooked:         make(map[string][]*beads.Issue),
+		inboxes:        make(map[string]*mail.UnreadIndex),
+		queues:         make(map[string][]refinery.QueueItem),
+		queueErrs:      make(map[string]error),
+	}
+}
+
+// Render writes the status line for a tmux session. With an empty session
+// name the Gas Town environment is read from the current process instead.
+func (r *Renderer) Render(w io.Writer, sessionName string) error {
+	env := map[string]string{}
+	if sessionName != "" {
+		// Non-fatal: missing env vars are handled gracefully below
+		env, _ = r.tmux.GetAllEnvironment(sessionName)
+	} else {
+		for _, key := range []string{"GT_RIG", "GT_POLECAT", "GT_CREW", "GT_ISSUE", "GT_ROLE"} {
+			env[key] = os.Getenv(key)
+		}
+	}
+	rigName, polecat, crew, issue, role := env["GT_RIG"], env["GT_POLECAT"], env["GT_CREW"], env["GT_ISSUE"], env["GT_ROLE"]
+
+	// Determine identity and output based on role
+	if role == "mayor" || sessionName == session.MayorSessionName() {
+		return r.renderMayor(w)
+	}
+
+	// Deacon status line
+	if role == "deacon" || sessionName == session.DeaconSessionName() {
+		return r.renderDeacon(w)
+	}
+
+	// Witness status line (session naming: gt-<rig>-witness)
+	if role == "witness" || strings.HasSuffix(sessionName, "-witness") {
+		return r.renderWitness(w, sessionName, rigName)
+	}
+
+	// Refinery status line
+	if role == "refinery" || strings.HasSuffix(sessionName, "-refinery") {
+		return r.renderRefinery(w, sessionName, rigName)
+	}
+
+	// Crew/Polecat status line
+	return r.renderWorker(w, sessionName, rigName, polecat, crew, issue)
+}
+
+// renderWorker outputs status for crew or polecat sessions.
+func (r *Renderer) renderWorker(w io.Writer, sessionName, rigName, polecat, crew, issue string) error {
+	// Determine agent type and identity
+	var icon, identity string
+	if polecat != "" {
+		icon = roleIcons[session.RolePolecat]
+		identity = fmt.Sprintf("%s/%s", rigName, polecat)
+	} else if crew != "" {
+		icon = roleIcons[session.RoleCrew]
+		identity = fmt.Sprintf("%s/crew/%s", rigName, crew)
+	}
+
+	townRoot := ""
+	if sessionName != "" {
+		townRoot = r.townRootFor(sessionName)
+	}
+
+	// Build status parts
+	var parts []string
+
+	// Priority 1: Check for hooked work (use rig beads)
+	hookedWork := ""
+	if identity != "" && rigName != "" && townRoot != "" {
+		rigBeadsDir := filepath.Join(townRoot, rigName, "mayor", "rig")
+		hookedWork = r.hookedWork(identity, 40, rigBeadsDir)
+	}
+
+	// Priority 2: Fall back to GT_ISSUE env var or in_progress beads
+	currentWork := issue
+	if currentWork == "" && hookedWork == "" && sessionName != "" {
+		currentWork = r.currentWork(sessionName, 40)
+	}
+
+	// Show hooked work (takes precedence)
+	if hookedWork != "" {
+		if icon != "" {
+			parts = append(parts, fmt.Sprintf("%s 🪝 %s", icon, hookedWork))
+		} else {
+			parts = append(parts, fmt.Sprintf("🪝 %s", hookedWork))
+		}
+	} else if currentWork != "" {
+		// Fall back to current work (in_progress)
+		if icon != "" {
+			parts = append(parts, fmt.Sprintf("%s %s", icon, currentWork))
+		} else {
+			parts = append(parts, currentWork)
+		}
+	} else if icon != "" {
+		parts = append(parts, icon)
+	}
+
+	// Mail preview - only show if hook is empty
+	if hookedWork == "" && identity != "" && townRoot != "" {
+		parts = r.appendMail(parts, identity, 45, townRoot)
+	}
+
+	// Output
+	if len(parts) > 0 {
+		fmt.Fprint(w, strings.Join(parts, " | ")+" |")
+	}
+
+	return nil
+}
+
+func (r *Renderer) renderMayor(w io.Writer) error {
+	// Count active sessions by listing tmux sessions
+	sessions, err := r.listSessions()
+	if err != nil {
+		return nil // Silent fail
+	}
+
+	// Get town root from mayor pane's working directory
+	townRoot := r.townRootFor(session.MayorSessionName())
+	registeredRigs := r.registered(townRoot)
+
+	// Track per-rig status for LED indicators and sorting
+	type rigStatus struct {
+		hasWitness  bool
+		hasRefinery bool
+		opState     string // "OPERATIONAL", "PARKED", or "DOCKED"
+	}
+	rigStatuses := make(map[string]*rigStatus)
+
+	// Initialize for all registered rigs
+	for rigName := range registeredRigs {
+		rigStatuses[rigName] = &rigStatus{}
+	}
+
+	// Track per-agent-type health (working/zombie counts)
+	type agentHealth struct {
+		total   int
+		working int
+	}
+	healthByRole := map[session.Role]*agentHealth{
+		session.RoleWitness:  {},
+		session.RoleRefinery: {},
+	}
+
+	// Track deacon presence (just icon, no count)
+	hasDeacon := false
+
+	// Single pass: track rig status AND agent health
+	for _, s := range sessions {
+		agent, err := session.ParseSessionName(s)
+		if err != nil {
+			continue
+		}
+
+		// Track rig-level status (witness/refinery presence)
+		// Polecats are not tracked in tmux - they're a GC concern, not a display concern
+		if agent.Rig != "" && registeredRigs[agent.Rig] {
+			if rigStatuses[agent.Rig] == nil {
+				rigStatuses[agent.Rig] = &rigStatus{}
+			}
+			switch agent.Role {
+			case session.RoleWitness:
+				rigStatuses[agent.Rig].hasWitness = true
+			case session.RoleRefinery:
+				rigStatuses[agent.Rig].hasRefinery = true
+			}
+		}
+
+		// Track agent health (skip Mayor and Crew)
+		if health := healthByRole[agent.Role]; health != nil {
+			health.total++
+			// Detect working state via ✻ symbol
+			if r.isSessionWorking(s) {
+				health.working++
+			}
+		}
+
+		// Track deacon presence (just the icon, no count)
+		if agent.Role == session.RoleDeacon {
+			hasDeacon = true
+		}
+	}
+
+	// Get operational state for each rig
+	for rigName, status := range rigStatuses {
+		opState := r.rigState(townRoot, rigName)
+		if opState == "PARKED" || opState == "DOCKED" {
+			status.opState = opState
+		} else {
+			status.opState = "OPERATIONAL"
+		}
+	}
+
+	// Build status
+	var parts []string
+
+	// Add per-agent-type health in consistent order
+	// Format: "1/3 👁️" = 1 working out of 3 total
+	// Only show agent types that have sessions
+	// Note: Polecats excluded - idle state is misleading noise
+	// Deacon gets just an icon (no count) - shown separately below
+	var agentParts []string
+	for _, role := range []session.Role{session.RoleWitness, session.RoleRefinery} {
+		health := healthByRole[role]
+		if health.total == 0 {
+			continue
+		}
+		agentParts = append(agentParts, fmt.Sprintf("%d/%d %s", health.working, health.total, roleIcons[role]))
+	}
+	if len(agentParts) > 0 {
+		parts = append(parts, strings.Join(agentParts, " "))
+	}
+
+	// Add deacon icon if running (just presence, no count)
+	if hasDeacon {
+		parts = append(parts, roleIcons[session.RoleDeacon])
+	}
+
+	// Build rig status display with LED indicators
+	// 🟢 = both witness and refinery running (fully active)
+	// 🟡 = one of witness/refinery running (partially active)
+	// 🅿️ = parked (nothing running, intentionally paused)
+	// 🛑 = docked (nothing running, global shutdown)
+	// ⚫ = operational but nothing running (unexpected state)
+
+	// Create sortable rig list
+	type rigInfo struct {
+		name   string
+		status *rigStatus
+	}
+	var rigs []rigInfo
+	for rigName, status := range rigStatuses {
+		rigs = append(rigs, rigInfo{name: rigName, status: status})
+	}
+
+	// Sort by: 1) running state, 2) operational state, 3) alphabetical
+	sort.Slice(rigs, func(i, j int) bool {
+		isRunningI := rigs[i].status.hasWitness || rigs[i].status.hasRefinery
+		isRunningJ := rigs[j].status.hasWitness || rigs[j].status.hasRefinery
+
+		// Primary sort: running rigs before non-running rigs
+		if isRunningI != isRunningJ {
+			return isRunningI
+		}
+
+		// Secondary sort: operational state (for non-running rigs: OPERATIONAL < PARKED < DOCKED)
+		stateOrder := map[string]int{"OPERATIONAL": 0, "PARKED": 1, "DOCKED": 2}
+		stateI := stateOrder[rigs[i].status.opState]
+		stateJ := stateOrder[rigs[j].status.opState]
+		if stateI != stateJ {
+			return stateI < stateJ
+		}
+
+		// Tertiary sort: alphabetical
+		return rigs[i].name < rigs[j].name
+	})
+
+	// Build display with group separators
+	var rigParts []string
+	var lastGroup string
+	for _, rig := range rigs {
+		isRunning := rig.status.hasWitness || rig.status.hasRefinery
+		var currentGroup string
+		if isRunning {
+			currentGroup = "running"
+		} else {
+			currentGroup = "idle-" + rig.status.opState
+		}
+
+		// Add separator when group changes (running -> non-running, or different opStates within non-running)
+		if lastGroup != "" && lastGroup != currentGroup {
+			rigParts = append(rigParts, "|")
+		}
+		lastGroup = currentGroup
+
+		status := rig.status
+		var led string
+
+		// Check if processes are running first (regardless of operational state)
+		if status.hasWitness && status.hasRefinery {
+			led = "🟢" // Both running - fully active
+		} else if status.hasWitness || status.hasRefinery {
+			led = "🟡" // One running - partially active
+		} else {
+			// Nothing running - show operational state
+			switch status.opState {
+			case "PARKED":
+				led = "🅿️" // Parked - intentionally paused
+			case "DOCKED":
+				led = "🛑" // Docked - global shutdown
+			default:
+				led = "⚫" // Operational but nothing running
+			}
+		}
+
+		// All icons get 1 space, Park gets 2
+		space := " "
+		if led == "🅿️" {
+			space = "  "
+		}
+		rigParts = append(rigParts, led+space+rig.name)
+	}
+
+	if len(rigParts) > 0 {
+		parts = append(parts, strings.Join(rigParts, " "))
+	}
+
+	// Priority 1: Check for hooked work (town beads for mayor)
+	hookedWork := ""
+	if townRoot != "" {
+		hookedWork = r.hookedWork("mayor", 40, townRoot)
+	}
+	if hookedWork != "" {
+		parts = append(parts, fmt.Sprintf("🪝 %s", hookedWork))
+	} else if townRoot != "" {
+		// Priority 2: Fall back to mail preview
+		parts = r.appendMail(parts, "mayor/", 45, townRoot)
+	}
+
+	fmt.Fprint(w, strings.Join(parts, " | ")+" |")
+	return nil
+}
+
+// renderDeacon outputs status for the deacon session.
+// Shows: active rigs, polecat count, hook or mail preview
+func (r *Renderer) renderDeacon(w io.Writer) error {
+	// Count active rigs and polecats
+	sessions, err := r.listSessions()
+	if err != nil {
+		return nil // Silent fail
+	}
+
+	// Get town root from deacon pane's working directory
+	townRoot := r.townRootFor(session.DeaconSessionName())
+	registeredRigs := r.registered(townRoot)
+
+	rigs := make(map[string]bool)
+	for _, s := range sessions {
+		agent, err := session.ParseSessionName(s)
+		if err != nil {
+			continue
+		}
+		// Only count registered rigs
+		if agent.Rig != "" && registeredRigs[agent.Rig] {
+			rigs[agent.Rig] = true
+		}
+	}
+	rigCount := len(rigs)
+
+	// Build status
+	// Note: Polecats excluded - they're ephemeral and idle detection is a GC concern
+	var parts []string
+	parts = append(parts, fmt.Sprintf("%d rigs", rigCount))
+
+	// Priority 1: Check for hooked work (town beads for deacon)
+	hookedWork := ""
+	if townRoot != "" {
+		hookedWork = r.hookedWork("deacon", 35, townRoot)
+	}
+	if hookedWork != "" {
+		parts = append(parts, fmt.Sprintf("🪝 %s", hookedWork))
+	} else if townRoot != "" {
+		// Priority 2: Fall back to mail preview
+		parts = r.appendMail(parts, "deacon/", 40, townRoot)
+	}
+
+	fmt.Fprint(w, strings.Join(parts, " | ")+" |")
+	return nil
+}
+
+// renderWitness outputs status for a witness session.
+// Shows: crew count, hook or mail preview
+// Note: Polecats excluded - they're ephemeral and idle detection is a GC concern
+func (r *Renderer) renderWitness(w io.Writer, sessionName, rigName string) error {
+	if rigName == "" {
+		// Try to extract from session name: gt-<rig>-witness
+		if strings.HasSuffix(sessionName, "-witness") && strings.HasPrefix(sessionName, "gt-") {
+			rigName = strings.TrimPrefix(strings.TrimSuffix(sessionName, "-witness"), "gt-")
+		}
+	}
+
+	// Get town root from witness pane's working directory
+	townRoot := r.townRootFor(session.WitnessSessionName(rigName))
+
+	// Count crew in this rig (crew are persistent, worth tracking)
+	sessions, err := r.listSessions()
+	if err != nil {
+		return nil // Silent fail
+	}
+
+	crewCount := 0
+	for _, s := range sessions {
+		agent, err := session.ParseSessionName(s)
+		if err != nil {
+			continue
+		}
+		if agent.Rig == rigName && agent.Role == session.RoleCrew {
+			crewCount++
+		}
+	}
+
+	identity := fmt.Sprintf("%s/witness", rigName)
+
+	// Build status
+	var parts []string
+	if crewCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d crew", crewCount))
+	}
+
+	// Priority 1: Check for hooked work (rig beads for witness)
+	hookedWork := ""
+	if townRoot != "" && rigName != "" {
+		rigBeadsDir := filepath.Join(townRoot, rigName, "mayor", "rig")
+		hookedWork = r.hookedWork(identity, 30, rigBeadsDir)
+	}
+	if hookedWork != "" {
+		parts = append(parts, fmt.Sprintf("🪝 %s", hookedWork))
+	} else if townRoot != "" {
+		// Priority 2: Fall back to mail preview
+		parts = r.appendMail(parts, identity, 35, townRoot)
+	}
+
+	fmt.Fprint(w, strings.Join(parts, " | ")+" |")
+	return nil
+}
+
+// renderRefinery outputs status for a refinery session.
+// Shows: MQ length, current item, hook or mail preview
+func (r *Renderer) renderRefinery(w io.Writer, sessionName, rigName string) error {
+	if rigName == "" {
+		// Try to extract from session name: gt-<rig>-refinery
+		if strings.HasPrefix(sessionName, "gt-") && strings.HasSuffix(sessionName, "-refinery") {
+			rigName = strings.TrimPrefix(sessionName, "gt-")
+			rigName = strings.TrimSuffix(rigName, "-refinery")
+		}
+	}
+
+	if rigName == "" {
+		fmt.Fprintf(w, "%s ? |", roleIcons[session.RoleRefinery])
+		return nil
+	}
+
+	// Get town root from refinery pane's working directory
+	townRoot := r.townRootFor(session.RefinerySessionName(rigName))
+
+	queue, err := r.queue(townRoot, rigName)
+	if err != nil {
+		// Fallback to simple status if we can't read the queue
+		fmt.Fprintf(w, "%s MQ: ? |", roleIcons[session.RoleRefinery])
+		return nil
+	}
+
+	// Count pending items and find current item
+	pending := 0
+	var currentItem string
+	for _, item := range queue {
+		if item.Position == 0 && item.MR != nil {
+			// Currently processing - show issue ID
+			currentItem = item.MR.IssueID
+		} else {
+			pending++
+		}
+	}
+
+	identity := fmt.Sprintf("%s/refinery", rigName)
+
+	// Build status
+	var parts []string
+	if currentItem != "" {
+		parts = append(parts, fmt.Sprintf("merging %s", currentItem))
+		if pending > 0 {
+			parts = append(parts, fmt.Sprintf("+%d queued", pending))
+		}
+	} else if pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d queued", pending))
+	} else {
+		parts = append(parts, "idle")
+	}
+
+	// Priority 1: Check for hooked work (rig beads for refinery)
+	hookedWork := ""
+	if townRoot != "" {
+		rigBeadsDir := filepath.Join(townRoot, rigName, "mayor", "rig")
+		hookedWork = r.hookedWork(identity, 25, rigBeadsDir)
+	}
+	if hookedWork != "" {
+		parts = append(parts, fmt.Sprintf("🪝 %s", hookedWork))
+	} else if townRoot != "" {
+		// Priority 2: Fall back to mail preview
+		parts = r.appendMail(parts, identity, 30, townRoot)
+	}
+
+	fmt.Fprint(w, strings.Join(parts, " | ")+" |")
+	return nil
+}
+
+// townRootFor returns the renderer's town, or finds it from a session's
+// pane directory.
+func (r *Renderer) townRootFor(sessionName string) string {
+	if r.townRoot != "" {
+		return r.townRoot
+	}
+	paneDir, err := r.tmux.GetPaneWorkDir(sessionName)
+	if err != nil || paneDir == "" {
+		return ""
+	}
+	townRoot, _ := workspace.Find(paneDir)
+	return townRoot
+}
+
+// listSessions returns the tmux session list, listed once per renderer.
+func (r *Renderer) listSessions() ([]string, error) {
+	if !r.listed {
+		r.sessions, r.sessionsErr = r.tmux.ListSessions()
+		r.listed = true
+	}
+	return r.sessions, r.sessionsErr
+}
+
+// registered returns the rigs registered in mayor/rigs.json.
+func (r *Renderer) registered(townRoot string) map[string]bool {
+	if rigs, ok := r.registeredRigs[townRoot]; ok {
+		return rigs
+	}
+	rigs := make(map[string]bool)
+	if townRoot != "" {
+		rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
+		if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
+			for rigName := range rigsConfig.Rigs {
+				rigs[rigName] = true
+			}
+		}
+	}
+	r.registeredRigs[townRoot] = rigs
+	return rigs
+}
+
+// rigState returns a rig's operational state.
+func (r *Renderer) rigState(townRoot, rigName string) string {
+	key := townRoot + "/" + rigName
+	if state, ok := r.rigStates[key]; ok {
+		return state
+	}
+	state, _ := rig.OperationalState(townRoot, rigName)
+	r.rigStates[key] = state
+	return state
+}
+
+// queue returns a rig's merge queue.
+func (r *Renderer) queue(townRoot, rigName string) ([]refinery.QueueItem, error) {
+	if townRoot == "" {
+		return nil, fmt.Errorf("town root unknown")
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	if items, ok := r.queues[rigPath]; ok {
+		return items, r.queueErrs[rigPath]
+	}
+	items, err := refinery.NewManager(&rig.Rig{Name: rigName, Path: rigPath}).Queue()
+	r.queues[rigPath] = items
+	r.queueErrs[rigPath] = err
+	return items, err
+}
+
+// isSessionWorking detects if a Claude Code session is actively working.
+// Returns true if the ✻ symbol is visible in the pane (indicates Claude is processing).
+// Returns false for idle sessions (showing ❯ prompt) or if state cannot be determined.
+func (r *Renderer) isSessionWorking(sessionName string) bool {
+	// Capture last few lines of the pane
+	lines, err := r.tmux.CapturePaneLines(sessionName, 5)
+	if err != nil || len(lines) == 0 {
+		return false
+	}
+
+	// Check all captured lines for the working indicator
+	// ✻ appears in Claude's status line when actively processing
+	for _, line := range lines {
+		if strings.Contains(line, "✻") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendMail adds an unread mail preview for identity to parts, if it has
+// unread mail.
+func (r *Renderer) appendMail(parts []string, identity string, maxLen int, townRoot string) []string {
+	unread, subject := r.mailPreview(identity, maxLen, townRoot)
+	if unread == 0 {
+		return parts
+	}
+	if subject != "" {
+		return append(parts, fmt.Sprintf("\U0001F4EC %s", subject))
+	}
+	return append(parts, fmt.Sprintf("\U0001F4EC %d", unread))
+}
+
+// mailPreview returns the unread count and a truncated subject of the newest
+// unread message. The town's unread mail is loaded once and shared.
+func (r *Renderer) mailPreview(identity string, maxLen int, townRoot string) (int, string) {
+	inbox, ok := r.inboxes[townRoot]
+	if !ok {
+		inbox, _ = mail.LoadUnreadIndex(townRoot)
+		r.inboxes[townRoot] = inbox
+	}
+	if inbox == nil {
+		return 0, ""
+	}
+
+	messages := inbox.Unread(identity)
+	if len(messages) == 0 {
+		return 0, ""
+	}
+
+	// Get first message subject, truncated
+	subject := messages[0].Subject
+	if len(subject) > maxLen {
+		subject = subject[:maxLen-1] + "…"
+	}
+
+	return len(messages), subject
+}
+
+// hookedWork returns a truncated title of the hooked bead for an agent.
+// Returns empty string if nothing is hooked. The hooked beads of each beads
+// directory are listed once and shared by every agent that uses it.
+func (r *Renderer) hookedWork(identity string, maxLen int, beadsDir string) string {
+	hooked, ok := r.hooked[beadsDir]
+	if !ok {
+		hooked, _ = beads.New(beadsDir).List(beads.ListOptions{
+			Status:   beads.StatusHooked,
+			Priority: -1,
+			Limit:    -1,
+		})
+		r.hooked[beadsDir] = hooked
+	}
+
+	for _, bead := range hooked {
+		if bead.Assignee != identity {
+			continue
+		}
+		// Return first hooked bead's ID and title, truncated
+		display := fmt.Sprintf("%s: %s", bead.ID, bead.Title)
+		if len(display) > maxLen {
+			display = display[:maxLen-1] + "…"
+		}
+		return display
+	}
+	return ""
+}
+
+// currentWork returns a truncated title of the first in_progress issue.
+// Uses the pane's working directory to find the beads.
+func (r *Renderer) currentWork(sessionName string, maxLen int) string {
+	// Get the pane's working directory
+	workDir, err := r.tmux.GetPaneWorkDir(sessionName)
+	if err != nil || workDir == "" {
+		return ""
+	}
+
+	// Check if there's a .beads directory
+	beadsDir := filepath.Join(workDir, ".beads")
+	if _, err := os.Stat(beadsDir); os.IsNotExist(err) {
+		return ""
+	}
+
+	// Query beads for in_progress issues
+	b := beads.New(workDir)
+	issues, err := b.List(beads.ListOptions{
+		Status:   "in_progress",
+		Priority: -1,
+	})
+	if err != nil || len(issues) == 0 {
+		return ""
+	}
+
+	// Return first issue's ID and title, truncated
+	issue := issues[0]
+	display := fmt.Sprintf("%s: %s", issue.ID, issue.Title)
+	if len(display) > maxLen {
+		display = display[:maxLen-1] + "…"
+	}
+	return display
+}
@@ -1620,3 +1620,69 @@ func (t *Tmux) SetPaneDiedHook(session, agentID string) error {
 	_, err := t.run("set-hook", "-t", session, "pane-died", hookCmd)
 	return err
 }
+
+// SetSessionClosedHook adds a global session-closed hook that appends the
+// closed session's name to eventsFile. The daemon watches this file so it can
+// react to session death within seconds instead of waiting for a heartbeat.
+// The hook is appended to any the user already has, and is not added twice
+// if it is already set (e.g. after a daemon restart).
+func (t *Tmux) SetSessionClosedHook(eventsFile string) error {
+	// Sanitize inputs to prevent shell injection
+	eventsFile = strings.ReplaceAll(eventsFile, "'", "'\\''")
+
+	indexes, err := t.sessionClosedHookIndexes(eventsFile)
+	if err != nil {
+		return err
+	}
+	if len(indexes) > 0 {
+		return nil
+	}
+
+	// #{hook_session_name} expands to the session that just closed
+	hookCmd := fmt.Sprintf(`run-shell -b "echo 'session-closed #{hook_session_name}' >> '%s'"`, eventsFile)
+
+	_, err = t.run("set-hook", "-ga", "session-closed", hookCmd)
+	return err
+}
+
+// RemoveSessionClosedHook removes the session-closed hook added by
+// SetSessionClosedHook for eventsFile, leaving any other session-closed
+// hooks in place.
+func (t *Tmux) RemoveSessionClosedHook(eventsFile string) error {
+	eventsFile = strings.ReplaceAll(eventsFile, "'", "'\\''")
+
+	indexes, err := t.sessionClosedHookIndexes(eventsFile)
+	if err != nil {
+		return err
+	}
+	for _, idx := range indexes {
+		if _, err := t.run("set-hook", "-gu", fmt.Sprintf("session-closed[%d]", idx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionClosedHookIndexes returns the array indexes of the global
+// session-closed hooks that append to eventsFile (already shell-escaped).
+func (t *Tmux) sessionClosedHookIndexes(eventsFile string) ([]int, error) {
+	out, err := t.run("show-hooks", "-g", "session-closed")
+	if err != nil {
+		return nil, err
+	}
+	target := fmt.Sprintf(">> '%s'", eventsFile)
+
+	var indexes []int
+	for _, line := range strings.Split(out, "\n") {
+		// Lines look like: session-closed[1] run-shell -b "echo ... >> '/path'"
+		name, cmd, ok := strings.Cut(line, " ")
+		if !ok || !strings.Contains(cmd, target) {
+			continue
+		}
+		var idx int
+		if _, err := fmt.Sscanf(name, "session-closed[%d]", &idx); err == nil {
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes, nil
+}
@@ -1084,3 +1084,51 @@ func TestKillPaneProcessesExcluding_FiltersPIDs(t *testing.T) {
 		}
 	}
 }
+
+func TestSessionClosedHook(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	// Hooks live on the server, so keep a session open for the test
+	sessionName := "gt-test-hook-" + t.Name()
+	_ = tm.KillSession(sessionName)
+	if err := tm.NewSession(sessionName, ""); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	userFile := t.TempDir() + "/user-events"
+	ourFile := t.TempDir() + "/gt-events"
+	if err := tm.SetSessionClosedHook(userFile); err != nil {
+		t.Fatalf("SetSessionClosedHook(user): %v", err)
+	}
+	defer func() { _ = tm.RemoveSessionClosedHook(userFile) }()
+
+	// Setting twice adds the hook once, next to the existing one
+	for i := 0; i < 2; i++ {
+		if err := tm.SetSessionClosedHook(ourFile); err != nil {
+			t.Fatalf("SetSessionClosedHook: %v", err)
+		}
+	}
+	for file, want := range map[string]int{userFile: 1, ourFile: 1} {
+		indexes, err := tm.sessionClosedHookIndexes(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(indexes) != want {
+			t.Errorf("hooks for %s = %v, want %d", file, indexes, want)
+		}
+	}
+
+	if err := tm.RemoveSessionClosedHook(ourFile); err != nil {
+		t.Fatalf("RemoveSessionClosedHook: %v", err)
+	}
+	if indexes, _ := tm.sessionClosedHookIndexes(ourFile); len(indexes) != 0 {
+		t.Errorf("hook still set after remove: %v", indexes)
+	}
+	if indexes, _ := tm.sessionClosedHookIndexes(userFile); len(indexes) != 1 {
+		t.Errorf("other hook removed: %v", indexes)
+	}
+}
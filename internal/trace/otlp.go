@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// OTLP-JSON (OpenTelemetry protocol, JSON encoding) structures.
+// Only the subset needed to describe a single trace is modeled; the output
+// can be loaded by tools that accept OTLP trace files (e.g. Jaeger, otel-desktop-viewer).
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpSpanKindInternal is SPAN_KIND_INTERNAL.
+const otlpSpanKindInternal = 1
+
+// WriteOTLP writes the timeline as an OTLP-JSON trace: one root span for the
+// bead covering the whole lifecycle, with a child span per stage.
+func WriteOTLP(w io.Writer, t *Timeline) error {
+	rootID := spanID(t.TraceID, "root")
+	end := t.End
+	if end.Equal(t.Start) {
+		// Zero-length spans render poorly; give single-event traces a width.
+		end = t.Start.Add(time.Second)
+	}
+
+	spans := []otlpSpan{{
+		TraceID:           t.TraceID,
+		SpanID:            rootID,
+		Name:              t.Bead,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: unixNano(t.Start),
+		EndTimeUnixNano:   unixNano(end),
+		Attributes:        []otlpKeyValue{{Key: "gt.bead", Value: otlpValue{StringValue: t.Bead}}},
+	}}
+
+	for i, stage := range t.Stages {
+		stageEnd := stage.Time.Add(stage.Duration)
+		if stage.Duration == 0 {
+			stageEnd = end
+		}
+
+		attrs := []otlpKeyValue{{Key: "gt.actor", Value: otlpValue{StringValue: stage.Actor}}}
+		keys := make([]string, 0, len(stage.Attributes))
+		for k := range stage.Attributes {
+			if k != PayloadKey {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			attrs = append(attrs, otlpKeyValue{Key: "gt." + k, Value: otlpValue{StringValue: stage.Attributes[k]}})
+		}
+
+		spans = append(spans, otlpSpan{
+			TraceID:           t.TraceID,
+			SpanID:            spanID(t.TraceID, strconv.Itoa(i)),
+			ParentSpanID:      rootID,
+			Name:              stage.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: unixNano(stage.Time),
+			EndTimeUnixNano:   unixNano(stageEnd),
+			Attributes:        attrs,
+		})
+	}
+
+	doc := otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{
+			{Key: "service.name", Value: otlpValue{StringValue: "gastown"}},
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "gastown/trace"},
+			Spans: spans,
+		}},
+	}}}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// spanID derives a stable 64-bit span ID, so re-exporting a trace yields
+// the same IDs.
+func spanID(traceID, name string) string {
+	sum := sha256.Sum256([]byte(traceID + "/" + name))
+	return hex.EncodeToString(sum[:8])
+}
+
+// unixNano formats a time as OTLP expects: decimal nanoseconds in a string.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
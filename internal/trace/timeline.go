@@ -0,0 +1,203 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Stage is one step of a bead's lifecycle.
+type Stage struct {
+	// Name is the event type that marks the stage (sling, spawn, hook, ...).
+	Name string `json:"name"`
+
+	// Time is when the stage began.
+	Time time.Time `json:"time"`
+
+	// Duration is how long until the next stage began (zero for the last stage).
+	Duration time.Duration `json:"duration_ns"`
+
+	// Actor is who performed the step.
+	Actor string `json:"actor"`
+
+	// Attributes are the event payload fields, stringified.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Timeline is the reconstructed lifecycle of a bead.
+type Timeline struct {
+	TraceID string    `json:"trace_id"`
+	Bead    string    `json:"bead"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Stages  []Stage   `json:"stages"`
+}
+
+// Duration returns the time from the first to the last stage.
+func (t *Timeline) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// stageOrder ranks the event types that make up a bead's lifecycle.
+// Events are logged with one-second resolution, so the rank orders stages
+// that share a timestamp (e.g. gt sling logs spawn before sling).
+var stageOrder = map[string]int{
+	events.TypeSling:        1,
+	events.TypeSpawn:        2,
+	events.TypeSessionStart: 3,
+	events.TypeHook:         4,
+	events.TypeMRSubmitted:  5,
+	events.TypeDone:         6,
+	events.TypeMergeStarted: 7,
+	events.TypeMerged:       8,
+	events.TypeMergeFailed:  8,
+	events.TypeMergeSkipped: 8,
+	events.TypeConvoyClosed: 9,
+}
+
+// ReadEvents reads all events from the town's events log.
+// Malformed lines are skipped; a missing log yields no events.
+func ReadEvents(townRoot string) ([]events.Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var evs []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		evs = append(evs, ev)
+	}
+	return evs, scanner.Err()
+}
+
+// Build reconstructs a bead's timeline from events. An event belongs to the
+// trace if it carries the trace ID, or (for events logged before tracing or
+// by older binaries) names the bead or one of its merge requests.
+// If traceID is empty it is taken from the most recent sling of the bead.
+// Returns nil if no events match.
+func Build(evs []events.Event, beadID, traceID string) *Timeline {
+	if traceID == "" {
+		for i := len(evs) - 1; i >= 0; i-- {
+			if evs[i].Type == events.TypeSling && payloadString(evs[i], "bead") == beadID {
+				traceID = payloadString(evs[i], PayloadKey)
+				break
+			}
+		}
+	}
+
+	// Merge requests for the bead, so refinery events that only name the MR match.
+	mrs := make(map[string]bool)
+	for _, ev := range evs {
+		if ev.Type == events.TypeMRSubmitted && belongs(ev, beadID, traceID, nil) {
+			if mr := payloadString(ev, "mr"); mr != "" {
+				mrs[mr] = true
+			}
+		}
+	}
+
+	var stages []Stage
+	for _, ev := range evs {
+		if stageOrder[ev.Type] == 0 || !belongs(ev, beadID, traceID, mrs) {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+		if err != nil {
+			continue
+		}
+		stages = append(stages, Stage{
+			Name:       ev.Type,
+			Time:       ts,
+			Actor:      ev.Actor,
+			Attributes: stringifyPayload(ev.Payload),
+		})
+	}
+	if len(stages) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(stages, func(i, j int) bool {
+		if !stages[i].Time.Equal(stages[j].Time) {
+			return stages[i].Time.Before(stages[j].Time)
+		}
+		return stageOrder[stages[i].Name] < stageOrder[stages[j].Name]
+	})
+	for i := 0; i+1 < len(stages); i++ {
+		stages[i].Duration = stages[i+1].Time.Sub(stages[i].Time)
+	}
+
+	if traceID == "" {
+		traceID = derivedID(beadID)
+	}
+
+	return &Timeline{
+		TraceID: traceID,
+		Bead:    beadID,
+		Start:   stages[0].Time,
+		End:     stages[len(stages)-1].Time,
+		Stages:  stages,
+	}
+}
+
+// belongs reports whether an event is part of the bead's trace.
+func belongs(ev events.Event, beadID, traceID string, mrs map[string]bool) bool {
+	if id := payloadString(ev, PayloadKey); id != "" {
+		return id == traceID
+	}
+	if payloadString(ev, "bead") == beadID {
+		return true
+	}
+	if mr := payloadString(ev, "mr"); mr != "" && mrs[mr] {
+		return true
+	}
+	if ev.Type == events.TypeConvoyClosed {
+		if tracked, ok := ev.Payload["tracked"].([]interface{}); ok {
+			for _, t := range tracked {
+				if s, ok := t.(string); ok && s == beadID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func payloadString(ev events.Event, key string) string {
+	s, _ := ev.Payload[key].(string)
+	return s
+}
+
+// stringifyPayload flattens an event payload into string attributes.
+func stringifyPayload(payload map[string]interface{}) map[string]string {
+	if len(payload) == 0 {
+		return nil
+	}
+	attrs := make(map[string]string, len(payload))
+	for k, v := range payload {
+		switch val := v.(type) {
+		case string:
+			attrs[k] = val
+		default:
+			data, err := json.Marshal(val)
+			if err != nil {
+				continue
+			}
+			attrs[k] = string(data)
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,60 @@
+// Package trace follows a bead through its lifecycle:
+// sling → polecat spawn → hook → MR submit → done → refinery merge → convoy close.
+//
+// A trace ID is assigned when a bead is slung and is carried along by the
+// bead's attachment fields, the polecat's GT_TRACE_ID environment variable,
+// MR bead fields and protocol messages. Each step records it in the payload
+// of its activity event, so a timeline can be rebuilt from .events.jsonl
+// alone and exported as OTLP-JSON for viewing in tracing tools.
+package trace
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EnvVar is the environment variable that carries the trace ID into agent sessions.
+const EnvVar = "GT_TRACE_ID"
+
+// PayloadKey is the event payload key holding the trace ID.
+const PayloadKey = "trace_id"
+
+// NewID returns a new random 128-bit trace ID as 32 lowercase hex characters,
+// the W3C Trace Context / OTLP format.
+// Falls back to a time-based ID if crypto/rand fails (extremely rare).
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// FromEnv returns the trace ID from the current environment, if any.
+func FromEnv() string {
+	return os.Getenv(EnvVar)
+}
+
+// Annotate adds the trace ID to an event payload and returns it.
+// Empty trace IDs leave the payload unchanged.
+func Annotate(payload map[string]interface{}, traceID string) map[string]interface{} {
+	if traceID == "" {
+		return payload
+	}
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	payload[PayloadKey] = traceID
+	return payload
+}
+
+// derivedID returns a stable trace ID for a bead slung before tracing existed,
+// so its timeline can still be exported.
+func derivedID(beadID string) string {
+	sum := sha256.Sum256([]byte("gastown-trace:" + beadID))
+	return hex.EncodeToString(sum[:16])
+}
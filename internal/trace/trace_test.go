@@ -0,0 +1,142 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestNewID(t *testing.T) {
+	id := NewID()
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(id) {
+		t.Errorf("NewID() = %q, want 32 hex chars", id)
+	}
+	if NewID() == id {
+		t.Error("NewID() returned the same ID twice")
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	p := Annotate(events.HookPayload("gt-abc"), "t1")
+	if p[PayloadKey] != "t1" || p["bead"] != "gt-abc" {
+		t.Errorf("Annotate() = %v", p)
+	}
+	p = Annotate(events.HookPayload("gt-abc"), "")
+	if _, ok := p[PayloadKey]; ok {
+		t.Error("Annotate() with empty ID should not add trace_id")
+	}
+}
+
+func ev(ts, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{Timestamp: ts, Type: typ, Actor: actor, Payload: payload}
+}
+
+func lifecycleEvents() []events.Event {
+	const id = "4bf92f3577b34da6a3ce929d0e0e4736"
+	return []events.Event{
+		// Earlier attempt, superseded by a re-sling
+		ev("2026-01-02T09:00:00Z", events.TypeSling, "mayor", Annotate(events.SlingPayload("gt-abc", "gastown/polecats/Nux"), "stale")),
+		// gt sling logs spawn before sling, in the same second
+		ev("2026-01-02T10:00:00Z", events.TypeSpawn, "gt", Annotate(events.SpawnPayload("gastown", "Toast"), id)),
+		ev("2026-01-02T10:00:00Z", events.TypeSling, "mayor", Annotate(events.SlingPayload("gt-abc", "gastown/polecats/Toast"), id)),
+		ev("2026-01-02T10:00:05Z", events.TypeSessionStart, "gastown/polecats/Toast", Annotate(events.SessionPayload("s1", "gastown/polecats/Toast", "", "/tmp"), id)),
+		ev("2026-01-02T10:00:30Z", events.TypeHook, "gastown/polecats/Toast", Annotate(events.HookPayload("gt-abc"), id)),
+		ev("2026-01-02T10:05:00Z", events.TypeSling, "mayor", Annotate(events.SlingPayload("gt-other", "gastown/polecats/Nux"), "other")),
+		ev("2026-01-02T10:20:00Z", events.TypeMRSubmitted, "gastown/polecats/Toast", Annotate(events.MRSubmittedPayload("gt-mr1", "gt-abc", "polecat/Toast/gt-abc", "main"), id)),
+		ev("2026-01-02T10:20:01Z", events.TypeDone, "gastown/polecats/Toast", Annotate(events.DonePayload("gt-abc", "polecat/Toast/gt-abc"), id)),
+		// Refinery event from an older binary: no trace_id, only the MR
+		ev("2026-01-02T10:30:00Z", events.TypeMerged, "gastown/refinery", events.MergePayload("gt-mr1", "Toast", "polecat/Toast/gt-abc", "")),
+		ev("2026-01-02T10:31:00Z", events.TypeConvoyClosed, "deacon", map[string]interface{}{
+			"convoy": "hq-cv1", "tracked": []interface{}{"gt-abc", "gt-def"},
+		}),
+		ev("2026-01-02T10:32:00Z", events.TypeMail, "mayor", Annotate(events.MailPayload("x", "y"), id)),
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tl := Build(lifecycleEvents(), "gt-abc", "")
+	if tl == nil {
+		t.Fatal("Build() = nil")
+	}
+	if tl.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %q", tl.TraceID)
+	}
+
+	var names []string
+	for _, s := range tl.Stages {
+		names = append(names, s.Name)
+	}
+	want := []string{"sling", "spawn", "session_start", "hook", "mr_submitted", "done", "merged", "convoy_closed"}
+	if len(names) != len(want) {
+		t.Fatalf("stages = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("stages = %v, want %v", names, want)
+		}
+	}
+
+	if tl.Stages[1].Duration != 5*time.Second {
+		t.Errorf("spawn duration = %v, want 5s", tl.Stages[1].Duration)
+	}
+	if tl.Stages[len(tl.Stages)-1].Duration != 0 {
+		t.Errorf("last stage duration = %v, want 0", tl.Stages[len(tl.Stages)-1].Duration)
+	}
+	if tl.Duration() != 31*time.Minute {
+		t.Errorf("Duration() = %v, want 31m", tl.Duration())
+	}
+}
+
+func TestBuild_NoMatch(t *testing.T) {
+	if tl := Build(lifecycleEvents(), "gt-missing", ""); tl != nil {
+		t.Errorf("Build() = %+v, want nil", tl)
+	}
+}
+
+func TestBuild_UntracedBeadGetsDerivedID(t *testing.T) {
+	evs := []events.Event{
+		ev("2026-01-02T10:00:00Z", events.TypeSling, "mayor", events.SlingPayload("gt-old", "gastown/polecats/Toast")),
+	}
+	tl := Build(evs, "gt-old", "")
+	if tl == nil {
+		t.Fatal("Build() = nil")
+	}
+	if tl.TraceID != derivedID("gt-old") {
+		t.Errorf("TraceID = %q, want derived ID", tl.TraceID)
+	}
+}
+
+func TestWriteOTLP(t *testing.T) {
+	tl := Build(lifecycleEvents(), "gt-abc", "")
+
+	var buf bytes.Buffer
+	if err := WriteOTLP(&buf, tl); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc otlpTraces
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	spans := doc.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != len(tl.Stages)+1 {
+		t.Fatalf("got %d spans, want %d", len(spans), len(tl.Stages)+1)
+	}
+
+	root := spans[0]
+	if root.TraceID != tl.TraceID || root.ParentSpanID != "" || len(root.SpanID) != 16 {
+		t.Errorf("bad root span: %+v", root)
+	}
+	for _, s := range spans[1:] {
+		if s.ParentSpanID != root.SpanID {
+			t.Errorf("span %s parent = %q, want root", s.Name, s.ParentSpanID)
+		}
+	}
+	if spans[1].StartTimeUnixNano != "1767348000000000000" {
+		t.Errorf("sling start = %s", spans[1].StartTimeUnixNano)
+	}
+}
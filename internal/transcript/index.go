@@ -0,0 +1,480 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// indexVersion is bumped when the on-disk format or tokenization changes;
+// an index with another version is discarded and rebuilt.
+const indexVersion = 1
+
+// indexLockTimeout bounds how long an update waits for a concurrent one.
+const indexLockTimeout = 30 * time.Second
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// snippetWidth is the number of characters shown around a match.
+const snippetWidth = 160
+
+// IndexDir returns the directory holding a town's transcript index.
+func IndexDir(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "cache", "seance")
+}
+
+// Posting records how often a term occurs in a document.
+type Posting struct {
+	Doc  int `json:"d"`
+	Freq int `json:"f"`
+}
+
+// document is an indexed transcript file. Files outside the town are kept
+// with a nil Session so they aren't re-parsed on every update.
+type document struct {
+	Path    string   `json:"path"`
+	Size    int64    `json:"size"`
+	ModTime int64    `json:"mtime"`
+	Length  int      `json:"len"`
+	Session *Session `json:"session,omitempty"`
+}
+
+// Index is an inverted index over session transcripts.
+type Index struct {
+	Version int                  `json:"version"`
+	Docs    []*document          `json:"docs"` // nil entries are free slots
+	Terms   map[string][]Posting `json:"terms"`
+
+	path   string
+	byPath map[string]int
+}
+
+// UpdateStats summarizes an index update.
+type UpdateStats struct {
+	Indexed  int // Transcripts (re)indexed
+	Removed  int // Transcripts whose files disappeared
+	Sessions int // Town sessions in the index afterwards
+}
+
+// Query is a search request. Text is required; the other fields filter.
+type Query struct {
+	Text  string
+	Role  string
+	Rig   string
+	Agent string // Substring of the agent address
+	Bead  string
+	Since time.Time
+	Limit int
+}
+
+// Snippet is an excerpt of a message matching a query.
+type Snippet struct {
+	Time    time.Time `json:"time,omitempty"`
+	Speaker string    `json:"speaker,omitempty"`
+	Text    string    `json:"text"`
+}
+
+// Hit is a session matching a query.
+type Hit struct {
+	Session  *Session  `json:"session"`
+	Score    float64   `json:"score"`
+	Snippets []Snippet `json:"snippets,omitempty"`
+}
+
+// Open loads the index in dir, or returns an empty one if there is none.
+func Open(dir string) (*Index, error) {
+	ix := &Index{
+		Version: indexVersion,
+		Terms:   make(map[string][]Posting),
+		path:    filepath.Join(dir, "index.json"),
+	}
+	data, err := os.ReadFile(ix.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			ix.reindexPaths()
+			return ix, nil
+		}
+		return nil, err
+	}
+
+	var loaded Index
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != indexVersion {
+		// Corrupt or outdated: start over
+		ix.reindexPaths()
+		return ix, nil
+	}
+	ix.Docs = loaded.Docs
+	if loaded.Terms != nil {
+		ix.Terms = loaded.Terms
+	}
+	ix.reindexPaths()
+	return ix, nil
+}
+
+// Save writes the index to disk.
+func (ix *Index) Save() error {
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return fmt.Errorf("creating index directory: %w", err)
+	}
+	// Compact encoding: the index can hold millions of postings
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(ix.path, data, 0644)
+}
+
+func (ix *Index) reindexPaths() {
+	ix.byPath = make(map[string]int, len(ix.Docs))
+	for i, d := range ix.Docs {
+		if d != nil {
+			ix.byPath[d.Path] = i
+		}
+	}
+}
+
+// Refresh brings a town's index up to date with its transcript sources and
+// saves it. Concurrent refreshes are serialized with a file lock.
+func Refresh(townRoot string) (*Index, UpdateStats, error) {
+	dir := IndexDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, UpdateStats{}, fmt.Errorf("creating index directory: %w", err)
+	}
+
+	lock := flock.New(filepath.Join(dir, "index.lock"))
+	ctx, cancel := context.WithTimeout(context.Background(), indexLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil {
+		return nil, UpdateStats{}, fmt.Errorf("acquiring index lock: %w", err)
+	}
+	if !locked {
+		return nil, UpdateStats{}, fmt.Errorf("timeout waiting for transcript index lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	ix, err := Open(dir)
+	if err != nil {
+		return nil, UpdateStats{}, err
+	}
+	stats := ix.Update(townRoot, Sources(townRoot))
+	if stats.Indexed > 0 || stats.Removed > 0 {
+		if err := ix.Save(); err != nil {
+			return nil, stats, fmt.Errorf("saving index: %w", err)
+		}
+	}
+	return ix, stats, nil
+}
+
+// Update indexes new and changed transcripts from sources and drops those
+// whose files are gone. Only sessions that ran inside townRoot are searchable.
+func (ix *Index) Update(townRoot string, sources []Source) UpdateStats {
+	var stats UpdateStats
+	seen := make(map[string]bool)
+	stale := make(map[int]bool)
+	type parsed struct {
+		slot  int
+		terms map[string]int
+	}
+	var added []parsed
+
+	for _, src := range sources {
+		for _, path := range src.Files() {
+			seen[path] = true
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			slot, known := ix.byPath[path]
+			if known {
+				d := ix.Docs[slot]
+				if d.Size == info.Size() && d.ModTime == info.ModTime().UnixNano() {
+					continue
+				}
+				stale[slot] = true
+			} else {
+				slot = ix.freeSlot()
+				ix.byPath[path] = slot
+			}
+
+			doc := &document{Path: path, Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+			ix.Docs[slot] = doc
+			stats.Indexed++
+
+			s, msgs, err := Parse(path, src.Preset, townRoot)
+			if err != nil || !s.InTown(townRoot) {
+				continue
+			}
+			doc.Session = s
+			terms := make(map[string]int)
+			for _, m := range msgs {
+				for _, term := range Tokenize(m.Text) {
+					terms[term]++
+					doc.Length++
+				}
+			}
+			added = append(added, parsed{slot: slot, terms: terms})
+		}
+	}
+
+	for path, slot := range ix.byPath {
+		if !seen[path] {
+			stale[slot] = true
+			ix.Docs[slot] = nil
+			delete(ix.byPath, path)
+			stats.Removed++
+		}
+	}
+
+	// Drop postings of changed and removed documents in one pass
+	if len(stale) > 0 {
+		for term, postings := range ix.Terms {
+			kept := postings[:0]
+			for _, p := range postings {
+				if !stale[p.Doc] {
+					kept = append(kept, p)
+				}
+			}
+			if len(kept) == 0 {
+				delete(ix.Terms, term)
+			} else {
+				ix.Terms[term] = kept
+			}
+		}
+	}
+	for _, a := range added {
+		for term, freq := range a.terms {
+			ix.Terms[term] = append(ix.Terms[term], Posting{Doc: a.slot, Freq: freq})
+		}
+	}
+
+	for _, d := range ix.Docs {
+		if d != nil && d.Session != nil {
+			stats.Sessions++
+		}
+	}
+	return stats
+}
+
+// freeSlot returns a free document slot, growing Docs if needed. Slots of
+// documents removed by earlier updates are reused; their postings are gone.
+func (ix *Index) freeSlot() int {
+	for i, d := range ix.Docs {
+		if d == nil {
+			return i
+		}
+	}
+	ix.Docs = append(ix.Docs, nil)
+	return len(ix.Docs) - 1
+}
+
+// Search returns the sessions containing every term of q.Text, ranked by
+// BM25 with the most recent session first among equal scores. Each hit
+// carries snippets of its best matching messages.
+func (ix *Index) Search(q Query) []Hit {
+	terms := uniqueTerms(Tokenize(q.Text))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	live, totalLen := 0, 0
+	for _, d := range ix.Docs {
+		if d != nil && d.Session != nil {
+			live++
+			totalLen += d.Length
+		}
+	}
+	if live == 0 {
+		return nil
+	}
+	avgLen := float64(totalLen) / float64(live)
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for _, term := range terms {
+		postings := ix.Terms[term]
+		if len(postings) == 0 {
+			return nil // Every term must match
+		}
+		idf := math.Log(1 + (float64(live)-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+		for _, p := range postings {
+			d := ix.Docs[p.Doc]
+			if d == nil || d.Session == nil || !q.matches(d.Session) {
+				continue
+			}
+			tf := float64(p.Freq)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(d.Length)/avgLen)
+			scores[p.Doc] += idf * tf * (bm25K1 + 1) / (tf + norm)
+			matched[p.Doc]++
+		}
+	}
+
+	var hits []Hit
+	for slot, score := range scores {
+		if matched[slot] == len(terms) {
+			hits = append(hits, Hit{Session: ix.Docs[slot].Session, Score: score})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Session.End.After(hits[j].Session.End)
+	})
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	for i := range hits {
+		hits[i].Snippets = Snippets(hits[i].Session, terms, 2)
+	}
+	return hits
+}
+
+func (q Query) matches(s *Session) bool {
+	if q.Role != "" && !strings.EqualFold(s.Role, q.Role) {
+		return false
+	}
+	if q.Rig != "" && !strings.EqualFold(s.Rig, q.Rig) {
+		return false
+	}
+	if q.Agent != "" && !strings.Contains(strings.ToLower(s.Agent), strings.ToLower(q.Agent)) {
+		return false
+	}
+	if q.Bead != "" && s.Bead != q.Bead {
+		return false
+	}
+	if !q.Since.IsZero() && s.End.Before(q.Since) {
+		return false
+	}
+	return true
+}
+
+// Snippets returns excerpts of the messages in a session's transcript that
+// match the most terms, in transcript order.
+func Snippets(s *Session, terms []string, max int) []Snippet {
+	_, msgs, err := Parse(s.Path, s.Preset, "")
+	if err != nil {
+		return nil
+	}
+
+	type candidate struct {
+		index, matches int
+	}
+	var candidates []candidate
+	for i, m := range msgs {
+		present := make(map[string]bool)
+		for _, t := range Tokenize(m.Text) {
+			present[t] = true
+		}
+		n := 0
+		for _, t := range terms {
+			if present[t] {
+				n++
+			}
+		}
+		if n > 0 {
+			candidates = append(candidates, candidate{i, n})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].matches > candidates[j].matches })
+	if len(candidates) > max {
+		candidates = candidates[:max]
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].index < candidates[j].index })
+
+	snippets := make([]Snippet, 0, len(candidates))
+	for _, c := range candidates {
+		m := msgs[c.index]
+		snippets = append(snippets, Snippet{Time: m.Time, Speaker: m.Speaker, Text: excerpt(m.Text, terms)})
+	}
+	return snippets
+}
+
+// excerpt returns about snippetWidth characters of text around the first
+// occurrence of any term, with whitespace collapsed.
+func excerpt(text string, terms []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := []rune(strings.ToLower(string(runes)))
+
+	at := 0
+	if len(lower) == len(runes) {
+		first := -1
+		for _, t := range terms {
+			if i := indexRunes(lower, []rune(t)); i >= 0 && (first < 0 || i < first) {
+				first = i
+			}
+		}
+		if first > 0 {
+			at = first
+		}
+	}
+
+	start := max(0, at-snippetWidth/3)
+	end := min(len(runes), start+snippetWidth)
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+func indexRunes(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// Tokenize splits text into lowercase index terms: runs of letters, digits
+// and underscores, at least two characters long. "payment.go" yields
+// "payment" and "go".
+func Tokenize(text string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		if len(word) >= 2 && len(word) <= 64 {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIndexSearch(t *testing.T) {
+	town := t.TempDir()
+	src := Source{Preset: "claude", Dir: t.TempDir()}
+	toast := filepath.Join(town, "gastown", "polecats", "Toast")
+	crewDir := filepath.Join(town, "gastown", "crew", "max")
+
+	writeTranscript(t, filepath.Join(src.Dir, "p1"), "a.jsonl",
+		claudeLine("sess-a", toast, "assistant", "2026-01-02T10:00:00Z", "I changed payment.go to retry on timeout"),
+		claudeLine("sess-a", toast, "assistant", "2026-01-02T10:01:00Z", "payment retry is now exponential"))
+	writeTranscript(t, filepath.Join(src.Dir, "p2"), "b.jsonl",
+		claudeLine("sess-b", crewDir, "user", "2026-01-03T10:00:00Z", "look at payment.go later"),
+		claudeLine("sess-b", crewDir, "assistant", "2026-01-03T10:01:00Z", "sure, I will get to it after the docs cleanup is done"))
+	writeTranscript(t, filepath.Join(src.Dir, "p3"), "c.jsonl",
+		claudeLine("sess-c", "/somewhere/else", "user", "2026-01-03T10:00:00Z", "payment.go outside the town"))
+
+	ix, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	stats := ix.Update(town, []Source{src})
+	if stats.Indexed != 3 || stats.Sessions != 2 {
+		t.Errorf("stats = %+v, want 3 indexed, 2 sessions", stats)
+	}
+
+	hits := ix.Search(Query{Text: "payment.go"})
+	if len(hits) != 2 {
+		t.Fatalf("Search(payment.go) = %d hits, want 2", len(hits))
+	}
+	if hits[0].Session.ID != "sess-a" {
+		t.Errorf("top hit = %s, want sess-a (more mentions)", hits[0].Session.ID)
+	}
+	if len(hits[0].Snippets) == 0 {
+		t.Error("top hit has no snippets")
+	}
+
+	if hits := ix.Search(Query{Text: "payment exponential"}); len(hits) != 1 || hits[0].Session.ID != "sess-a" {
+		t.Errorf("Search requiring all terms = %+v, want only sess-a", hits)
+	}
+	if hits := ix.Search(Query{Text: "payment", Role: "crew"}); len(hits) != 1 || hits[0].Session.ID != "sess-b" {
+		t.Errorf("Search with role filter = %+v, want only sess-b", hits)
+	}
+	if hits := ix.Search(Query{Text: "payment", Since: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}); len(hits) != 1 {
+		t.Errorf("Search with since filter = %d hits, want 1", len(hits))
+	}
+	if hits := ix.Search(Query{Text: "nonexistent"}); len(hits) != 0 {
+		t.Errorf("Search(nonexistent) = %d hits, want 0", len(hits))
+	}
+}
+
+func TestIndexIncrementalUpdate(t *testing.T) {
+	town := t.TempDir()
+	src := Source{Preset: "claude", Dir: t.TempDir()}
+	cwd := filepath.Join(town, "gastown", "polecats", "Toast")
+	path := writeTranscript(t, src.Dir, "a.jsonl",
+		claudeLine("sess-a", cwd, "user", "2026-01-02T10:00:00Z", "alpha"))
+
+	dir := t.TempDir()
+	ix, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ix.Update(town, []Source{src})
+	if err := ix.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Reloaded index skips unchanged files
+	ix, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := ix.Update(town, []Source{src}); stats.Indexed != 0 {
+		t.Errorf("unchanged update indexed %d, want 0", stats.Indexed)
+	}
+	if len(ix.Search(Query{Text: "alpha"})) != 1 {
+		t.Error("reloaded index lost alpha")
+	}
+
+	// Rewritten transcript replaces its postings
+	writeTranscript(t, src.Dir, "a.jsonl",
+		claudeLine("sess-a", cwd, "user", "2026-01-02T10:00:00Z", "beta gamma"))
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(path, future, future)
+	if stats := ix.Update(town, []Source{src}); stats.Indexed != 1 {
+		t.Errorf("changed update indexed %d, want 1", stats.Indexed)
+	}
+	if len(ix.Search(Query{Text: "alpha"})) != 0 || len(ix.Search(Query{Text: "beta"})) != 1 {
+		t.Error("changed transcript not reindexed")
+	}
+
+	// Deleted transcript is dropped
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if stats := ix.Update(town, []Source{src}); stats.Removed != 1 || stats.Sessions != 0 {
+		t.Errorf("stats after delete = %+v, want 1 removed, 0 sessions", stats)
+	}
+	if len(ix.Search(Query{Text: "beta"})) != 0 {
+		t.Error("deleted transcript still searchable")
+	}
+}
@@ -0,0 +1,91 @@
+package transcript
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Source is a directory of transcripts written by one agent preset.
+type Source struct {
+	Preset string
+	Dir    string
+}
+
+// Sources returns the transcript directories to index for a town: the
+// TranscriptDir of every known agent preset, plus the projects directory of
+// each Claude account in mayor/accounts.json (accounts keep their sessions
+// under their own CLAUDE_CONFIG_DIR).
+func Sources(townRoot string) []Source {
+	var sources []Source
+	seen := make(map[string]bool)
+	add := func(preset, dir string) {
+		dir = expandHome(dir)
+		if dir == "" || seen[dir] {
+			return
+		}
+		seen[dir] = true
+		sources = append(sources, Source{Preset: preset, Dir: dir})
+	}
+
+	presets := config.ListAgentPresets()
+	sort.Strings(presets)
+	for _, name := range presets {
+		if info := config.GetAgentPresetByName(name); info != nil && info.TranscriptDir != "" {
+			add(name, info.TranscriptDir)
+		}
+	}
+
+	if cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot)); err == nil {
+		handles := make([]string, 0, len(cfg.Accounts))
+		for handle := range cfg.Accounts {
+			handles = append(handles, handle)
+		}
+		sort.Strings(handles)
+		for _, handle := range handles {
+			if dir := cfg.Accounts[handle].ConfigDir; dir != "" {
+				add(string(config.AgentClaude), filepath.Join(expandHome(dir), "projects"))
+			}
+		}
+	}
+	return sources
+}
+
+// Files lists the transcript files under a source directory.
+func (s Source) Files() []string {
+	var files []string
+	_ = filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if d.IsDir() || !isTranscriptFile(d.Name()) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files
+}
+
+func isTranscriptFile(name string) bool {
+	if name == "sessions-index.json" {
+		return false // Claude Code's session picker index
+	}
+	return strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".json")
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
@@ -0,0 +1,338 @@
+// Package transcript indexes agent session transcripts for full-text search.
+//
+// Transcripts are the JSONL (or JSON) logs agent CLIs write for each session:
+// Claude Code under ~/.claude/projects/, Codex under ~/.codex/sessions/, and
+// so on. Each is parsed into messages, attributed to the Gas Town agent that
+// ran it (from the session's working directory and startup beacon), and
+// added to an on-disk inverted index that gt seance search queries.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// maxMessageText caps the text kept from a single message. Tool results can
+// contain whole files; the head is enough to find them.
+const maxMessageText = 4096
+
+// Session describes an indexed transcript.
+type Session struct {
+	// ID is the agent CLI's session ID, used with gt seance --talk.
+	ID string `json:"id"`
+
+	// Path is the transcript file.
+	Path string `json:"path"`
+
+	// Preset is the agent preset that wrote the transcript (claude, codex, ...).
+	Preset string `json:"preset"`
+
+	// Cwd is the session's working directory.
+	Cwd string `json:"cwd,omitempty"`
+
+	// Agent is the Gas Town address of the agent (e.g. gastown/polecats/Toast).
+	Agent string `json:"agent,omitempty"`
+
+	// Role and Rig are derived from Agent.
+	Role string `json:"role,omitempty"`
+	Rig  string `json:"rig,omitempty"`
+
+	// Bead is the work the session was started on, from its startup beacon.
+	Bead string `json:"bead,omitempty"`
+
+	// Start and End are the first and last message timestamps.
+	Start time.Time `json:"start,omitempty"`
+	End   time.Time `json:"end,omitempty"`
+}
+
+// Message is one entry of a transcript.
+type Message struct {
+	Time    time.Time
+	Speaker string // user, assistant, or empty if unknown
+	Text    string
+}
+
+// beaconPattern matches a startup beacon:
+// [GAS TOWN] <recipient> <- <sender> • <timestamp> • <topic[:mol-id]>
+var beaconPattern = regexp.MustCompile(`\[GAS TOWN\] (\S+) <- \S+ • \S+ • (\S+)`)
+
+// skipKeys are JSON keys whose values are metadata, not conversation text.
+var skipKeys = map[string]bool{
+	"uuid": true, "parentUuid": true, "sessionId": true, "session_id": true,
+	"id": true, "tool_use_id": true, "requestId": true, "signature": true,
+	"type": true, "role": true, "model": true, "timestamp": true, "cwd": true,
+	"version": true, "gitBranch": true, "userType": true, "stop_reason": true,
+	"usage": true, "isSidechain": true, "leafUuid": true,
+}
+
+// Parse reads a transcript file into session metadata and messages.
+// townRoot is used to attribute the session to an agent by its working
+// directory. JSONL files are read line by line; JSON files (e.g. Gemini
+// chats) are read whole, with each element of a "messages" array taken as
+// a message.
+func Parse(path, preset, townRoot string) (*Session, []Message, error) {
+	s := &Session{Path: path, Preset: preset}
+
+	var msgs []Message
+	var err error
+	if strings.HasSuffix(path, ".json") {
+		msgs, err = parseJSON(path, s)
+	} else {
+		msgs, err = parseJSONL(path, s)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.ID == "" {
+		s.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for _, m := range msgs {
+		if m.Time.IsZero() {
+			continue
+		}
+		if s.Start.IsZero() || m.Time.Before(s.Start) {
+			s.Start = m.Time
+		}
+		if m.Time.After(s.End) {
+			s.End = m.Time
+		}
+	}
+	attribute(s, msgs, townRoot)
+	return s, msgs, nil
+}
+
+func parseJSONL(path string, s *Session) ([]Message, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a discovered transcript
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var msgs []Message
+	scanner := bufio.NewScanner(f)
+	// Transcript lines carry whole tool results
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip malformed lines
+		}
+		if m, ok := parseEntry(entry, s); ok {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, scanner.Err()
+}
+
+func parseJSON(path string, s *Session) ([]Message, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a discovered transcript
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	readMeta(doc, s)
+	items, _ := doc["messages"].([]interface{})
+	if items == nil {
+		if m, ok := parseEntry(doc, s); ok {
+			return []Message{m}, nil
+		}
+		return nil, nil
+	}
+	var msgs []Message
+	for _, item := range items {
+		if entry, ok := item.(map[string]interface{}); ok {
+			if m, ok := parseEntry(entry, s); ok {
+				msgs = append(msgs, m)
+			}
+		}
+	}
+	return msgs, nil
+}
+
+// parseEntry turns one transcript entry into a message, recording any
+// session metadata it carries. Claude Code entries hold the conversation
+// under "message", Codex entries under "payload".
+func parseEntry(entry map[string]interface{}, s *Session) (Message, bool) {
+	readMeta(entry, s)
+
+	var m Message
+	if ts, ok := entry["timestamp"].(string); ok {
+		m.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+
+	body := entry
+	if inner, ok := entry["message"].(map[string]interface{}); ok {
+		body = inner
+	} else if inner, ok := entry["payload"].(map[string]interface{}); ok {
+		body = inner
+	}
+
+	m.Speaker = speaker(entry)
+	if m.Speaker == "" {
+		m.Speaker = speaker(body)
+	}
+
+	var b strings.Builder
+	collectText(body, &b)
+	m.Text = strings.TrimSpace(b.String())
+	if len(m.Text) > maxMessageText {
+		m.Text = m.Text[:maxMessageText]
+	}
+	return m, m.Text != ""
+}
+
+// readMeta records the session ID and working directory from an entry.
+func readMeta(entry map[string]interface{}, s *Session) {
+	if s.ID == "" {
+		s.ID = firstString(entry, "sessionId", "session_id")
+	}
+	if s.Cwd == "" {
+		s.Cwd = firstString(entry, "cwd")
+	}
+	// Codex: {"type":"session_meta","payload":{"id":...,"cwd":...}}
+	if t, _ := entry["type"].(string); t == "session_meta" {
+		if payload, ok := entry["payload"].(map[string]interface{}); ok {
+			if s.ID == "" {
+				s.ID = firstString(payload, "id", "session_id")
+			}
+			if s.Cwd == "" {
+				s.Cwd = firstString(payload, "cwd")
+			}
+		}
+	}
+}
+
+func speaker(entry map[string]interface{}) string {
+	for _, key := range []string{"role", "type"} {
+		switch v, _ := entry[key].(string); v {
+		case "user", "assistant":
+			return v
+		case "model", "gemini":
+			return "assistant"
+		}
+	}
+	return ""
+}
+
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := m[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// collectText appends every conversational string in v to b: message text,
+// tool inputs (commands, file paths) and tool results.
+func collectText(v interface{}, b *strings.Builder) {
+	switch v := v.(type) {
+	case string:
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(v)
+	case []interface{}:
+		for _, item := range v {
+			collectText(item, b)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if !skipKeys[key] {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			collectText(v[key], b)
+		}
+	}
+}
+
+// attribute fills in the agent, role, rig and bead of a session. The
+// startup beacon names the agent and its work directly; the working
+// directory is the fallback for sessions started without one.
+func attribute(s *Session, msgs []Message, townRoot string) {
+	var id *session.AgentIdentity
+	for _, m := range msgs {
+		match := beaconPattern.FindStringSubmatch(m.Text)
+		if match == nil {
+			continue
+		}
+		id, _ = session.ParseAddress(match[1])
+		if _, bead, ok := strings.Cut(match[2], ":"); ok {
+			s.Bead = bead
+		}
+		break
+	}
+	if id == nil {
+		id = identityFromPath(townRoot, s.Cwd)
+	}
+	if id == nil {
+		return
+	}
+	s.Agent = id.Address()
+	s.Role = string(id.Role)
+	s.Rig = id.Rig
+}
+
+// identityFromPath derives the agent from a working directory within the town.
+func identityFromPath(townRoot, cwd string) *session.AgentIdentity {
+	if townRoot == "" || cwd == "" {
+		return nil
+	}
+	rel, err := filepath.Rel(townRoot, cwd)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	switch parts[0] {
+	case "mayor":
+		return &session.AgentIdentity{Role: session.RoleMayor}
+	case "deacon":
+		return &session.AgentIdentity{Role: session.RoleDeacon}
+	}
+	if len(parts) < 2 {
+		return nil
+	}
+	rig := parts[0]
+	switch parts[1] {
+	case "witness":
+		return &session.AgentIdentity{Role: session.RoleWitness, Rig: rig}
+	case "refinery":
+		return &session.AgentIdentity{Role: session.RoleRefinery, Rig: rig}
+	case "polecats", "crew":
+		if len(parts) < 3 {
+			return nil
+		}
+		role := session.RolePolecat
+		if parts[1] == "crew" {
+			role = session.RoleCrew
+		}
+		return &session.AgentIdentity{Role: role, Rig: rig, Name: parts[2]}
+	}
+	return nil
+}
+
+// InTown reports whether a session ran inside the town.
+func (s *Session) InTown(townRoot string) bool {
+	if s.Agent != "" {
+		return true
+	}
+	rel, err := filepath.Rel(townRoot, s.Cwd)
+	return s.Cwd != "" && err == nil && !strings.HasPrefix(rel, "..")
+}
@@ -0,0 +1,132 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTranscript writes JSONL lines to dir/name.
+func writeTranscript(t *testing.T, dir, name string, lines ...string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// claudeLine builds a Claude Code transcript entry.
+func claudeLine(sessionID, cwd, role, ts, text string) string {
+	return `{"type":"` + role + `","sessionId":"` + sessionID + `","cwd":"` + cwd +
+		`","timestamp":"` + ts + `","message":{"role":"` + role + `","content":[{"type":"text","text":"` + text + `"}]}}`
+}
+
+func TestParseClaudeTranscript(t *testing.T) {
+	town := t.TempDir()
+	cwd := filepath.Join(town, "gastown", "polecats", "Toast", "gastown")
+	path := writeTranscript(t, t.TempDir(), "abc.jsonl",
+		claudeLine("sess-1", cwd, "user", "2026-01-02T10:00:00Z",
+			"[GAS TOWN] gastown/polecats/Toast <- witness • 2026-01-02T10:00 • assigned:gt-abc12"),
+		`not json`,
+		`{"type":"assistant","sessionId":"sess-1","timestamp":"2026-01-02T10:05:00Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"x","name":"Edit","input":{"file_path":"internal/payment.go"}}]}}`,
+	)
+
+	s, msgs, err := Parse(path, "claude", town)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if s.ID != "sess-1" || s.Cwd != cwd {
+		t.Errorf("session = %+v, want ID sess-1 and cwd %s", s, cwd)
+	}
+	if s.Agent != "gastown/polecats/Toast" || s.Role != "polecat" || s.Rig != "gastown" {
+		t.Errorf("attribution = %q %q %q", s.Agent, s.Role, s.Rig)
+	}
+	if s.Bead != "gt-abc12" {
+		t.Errorf("Bead = %q, want gt-abc12", s.Bead)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if msgs[1].Speaker != "assistant" || !strings.Contains(msgs[1].Text, "internal/payment.go") {
+		t.Errorf("tool use message = %+v, want assistant mentioning payment.go", msgs[1])
+	}
+	if !s.End.After(s.Start) {
+		t.Errorf("Start %v / End %v not ordered", s.Start, s.End)
+	}
+}
+
+func TestParseCodexTranscript(t *testing.T) {
+	town := t.TempDir()
+	cwd := filepath.Join(town, "gastown", "crew", "max")
+	path := writeTranscript(t, t.TempDir(), "rollout.jsonl",
+		`{"timestamp":"2026-01-02T10:00:00Z","type":"session_meta","payload":{"id":"codex-1","cwd":"`+cwd+`"}}`,
+		`{"timestamp":"2026-01-02T10:01:00Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"fixed the retry"}]}}`,
+	)
+
+	s, msgs, err := Parse(path, "codex", town)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if s.ID != "codex-1" || s.Agent != "gastown/crew/max" || s.Role != "crew" {
+		t.Errorf("session = %+v", s)
+	}
+	found := false
+	for _, m := range msgs {
+		if m.Speaker == "assistant" && strings.Contains(m.Text, "fixed the retry") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("messages = %+v, want assistant 'fixed the retry'", msgs)
+	}
+}
+
+func TestIdentityFromPath(t *testing.T) {
+	town := "/town"
+	tests := []struct {
+		cwd  string
+		want string
+	}{
+		{"/town/mayor", "mayor"},
+		{"/town/deacon/dogs/alpha", "deacon"},
+		{"/town/gastown/witness", "gastown/witness"},
+		{"/town/gastown/refinery/rig", "gastown/refinery"},
+		{"/town/gastown/crew/max", "gastown/crew/max"},
+		{"/town/gastown/polecats/Toast/gastown/src", "gastown/polecats/Toast"},
+		{"/town/gastown", ""},
+		{"/elsewhere/project", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if id := identityFromPath(town, tt.cwd); id != nil {
+			got = id.Address()
+		}
+		if got != tt.want {
+			t.Errorf("identityFromPath(%q) = %q, want %q", tt.cwd, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Edited internal/Payment.go: a retry_count x")
+	want := []string{"edited", "internal", "payment", "go", "retry_count"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	text := strings.Repeat("filler ", 60) + "the Payment bug " + strings.Repeat("tail ", 60)
+	got := excerpt(text, []string{"payment"})
+	if !strings.Contains(got, "Payment bug") {
+		t.Errorf("excerpt missing match: %q", got)
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("excerpt should be elided on both sides: %q", got)
+	}
+}
@@ -464,6 +464,13 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "mail sent"
 
+	case "mr_submitted":
+		bead := getPayloadString(payload, "bead")
+		if bead != "" {
+			return fmt.Sprintf("submitted %s to merge queue", bead)
+		}
+		return "submitted to merge queue"
+
 	case "merged":
 		worker := getPayloadString(payload, "worker")
 		if worker != "" {
@@ -478,6 +485,38 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "merge failed"
 
+	case "convoy_created":
+		title := getPayloadString(payload, "title")
+		if template := getPayloadString(payload, "template"); template != "" && title != "" {
+			return fmt.Sprintf("convoy created from %s: %s", template, title)
+		}
+		if title != "" {
+			return fmt.Sprintf("convoy created: %s", title)
+		}
+		return "convoy created"
+
+	case "convoy_closed":
+		title := getPayloadString(payload, "title")
+		if title != "" {
+			return fmt.Sprintf("convoy closed: %s", title)
+		}
+		return "convoy closed"
+
+	case "guard_blocked":
+		rule := getPayloadString(payload, "rule")
+		subject := getPayloadString(payload, "subject")
+		if subject != "" {
+			return fmt.Sprintf("guard %s blocked: %s", rule, subject)
+		}
+		return fmt.Sprintf("guard %s blocked %s", rule, getPayloadString(payload, "tool"))
+
+	case "account_limited":
+		account := getPayloadString(payload, "account")
+		if to := getPayloadString(payload, "rotated_to"); to != "" {
+			return fmt.Sprintf("account %s limited, moved to %s", account, to)
+		}
+		return fmt.Sprintf("account %s limited", account)
+
 	default:
 		if msg := getPayloadString(payload, "message"); msg != "" {
 			return msg
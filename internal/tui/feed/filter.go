@@ -0,0 +1,164 @@
+package feed
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// filterFields are the keys understood by the filter query language.
+var filterFields = map[string]bool{
+	"rig":   true,
+	"actor": true,
+	"type":  true,
+	"bead":  true,
+	"role":  true,
+}
+
+// filterTerm is one key:value condition. Values are OR'ed.
+type filterTerm struct {
+	field  string // rig, actor, type, bead, role, or "" for free text
+	values []string
+	negate bool
+}
+
+// Query is a parsed feed filter.
+//
+// Syntax: space-separated terms, all of which must match.
+//
+//	rig:gastown             events from a rig
+//	actor:*/polecats/*      actor glob (plain values match as substrings)
+//	type:done,merged        any of several event types
+//	bead:gt-abc             target bead (glob or substring)
+//	role:witness            actor's role
+//	-type:update            negate a term
+//	conflict                free text, matched against the whole event
+type Query struct {
+	raw   string
+	terms []filterTerm
+}
+
+// ParseQuery parses a filter query. An empty query matches everything.
+func ParseQuery(s string) (*Query, error) {
+	q := &Query{raw: strings.TrimSpace(s)}
+	for _, tok := range strings.Fields(q.raw) {
+		term := filterTerm{}
+		if strings.HasPrefix(tok, "-") && len(tok) > 1 {
+			term.negate = true
+			tok = tok[1:]
+		}
+
+		if field, value, ok := strings.Cut(tok, ":"); ok && filterFields[strings.ToLower(field)] {
+			if value == "" {
+				return nil, fmt.Errorf("%s: missing value", field)
+			}
+			term.field = strings.ToLower(field)
+			for _, v := range strings.Split(value, ",") {
+				if v == "" {
+					continue
+				}
+				if _, err := path.Match(v, ""); err != nil {
+					return nil, fmt.Errorf("%s: bad pattern %q", field, v)
+				}
+				term.values = append(term.values, strings.ToLower(v))
+			}
+		} else {
+			term.values = []string{strings.ToLower(tok)}
+		}
+		q.terms = append(q.terms, term)
+	}
+	return q, nil
+}
+
+// String returns the query as typed.
+func (q *Query) String() string {
+	if q == nil {
+		return ""
+	}
+	return q.raw
+}
+
+// Empty reports whether the query matches everything.
+func (q *Query) Empty() bool {
+	return q == nil || len(q.terms) == 0
+}
+
+// Match reports whether an event satisfies the query.
+func (q *Query) Match(e Event) bool {
+	if q.Empty() {
+		return true
+	}
+	for _, term := range q.terms {
+		if term.match(e) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t filterTerm) match(e Event) bool {
+	for _, v := range t.values {
+		switch t.field {
+		case "rig":
+			if globOrEqual(v, e.Rig) {
+				return true
+			}
+		case "type":
+			if globOrEqual(v, e.Type) {
+				return true
+			}
+		case "role":
+			if globOrEqual(v, e.Role) {
+				return true
+			}
+		case "actor":
+			if globOrContains(v, e.Actor) {
+				return true
+			}
+		case "bead":
+			if globOrContains(v, e.Target) {
+				return true
+			}
+		default:
+			if matchText(v, e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchText reports whether text appears anywhere in the event (case-insensitive).
+func matchText(text string, e Event) bool {
+	if text == "" {
+		return true
+	}
+	for _, s := range []string{e.Message, e.Actor, e.Target, e.Type, e.Rig, e.Raw} {
+		if strings.Contains(strings.ToLower(s), text) {
+			return true
+		}
+	}
+	return false
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func globOrEqual(pattern, s string) bool {
+	s = strings.ToLower(s)
+	if isGlob(pattern) {
+		ok, _ := path.Match(pattern, s)
+		return ok
+	}
+	return pattern == s
+}
+
+func globOrContains(pattern, s string) bool {
+	s = strings.ToLower(s)
+	if isGlob(pattern) {
+		ok, _ := path.Match(pattern, s)
+		return ok
+	}
+	return strings.Contains(s, pattern)
+}
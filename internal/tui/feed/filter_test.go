@@ -0,0 +1,73 @@
+package feed
+
+import "testing"
+
+func TestQuery_Match(t *testing.T) {
+	done := Event{Type: "done", Actor: "gastown/polecats/nux", Target: "gt-abc", Rig: "gastown", Role: "polecat", Message: "done: gt-abc"}
+	update := Event{Type: "update", Actor: "gastown/crew/joe", Target: "gt-xyz", Rig: "gastown", Role: "crew", Message: "Fix merge conflict"}
+	other := Event{Type: "merged", Actor: "beads/refinery", Target: "bd-1", Rig: "beads", Role: "refinery"}
+
+	tests := []struct {
+		query string
+		want  []bool // done, update, other
+	}{
+		{"", []bool{true, true, true}},
+		{"rig:gastown", []bool{true, true, false}},
+		{"actor:*/polecats/*", []bool{true, false, false}},
+		{"actor:joe", []bool{false, true, false}},
+		{"type:done,merged", []bool{true, false, true}},
+		{"-type:update", []bool{true, false, true}},
+		{"bead:gt-*", []bool{true, true, false}},
+		{"role:refinery", []bool{false, false, true}},
+		{"CONFLICT", []bool{false, true, false}},
+		{"rig:gastown -role:crew", []bool{true, false, false}},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+		}
+		for i, e := range []Event{done, update, other} {
+			if got := q.Match(e); got != tt.want[i] {
+				t.Errorf("ParseQuery(%q).Match(%s) = %v, want %v", tt.query, e.Type, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, query := range []string{"rig:", "actor:[abc"} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("ParseQuery(%q) expected error", query)
+		}
+	}
+}
+
+func TestEventRing(t *testing.T) {
+	r := newEventRing(3)
+	for _, typ := range []string{"a", "b", "c", "d"} {
+		r.Add(Event{Type: typ})
+	}
+
+	if r.Len() != 3 || r.Total() != 4 {
+		t.Fatalf("Len() = %d, Total() = %d, want 3, 4", r.Len(), r.Total())
+	}
+	if last, _ := r.Last(); last.Type != "d" {
+		t.Errorf("Last() = %s, want d", last.Type)
+	}
+
+	var got []string
+	var seqs []uint64
+	r.Each(func(seq uint64, e Event) bool {
+		got = append(got, e.Type)
+		seqs = append(seqs, seq)
+		return true
+	})
+	if len(got) != 3 || got[0] != "d" || got[1] != "c" || got[2] != "b" {
+		t.Errorf("Each() order = %v, want [d c b]", got)
+	}
+	if seqs[0] != 4 || seqs[2] != 2 {
+		t.Errorf("Each() seqs = %v, want [4 3 2]", seqs)
+	}
+}
@@ -28,6 +28,9 @@ type KeyMap struct {
 	Search      key.Binding
 	Filter      key.Binding
 	ClearFilter key.Binding
+	Pause       key.Binding
+	NextView    key.Binding
+	SaveView    key.Binding
 
 	// General
 	Help key.Binding
@@ -105,6 +108,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "clear"),
 		),
+		Pause: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause"),
+		),
+		NextView: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "next view"),
+		),
+		SaveView: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "save view"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
@@ -126,7 +141,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
 		{k.Tab, k.FocusTree, k.FocusConvoy, k.FocusFeed, k.Enter, k.Expand},
-		{k.Search, k.Filter, k.ClearFilter, k.Refresh},
+		{k.Search, k.Filter, k.ClearFilter, k.Pause, k.NextView, k.SaveView, k.Refresh},
 		{k.Help, k.Quit},
 	}
 }
@@ -1,6 +1,9 @@
 package feed
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +35,19 @@ type Event struct {
 	Raw      string // raw line for fallback display
 }
 
+// inputMode is what the input bar is currently editing
+type inputMode int
+
+const (
+	inputNone inputMode = iota
+	inputFilter
+	inputSearch
+	inputViewName
+)
+
+// SaveViewFunc persists a named filter view.
+type SaveViewFunc func(name, query string) error
+
 // Agent represents an agent in the tree
 type Agent struct {
 	ID         string
@@ -65,7 +81,7 @@ type Model struct {
 
 	// Data
 	rigs        map[string]*Rig
-	events      []Event
+	events      *eventRing
 	convoyState *ConvoyState
 	townRoot    string
 
@@ -73,7 +89,22 @@ type Model struct {
 	keys     KeyMap
 	help     help.Model
 	showHelp bool
-	filter   string
+
+	// Filtering and search
+	filter    *Query
+	search    string
+	input     inputMode
+	inputText string
+	inputErr  string
+
+	// Pause: while paused the feed shows only events up to pausedAt
+	paused   bool
+	pausedAt uint64
+
+	// Named views (filter queries saved in town settings)
+	views    map[string]string
+	view     string
+	saveView SaveViewFunc
 
 	// Event source
 	eventChan <-chan Event
@@ -92,7 +123,7 @@ func NewModel() *Model {
 		convoyViewport: viewport.New(0, 0),
 		feedViewport:   viewport.New(0, 0),
 		rigs:           make(map[string]*Rig),
-		events:         make([]Event, 0, 1000),
+		events:         newEventRing(DefaultEventBuffer),
 		keys:           DefaultKeyMap(),
 		help:           h,
 		done:           make(chan struct{}),
@@ -104,6 +135,45 @@ func (m *Model) SetTownRoot(townRoot string) {
 	m.townRoot = townRoot
 }
 
+// SetBufferSize sets how many events are kept for scroll-back.
+// Must be called before events arrive.
+func (m *Model) SetBufferSize(n int) {
+	m.events = newEventRing(n)
+}
+
+// SetFilter sets the feed filter query.
+func (m *Model) SetFilter(query string) error {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return err
+	}
+	m.filter = q
+	m.updateViewContent()
+	return nil
+}
+
+// SetViews sets the saved filter views and how to persist new ones.
+func (m *Model) SetViews(views map[string]string, save SaveViewFunc) {
+	m.views = make(map[string]string, len(views))
+	for name, query := range views {
+		m.views[name] = query
+	}
+	m.saveView = save
+}
+
+// SelectView applies a saved view by name.
+func (m *Model) SelectView(name string) error {
+	query, ok := m.views[name]
+	if !ok {
+		return fmt.Errorf("no saved view %q", name)
+	}
+	if err := m.SetFilter(query); err != nil {
+		return fmt.Errorf("view %s: %w", name, err)
+	}
+	m.view = name
+	return nil
+}
+
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
@@ -220,6 +290,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKey processes key presses
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.input != inputNone {
+		return m.handleInputKey(msg)
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Quit):
 		m.closeOnce.Do(func() { close(m.done) })
@@ -257,6 +331,37 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Refresh):
 		m.updateViewContent()
 		return m, nil
+
+	case key.Matches(msg, m.keys.Filter):
+		m.startInput(inputFilter, m.filter.String())
+		return m, nil
+
+	case key.Matches(msg, m.keys.Search):
+		m.startInput(inputSearch, m.search)
+		return m, nil
+
+	case key.Matches(msg, m.keys.ClearFilter):
+		m.filter = nil
+		m.search = ""
+		m.view = ""
+		m.updateViewContent()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Pause):
+		m.paused = !m.paused
+		m.pausedAt = m.events.Total()
+		m.updateViewContent()
+		return m, nil
+
+	case key.Matches(msg, m.keys.NextView):
+		m.cycleView()
+		return m, nil
+
+	case key.Matches(msg, m.keys.SaveView):
+		if !m.filter.Empty() {
+			m.startInput(inputViewName, m.view)
+		}
+		return m, nil
 	}
 
 	// Pass to focused viewport
@@ -272,6 +377,125 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// startInput opens the input bar.
+func (m *Model) startInput(mode inputMode, initial string) {
+	m.input = mode
+	m.inputText = initial
+	m.inputErr = ""
+}
+
+// handleInputKey edits the input bar. Search applies as you type; filters
+// apply on enter so half-typed queries don't blank the feed.
+func (m *Model) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.closeOnce.Do(func() { close(m.done) })
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		if m.input == inputSearch {
+			m.search = ""
+			m.updateViewContent()
+		}
+		m.input = inputNone
+		m.inputErr = ""
+		return m, nil
+
+	case tea.KeyEnter:
+		m.submitInput()
+		return m, nil
+
+	case tea.KeyBackspace:
+		if r := []rune(m.inputText); len(r) > 0 {
+			m.inputText = string(r[:len(r)-1])
+		}
+
+	case tea.KeyCtrlU:
+		m.inputText = ""
+
+	case tea.KeySpace:
+		m.inputText += " "
+
+	case tea.KeyRunes:
+		m.inputText += string(msg.Runes)
+
+	default:
+		return m, nil
+	}
+
+	if m.input == inputSearch {
+		m.search = m.inputText
+		m.updateViewContent()
+	}
+	return m, nil
+}
+
+// submitInput applies the input bar's contents.
+func (m *Model) submitInput() {
+	switch m.input {
+	case inputFilter:
+		if err := m.SetFilter(m.inputText); err != nil {
+			m.inputErr = err.Error()
+			return
+		}
+		m.view = ""
+	case inputSearch:
+		m.search = m.inputText
+		m.updateViewContent()
+	case inputViewName:
+		name := strings.TrimSpace(m.inputText)
+		if name == "" {
+			return
+		}
+		if m.saveView != nil {
+			if err := m.saveView(name, m.filter.String()); err != nil {
+				m.inputErr = err.Error()
+				return
+			}
+		}
+		if m.views == nil {
+			m.views = make(map[string]string)
+		}
+		m.views[name] = m.filter.String()
+		m.view = name
+	}
+	m.input = inputNone
+	m.inputErr = ""
+}
+
+// cycleView applies the next saved view in name order, then no view.
+func (m *Model) cycleView() {
+	if len(m.views) == 0 {
+		return
+	}
+	names := make([]string, 0, len(m.views))
+	for name := range m.views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	next := names[0]
+	if m.view != "" {
+		i := sort.SearchStrings(names, m.view)
+		if i+1 >= len(names) {
+			m.view = ""
+			m.filter = nil
+			m.updateViewContent()
+			return
+		}
+		next = names[i+1]
+	}
+	_ = m.SelectView(next)
+}
+
+// visible reports whether an event passes the filter and search.
+func (m *Model) visible(e Event) bool {
+	if !m.filter.Match(e) {
+		return false
+	}
+	return matchText(strings.ToLower(m.search), e)
+}
+
 // updateViewportSizes recalculates viewport dimensions
 func (m *Model) updateViewportSizes() {
 	// Reserve space: header (1) + borders (6 for 3 panels) + status bar (1) + help (1-2)
@@ -375,8 +599,7 @@ func (m *Model) addEvent(e Event) {
 
 	// Deduplicate rapid updates to the same bead within 2 seconds.
 	// This prevents spam when multiple deps/labels are added to one issue.
-	if e.Type == "update" && e.Target != "" && len(m.events) > 0 {
-		lastEvent := m.events[len(m.events)-1]
+	if lastEvent, ok := m.events.Last(); ok && e.Type == "update" && e.Target != "" {
 		if lastEvent.Type == "update" && lastEvent.Target == e.Target {
 			// Same bead updated within 2 seconds - skip duplicate
 			if e.Time.Sub(lastEvent.Time) < 2*time.Second {
@@ -385,13 +608,8 @@ func (m *Model) addEvent(e Event) {
 		}
 	}
 
-	// Add to event feed
-	m.events = append(m.events, e)
-
-	// Keep max 1000 events
-	if len(m.events) > 1000 {
-		m.events = m.events[len(m.events)-1000:]
-	}
+	// Add to event feed (the ring drops the oldest event when full)
+	m.events.Add(e)
 
 	m.updateViewContent()
 }
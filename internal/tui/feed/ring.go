@@ -0,0 +1,61 @@
+package feed
+
+// DefaultEventBuffer is how many events the feed keeps for scroll-back.
+const DefaultEventBuffer = 1000
+
+// eventRing is a bounded ring buffer of events. Once full, adding an event
+// drops the oldest one.
+type eventRing struct {
+	buf   []Event
+	start int    // index of the oldest event
+	count int    // number of events held
+	total uint64 // events ever added; the newest event has sequence total
+}
+
+func newEventRing(capacity int) *eventRing {
+	if capacity <= 0 {
+		capacity = DefaultEventBuffer
+	}
+	return &eventRing{buf: make([]Event, capacity)}
+}
+
+// Add appends an event, evicting the oldest if the ring is full.
+func (r *eventRing) Add(e Event) {
+	if r.count < len(r.buf) {
+		r.buf[(r.start+r.count)%len(r.buf)] = e
+		r.count++
+	} else {
+		r.buf[r.start] = e
+		r.start = (r.start + 1) % len(r.buf)
+	}
+	r.total++
+}
+
+// Len returns the number of events held.
+func (r *eventRing) Len() int {
+	return r.count
+}
+
+// Total returns the number of events ever added.
+func (r *eventRing) Total() uint64 {
+	return r.total
+}
+
+// Last returns the newest event.
+func (r *eventRing) Last() (Event, bool) {
+	if r.count == 0 {
+		return Event{}, false
+	}
+	return r.buf[(r.start+r.count-1)%len(r.buf)], true
+}
+
+// Each calls fn for each event from newest to oldest, with the event's
+// sequence number, until fn returns false.
+func (r *eventRing) Each(fn func(seq uint64, e Event) bool) {
+	for i := r.count - 1; i >= 0; i-- {
+		seq := r.total - uint64(r.count-1-i)
+		if !fn(seq, r.buf[(r.start+i)%len(r.buf)]) {
+			return
+		}
+	}
+}
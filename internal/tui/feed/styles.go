@@ -140,10 +140,16 @@ var (
 		"polecat_nudged":  "⚡",
 		"escalation_sent": "⬆",
 		// Merge events
-		"merge_started": "⚙",
-		"merged":        "✓",
-		"merge_failed":  "✗",
-		"merge_skipped": "⊘",
+		"mr_submitted":   "⇪",
+		"merge_started":  "⚙",
+		"merged":         "✓",
+		"merge_failed":   "✗",
+		"merge_skipped":  "⊘",
+		"convoy_created": "🚚",
+		"convoy_closed":  "🚚",
+		"guard_blocked":  "🛡",
+		// Account events
+		"account_limited": "⏳",
 		// General gt events
 		"sling":   "🎯",
 		"hook":    "🪝",
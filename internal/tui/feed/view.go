@@ -47,12 +47,22 @@ func (m *Model) render() string {
 func (m *Model) renderHeader() string {
 	title := TitleStyle.Render("GT Feed")
 
-	filter := ""
-	if m.filter != "" {
-		filter = FilterStyle.Render(fmt.Sprintf("Filter: %s", m.filter))
+	var parts []string
+	if m.paused {
+		parts = append(parts, fmt.Sprintf("PAUSED (+%d new)", m.events.Total()-m.pausedAt))
+	}
+	if m.view != "" {
+		parts = append(parts, "View: "+m.view)
+	}
+	if !m.filter.Empty() {
+		parts = append(parts, "Filter: "+m.filter.String())
 	} else {
-		filter = FilterStyle.Render("Filter: all")
+		parts = append(parts, "Filter: all")
 	}
+	if m.search != "" {
+		parts = append(parts, "Search: "+m.search)
+	}
+	filter := FilterStyle.Render(strings.Join(parts, "  "))
 
 	// Right-align filter
 	gap := m.width - lipgloss.Width(title) - lipgloss.Width(filter) - 4
@@ -215,21 +225,26 @@ func (m *Model) renderAgent(icon string, agent *Agent, indent int) string {
 
 // renderFeed renders the event feed content
 func (m *Model) renderFeed() string {
-	if len(m.events) == 0 {
+	if m.events.Len() == 0 {
 		return AgentIdleStyle.Render("No events yet")
 	}
 
 	var lines []string
 
-	// Show most recent events first (reversed)
-	start := 0
-	if len(m.events) > 100 {
-		start = len(m.events) - 100
-	}
+	// Show most recent events first; the whole buffer is scroll-back.
+	// While paused, events that arrived after the pause are held back.
+	m.events.Each(func(seq uint64, event Event) bool {
+		if m.paused && seq > m.pausedAt {
+			return true
+		}
+		if m.visible(event) {
+			lines = append(lines, m.renderEvent(event))
+		}
+		return true
+	})
 
-	for i := len(m.events) - 1; i >= start; i-- {
-		event := m.events[i]
-		lines = append(lines, m.renderEvent(event))
+	if len(lines) == 0 {
+		return AgentIdleStyle.Render("No events match the filter")
 	}
 
 	return strings.Join(lines, "\n")
@@ -255,7 +270,7 @@ func (m *Model) renderEvent(e Event) string {
 		symbolStyle = EventUpdateStyle
 	case "complete", "patrol_complete", "merged", "done":
 		symbolStyle = EventCompleteStyle
-	case "fail", "merge_failed":
+	case "fail", "merge_failed", "guard_blocked", "account_limited":
 		symbolStyle = EventFailStyle
 	case "delete":
 		symbolStyle = EventDeleteStyle
@@ -301,6 +316,10 @@ func (m *Model) renderEvent(e Event) string {
 
 // renderStatusBar renders the bottom status bar
 func (m *Model) renderStatusBar() string {
+	if m.input != inputNone {
+		return m.renderInputBar()
+	}
+
 	// Panel indicator
 	var panelName string
 	switch m.focusedPanel {
@@ -314,7 +333,17 @@ func (m *Model) renderStatusBar() string {
 	panel := fmt.Sprintf("[%s]", panelName)
 
 	// Event count
-	count := fmt.Sprintf("%d events", len(m.events))
+	count := fmt.Sprintf("%d events", m.events.Len())
+	if !m.filter.Empty() || m.search != "" {
+		matched := 0
+		m.events.Each(func(_ uint64, e Event) bool {
+			if m.visible(e) {
+				matched++
+			}
+			return true
+		})
+		count = fmt.Sprintf("%d/%d events", matched, m.events.Len())
+	}
 
 	// Short help
 	help := m.renderShortHelp()
@@ -329,12 +358,33 @@ func (m *Model) renderStatusBar() string {
 	return StatusBarStyle.Width(m.width).Render(left + strings.Repeat(" ", gap) + help)
 }
 
+// renderInputBar renders the filter/search/view-name prompt in place of the status bar
+func (m *Model) renderInputBar() string {
+	var prompt string
+	switch m.input {
+	case inputFilter:
+		prompt = "filter (rig: actor: type: bead: role: -neg): "
+	case inputSearch:
+		prompt = "/"
+	case inputViewName:
+		prompt = "save view as: "
+	}
+
+	line := HelpKeyStyle.Render(prompt) + m.inputText + "█"
+	if m.inputErr != "" {
+		line += "  " + EventFailStyle.Render(m.inputErr)
+	}
+	return StatusBarStyle.Width(m.width).Render(line)
+}
+
 // renderShortHelp renders abbreviated key hints
 func (m *Model) renderShortHelp() string {
 	hints := []string{
 		HelpKeyStyle.Render("j/k") + HelpDescStyle.Render(":scroll"),
 		HelpKeyStyle.Render("tab") + HelpDescStyle.Render(":switch"),
 		HelpKeyStyle.Render("/") + HelpDescStyle.Render(":search"),
+		HelpKeyStyle.Render("f") + HelpDescStyle.Render(":filter"),
+		HelpKeyStyle.Render("p") + HelpDescStyle.Render(":pause"),
 		HelpKeyStyle.Render("q") + HelpDescStyle.Render(":quit"),
 		HelpKeyStyle.Render("?") + HelpDescStyle.Render(":help"),
 	}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -48,18 +49,14 @@ func runBdCmd(beadsDir string, args ...string) (*bytes.Buffer, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "bd", args...)
-	cmd.Dir = beadsDir
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
+	stdout, _, err := beads.Exec(ctx, beads.Command{Dir: beadsDir, Args: args})
+	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("bd timed out after %v", cmdTimeout)
 		}
 		return nil, err
 	}
-	return &stdout, nil
+	return bytes.NewBuffer(stdout), nil
 }
 
 // LiveConvoyFetcher fetches convoy data from beads.
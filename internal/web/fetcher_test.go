@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
 )
 
 func TestCalculateWorkStatus(t *testing.T) {
@@ -415,3 +417,36 @@ func TestParseActivityTimestamp(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchConvoysWithFakeBeads(t *testing.T) {
+	fake := beadstest.Install(t)
+	fake.Add(
+		beads.Issue{ID: "hq-cv-1", Title: "Ship it", Type: "convoy"},
+		beads.Issue{ID: "hq-cv-2", Title: "Landed", Type: "convoy", Status: "closed"},
+		beads.Issue{ID: "gt-1", Title: "First", Status: "closed"},
+		beads.Issue{ID: "gt-2", Title: "Second", Status: "in_progress", Assignee: "gastown/polecats/toast"},
+	)
+	fake.AddDependency("hq-cv-1", "gt-1", "tracks")
+	fake.AddDependency("hq-cv-1", "gt-2", "tracks")
+
+	townRoot := t.TempDir()
+	f := &LiveConvoyFetcher{townRoot: townRoot}
+	rows, err := f.FetchConvoys()
+	if err != nil {
+		t.Fatalf("FetchConvoys: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d convoys, want 1 open convoy", len(rows))
+	}
+
+	row := rows[0]
+	if row.ID != "hq-cv-1" || row.Progress != "1/2" || len(row.TrackedIssues) != 2 {
+		t.Errorf("row = %+v", row)
+	}
+	if row.TrackedIssues[1].Assignee != "gastown/polecats/toast" {
+		t.Errorf("tracked = %+v", row.TrackedIssues)
+	}
+	if n := fake.Count("show"); n != 1 {
+		t.Errorf("show invoked %d times, want one batched call", n)
+	}
+}
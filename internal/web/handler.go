@@ -3,9 +3,7 @@ package web
 import (
 	"context"
 	"html/template"
-	"log"
 	"net/http"
-	"sync"
 	"time"
 )
 
@@ -32,11 +30,12 @@ type ConvoyFetcher interface {
 
 // ConvoyHandler handles HTTP requests for the convoy dashboard.
 type ConvoyHandler struct {
-	fetcher  ConvoyFetcher
+	load     func(ctx context.Context) *Snapshot
 	template *template.Template
 }
 
-// NewConvoyHandler creates a new convoy handler with the given fetcher.
+// NewConvoyHandler creates a convoy handler that fetches fresh data from
+// fetcher on every request.
 func NewConvoyHandler(fetcher ConvoyFetcher) (*ConvoyHandler, error) {
 	tmpl, err := LoadTemplates()
 	if err != nil {
@@ -44,189 +43,46 @@ func NewConvoyHandler(fetcher ConvoyFetcher) (*ConvoyHandler, error) {
 	}
 
 	return &ConvoyHandler{
-		fetcher:  fetcher,
+		load: func(ctx context.Context) *Snapshot {
+			return withStaleness(fetchSnapshot(ctx, fetcher, nil, nil), fetchTimeout)
+		},
+		template: tmpl,
+	}, nil
+}
+
+// NewCachedConvoyHandler creates a convoy handler that serves the latest
+// snapshot from cache, so requests don't touch bd, tmux or gh at all.
+func NewCachedConvoyHandler(cache *SnapshotCache) (*ConvoyHandler, error) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConvoyHandler{
+		load:     cache.Snapshot,
 		template: tmpl,
 	}, nil
 }
 
 // ServeHTTP handles GET / requests and renders the convoy dashboard.
 func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Check for expand parameter (fullscreen a specific panel)
-	expandPanel := r.URL.Query().Get("expand")
-
-	// Create a timeout context for all fetches
+	// Only waits this long for the first snapshot when cached
 	ctx, cancel := context.WithTimeout(r.Context(), fetchTimeout)
 	defer cancel()
 
-	var (
-		convoys     []ConvoyRow
-		mergeQueue  []MergeQueueRow
-		workers     []WorkerRow
-		mail        []MailRow
-		rigs        []RigRow
-		dogs        []DogRow
-		escalations []EscalationRow
-		health      *HealthRow
-		queues      []QueueRow
-		sessions    []SessionRow
-		hooks       []HookRow
-		mayor       *MayorStatus
-		issues      []IssueRow
-		activity    []ActivityRow
-		wg          sync.WaitGroup
-	)
-
-	// Run all fetches in parallel with error logging
-	wg.Add(14)
-
-	go func() {
-		defer wg.Done()
-		var err error
-		convoys, err = h.fetcher.FetchConvoys()
-		if err != nil {
-			log.Printf("dashboard: FetchConvoys failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		mergeQueue, err = h.fetcher.FetchMergeQueue()
-		if err != nil {
-			log.Printf("dashboard: FetchMergeQueue failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		workers, err = h.fetcher.FetchWorkers()
-		if err != nil {
-			log.Printf("dashboard: FetchWorkers failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		mail, err = h.fetcher.FetchMail()
-		if err != nil {
-			log.Printf("dashboard: FetchMail failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		rigs, err = h.fetcher.FetchRigs()
-		if err != nil {
-			log.Printf("dashboard: FetchRigs failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		dogs, err = h.fetcher.FetchDogs()
-		if err != nil {
-			log.Printf("dashboard: FetchDogs failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		escalations, err = h.fetcher.FetchEscalations()
-		if err != nil {
-			log.Printf("dashboard: FetchEscalations failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		health, err = h.fetcher.FetchHealth()
-		if err != nil {
-			log.Printf("dashboard: FetchHealth failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		queues, err = h.fetcher.FetchQueues()
-		if err != nil {
-			log.Printf("dashboard: FetchQueues failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		sessions, err = h.fetcher.FetchSessions()
-		if err != nil {
-			log.Printf("dashboard: FetchSessions failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		hooks, err = h.fetcher.FetchHooks()
-		if err != nil {
-			log.Printf("dashboard: FetchHooks failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		mayor, err = h.fetcher.FetchMayor()
-		if err != nil {
-			log.Printf("dashboard: FetchMayor failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		issues, err = h.fetcher.FetchIssues()
-		if err != nil {
-			log.Printf("dashboard: FetchIssues failed: %v", err)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		var err error
-		activity, err = h.fetcher.FetchActivity()
-		if err != nil {
-			log.Printf("dashboard: FetchActivity failed: %v", err)
-		}
-	}()
-
-	// Wait for fetches or timeout
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
-
-	select {
-	case <-done:
-		// All fetches completed
-	case <-ctx.Done():
-		log.Printf("dashboard: fetch timeout after %v", fetchTimeout)
+	snap := h.load(ctx)
+	if snap == nil {
+		http.Error(w, "Dashboard data is still loading, try again shortly", http.StatusServiceUnavailable)
+		return
 	}
 
-	// Compute summary from already-fetched data
-	summary := computeSummary(workers, hooks, issues, convoys, escalations, activity)
-
-	data := ConvoyData{
-		Convoys:     convoys,
-		MergeQueue:  mergeQueue,
-		Workers:     workers,
-		Mail:        mail,
-		Rigs:        rigs,
-		Dogs:        dogs,
-		Escalations: escalations,
-		Health:      health,
-		Queues:      queues,
-		Sessions:    sessions,
-		Hooks:       hooks,
-		Mayor:       mayor,
-		Issues:      issues,
-		Activity:    activity,
-		Summary:     summary,
-		Expand:      expandPanel,
-	}
+	data := snap.Data
+	data.RefreshedAt = snap.RefreshedAt
+	data.RefreshedAge = formatRefreshAge(time.Since(snap.RefreshedAt))
+	data.Panels = snap.Panels
+	data.Resuming = snap.Resuming
+	// Check for expand parameter (fullscreen a specific panel)
+	data.Expand = r.URL.Query().Get("expand")
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
@@ -0,0 +1,36 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+// MetricsHandler serves town health metrics in Prometheus text format.
+type MetricsHandler struct {
+	collector *metrics.Collector
+}
+
+// NewMetricsHandler creates a metrics handler for the given town.
+func NewMetricsHandler(townRoot string) *MetricsHandler {
+	return &MetricsHandler{collector: metrics.NewCollector(townRoot)}
+}
+
+// ServeHTTP handles GET /metrics requests.
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Render to a buffer so a collection error doesn't leave a partial response
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf, h.collector.Collect()); err != nil {
+		http.Error(w, "Failed to render metrics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	_, _ = w.Write(buf.Bytes())
+}
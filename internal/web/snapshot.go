@@ -0,0 +1,410 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Snapshot cache defaults
+const (
+	// DefaultRefreshInterval is how often the snapshot cache refetches
+	// every panel when nothing happens in the town.
+	DefaultRefreshInterval = 10 * time.Second
+
+	// eventPollInterval is how often the cache checks .events.jsonl for
+	// new events.
+	eventPollInterval = time.Second
+
+	// minEventRefreshGap debounces event-triggered refreshes, so a burst of
+	// events (a convoy of slings) costs one refresh rather than one each.
+	minEventRefreshGap = 2 * time.Second
+
+	// idleRefreshes is how many refresh intervals the cache keeps refreshing
+	// after the last request before it pauses.
+	idleRefreshes = 3
+)
+
+// PanelStatus records when a dashboard panel's data was last fetched
+// successfully.
+type PanelStatus struct {
+	RefreshedAt time.Time // Zero if the panel has never loaded
+	Err         string    // Error from the most recent fetch, if it failed
+	Stale       bool      // Data is older than the cache's staleness limit
+}
+
+// Age returns the panel data's age for display (e.g., "45s").
+func (p PanelStatus) Age() string {
+	if p.RefreshedAt.IsZero() {
+		return "never"
+	}
+	return formatRefreshAge(time.Since(p.RefreshedAt))
+}
+
+// Snapshot is one complete set of dashboard data.
+type Snapshot struct {
+	Data        ConvoyData
+	RefreshedAt time.Time
+	Panels      map[string]PanelStatus
+
+	// Resuming is set when the cache was paused for lack of viewers: the data
+	// is from before the pause, and a refresh is under way.
+	Resuming bool
+}
+
+// panelFetch fetches one panel's data. On success it returns a function that
+// stores the data in a ConvoyData; it's applied by the caller so panel
+// goroutines never write shared state.
+type panelFetch func(f ConvoyFetcher) (func(*ConvoyData), error)
+
+// panel pairs a panel name (as used in the template) with its fetch.
+type panel struct {
+	name  string
+	fetch panelFetch
+}
+
+// fetchPanel adapts a ConvoyFetcher method and a ConvoyData setter to a panelFetch.
+func fetchPanel[T any](get func(ConvoyFetcher) (T, error), set func(*ConvoyData, T)) panelFetch {
+	return func(f ConvoyFetcher) (func(*ConvoyData), error) {
+		v, err := get(f)
+		if err != nil {
+			return nil, err
+		}
+		return func(d *ConvoyData) { set(d, v) }, nil
+	}
+}
+
+// dashboardPanels lists every panel the dashboard fetches.
+var dashboardPanels = []panel{
+	{"convoys", fetchPanel(ConvoyFetcher.FetchConvoys, func(d *ConvoyData, v []ConvoyRow) { d.Convoys = v })},
+	{"merge_queue", fetchPanel(ConvoyFetcher.FetchMergeQueue, func(d *ConvoyData, v []MergeQueueRow) { d.MergeQueue = v })},
+	{"workers", fetchPanel(ConvoyFetcher.FetchWorkers, func(d *ConvoyData, v []WorkerRow) { d.Workers = v })},
+	{"mail", fetchPanel(ConvoyFetcher.FetchMail, func(d *ConvoyData, v []MailRow) { d.Mail = v })},
+	{"rigs", fetchPanel(ConvoyFetcher.FetchRigs, func(d *ConvoyData, v []RigRow) { d.Rigs = v })},
+	{"dogs", fetchPanel(ConvoyFetcher.FetchDogs, func(d *ConvoyData, v []DogRow) { d.Dogs = v })},
+	{"escalations", fetchPanel(ConvoyFetcher.FetchEscalations, func(d *ConvoyData, v []EscalationRow) { d.Escalations = v })},
+	{"health", fetchPanel(ConvoyFetcher.FetchHealth, func(d *ConvoyData, v *HealthRow) { d.Health = v })},
+	{"queues", fetchPanel(ConvoyFetcher.FetchQueues, func(d *ConvoyData, v []QueueRow) { d.Queues = v })},
+	{"sessions", fetchPanel(ConvoyFetcher.FetchSessions, func(d *ConvoyData, v []SessionRow) { d.Sessions = v })},
+	{"hooks", fetchPanel(ConvoyFetcher.FetchHooks, func(d *ConvoyData, v []HookRow) { d.Hooks = v })},
+	{"mayor", fetchPanel(ConvoyFetcher.FetchMayor, func(d *ConvoyData, v *MayorStatus) { d.Mayor = v })},
+	{"issues", fetchPanel(ConvoyFetcher.FetchIssues, func(d *ConvoyData, v []IssueRow) { d.Issues = v })},
+	{"activity", fetchPanel(ConvoyFetcher.FetchActivity, func(d *ConvoyData, v []ActivityRow) { d.Activity = v })},
+}
+
+// panelsInFlight tracks panel fetches that are still running. Fetches can't
+// be cancelled, so one that outlives its refresh's deadline keeps running;
+// the next refresh skips that panel rather than starting a second fetch.
+type panelsInFlight struct {
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+// start marks a panel's fetch as running. It returns false if a fetch for
+// the panel is already running. A nil tracker allows every fetch.
+func (p *panelsInFlight) start(name string) bool {
+	if p == nil {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.names[name] {
+		return false
+	}
+	if p.names == nil {
+		p.names = make(map[string]bool)
+	}
+	p.names[name] = true
+	return true
+}
+
+// done marks a panel's fetch as finished.
+func (p *panelsInFlight) done(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.names, name)
+}
+
+// fetchSnapshot fetches every panel in parallel, waiting at most until ctx is
+// done. Panels that fail or don't finish in time keep their data and
+// timestamp from prev (if any) and record the error. Panels whose fetch from
+// an earlier call is still running (per inFlight, which may be nil) are not
+// fetched again.
+func fetchSnapshot(ctx context.Context, fetcher ConvoyFetcher, prev *Snapshot, inFlight *panelsInFlight) *Snapshot {
+	type result struct {
+		name  string
+		apply func(*ConvoyData)
+		err   error
+	}
+
+	snap := &Snapshot{Panels: make(map[string]PanelStatus, len(dashboardPanels))}
+	if prev != nil {
+		snap.Data = prev.Data
+		for name, status := range prev.Panels {
+			snap.Panels[name] = status
+		}
+	}
+
+	// Buffered so goroutines that finish after the deadline don't leak
+	results := make(chan result, len(dashboardPanels))
+	now := time.Now()
+	pending := make(map[string]bool, len(dashboardPanels))
+	for _, p := range dashboardPanels {
+		if !inFlight.start(p.name) {
+			status := snap.Panels[p.name]
+			status.Err = "previous fetch still running"
+			snap.Panels[p.name] = status
+			continue
+		}
+		pending[p.name] = true
+		go func(p panel) {
+			defer inFlight.done(p.name)
+			apply, err := p.fetch(fetcher)
+			results <- result{name: p.name, apply: apply, err: err}
+		}(p)
+	}
+
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.name)
+			status := snap.Panels[r.name]
+			if r.err != nil {
+				log.Printf("dashboard: fetching %s failed: %v", r.name, r.err)
+				status.Err = r.err.Error()
+			} else {
+				r.apply(&snap.Data)
+				status = PanelStatus{RefreshedAt: now}
+			}
+			snap.Panels[r.name] = status
+		case <-ctx.Done():
+			log.Printf("dashboard: fetch timeout after %v", fetchTimeout)
+			for name := range pending {
+				status := snap.Panels[name]
+				status.Err = fmt.Sprintf("timed out after %v", fetchTimeout)
+				snap.Panels[name] = status
+			}
+			pending = nil
+		}
+	}
+
+	snap.RefreshedAt = now
+	snap.Data.Summary = computeSummary(snap.Data.Workers, snap.Data.Hooks, snap.Data.Issues,
+		snap.Data.Convoys, snap.Data.Escalations, snap.Data.Activity)
+	return snap
+}
+
+// SnapshotCache holds the latest dashboard snapshot and refreshes it in the
+// background, so page loads cost a map lookup no matter how many people are
+// watching. It refreshes every interval, and sooner when the town's event
+// log grows, but only while someone is looking: with no request for
+// idleRefreshes intervals it pauses, and the next request gets the old data
+// (marked stale) while it catches up.
+type SnapshotCache struct {
+	fetcher    ConvoyFetcher
+	interval   time.Duration
+	staleAfter time.Duration
+	idleAfter  time.Duration
+	eventsPath string
+	inFlight   panelsInFlight
+
+	// lastRequest is when Snapshot was last called, in Unix nanoseconds
+	// (zero before the first request).
+	lastRequest atomic.Int64
+
+	mu    sync.RWMutex
+	snap  *Snapshot
+	ready chan struct{} // closed once the first snapshot is stored
+
+	kick chan struct{} // requests an immediate refresh
+}
+
+// NewSnapshotCache creates a cache that refreshes from fetcher every
+// interval (DefaultRefreshInterval if zero), and whenever the events log in
+// townRoot changes (no event watching if townRoot is empty). Call Start to
+// begin refreshing.
+func NewSnapshotCache(fetcher ConvoyFetcher, townRoot string, interval time.Duration) *SnapshotCache {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	c := &SnapshotCache{
+		fetcher: fetcher,
+		// A panel missing two refreshes in a row is worth flagging
+		staleAfter: 2*interval + fetchTimeout,
+		idleAfter:  idleRefreshes * interval,
+		interval:   interval,
+		ready:      make(chan struct{}),
+		kick:       make(chan struct{}, 1),
+	}
+	if townRoot != "" {
+		c.eventsPath = filepath.Join(townRoot, events.EventsFile)
+	}
+	return c
+}
+
+// Start refreshes the snapshot now and then in the background until ctx is
+// cancelled.
+func (c *SnapshotCache) Start(ctx context.Context) {
+	go c.run(ctx)
+	if c.eventsPath != "" {
+		go c.watchEvents(ctx)
+	}
+}
+
+// Refresh requests a refresh as soon as the current one (if any) finishes.
+func (c *SnapshotCache) Refresh() {
+	select {
+	case c.kick <- struct{}{}:
+	default:
+		// A refresh is already queued
+	}
+}
+
+// run is the refresh loop. Refreshes never overlap, and a panel whose fetch
+// outlived the last refresh isn't fetched again until it returns, so a slow
+// bd can't pile up concurrent fetches. The first refresh always runs so the
+// first request has data; later ones are skipped while the cache is idle.
+func (c *SnapshotCache) run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.kick:
+		}
+		if !c.idle(time.Now()) {
+			c.refresh(ctx)
+		}
+	}
+}
+
+// idle reports whether no request has arrived within idleAfter of now.
+func (c *SnapshotCache) idle(now time.Time) bool {
+	last := c.lastRequest.Load()
+	return last == 0 || now.Sub(time.Unix(0, last)) > c.idleAfter
+}
+
+// refresh fetches a new snapshot on top of the current one and stores it.
+func (c *SnapshotCache) refresh(ctx context.Context) {
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	c.mu.RLock()
+	prev := c.snap
+	c.mu.RUnlock()
+
+	snap := fetchSnapshot(fetchCtx, c.fetcher, prev, &c.inFlight)
+
+	c.mu.Lock()
+	first := c.snap == nil
+	c.snap = snap
+	c.mu.Unlock()
+	if first {
+		close(c.ready)
+	}
+}
+
+// watchEvents polls the events log and requests a refresh when it changes,
+// at most once per minEventRefreshGap.
+func (c *SnapshotCache) watchEvents(ctx context.Context) {
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	lastSize, lastMod := statEvents(c.eventsPath)
+	var lastKick time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		size, mod := statEvents(c.eventsPath)
+		if size == lastSize && mod.Equal(lastMod) {
+			continue
+		}
+		if time.Since(lastKick) < minEventRefreshGap {
+			// Leave last* alone so the change is picked up next tick
+			continue
+		}
+		lastSize, lastMod = size, mod
+		lastKick = time.Now()
+		c.Refresh()
+	}
+}
+
+// statEvents returns the events log's size and modification time (zero if
+// it doesn't exist yet).
+func statEvents(path string) (int64, time.Time) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, time.Time{}
+	}
+	return info.Size(), info.ModTime()
+}
+
+// Snapshot returns the latest snapshot with staleness computed as of now. It
+// waits for the first refresh if none has completed yet, returning nil if ctx
+// ends first. A request to a paused cache resumes refreshing and gets the
+// pre-pause snapshot, marked Resuming.
+func (c *SnapshotCache) Snapshot(ctx context.Context) *Snapshot {
+	now := time.Now()
+	idle := c.idle(now)
+	c.lastRequest.Store(now.UnixNano())
+
+	select {
+	case <-c.ready:
+	case <-ctx.Done():
+		return nil
+	}
+
+	c.mu.RLock()
+	snap := c.snap
+	c.mu.RUnlock()
+
+	out := withStaleness(snap, c.staleAfter)
+	// The first request after startup gets the startup refresh, not old data
+	if idle && now.Sub(snap.RefreshedAt) > c.interval {
+		out.Resuming = true
+		c.Refresh()
+	}
+	return out
+}
+
+// withStaleness returns a copy of snap with each panel's Stale flag set.
+// The data itself is shared; snapshots are never modified once stored.
+func withStaleness(snap *Snapshot, staleAfter time.Duration) *Snapshot {
+	out := *snap
+	out.Panels = make(map[string]PanelStatus, len(snap.Panels))
+	for name, status := range snap.Panels {
+		status.Stale = status.RefreshedAt.IsZero() || time.Since(status.RefreshedAt) > staleAfter
+		out.Panels[name] = status
+	}
+	return &out
+}
+
+// formatRefreshAge formats a refresh age compactly (e.g., "5s", "3m", "2h").
+func formatRefreshAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
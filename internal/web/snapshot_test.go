@@ -0,0 +1,219 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// countingFetcher counts FetchConvoys calls and can be switched to fail.
+type countingFetcher struct {
+	MockConvoyFetcher
+	mu    sync.Mutex
+	calls int
+	fail  bool
+}
+
+func (f *countingFetcher) FetchConvoys() ([]ConvoyRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.fail {
+		return nil, errFetchFailed
+	}
+	return f.Convoys, nil
+}
+
+func (f *countingFetcher) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *countingFetcher) setFail(fail bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fail = fail
+}
+
+// waitFor polls cond until it holds or the deadline passes.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCachedHandlerServesSnapshot(t *testing.T) {
+	fetcher := &countingFetcher{MockConvoyFetcher: MockConvoyFetcher{
+		Convoys: []ConvoyRow{{ID: "hq-cv-abc", Status: "open"}},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := NewSnapshotCache(fetcher, "", time.Hour)
+	cache.Start(ctx)
+	handler, err := NewCachedConvoyHandler(cache)
+	if err != nil {
+		t.Fatalf("NewCachedConvoyHandler() error = %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		body, _ := io.ReadAll(w.Result().Body)
+		if !strings.Contains(string(body), "hq-cv-abc") || !strings.Contains(string(body), "Last refreshed:") {
+			t.Fatalf("request %d: body missing snapshot data", i)
+		}
+	}
+	if n := fetcher.count(); n != 1 {
+		t.Errorf("FetchConvoys called %d times for 5 requests, want 1", n)
+	}
+
+	cache.Refresh()
+	waitFor(t, "explicit refresh", func() bool { return fetcher.count() == 2 })
+}
+
+func TestSnapshotKeepsDataFromFailedPanels(t *testing.T) {
+	fetcher := &countingFetcher{MockConvoyFetcher: MockConvoyFetcher{
+		Convoys: []ConvoyRow{{ID: "hq-cv-abc", Title: "Old convoy"}},
+		Hooks:   []HookRow{{ID: "gt-1"}},
+	}}
+
+	first := fetchSnapshot(context.Background(), fetcher, nil, nil)
+	first.Panels["convoys"] = PanelStatus{RefreshedAt: time.Now().Add(-time.Hour)}
+
+	fetcher.setFail(true)
+	fetcher.Hooks = nil
+	second := withStaleness(fetchSnapshot(context.Background(), fetcher, first, nil), time.Minute)
+
+	if len(second.Data.Convoys) != 1 || second.Data.Convoys[0].Title != "Old convoy" {
+		t.Errorf("convoys = %v, want previous data kept", second.Data.Convoys)
+	}
+	convoys := second.Panels["convoys"]
+	if !convoys.Stale || convoys.Err == "" || convoys.Age() != "1h" {
+		t.Errorf("convoys status = %+v", convoys)
+	}
+	if hooks := second.Panels["hooks"]; hooks.Stale || len(second.Data.Hooks) != 0 {
+		t.Errorf("hooks = %+v %v, want fresh empty data", hooks, second.Data.Hooks)
+	}
+	if second.Data.Summary == nil || second.Data.Summary.ConvoyCount != 1 {
+		t.Errorf("summary = %+v", second.Data.Summary)
+	}
+
+	handler, err := NewConvoyHandler(fetcher)
+	if err != nil {
+		t.Fatalf("NewConvoyHandler() error = %v", err)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	body, _ := io.ReadAll(w.Result().Body)
+	if !strings.Contains(string(body), "stale never") || !strings.Contains(string(body), "Last fetch failed: fetch failed") {
+		t.Error("failed panel not marked stale")
+	}
+}
+
+// blockingFetcher's FetchConvoys blocks until release is closed.
+type blockingFetcher struct {
+	MockConvoyFetcher
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (f *blockingFetcher) FetchConvoys() ([]ConvoyRow, error) {
+	f.calls.Add(1)
+	<-f.release
+	return f.Convoys, nil
+}
+
+func TestSnapshotSkipsPanelsStillFetching(t *testing.T) {
+	fetcher := &blockingFetcher{release: make(chan struct{})}
+	var inFlight panelsInFlight
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	first := fetchSnapshot(ctx, fetcher, nil, &inFlight)
+	if first.Panels["convoys"].Err == "" {
+		t.Fatalf("convoys status = %+v, want timeout", first.Panels["convoys"])
+	}
+
+	// The timed-out fetch is still running, so it isn't started again
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel2()
+	second := fetchSnapshot(ctx2, fetcher, first, &inFlight)
+	if n := fetcher.calls.Load(); n != 1 {
+		t.Errorf("FetchConvoys called %d times, want 1", n)
+	}
+	if !strings.Contains(second.Panels["convoys"].Err, "still running") {
+		t.Errorf("convoys status = %+v", second.Panels["convoys"])
+	}
+	if second.Panels["hooks"].RefreshedAt.IsZero() {
+		t.Error("other panels not refreshed")
+	}
+
+	// Once it returns, the panel is fetched again
+	close(fetcher.release)
+	waitFor(t, "convoys fetch to finish", func() bool { return inFlight.start("convoys") })
+	inFlight.done("convoys")
+	fetchSnapshot(context.Background(), fetcher, second, &inFlight)
+	if n := fetcher.calls.Load(); n != 2 {
+		t.Errorf("FetchConvoys called %d times, want 2", n)
+	}
+}
+
+func TestSnapshotCacheRefreshesOnEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	fetcher := &countingFetcher{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := NewSnapshotCache(fetcher, townRoot, time.Hour)
+	cache.Start(ctx)
+	waitFor(t, "first refresh", func() bool { return fetcher.count() == 1 })
+	cache.Snapshot(ctx) // someone is watching
+
+	line := []byte(`{"type":"sling","actor":"mayor"}` + "\n")
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), line, 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "event-triggered refresh", func() bool { return fetcher.count() == 2 })
+}
+
+func TestSnapshotCachePausesWithoutViewers(t *testing.T) {
+	fetcher := &countingFetcher{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 20 * time.Millisecond
+	cache := NewSnapshotCache(fetcher, "", interval)
+	cache.Start(ctx)
+	waitFor(t, "first refresh", func() bool { return fetcher.count() == 1 })
+
+	time.Sleep(10 * interval)
+	if n := fetcher.count(); n != 1 {
+		t.Fatalf("FetchConvoys called %d times with no viewers, want 1", n)
+	}
+
+	snap := cache.Snapshot(ctx)
+	if snap == nil || !snap.Resuming {
+		t.Fatalf("snapshot after pause = %+v, want Resuming", snap)
+	}
+	waitFor(t, "refresh after request", func() bool { return fetcher.count() > 1 })
+
+	if snap := cache.Snapshot(ctx); snap.Resuming {
+		t.Error("snapshot while watched is marked Resuming")
+	}
+}
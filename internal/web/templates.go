@@ -5,6 +5,7 @@ import (
 	"embed"
 	"html/template"
 	"io/fs"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
 )
@@ -30,6 +31,12 @@ type ConvoyData struct {
 	Activity    []ActivityRow
 	Summary     *DashboardSummary
 	Expand      string // Panel to show fullscreen (from ?expand=name)
+
+	// Freshness of the data (see SnapshotCache)
+	RefreshedAt  time.Time
+	RefreshedAge string                 // Formatted age of the snapshot (e.g., "4s")
+	Panels       map[string]PanelStatus // Per-panel fetch status, keyed by panel name
+	Resuming     bool                   // Refreshes were paused for lack of viewers and are catching up
 }
 
 // RigRow represents a registered rig in the dashboard.
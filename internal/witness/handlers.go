@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -198,10 +199,58 @@ func HandleHelp(workDir, rigName string, msg *mail.Message, router *mail.Router)
 		return result
 	}
 
-	// Assess the help request
-	assessment := AssessHelpRequest(payload)
+	if payload.Agent == "" {
+		payload.Agent = msg.From
+	}
+	polecat := PolecatFromAgent(payload.Agent)
+	payload.Retries = priorHelpRequests(workDir, rigName, msg, payload)
+
+	// Assess the help request against the rig's triage rules
+	rules, err := LoadHelpRules(helpRigPath(workDir, rigName))
+	if err != nil {
+		// A broken rules file must not swallow help requests
+		result.Error = err
+		rules = DefaultHelpRules()
+	}
+	var labels []string
+	if payload.IssueID != "" && rules.NeedsLabels() {
+		if issue, err := beads.New(workDir).Show(payload.IssueID); err == nil {
+			labels = issue.Labels
+		}
+	}
+	assessment := AssessHelpRequestWithRules(rules, payload, polecat, labels)
 
 	if assessment.CanHelp {
+		action := HelpActionHint
+		if assessment.Rule != nil {
+			action = assessment.Rule.Action
+		}
+
+		switch action {
+		case HelpActionNudge:
+			if polecat == "" {
+				break
+			}
+			if err := tmux.NewTmux().NudgeSession(session.PolecatSessionName(rigName, polecat), assessment.HelpAction); err != nil {
+				result.Error = fmt.Errorf("nudging %s: %w", polecat, err)
+				return result
+			}
+			result.Handled = true
+			result.Action = fmt.Sprintf("nudged %s about '%s': %s", polecat, payload.Topic, assessment.HelpAction)
+			return result
+
+		case HelpActionDog:
+			mailID, err := requestDogForHelp(router, rigName, payload, assessment.Rule)
+			if err != nil {
+				result.Error = fmt.Errorf("requesting dog: %w", err)
+				return result
+			}
+			result.Handled = true
+			result.MailSent = mailID
+			result.Action = fmt.Sprintf("requested dog for '%s': %s", payload.Topic, assessment.HelpAction)
+			return result
+		}
+
 		// Log that we can help - actual help is done by the Claude agent
 		result.Handled = true
 		result.Action = fmt.Sprintf("can help with '%s': %s", payload.Topic, assessment.HelpAction)
@@ -210,7 +259,7 @@ func HandleHelp(workDir, rigName string, msg *mail.Message, router *mail.Router)
 
 	// Need to escalate to Mayor
 	if assessment.NeedsEscalation {
-		mailID, err := escalateToMayor(router, rigName, payload, assessment.EscalationReason)
+		mailID, err := escalateToMayor(router, rigName, payload, assessment.EscalationReason, assessment.Severity)
 		if err != nil {
 			result.Error = fmt.Errorf("escalating to mayor: %w", err)
 			return result
@@ -218,12 +267,76 @@ func HandleHelp(workDir, rigName string, msg *mail.Message, router *mail.Router)
 
 		result.Handled = true
 		result.MailSent = mailID
-		result.Action = fmt.Sprintf("escalated '%s' to mayor: %s", payload.Topic, assessment.EscalationReason)
+		result.Action = fmt.Sprintf("escalated '%s' to mayor (%s): %s", payload.Topic, assessment.Severity, assessment.EscalationReason)
 	}
 
 	return result
 }
 
+// helpRetryWindow is how far back priorHelpRequests looks for earlier HELP
+// requests, so the scan stays bounded as the Witness mailbox grows.
+const helpRetryWindow = 24 * time.Hour
+
+// priorHelpRequests counts the HELP mail the Witness received in the
+// helpRetryWindow before msg from the same agent about the same issue (or
+// the same topic, when the request names no issue). Handled and archived
+// requests count too. Returns 0 when the mailbox can't be read.
+func priorHelpRequests(workDir, rigName string, msg *mail.Message, payload *HelpPayload) int {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		townRoot = workDir
+	}
+	since := msg.Timestamp.Add(-helpRetryWindow)
+	history, err := mail.NewMailboxFromAddress(rigName+"/witness", townRoot).History(since)
+	if err != nil {
+		return 0
+	}
+
+	retries := 0
+	for _, prior := range history {
+		if prior.ID == msg.ID || !prior.Timestamp.Before(msg.Timestamp) {
+			continue
+		}
+		p, err := ParseHelp(prior.Subject, prior.Body)
+		if err != nil {
+			continue
+		}
+		if p.Agent == "" {
+			p.Agent = prior.From
+		}
+		if p.Agent != payload.Agent {
+			continue
+		}
+		if payload.IssueID != "" && p.IssueID == payload.IssueID ||
+			payload.IssueID == "" && p.IssueID == "" && p.Topic == payload.Topic {
+			retries++
+		}
+	}
+	return retries
+}
+
+// helpRigPath returns the rig directory holding the help triage rules.
+func helpRigPath(workDir, rigName string) string {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return workDir
+	}
+	return filepath.Join(townRoot, rigName)
+}
+
+// PolecatFromAgent extracts the polecat name from an agent address
+// (<rig>/polecats/<name> or <rig>/<name>).
+func PolecatFromAgent(agent string) string {
+	parts := strings.Split(strings.TrimSuffix(agent, "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[1] == "polecats":
+		return parts[2]
+	case len(parts) == 2 && parts[1] != "witness" && parts[1] != "refinery":
+		return parts[1]
+	}
+	return ""
+}
+
 // HandleMerged processes a MERGED message from the Refinery.
 // Verifies cleanup_status before allowing nuke, escalates if work is at risk.
 func HandleMerged(workDir, rigName string, msg *mail.Message) *HandlerResult {
@@ -554,34 +667,22 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 		return ""
 	}
 
-	// Parse cleanup_status from description
-	// Description format has "cleanup_status: <value>" line
-	for _, line := range strings.Split(resp.Description, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(strings.ToLower(line), "cleanup_status:") {
-			value := strings.TrimSpace(strings.TrimPrefix(line, "cleanup_status:"))
-			value = strings.TrimSpace(strings.TrimPrefix(value, "Cleanup_status:"))
-			if value != "" && value != "null" {
-				return value
-			}
-		}
-	}
-
-	return ""
+	return beads.ParseAgentFields(resp.Description).CleanupStatus
 }
 
 // escalateToMayor sends an escalation mail to the Mayor.
-func escalateToMayor(router *mail.Router, rigName string, payload *HelpPayload, reason string) (string, error) {
+func escalateToMayor(router *mail.Router, rigName string, payload *HelpPayload, reason, severity string) (string, error) {
 	msg := &mail.Message{
 		From:     fmt.Sprintf("%s/witness", rigName),
 		To:       "mayor/",
 		Subject:  fmt.Sprintf("Escalation: %s needs help", payload.Agent),
-		Priority: mail.PriorityHigh,
+		Priority: severityPriority(severity),
 		Body: fmt.Sprintf(`Agent: %s
 Issue: %s
 Topic: %s
 Problem: %s
 Tried: %s
+Severity: %s
 Escalation reason: %s
 Requested at: %s`,
 			payload.Agent,
@@ -589,6 +690,7 @@ Requested at: %s`,
 			payload.Topic,
 			payload.Problem,
 			payload.Tried,
+			severity,
 			reason,
 			payload.RequestedAt.Format(time.RFC3339),
 		),
@@ -601,6 +703,57 @@ Requested at: %s`,
 	return msg.ID, nil
 }
 
+// severityPriority maps an escalation severity to a mail priority.
+func severityPriority(severity string) mail.Priority {
+	switch severity {
+	case config.SeverityCritical:
+		return mail.PriorityUrgent
+	case config.SeverityMedium:
+		return mail.PriorityNormal
+	case config.SeverityLow:
+		return mail.PriorityLow
+	default:
+		return mail.PriorityHigh
+	}
+}
+
+// requestDogForHelp asks the Deacon to dispatch a dog to a help request.
+// The Witness can't dispatch dogs itself; the Deacon owns the kennel.
+func requestDogForHelp(router *mail.Router, rigName string, payload *HelpPayload, rule *HelpRule) (string, error) {
+	sling := "gt sling <formula> deacon/dogs"
+	if rule.Formula != "" {
+		sling = fmt.Sprintf("gt sling %s deacon/dogs --var issue=%s", rule.Formula, payload.IssueID)
+	}
+
+	msg := &mail.Message{
+		From:     fmt.Sprintf("%s/witness", rigName),
+		To:       "deacon/",
+		Subject:  fmt.Sprintf("Dog requested: %s", payload.Topic),
+		Priority: mail.PriorityHigh,
+		Body: fmt.Sprintf(`Agent: %s
+Issue: %s
+Topic: %s
+Problem: %s
+Rule: %s
+Instructions: %s
+Dispatch: %s`,
+			payload.Agent,
+			payload.IssueID,
+			payload.Topic,
+			payload.Problem,
+			rule.Name,
+			rule.Message,
+			sling,
+		),
+	}
+
+	if err := router.Send(msg); err != nil {
+		return "", err
+	}
+
+	return msg.ID, nil
+}
+
 // RecoveryPayload contains data for RECOVERY_NEEDED escalation.
 type RecoveryPayload struct {
 	PolecatName   string
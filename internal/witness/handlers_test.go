@@ -0,0 +1,83 @@
+package witness
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/beads/beadstest"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// TestHandleHelpCountsRetries checks that min_retries rules see how often
+// the polecat has already asked about the issue, including handled requests.
+func TestHandleHelpCountsRetries(t *testing.T) {
+	fake := beadstest.Install(t)
+	clock := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	fake.Now = func() time.Time { return clock }
+	fake.Add(beads.Issue{ID: "gt-gastown-witness", Title: "Witness", Type: "agent"})
+
+	townRoot := t.TempDir()
+	for _, dir := range []string{".beads", "mayor", "gastown/settings"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules := `{"version": 1, "rules": [
+		{"name": "repeat", "match": {"min_retries": 2}, "action": "hint", "message": "asked twice before"},
+		{"name": "first", "match": {}, "action": "hint", "message": "try again"}
+	]}`
+	if err := os.WriteFile(HelpRulesPath(filepath.Join(townRoot, "gastown")), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	inbox := mail.NewMailboxFromAddress("gastown/witness", townRoot)
+	help := func(agent, issue string) *HandlerResult {
+		t.Helper()
+		clock = clock.Add(time.Minute)
+		body := "Agent: " + agent + "\nIssue: " + issue + "\nProblem: stuck"
+		if err := router.Send(mail.NewMessage(agent, "gastown/witness", "HELP: stuck", body)); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		unread, err := inbox.ListUnread()
+		if err != nil || len(unread) != 1 {
+			t.Fatalf("witness inbox = %v, %v", unread, err)
+		}
+		result := HandleHelp(townRoot, "gastown", unread[0], router)
+		if result.Error != nil {
+			t.Fatalf("HandleHelp: %v", result.Error)
+		}
+		// The Witness marks handled requests read
+		if err := inbox.MarkRead(unread[0].ID); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	for i, want := range []string{"try again", "try again", "asked twice before"} {
+		if result := help("gastown/polecats/nux", "gt-abc"); !strings.Contains(result.Action, want) {
+			t.Errorf("request %d: action = %q, want %q", i+1, result.Action, want)
+		}
+	}
+
+	// Requests about other issues, or from other polecats, start over
+	if result := help("gastown/polecats/nux", "gt-xyz"); !strings.Contains(result.Action, "try again") {
+		t.Errorf("other issue: action = %q", result.Action)
+	}
+	if result := help("gastown/polecats/slit", "gt-abc"); !strings.Contains(result.Action, "try again") {
+		t.Errorf("other polecat: action = %q", result.Action)
+	}
+
+	// Requests older than the retry window no longer count
+	clock = clock.Add(helpRetryWindow)
+	if result := help("gastown/polecats/nux", "gt-abc"); !strings.Contains(result.Action, "try again") {
+		t.Errorf("after window: action = %q", result.Action)
+	}
+}
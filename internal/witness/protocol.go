@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // Protocol message patterns for Witness inbox routing.
@@ -54,6 +56,7 @@ type PolecatDonePayload struct {
 	MRID        string
 	Branch      string
 	Gate        string // Gate ID when Exit is PHASE_COMPLETE
+	TraceID     string // Lifecycle trace ID (see gt trace)
 }
 
 // HelpPayload contains parsed data from a HELP message.
@@ -63,6 +66,7 @@ type HelpPayload struct {
 	IssueID     string
 	Problem     string
 	Tried       string
+	Retries     int // Prior help requests about the same issue (set by HandleHelp)
 	RequestedAt time.Time
 }
 
@@ -123,6 +127,7 @@ func ClassifyMessage(subject string) ProtocolType {
 //	MR: <mr-id>
 //	Gate: <gate-id>
 //	Branch: <branch>
+//	Trace-ID: <trace-id>
 func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 	matches := PatternPolecatDone.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -146,6 +151,8 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 			payload.Gate = strings.TrimSpace(strings.TrimPrefix(line, "Gate:"))
 		} else if strings.HasPrefix(line, "Branch:") {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
+		} else if strings.HasPrefix(line, "Trace-ID:") {
+			payload.TraceID = strings.TrimSpace(strings.TrimPrefix(line, "Trace-ID:"))
 		}
 	}
 
@@ -307,54 +314,46 @@ type HelpAssessment struct {
 	HelpAction  string // What the Witness can do to help
 	NeedsEscalation bool
 	EscalationReason string
+
+	Rule     *HelpRule // Triage rule that matched (nil if none did)
+	Severity string    // Escalation severity (when NeedsEscalation)
 }
 
-// AssessHelpRequest provides guidance for the Witness to assess a help request.
-// This is a template/guide - actual assessment is done by the Claude agent.
+// AssessHelpRequest assesses a help request against the default triage rules.
+// Use AssessHelpRequestWithRules to apply a rig's own rules file.
 func AssessHelpRequest(payload *HelpPayload) *HelpAssessment {
-	assessment := &HelpAssessment{}
-
-	// Heuristics for common help requests that Witness can handle
-	topic := strings.ToLower(payload.Topic)
-	problem := strings.ToLower(payload.Problem)
-
-	// Git issues - Witness can often help
-	if strings.Contains(topic, "git") || strings.Contains(problem, "git") {
-		if strings.Contains(problem, "conflict") {
-			assessment.CanHelp = false
-			assessment.NeedsEscalation = true
-			assessment.EscalationReason = "Git conflicts require human review"
-		} else if strings.Contains(problem, "push") || strings.Contains(problem, "fetch") {
-			assessment.CanHelp = true
-			assessment.HelpAction = "Check git remote status and network connectivity"
-		}
-	}
-
-	// Test failures - usually need escalation
-	if strings.Contains(topic, "test") || strings.Contains(problem, "test fail") {
-		assessment.CanHelp = false
-		assessment.NeedsEscalation = true
-		assessment.EscalationReason = "Test failures require investigation"
-	}
+	return AssessHelpRequestWithRules(DefaultHelpRules(), payload, "", nil)
+}
 
-	// Build issues - Witness can check basics
-	if strings.Contains(topic, "build") || strings.Contains(problem, "compile") {
-		assessment.CanHelp = true
-		assessment.HelpAction = "Verify dependencies and build configuration"
-	}
+// AssessHelpRequestWithRules evaluates a help request against a rule set.
+// polecat and labels (the issue bead's labels) feed the rules' match conditions.
+// Requests no rule matches are escalated.
+func AssessHelpRequestWithRules(rules *HelpRules, payload *HelpPayload, polecat string, labels []string) *HelpAssessment {
+	assessment := &HelpAssessment{}
 
-	// Requirements unclear - always escalate
-	if strings.Contains(topic, "unclear") || strings.Contains(problem, "requirement") ||
-		strings.Contains(problem, "don't understand") {
-		assessment.CanHelp = false
+	rule := rules.Evaluate(HelpContext{
+		Topic:   payload.Topic,
+		Problem: payload.Problem,
+		Polecat: polecat,
+		Labels:  labels,
+		Retries: payload.Retries,
+	})
+	if rule == nil {
+		// Default: escalate if we don't recognize the pattern
 		assessment.NeedsEscalation = true
-		assessment.EscalationReason = "Requirements clarification needed from Mayor"
+		assessment.EscalationReason = "Unknown help request type"
+		assessment.Severity = config.SeverityMedium
+		return assessment
 	}
 
-	// Default: escalate if we don't recognize the pattern
-	if !assessment.CanHelp && !assessment.NeedsEscalation {
+	assessment.Rule = rule
+	if rule.Action == HelpActionEscalate {
 		assessment.NeedsEscalation = true
-		assessment.EscalationReason = "Unknown help request type"
+		assessment.EscalationReason = rule.Message
+		assessment.Severity = rule.Severity
+	} else {
+		assessment.CanHelp = true
+		assessment.HelpAction = rule.Message
 	}
 
 	return assessment
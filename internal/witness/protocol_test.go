@@ -40,7 +40,8 @@ func TestParsePolecatDone(t *testing.T) {
 	body := `Exit: MERGED
 Issue: gt-abc123
 MR: gt-mr-xyz
-Branch: feature-branch`
+Branch: feature-branch
+Trace-ID: 4bf92f3577b34da6a3ce929d0e0e4736`
 
 	payload, err := ParsePolecatDone(subject, body)
 	if err != nil {
@@ -62,6 +63,9 @@ Branch: feature-branch`
 	if payload.Branch != "feature-branch" {
 		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-branch")
 	}
+	if payload.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %q, want trace ID", payload.TraceID)
+	}
 }
 
 func TestParsePolecatDone_MinimalBody(t *testing.T) {
@@ -0,0 +1,78 @@
+package witness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// ResourceCheck is the resource report for one polecat.
+type ResourceCheck struct {
+	Polecat string          `json:"polecat"`
+	Running bool            `json:"running"`
+	Report  *sandbox.Report `json:"report"`
+}
+
+// NeedsAttention reports whether the polecat is at or over a limit.
+func (c *ResourceCheck) NeedsAttention() bool {
+	return c.Report != nil && len(c.Report.Warnings) > 0
+}
+
+// CheckPolecatResources inspects every polecat in a rig against the rig's
+// resource policy. It returns nil when the rig has no policy.
+func CheckPolecatResources(rigPath, rigName string) ([]ResourceCheck, error) {
+	res, err := sandbox.LoadPolicy(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(rigPath, "polecats"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing polecats: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	var checks []ResourceCheck
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		running, _ := t.HasSession(session.PolecatSessionName(rigName, name))
+		checks = append(checks, ResourceCheck{
+			Polecat: name,
+			Running: running,
+			Report:  sandbox.Inspect(rigName, name, polecatWorktree(rigPath, rigName, name), res, running),
+		})
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Polecat < checks[j].Polecat })
+	return checks, nil
+}
+
+// polecatWorktree returns a polecat's worktree, preferring the
+// polecats/<name>/<rig>/ layout over the older polecats/<name>/.
+func polecatWorktree(rigPath, rigName, polecat string) string {
+	newPath := filepath.Join(rigPath, "polecats", polecat, rigName)
+	if info, err := os.Stat(newPath); err == nil && info.IsDir() {
+		return newPath
+	}
+	return filepath.Join(rigPath, "polecats", polecat)
+}
+
+// ResourceNudge formats the nudge sent to a polecat that is over a limit.
+func ResourceNudge(c *ResourceCheck) string {
+	return fmt.Sprintf("Witness resource check: %s. Clean up build artifacts and caches, and avoid running more in parallel than the rig's limits allow.",
+		strings.Join(c.Report.Warnings, "; "))
+}
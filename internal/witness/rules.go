@@ -0,0 +1,274 @@
+package witness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Help triage actions a rule can produce.
+const (
+	// HelpActionHint returns a self-help hint the Witness relays to the polecat.
+	HelpActionHint = "hint"
+
+	// HelpActionNudge nudges the polecat's session with canned instructions.
+	HelpActionNudge = "nudge"
+
+	// HelpActionDog asks the Deacon to dispatch a dog to the problem.
+	HelpActionDog = "dog"
+
+	// HelpActionEscalate escalates to the Mayor at the rule's severity.
+	HelpActionEscalate = "escalate"
+)
+
+// HelpRulesFile is the per-rig rules file, relative to the rig's settings directory.
+const HelpRulesFile = "witness-rules.json"
+
+// HelpRulesPath returns the path to a rig's help triage rules file.
+func HelpRulesPath(rigPath string) string {
+	return filepath.Join(rigPath, "settings", HelpRulesFile)
+}
+
+// HelpRuleMatch describes which help requests a rule applies to.
+// All non-empty conditions must hold; an empty match matches everything.
+type HelpRuleMatch struct {
+	// Topic is a regex matched (case-insensitively) against the HELP subject topic.
+	Topic string `json:"topic,omitempty"`
+
+	// Problem is a regex matched (case-insensitively) against the Problem field.
+	Problem string `json:"problem,omitempty"`
+
+	// Text is a regex matched (case-insensitively) against topic and problem together.
+	Text string `json:"text,omitempty"`
+
+	// Polecat is a regex matched against the requesting polecat's name.
+	Polecat string `json:"polecat,omitempty"`
+
+	// Labels must all be present on the issue bead.
+	Labels []string `json:"labels,omitempty"`
+
+	// MinRetries matches only when the polecat has asked at least this many
+	// times before about the same issue.
+	MinRetries int `json:"min_retries,omitempty"`
+
+	topic, problem, text, polecat *regexp.Regexp
+}
+
+// HelpRule maps a class of help requests to a triage action.
+type HelpRule struct {
+	// Name identifies the rule in logs and dry runs.
+	Name string `json:"name"`
+
+	// Match selects the requests this rule handles.
+	Match HelpRuleMatch `json:"match"`
+
+	// Action is one of hint, nudge, dog or escalate.
+	Action string `json:"action"`
+
+	// Message is the hint, nudge text, dog instructions or escalation reason.
+	Message string `json:"message"`
+
+	// Severity is the escalation severity (low, medium, high, critical).
+	// Only used by the escalate action; defaults to medium.
+	Severity string `json:"severity,omitempty"`
+
+	// Formula is the formula a dog should run (dog action only, optional).
+	Formula string `json:"formula,omitempty"`
+}
+
+// HelpRules is an ordered rule set. The first matching rule wins.
+type HelpRules struct {
+	Version int        `json:"version"`
+	Rules   []HelpRule `json:"rules"`
+}
+
+// HelpContext is what rules are evaluated against.
+type HelpContext struct {
+	Topic   string
+	Problem string
+	Polecat string
+	Labels  []string
+	Retries int
+}
+
+// DefaultHelpRules returns the built-in rule set used when a rig has no
+// rules file. It mirrors the Witness's historical heuristics.
+func DefaultHelpRules() *HelpRules {
+	rules := &HelpRules{Version: 1, Rules: []HelpRule{
+		{
+			Name:     "requirements-unclear",
+			Match:    HelpRuleMatch{Text: `unclear|requirement|don't understand`},
+			Action:   HelpActionEscalate,
+			Message:  "Requirements clarification needed from Mayor",
+			Severity: config.SeverityHigh,
+		},
+		{
+			Name:    "build",
+			Match:   HelpRuleMatch{Text: `build|compile`},
+			Action:  HelpActionHint,
+			Message: "Verify dependencies and build configuration",
+		},
+		{
+			Name:     "test-failures",
+			Match:    HelpRuleMatch{Text: `\btests?\b|test fail`},
+			Action:   HelpActionEscalate,
+			Message:  "Test failures require investigation",
+			Severity: config.SeverityHigh,
+		},
+		{
+			Name:     "git-conflict",
+			Match:    HelpRuleMatch{Text: `git`, Problem: `conflict`},
+			Action:   HelpActionEscalate,
+			Message:  "Git conflicts require human review",
+			Severity: config.SeverityHigh,
+		},
+		{
+			Name:    "git-remote",
+			Match:   HelpRuleMatch{Text: `git`, Problem: `push|fetch`},
+			Action:  HelpActionHint,
+			Message: "Check git remote status and network connectivity",
+		},
+		{
+			Name:     "unknown",
+			Action:   HelpActionEscalate,
+			Message:  "Unknown help request type",
+			Severity: config.SeverityHigh,
+		},
+	}}
+	if err := rules.compile(); err != nil {
+		panic("witness: invalid default help rules: " + err.Error())
+	}
+	return rules
+}
+
+// LoadHelpRules loads a rig's help triage rules. A missing file yields the
+// default rules.
+func LoadHelpRules(rigPath string) (*HelpRules, error) {
+	return LoadHelpRulesFile(HelpRulesPath(rigPath))
+}
+
+// LoadHelpRulesFile loads and validates a help triage rules file.
+// A missing file yields the default rules.
+func LoadHelpRulesFile(path string) (*HelpRules, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally or given by the operator
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultHelpRules(), nil
+		}
+		return nil, fmt.Errorf("reading help rules: %w", err)
+	}
+
+	var rules HelpRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing help rules %s: %w", path, err)
+	}
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("invalid help rules %s: %w", path, err)
+	}
+	return &rules, nil
+}
+
+// compile validates the rules and compiles their regexes.
+func (r *HelpRules) compile() error {
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		switch rule.Action {
+		case HelpActionHint, HelpActionNudge, HelpActionDog:
+		case HelpActionEscalate:
+			if rule.Severity == "" {
+				rule.Severity = config.SeverityMedium
+			}
+			if !config.IsValidSeverity(rule.Severity) {
+				return fmt.Errorf("rule %s: invalid severity %q", rule.Name, rule.Severity)
+			}
+		default:
+			return fmt.Errorf("rule %s: invalid action %q (want hint, nudge, dog or escalate)", rule.Name, rule.Action)
+		}
+
+		m := &rule.Match
+		var err error
+		if m.topic, err = compileRulePattern(m.Topic); err != nil {
+			return fmt.Errorf("rule %s: topic: %w", rule.Name, err)
+		}
+		if m.problem, err = compileRulePattern(m.Problem); err != nil {
+			return fmt.Errorf("rule %s: problem: %w", rule.Name, err)
+		}
+		if m.text, err = compileRulePattern(m.Text); err != nil {
+			return fmt.Errorf("rule %s: text: %w", rule.Name, err)
+		}
+		if m.polecat, err = compileRulePattern(m.Polecat); err != nil {
+			return fmt.Errorf("rule %s: polecat: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+func compileRulePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// NeedsLabels reports whether any rule matches on bead labels, so callers
+// can skip looking up the issue bead when it's not needed.
+func (r *HelpRules) NeedsLabels() bool {
+	for _, rule := range r.Rules {
+		if len(rule.Match.Labels) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate returns the first rule matching the request, or nil if none does.
+func (r *HelpRules) Evaluate(ctx HelpContext) *HelpRule {
+	for i := range r.Rules {
+		if r.Rules[i].Match.matches(ctx) {
+			return &r.Rules[i]
+		}
+	}
+	return nil
+}
+
+func (m *HelpRuleMatch) matches(ctx HelpContext) bool {
+	if ctx.Retries < m.MinRetries {
+		return false
+	}
+	if m.polecat != nil && !m.polecat.MatchString(ctx.Polecat) {
+		return false
+	}
+	for _, want := range m.Labels {
+		if !containsLabel(ctx.Labels, want) {
+			return false
+		}
+	}
+	if m.topic != nil && !m.topic.MatchString(ctx.Topic) {
+		return false
+	}
+	if m.problem != nil && !m.problem.MatchString(ctx.Problem) {
+		return false
+	}
+	if m.text != nil && !m.text.MatchString(ctx.Topic+"\n"+ctx.Problem) {
+		return false
+	}
+	return true
+}
+
+func containsLabel(labels []string, want string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package witness
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeHelpRules(t *testing.T, content string) string {
+	t.Helper()
+	rigPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(HelpRulesPath(rigPath), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return rigPath
+}
+
+func TestLoadHelpRules_MissingFileUsesDefaults(t *testing.T) {
+	rules, err := LoadHelpRules(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadHelpRules() error = %v", err)
+	}
+	if len(rules.Rules) != len(DefaultHelpRules().Rules) {
+		t.Errorf("got %d rules, want defaults", len(rules.Rules))
+	}
+}
+
+func TestLoadHelpRules_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad regex":    `{"rules": [{"name": "x", "match": {"topic": "("}, "action": "hint"}]}`,
+		"bad action":   `{"rules": [{"name": "x", "action": "reboot"}]}`,
+		"bad severity": `{"rules": [{"name": "x", "action": "escalate", "severity": "meh"}]}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadHelpRules(writeHelpRules(t, content)); err == nil {
+				t.Error("LoadHelpRules() expected error")
+			}
+		})
+	}
+}
+
+func TestHelpRules_Evaluate(t *testing.T) {
+	rigPath := writeHelpRules(t, `{
+  "version": 1,
+  "rules": [
+    {"name": "repeat", "match": {"min_retries": 2}, "action": "escalate", "severity": "critical", "message": "stuck"},
+    {"name": "flaky", "match": {"problem": "flak", "labels": ["ci"]}, "action": "nudge", "message": "re-run"},
+    {"name": "nux-build", "match": {"topic": "^build", "polecat": "^nux$"}, "action": "dog", "formula": "mol-build-doctor"},
+    {"name": "fallback", "action": "hint", "message": "read the docs"}
+  ]
+}`)
+	rules, err := LoadHelpRules(rigPath)
+	if err != nil {
+		t.Fatalf("LoadHelpRules() error = %v", err)
+	}
+
+	tests := []struct {
+		ctx  HelpContext
+		want string
+	}{
+		{HelpContext{Topic: "anything", Retries: 2}, "repeat"},
+		{HelpContext{Topic: "CI", Problem: "Flaky test", Labels: []string{"CI"}}, "flaky"},
+		{HelpContext{Topic: "CI", Problem: "Flaky test"}, "fallback"},
+		{HelpContext{Topic: "Build broken", Polecat: "nux"}, "nux-build"},
+		{HelpContext{Topic: "Build broken", Polecat: "toast"}, "fallback"},
+	}
+	for _, tt := range tests {
+		rule := rules.Evaluate(tt.ctx)
+		if rule == nil || rule.Name != tt.want {
+			t.Errorf("Evaluate(%+v) = %v, want %s", tt.ctx, rule, tt.want)
+		}
+	}
+}
+
+func TestAssessHelpRequestWithRules_Escalation(t *testing.T) {
+	rules, err := LoadHelpRules(writeHelpRules(t, `{"rules": [
+    {"name": "db", "match": {"text": "database"}, "action": "escalate", "message": "DB trouble"}
+  ]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := AssessHelpRequestWithRules(rules, &HelpPayload{Topic: "database locked"}, "", nil)
+	if !a.NeedsEscalation || a.Rule == nil || a.Rule.Name != "db" {
+		t.Fatalf("assessment = %+v, want escalation by db", a)
+	}
+	if a.Severity != config.SeverityMedium {
+		t.Errorf("Severity = %q, want default medium", a.Severity)
+	}
+
+	// Nothing matches: escalate as unknown
+	a = AssessHelpRequestWithRules(rules, &HelpPayload{Topic: "other"}, "", nil)
+	if !a.NeedsEscalation || a.Rule != nil {
+		t.Errorf("assessment = %+v, want unmatched escalation", a)
+	}
+}
+
+func TestPolecatFromAgent(t *testing.T) {
+	tests := map[string]string{
+		"gastown/polecats/nux": "nux",
+		"gastown/nux":          "nux",
+		"gastown/witness":      "",
+		"gastown/crew/joe":     "",
+		"mayor/":               "",
+		"":                     "",
+	}
+	for agent, want := range tests {
+		if got := PolecatFromAgent(agent); got != want {
+			t.Errorf("PolecatFromAgent(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}